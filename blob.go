@@ -0,0 +1,51 @@
+package consensus
+
+import (
+	"crypto/sha256"
+	"strconv"
+)
+
+// VersionedHashVersionKZG is the version byte of the versioned hash of a KZG commitment
+const VersionedHashVersionKZG = byte(0x01)
+
+// VersionedHash returns the hash of the KZG commitment that the execution
+// layer uses to reference the blob
+func VersionedHash(commitment [48]byte) [32]byte {
+	hash := sha256.Sum256(commitment[:])
+	hash[0] = VersionedHashVersionKZG
+	return hash
+}
+
+// VersionedHashes returns the versioned hashes of the KZG commitments
+func VersionedHashes(commitments [][48]byte) [][32]byte {
+	hashes := make([][32]byte, len(commitments))
+	for indx, commitment := range commitments {
+		hashes[indx] = VersionedHash(commitment)
+	}
+	return hashes
+}
+
+// BlobCommitmentGIndex returns the generalized index of the KZG commitment at the
+// given index in the block body. The index is the same for Deneb and Electra.
+func BlobCommitmentGIndex(index uint64) (uint64, error) {
+	return GIndex(&BeaconBlockBodyDeneb{}, "blob_kzg_commitments", strconv.FormatUint(index, 10))
+}
+
+// VerifyInclusionProof checks that the KZG commitment of the sidecar is part of
+// the body of the block of the signed block header
+func (b *BlobSidecar) VerifyInclusionProof() bool {
+	if b.SignedBlockHeader == nil || b.SignedBlockHeader.Header == nil {
+		return false
+	}
+	gindex, err := BlobCommitmentGIndex(b.Index)
+	if err != nil {
+		return false
+	}
+
+	// the leaf is the hash tree root of the 48 bytes commitment
+	var chunks [64]byte
+	copy(chunks[:], b.KZGCommitment[:])
+	leaf := sha256.Sum256(chunks[:])
+
+	return VerifyProof(b.SignedBlockHeader.Header.BodyRoot, leaf, b.KZGCommitmentInclusionProof[:], gindex)
+}
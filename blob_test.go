@@ -0,0 +1,72 @@
+package consensus
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionedHash(t *testing.T) {
+	commitment := [48]byte{0x1, 0x2}
+
+	hash := VersionedHash(commitment)
+	expected := sha256.Sum256(commitment[:])
+
+	require.Equal(t, VersionedHashVersionKZG, hash[0])
+	require.Equal(t, expected[1:], hash[1:])
+	require.Equal(t, [][32]byte{hash}, VersionedHashes([][48]byte{commitment}))
+}
+
+func TestBlobSidecar_VerifyInclusionProof(t *testing.T) {
+	// generalized index of the first commitment with the proof depth of the spec
+	gindex, err := BlobCommitmentGIndex(0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(221184), gindex)
+
+	body := &BeaconBlockBodyDeneb{
+		Eth1Data:           &Eth1Data{},
+		SyncAggregate:      &SyncAggregate{},
+		ExecutionPayload:   &ExecutionPayloadDeneb{},
+		BlobKZGCommitments: [][48]byte{{0x1}, {0x2}, {0x3}},
+	}
+	bodyRoot, err := body.HashTreeRoot()
+	require.NoError(t, err)
+
+	newSidecar := func(index uint64) *BlobSidecar {
+		gindex, err := BlobCommitmentGIndex(index)
+		require.NoError(t, err)
+
+		proof, err := Prove(body, gindex)
+		require.NoError(t, err)
+
+		sidecar := &BlobSidecar{
+			Index:         index,
+			KZGCommitment: body.BlobKZGCommitments[index],
+			SignedBlockHeader: &SignedBeaconBlockHeader{
+				Header: &BeaconBlockHeader{BodyRoot: bodyRoot},
+			},
+		}
+		copy(sidecar.KZGCommitmentInclusionProof[:], proof.Branch)
+		return sidecar
+	}
+
+	for i := range body.BlobKZGCommitments {
+		require.True(t, newSidecar(uint64(i)).VerifyInclusionProof())
+	}
+
+	// wrong commitment
+	sidecar := newSidecar(1)
+	sidecar.KZGCommitment = [48]byte{0x4}
+	require.False(t, sidecar.VerifyInclusionProof())
+
+	// wrong index
+	sidecar = newSidecar(1)
+	sidecar.Index = 2
+	require.False(t, sidecar.VerifyInclusionProof())
+
+	// no header
+	sidecar = newSidecar(1)
+	sidecar.SignedBlockHeader = nil
+	require.False(t, sidecar.VerifyInclusionProof())
+}
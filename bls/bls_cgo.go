@@ -65,6 +65,22 @@ func AggregateSignatures(sigs []*Signature) *Signature {
 	return &Signature{sig: sig.ToAffine()}
 }
 
+// AggregatePublicKeys aggregates the public keys into a single one
+func AggregatePublicKeys(pubs []*PublicKey) *PublicKey {
+	if len(pubs) == 0 {
+		return nil
+	}
+	raw := make([]*blstPublicKey, len(pubs))
+	for indx, i := range pubs {
+		raw[indx] = i.pub
+	}
+
+	pub := new(blst.P1Aggregate)
+	pub.Aggregate(raw, false)
+
+	return &PublicKey{pub: pub.ToAffine()}
+}
+
 // PublicKey is a Bls public key
 type PublicKey struct {
 	pub *blstPublicKey
@@ -89,6 +89,22 @@ func AggregateSignatures(sigs []*Signature) *Signature {
 	return &Signature{sig: aggSig}
 }
 
+// AggregatePublicKeys aggregates the public keys into a single one
+func AggregatePublicKeys(pubs []*PublicKey) *PublicKey {
+	if len(pubs) == 0 {
+		return nil
+	}
+
+	aggPub := new(bls12381.PointG1)
+	g1 := bls12381.NewG1()
+
+	for _, pub := range pubs {
+		aggPub = g1.Add(aggPub, aggPub, pub.pub)
+	}
+
+	return &PublicKey{pub: aggPub}
+}
+
 // PublicKey is a Bls public key
 type PublicKey struct {
 	pub *blstPublicKey
@@ -37,6 +37,27 @@ func TestBLS_Simple(t *testing.T) {
 	require.True(t, valid)
 }
 
+func TestBLS_AggregatePublicKeys(t *testing.T) {
+	msg := []byte("msg")
+
+	pubs := []*PublicKey{}
+	sigs := []*Signature{}
+	for i := 0; i < 3; i++ {
+		priv := RandomKey()
+
+		sig, err := priv.Sign(msg)
+		require.NoError(t, err)
+
+		pubs = append(pubs, priv.GetPublicKey())
+		sigs = append(sigs, sig)
+	}
+
+	// the aggregated signature is valid for the aggregated public key
+	valid, err := AggregateSignatures(sigs).VerifyByte(AggregatePublicKeys(pubs), msg)
+	require.NoError(t, err)
+	require.True(t, valid)
+}
+
 func TestBLS_Aggregate(t *testing.T) {
 	type ref struct {
 		Input  []argBytes
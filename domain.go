@@ -51,6 +51,23 @@ func ComputeDomain(domain Domain, forkVersion [4]byte, genesisValidatorsRoot Roo
 	return ToBytes32(append(domain[:], forkRoot[:28]...)), nil
 }
 
+// ComputeForkDigest returns the 4 byte digest of the fork used to identify the fork in the p2p
+// network and in the ssz responses of the beacon api.
+func ComputeForkDigest(forkVersion [4]byte, genesisValidatorsRoot Root) ([4]byte, error) {
+	forkData := ForkData{
+		CurrentVersion:        forkVersion,
+		GenesisValidatorsRoot: genesisValidatorsRoot,
+	}
+	forkRoot, err := forkData.HashTreeRoot()
+	if err != nil {
+		return [4]byte{}, err
+	}
+
+	var digest [4]byte
+	copy(digest[:], forkRoot[:4])
+	return digest, nil
+}
+
 func ComputeSigningRoot(domain [32]byte, obj ssz.HashRoot) ([32]byte, error) {
 	unsignedMsgRoot, err := obj.HashTreeRoot()
 	if err != nil {
@@ -81,4 +98,8 @@ var (
 	DomainSyncCommitteeType           = Domain{7, 0, 0, 0}
 	DomainSyncCommitteeSelectionProof = Domain{8, 0, 0, 0}
 	DomainContributionAndProof        = Domain{9, 0, 0, 0}
+	DomainBLSToExecutionChange        = Domain{10, 0, 0, 0}
+
+	// DomainApplicationBuilder is the domain of the messages of the builder api
+	DomainApplicationBuilder = Domain{0, 0, 0, 1}
 )
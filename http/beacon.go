@@ -1,7 +1,11 @@
 package http
 
 import (
+	"context"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 
 	consensus "github.com/umbracle/go-eth-consensus"
 )
@@ -21,25 +25,51 @@ type GenesisInfo struct {
 }
 
 func (b *BeaconEndpoint) Genesis() (*GenesisInfo, error) {
+	return b.GenesisWithContext(context.Background())
+}
+
+func (b *BeaconEndpoint) GenesisWithContext(ctx context.Context) (*GenesisInfo, error) {
 	var out GenesisInfo
-	err := b.c.Get("/eth/v1/beacon/genesis", &out)
+	err := b.c.GetWithContext(ctx, "/eth/v1/beacon/genesis", &out)
 	return &out, err
 }
 
 func (b *BeaconEndpoint) SubmitCommitteeDuties(duties []*consensus.SyncCommitteeMessage) error {
-	err := b.c.Post("/eth/v1/beacon/pool/sync_committees", duties, nil)
+	return b.SubmitCommitteeDutiesWithContext(context.Background(), duties)
+}
+
+func (b *BeaconEndpoint) SubmitCommitteeDutiesWithContext(ctx context.Context, duties []*consensus.SyncCommitteeMessage) error {
+	err := b.c.PostWithContext(ctx, "/eth/v1/beacon/pool/sync_committees", duties, nil)
 	return err
 }
 
 type ValidatorStatus string
 
 const (
-	ValidatorStatusUnknown ValidatorStatus = "unknown"
-	ValidatorStatusActive  ValidatorStatus = "active"
-	ValidatorStatusPending ValidatorStatus = "pending"
-	ValidatorStatusExited  ValidatorStatus = "exited"
+	ValidatorStatusUnknown    ValidatorStatus = "unknown"
+	ValidatorStatusActive     ValidatorStatus = "active"
+	ValidatorStatusPending    ValidatorStatus = "pending"
+	ValidatorStatusExited     ValidatorStatus = "exited"
+	ValidatorStatusWithdrawal ValidatorStatus = "withdrawal"
+
+	ValidatorStatusPendingInitialized ValidatorStatus = "pending_initialized"
+	ValidatorStatusPendingQueued      ValidatorStatus = "pending_queued"
+	ValidatorStatusActiveOngoing      ValidatorStatus = "active_ongoing"
+	ValidatorStatusActiveExiting      ValidatorStatus = "active_exiting"
+	ValidatorStatusActiveSlashed      ValidatorStatus = "active_slashed"
+	ValidatorStatusExitedUnslashed    ValidatorStatus = "exited_unslashed"
+	ValidatorStatusExitedSlashed      ValidatorStatus = "exited_slashed"
+	ValidatorStatusWithdrawalPossible ValidatorStatus = "withdrawal_possible"
+	ValidatorStatusWithdrawalDone     ValidatorStatus = "withdrawal_done"
 )
 
+// Group returns the general status (pending, active, exited or withdrawal)
+// of the status
+func (v ValidatorStatus) Group() ValidatorStatus {
+	group, _, _ := strings.Cut(string(v), "_")
+	return ValidatorStatus(group)
+}
+
 type Validator struct {
 	Index     uint64             `json:"index"`
 	Balance   uint64             `json:"balance"`
@@ -93,16 +123,24 @@ const (
 )
 
 func (b *BeaconEndpoint) GetRoot(id StateId) ([32]byte, error) {
+	return b.GetRootWithContext(context.Background(), id)
+}
+
+func (b *BeaconEndpoint) GetRootWithContext(ctx context.Context, id StateId) ([32]byte, error) {
 	var out struct {
 		Root [32]byte
 	}
-	err := b.c.Get("/eth/v1/beacon/states/"+id.StateID()+"/root", &out)
+	err := b.c.GetWithContext(ctx, "/eth/v1/beacon/states/"+id.StateID()+"/root", &out)
 	return out.Root, err
 }
 
 func (b *BeaconEndpoint) GetFork(id StateId) (*consensus.Fork, error) {
+	return b.GetForkWithContext(context.Background(), id)
+}
+
+func (b *BeaconEndpoint) GetForkWithContext(ctx context.Context, id StateId) (*consensus.Fork, error) {
 	var out *consensus.Fork
-	err := b.c.Get("/eth/v1/beacon/states/"+id.StateID()+"/fork", &out)
+	err := b.c.GetWithContext(ctx, "/eth/v1/beacon/states/"+id.StateID()+"/fork", &out)
 	return out, err
 }
 
@@ -113,30 +151,345 @@ type FinalizedCheckpoints struct {
 }
 
 func (b *BeaconEndpoint) GetFinalityCheckpoints(id StateId) (*FinalizedCheckpoints, error) {
+	return b.GetFinalityCheckpointsWithContext(context.Background(), id)
+}
+
+func (b *BeaconEndpoint) GetFinalityCheckpointsWithContext(ctx context.Context, id StateId) (*FinalizedCheckpoints, error) {
 	var out *FinalizedCheckpoints
-	err := b.c.Get("/eth/v1/beacon/states/"+id.StateID()+"/finality_checkpoints", &out)
+	err := b.c.GetWithContext(ctx, "/eth/v1/beacon/states/"+id.StateID()+"/finality_checkpoints", &out)
 	return out, err
 }
 
 func (b *BeaconEndpoint) GetValidators(id StateId) ([]*Validator, error) {
+	return b.GetValidatorsWithContext(context.Background(), id)
+}
+
+func (b *BeaconEndpoint) GetValidatorsWithContext(ctx context.Context, id StateId) ([]*Validator, error) {
 	var out []*Validator
-	err := b.c.Get("/eth/v1/beacon/states/"+id.StateID()+"/validators", &out)
+	err := b.c.GetWithContext(ctx, "/eth/v1/beacon/states/"+id.StateID()+"/validators", &out)
 	return out, err
 }
 
 func (b *BeaconEndpoint) GetValidatorByPubKey(pub string, id StateId) (*Validator, error) {
+	return b.GetValidatorByPubKeyWithContext(context.Background(), pub, id)
+}
+
+func (b *BeaconEndpoint) GetValidatorByPubKeyWithContext(ctx context.Context, pub string, id StateId) (*Validator, error) {
 	var out *Validator
-	err := b.c.Get("/eth/v1/beacon/states/"+id.StateID()+"/validators/"+pub, &out)
+	err := b.c.GetWithContext(ctx, "/eth/v1/beacon/states/"+id.StateID()+"/validators/"+pub, &out)
+	return out, err
+}
+
+// ValidatorsFilter filters the validators of a state. The ids are either
+// indexes or 0x prefixed public keys. Empty fields do not filter.
+type ValidatorsFilter struct {
+	IDs      []string          `json:"ids,omitempty"`
+	Statuses []ValidatorStatus `json:"statuses,omitempty"`
+}
+
+func (b *BeaconEndpoint) FilterValidators(id StateId, filter *ValidatorsFilter) ([]*Validator, error) {
+	return b.FilterValidatorsWithContext(context.Background(), id, filter)
+}
+
+func (b *BeaconEndpoint) FilterValidatorsWithContext(ctx context.Context, id StateId, filter *ValidatorsFilter) ([]*Validator, error) {
+	if filter == nil {
+		filter = &ValidatorsFilter{}
+	}
+	var out []*Validator
+	err := b.c.PostWithContext(ctx, "/eth/v1/beacon/states/"+id.StateID()+"/validators", filter, &out)
+	return out, err
+}
+
+type ValidatorBalance struct {
+	Index   uint64 `json:"index"`
+	Balance uint64 `json:"balance"`
+}
+
+// GetValidatorBalances returns the balances of the validators with the given
+// ids (indexes or public keys). All the balances are returned if there are no ids.
+func (b *BeaconEndpoint) GetValidatorBalances(id StateId, ids []string) ([]*ValidatorBalance, error) {
+	return b.GetValidatorBalancesWithContext(context.Background(), id, ids)
+}
+
+func (b *BeaconEndpoint) GetValidatorBalancesWithContext(ctx context.Context, id StateId, ids []string) ([]*ValidatorBalance, error) {
+	query := url.Values{}
+	if len(ids) != 0 {
+		query.Set("id", strings.Join(ids, ","))
+	}
+	var out []*ValidatorBalance
+	err := b.c.GetWithContext(ctx, withQuery("/eth/v1/beacon/states/"+id.StateID()+"/validator_balances", query), &out)
 	return out, err
 }
 
+type Committee struct {
+	Index      uint64   `json:"index"`
+	Slot       uint64   `json:"slot"`
+	Validators []uint64 `json:"validators"`
+}
+
+// CommitteeFilter filters the committees of a state. Nil fields do not filter,
+// the committees of the epoch of the state are returned if there is no epoch.
+type CommitteeFilter struct {
+	Epoch *uint64
+	Index *uint64
+	Slot  *uint64
+}
+
+func (b *BeaconEndpoint) GetCommittees(id StateId, filter *CommitteeFilter) ([]*Committee, error) {
+	return b.GetCommitteesWithContext(context.Background(), id, filter)
+}
+
+func (b *BeaconEndpoint) GetCommitteesWithContext(ctx context.Context, id StateId, filter *CommitteeFilter) ([]*Committee, error) {
+	query := url.Values{}
+	if filter != nil {
+		setUintQuery(query, "epoch", filter.Epoch)
+		setUintQuery(query, "index", filter.Index)
+		setUintQuery(query, "slot", filter.Slot)
+	}
+	var out []*Committee
+	err := b.c.GetWithContext(ctx, withQuery("/eth/v1/beacon/states/"+id.StateID()+"/committees", query), &out)
+	return out, err
+}
+
+type SyncCommittees struct {
+	// Validators are the indexes of the validators of the sync committee
+	Validators []uint64 `json:"validators"`
+
+	// ValidatorAggregates are the indexes of the validators of each subcommittee
+	ValidatorAggregates [][]uint64 `json:"validator_aggregates"`
+}
+
+// GetSyncCommittees returns the sync committee of the epoch or of the epoch of
+// the state if the epoch is nil
+func (b *BeaconEndpoint) GetSyncCommittees(id StateId, epoch *uint64) (*SyncCommittees, error) {
+	return b.GetSyncCommitteesWithContext(context.Background(), id, epoch)
+}
+
+func (b *BeaconEndpoint) GetSyncCommitteesWithContext(ctx context.Context, id StateId, epoch *uint64) (*SyncCommittees, error) {
+	query := url.Values{}
+	setUintQuery(query, "epoch", epoch)
+
+	var out *SyncCommittees
+	err := b.c.GetWithContext(ctx, withQuery("/eth/v1/beacon/states/"+id.StateID()+"/sync_committees", query), &out)
+	return out, err
+}
+
+// GetRandao returns the randao mix of the epoch or of the epoch of the state
+// if the epoch is nil
+func (b *BeaconEndpoint) GetRandao(id StateId, epoch *uint64) ([32]byte, error) {
+	return b.GetRandaoWithContext(context.Background(), id, epoch)
+}
+
+func (b *BeaconEndpoint) GetRandaoWithContext(ctx context.Context, id StateId, epoch *uint64) ([32]byte, error) {
+	query := url.Values{}
+	setUintQuery(query, "epoch", epoch)
+
+	var out struct {
+		Randao [32]byte `json:"randao"`
+	}
+	err := b.c.GetWithContext(ctx, withQuery("/eth/v1/beacon/states/"+id.StateID()+"/randao", query), &out)
+	return out.Randao, err
+}
+
+func setUintQuery(query url.Values, key string, val *uint64) {
+	if val != nil {
+		query.Set(key, strconv.FormatUint(*val, 10))
+	}
+}
+
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+
 func (b *BeaconEndpoint) PublishSignedBlock(block consensus.SignedBeaconBlock) error {
-	err := b.c.Post("/eth/v1/beacon/blocks", block, nil)
+	return b.PublishSignedBlockWithContext(context.Background(), block)
+}
+
+func (b *BeaconEndpoint) PublishSignedBlockWithContext(ctx context.Context, block consensus.SignedBeaconBlock) error {
+	versioned, err := consensus.NewVersionedSignedBeaconBlock(block)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{
+		headerConsensusVersion: versioned.Version.String(),
+	}
+	err = b.c.post(ctx, "/eth/v1/beacon/blocks", block, nil, headers)
+	return err
+}
+
+// BroadcastValidation is the validation of a block that the node
+// does before it broadcasts the block
+type BroadcastValidation string
+
+const (
+	BroadcastValidationGossip                   BroadcastValidation = "gossip"
+	BroadcastValidationConsensus                BroadcastValidation = "consensus"
+	BroadcastValidationConsensusAndEquivocation BroadcastValidation = "consensus_and_equivocation"
+)
+
+func broadcastValidationQuery(validation BroadcastValidation) url.Values {
+	query := url.Values{}
+	if validation != "" {
+		query.Set("broadcast_validation", string(validation))
+	}
+	return query
+}
+
+// PublishSignedBlockContents publishes a signed block with the given broadcast
+// validation. From Deneb the block is published with its blobs and their proofs.
+func (b *BeaconEndpoint) PublishSignedBlockContents(block consensus.SignedBeaconBlock, kzgProofs [][48]byte, blobs [][131072]byte, validation BroadcastValidation) error {
+	return b.PublishSignedBlockContentsWithContext(context.Background(), block, kzgProofs, blobs, validation)
+}
+
+func (b *BeaconEndpoint) PublishSignedBlockContentsWithContext(ctx context.Context, block consensus.SignedBeaconBlock, kzgProofs [][48]byte, blobs [][131072]byte, validation BroadcastValidation) error {
+	versioned, err := consensus.NewVersionedSignedBeaconBlock(block)
+	if err != nil {
+		return err
+	}
+
+	var input interface{} = block
+	switch obj := block.(type) {
+	case *consensus.SignedBeaconBlockDeneb:
+		input = &consensus.SignedBlockContentsDeneb{SignedBlock: obj, KZGProofs: kzgProofs, Blobs: blobs}
+	case *consensus.SignedBeaconBlockElectra:
+		input = &consensus.SignedBlockContentsElectra{SignedBlock: obj, KZGProofs: kzgProofs, Blobs: blobs}
+	default:
+		if len(kzgProofs) != 0 || len(blobs) != 0 {
+			return fmt.Errorf("blobs are not supported in %s blocks", versioned.Version)
+		}
+	}
+
+	headers := map[string]string{
+		headerConsensusVersion: versioned.Version.String(),
+	}
+	err = b.c.post(ctx, withQuery("/eth/v2/beacon/blocks", broadcastValidationQuery(validation)), input, nil, headers)
+	return err
+}
+
+// PublishBlindedBlock publishes a signed blinded block with the given broadcast
+// validation. The node retrieves the execution payload from the builder.
+func (b *BeaconEndpoint) PublishBlindedBlock(block *consensus.VersionedSignedBlindedBeaconBlock, validation BroadcastValidation) error {
+	return b.PublishBlindedBlockWithContext(context.Background(), block, validation)
+}
+
+func (b *BeaconEndpoint) PublishBlindedBlockWithContext(ctx context.Context, block *consensus.VersionedSignedBlindedBeaconBlock, validation BroadcastValidation) error {
+	obj := block.Object()
+	if obj == nil {
+		return fmt.Errorf("signed blinded block is empty")
+	}
+	headers := map[string]string{
+		headerConsensusVersion: block.Version.String(),
+	}
+	err := b.c.post(ctx, withQuery("/eth/v2/beacon/blinded_blocks", broadcastValidationQuery(validation)), obj, nil, headers)
 	return err
 }
 
 func (b *BeaconEndpoint) PublishAttestations(data []*consensus.Attestation) error {
-	err := b.c.Post("/eth/v1/beacon/pool/attestations", data, nil)
+	return b.PublishAttestationsWithContext(context.Background(), data)
+}
+
+func (b *BeaconEndpoint) PublishAttestationsWithContext(ctx context.Context, data []*consensus.Attestation) error {
+	err := b.c.PostWithContext(ctx, "/eth/v1/beacon/pool/attestations", data, nil)
+	return err
+}
+
+// PoolAttestationsFilter filters the attestations of the pool. Nil fields do not filter.
+type PoolAttestationsFilter struct {
+	Slot           *uint64
+	CommitteeIndex *uint64
+}
+
+func (b *BeaconEndpoint) GetPoolAttestations(filter *PoolAttestationsFilter) ([]*consensus.Attestation, error) {
+	return b.GetPoolAttestationsWithContext(context.Background(), filter)
+}
+
+func (b *BeaconEndpoint) GetPoolAttestationsWithContext(ctx context.Context, filter *PoolAttestationsFilter) ([]*consensus.Attestation, error) {
+	query := url.Values{}
+	if filter != nil {
+		setUintQuery(query, "slot", filter.Slot)
+		setUintQuery(query, "committee_index", filter.CommitteeIndex)
+	}
+	var out []*consensus.Attestation
+	err := b.c.GetWithContext(ctx, withQuery("/eth/v1/beacon/pool/attestations", query), &out)
+	return out, err
+}
+
+func (b *BeaconEndpoint) GetPoolAttesterSlashings() ([]*consensus.AttesterSlashing, error) {
+	return b.GetPoolAttesterSlashingsWithContext(context.Background())
+}
+
+func (b *BeaconEndpoint) GetPoolAttesterSlashingsWithContext(ctx context.Context) ([]*consensus.AttesterSlashing, error) {
+	var out []*consensus.AttesterSlashing
+	err := b.c.GetWithContext(ctx, "/eth/v1/beacon/pool/attester_slashings", &out)
+	return out, err
+}
+
+func (b *BeaconEndpoint) SubmitAttesterSlashing(slashing *consensus.AttesterSlashing) error {
+	return b.SubmitAttesterSlashingWithContext(context.Background(), slashing)
+}
+
+func (b *BeaconEndpoint) SubmitAttesterSlashingWithContext(ctx context.Context, slashing *consensus.AttesterSlashing) error {
+	err := b.c.PostWithContext(ctx, "/eth/v1/beacon/pool/attester_slashings", slashing, nil)
+	return err
+}
+
+func (b *BeaconEndpoint) GetPoolProposerSlashings() ([]*consensus.ProposerSlashing, error) {
+	return b.GetPoolProposerSlashingsWithContext(context.Background())
+}
+
+func (b *BeaconEndpoint) GetPoolProposerSlashingsWithContext(ctx context.Context) ([]*consensus.ProposerSlashing, error) {
+	var out []*consensus.ProposerSlashing
+	err := b.c.GetWithContext(ctx, "/eth/v1/beacon/pool/proposer_slashings", &out)
+	return out, err
+}
+
+func (b *BeaconEndpoint) SubmitProposerSlashing(slashing *consensus.ProposerSlashing) error {
+	return b.SubmitProposerSlashingWithContext(context.Background(), slashing)
+}
+
+func (b *BeaconEndpoint) SubmitProposerSlashingWithContext(ctx context.Context, slashing *consensus.ProposerSlashing) error {
+	err := b.c.PostWithContext(ctx, "/eth/v1/beacon/pool/proposer_slashings", slashing, nil)
+	return err
+}
+
+func (b *BeaconEndpoint) GetPoolVoluntaryExits() ([]*consensus.SignedVoluntaryExit, error) {
+	return b.GetPoolVoluntaryExitsWithContext(context.Background())
+}
+
+func (b *BeaconEndpoint) GetPoolVoluntaryExitsWithContext(ctx context.Context) ([]*consensus.SignedVoluntaryExit, error) {
+	var out []*consensus.SignedVoluntaryExit
+	err := b.c.GetWithContext(ctx, "/eth/v1/beacon/pool/voluntary_exits", &out)
+	return out, err
+}
+
+func (b *BeaconEndpoint) SubmitVoluntaryExit(exit *consensus.SignedVoluntaryExit) error {
+	return b.SubmitVoluntaryExitWithContext(context.Background(), exit)
+}
+
+func (b *BeaconEndpoint) SubmitVoluntaryExitWithContext(ctx context.Context, exit *consensus.SignedVoluntaryExit) error {
+	err := b.c.PostWithContext(ctx, "/eth/v1/beacon/pool/voluntary_exits", exit, nil)
+	return err
+}
+
+func (b *BeaconEndpoint) GetPoolBLSToExecutionChanges() ([]*consensus.SignedBLSToExecutionChange, error) {
+	return b.GetPoolBLSToExecutionChangesWithContext(context.Background())
+}
+
+func (b *BeaconEndpoint) GetPoolBLSToExecutionChangesWithContext(ctx context.Context) ([]*consensus.SignedBLSToExecutionChange, error) {
+	var out []*consensus.SignedBLSToExecutionChange
+	err := b.c.GetWithContext(ctx, "/eth/v1/beacon/pool/bls_to_execution_changes", &out)
+	return out, err
+}
+
+func (b *BeaconEndpoint) SubmitBLSToExecutionChanges(changes []*consensus.SignedBLSToExecutionChange) error {
+	return b.SubmitBLSToExecutionChangesWithContext(context.Background(), changes)
+}
+
+func (b *BeaconEndpoint) SubmitBLSToExecutionChangesWithContext(ctx context.Context, changes []*consensus.SignedBLSToExecutionChange) error {
+	err := b.c.PostWithContext(ctx, "/eth/v1/beacon/pool/bls_to_execution_changes", changes, nil)
 	return err
 }
 
@@ -146,10 +499,14 @@ type Block struct {
 }
 
 func (b *BeaconEndpoint) GetBlock(id BlockId, block consensus.BeaconBlock) (*Block, error) {
+	return b.GetBlockWithContext(context.Background(), id, block)
+}
+
+func (b *BeaconEndpoint) GetBlockWithContext(ctx context.Context, id BlockId, block consensus.BeaconBlock) (*Block, error) {
 	out := &Block{
 		Message: block,
 	}
-	err := b.c.Get("/eth/v2/beacon/blocks/"+id.BlockID(), out)
+	err := b.c.GetWithContext(ctx, "/eth/v2/beacon/blocks/"+id.BlockID(), out)
 	return out, err
 }
 
@@ -165,21 +522,49 @@ type BlockHeader struct {
 }
 
 func (b *BeaconEndpoint) GetBlockHeader(id BlockId) (*BlockHeaderResponse, error) {
+	return b.GetBlockHeaderWithContext(context.Background(), id)
+}
+
+func (b *BeaconEndpoint) GetBlockHeaderWithContext(ctx context.Context, id BlockId) (*BlockHeaderResponse, error) {
 	var out *BlockHeaderResponse
-	err := b.c.Get("/eth/v1/beacon/headers/"+id.BlockID(), &out)
+	err := b.c.GetWithContext(ctx, "/eth/v1/beacon/headers/"+id.BlockID(), &out)
 	return out, err
 }
 
 func (b *BeaconEndpoint) GetBlockRoot(id BlockId) ([32]byte, error) {
+	return b.GetBlockRootWithContext(context.Background(), id)
+}
+
+func (b *BeaconEndpoint) GetBlockRootWithContext(ctx context.Context, id BlockId) ([32]byte, error) {
 	var data struct {
 		Root [32]byte
 	}
-	err := b.c.Get("/eth/v1/beacon/blocks/"+id.BlockID()+"/root", &data)
+	err := b.c.GetWithContext(ctx, "/eth/v1/beacon/blocks/"+id.BlockID()+"/root", &data)
 	return data.Root, err
 }
 
 func (b *BeaconEndpoint) GetBlockAttestations(id BlockId) ([]*consensus.Attestation, error) {
+	return b.GetBlockAttestationsWithContext(context.Background(), id)
+}
+
+func (b *BeaconEndpoint) GetBlockAttestationsWithContext(ctx context.Context, id BlockId) ([]*consensus.Attestation, error) {
 	var out []*consensus.Attestation
-	err := b.c.Get("/eth/v1/beacon/blocks/"+id.BlockID()+"/attestations", &out)
+	err := b.c.GetWithContext(ctx, "/eth/v1/beacon/blocks/"+id.BlockID()+"/attestations", &out)
+	return out, err
+}
+
+// GetBlobSidecars returns the blob sidecars of the block. Only the sidecars
+// of the given indices are returned if there are any.
+func (b *BeaconEndpoint) GetBlobSidecars(id BlockId, indices []uint64) ([]*consensus.BlobSidecar, error) {
+	return b.GetBlobSidecarsWithContext(context.Background(), id, indices)
+}
+
+func (b *BeaconEndpoint) GetBlobSidecarsWithContext(ctx context.Context, id BlockId, indices []uint64) ([]*consensus.BlobSidecar, error) {
+	query := url.Values{}
+	for _, indx := range indices {
+		query.Add("indices", strconv.FormatUint(indx, 10))
+	}
+	var out []*consensus.BlobSidecar
+	err := b.c.GetWithContext(ctx, withQuery("/eth/v1/beacon/blob_sidecars/"+id.BlockID(), query), &out)
 	return out, err
 }
@@ -1,9 +1,12 @@
 package http
 
 import (
+	"io"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	consensus "github.com/umbracle/go-eth-consensus"
 )
 
@@ -73,3 +76,194 @@ func TestBeaconEndpoint(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestBeaconEndpoint_StateQueries(t *testing.T) {
+	handler := func(m *http.ServeMux) {
+		m.HandleFunc("/eth/v1/beacon/states/head/committees", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "epoch=2&slot=65", r.URL.RawQuery)
+			w.Write([]byte(`{"data": [{"index": "1", "slot": "65", "validators": ["1", "2"]}]}`))
+		})
+		m.HandleFunc("/eth/v1/beacon/states/head/sync_committees", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "", r.URL.RawQuery)
+			w.Write([]byte(`{"data": {"validators": ["1", "2"], "validator_aggregates": [["1"], ["2"]]}}`))
+		})
+		m.HandleFunc("/eth/v1/beacon/states/finalized/randao", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "epoch=3", r.URL.RawQuery)
+			w.Write([]byte(`{"data": {"randao": "0x0100000000000000000000000000000000000000000000000000000000000000"}}`))
+		})
+		m.HandleFunc("/eth/v1/beacon/states/head/validator_balances", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "1,0x01", r.URL.Query().Get("id"))
+			w.Write([]byte(`{"data": [{"index": "1", "balance": "32000000000"}]}`))
+		})
+		m.HandleFunc("/eth/v1/beacon/states/head/validators", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+
+			data, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			var filter ValidatorsFilter
+			require.NoError(t, Unmarshal(data, &filter, false))
+			require.Equal(t, ValidatorsFilter{Statuses: []ValidatorStatus{ValidatorStatusActiveOngoing}}, filter)
+
+			w.Write([]byte(`{"data": [{"index": "1", "balance": "1", "status": "active_ongoing", "validator": {}}]}`))
+		})
+	}
+
+	addr := newMockHttpServer(t, handler)
+	n := New("http://" + addr).Beacon()
+
+	epoch, slot := uint64(2), uint64(65)
+	committees, err := n.GetCommittees(Head, &CommitteeFilter{Epoch: &epoch, Slot: &slot})
+	require.NoError(t, err)
+	require.Equal(t, []*Committee{{Index: 1, Slot: 65, Validators: []uint64{1, 2}}}, committees)
+
+	syncCommittees, err := n.GetSyncCommittees(Head, nil)
+	require.NoError(t, err)
+	require.Equal(t, &SyncCommittees{Validators: []uint64{1, 2}, ValidatorAggregates: [][]uint64{{1}, {2}}}, syncCommittees)
+
+	epoch = 3
+	randao, err := n.GetRandao(Finalized, &epoch)
+	require.NoError(t, err)
+	require.Equal(t, [32]byte{0x1}, randao)
+
+	balances, err := n.GetValidatorBalances(Head, []string{"1", "0x01"})
+	require.NoError(t, err)
+	require.Equal(t, []*ValidatorBalance{{Index: 1, Balance: 32000000000}}, balances)
+
+	validators, err := n.FilterValidators(Head, &ValidatorsFilter{Statuses: []ValidatorStatus{ValidatorStatusActiveOngoing}})
+	require.NoError(t, err)
+	require.Len(t, validators, 1)
+	require.Equal(t, ValidatorStatusActive, validators[0].Status.Group())
+}
+
+func TestBeaconEndpoint_Pool(t *testing.T) {
+	exit := &consensus.SignedVoluntaryExit{
+		Exit: &consensus.VoluntaryExit{Epoch: 1, ValidatorIndex: 2},
+	}
+	change := &consensus.SignedBLSToExecutionChange{
+		Message: &consensus.BLSToExecutionChange{ValidatorIndex: 3},
+	}
+
+	handler := func(m *http.ServeMux) {
+		m.HandleFunc("/eth/v1/beacon/pool/attestations", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "committee_index=2&slot=1", r.URL.RawQuery)
+			w.Write([]byte(`{"data": []}`))
+		})
+		m.HandleFunc("/eth/v1/beacon/pool/voluntary_exits", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				data, err := Marshal([]*consensus.SignedVoluntaryExit{exit})
+				require.NoError(t, err)
+				w.Write([]byte(`{"data": ` + string(data) + `}`))
+				return
+			}
+
+			data, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			var obj consensus.SignedVoluntaryExit
+			require.NoError(t, Unmarshal(data, &obj, false))
+			require.Equal(t, exit, &obj)
+		})
+		m.HandleFunc("/eth/v1/beacon/pool/bls_to_execution_changes", func(w http.ResponseWriter, r *http.Request) {
+			data, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			var obj []*consensus.SignedBLSToExecutionChange
+			require.NoError(t, Unmarshal(data, &obj, false))
+			require.Equal(t, []*consensus.SignedBLSToExecutionChange{change}, obj)
+		})
+	}
+
+	addr := newMockHttpServer(t, handler)
+	n := New("http://" + addr).Beacon()
+
+	slot, index := uint64(1), uint64(2)
+	attestations, err := n.GetPoolAttestations(&PoolAttestationsFilter{Slot: &slot, CommitteeIndex: &index})
+	require.NoError(t, err)
+	require.Empty(t, attestations)
+
+	require.NoError(t, n.SubmitVoluntaryExit(exit))
+
+	exits, err := n.GetPoolVoluntaryExits()
+	require.NoError(t, err)
+	require.Equal(t, []*consensus.SignedVoluntaryExit{exit}, exits)
+
+	require.NoError(t, n.SubmitBLSToExecutionChanges([]*consensus.SignedBLSToExecutionChange{change}))
+}
+
+func TestBeaconEndpoint_BlobSidecars(t *testing.T) {
+	sidecar := &consensus.BlobSidecar{
+		Index:         1,
+		KZGCommitment: [48]byte{0x1},
+		KZGProof:      [48]byte{0x2},
+		SignedBlockHeader: &consensus.SignedBeaconBlockHeader{
+			Header: &consensus.BeaconBlockHeader{Slot: 10},
+		},
+	}
+	sidecar.Blob[0] = 0x3
+	sidecar.KZGCommitmentInclusionProof[16] = [32]byte{0x4}
+
+	handler := func(m *http.ServeMux) {
+		m.HandleFunc("/eth/v1/beacon/blob_sidecars/head", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, []string{"1", "2"}, r.URL.Query()["indices"])
+
+			data, err := Marshal([]*consensus.BlobSidecar{sidecar})
+			require.NoError(t, err)
+			w.Write([]byte(`{"data": ` + string(data) + `}`))
+		})
+	}
+
+	addr := newMockHttpServer(t, handler)
+	n := New("http://" + addr).Beacon()
+
+	sidecars, err := n.GetBlobSidecars(Head, []uint64{1, 2})
+	require.NoError(t, err)
+	require.Equal(t, []*consensus.BlobSidecar{sidecar}, sidecars)
+}
+
+func TestBeaconEndpoint_PublishBlocks(t *testing.T) {
+	signedBlock := &consensus.SignedBeaconBlockDeneb{
+		Block: &consensus.BeaconBlockDeneb{Slot: 1},
+	}
+	blindedBlock, err := consensus.NewVersionedBlindedBeaconBlock(consensus.VersionCapella)
+	require.NoError(t, err)
+	signedBlinded := blindedBlock.WithSignature(consensus.Signature{0x1})
+
+	handler := func(m *http.ServeMux) {
+		m.HandleFunc("/eth/v2/beacon/blocks", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "consensus", r.URL.Query().Get("broadcast_validation"))
+			require.Equal(t, "deneb", r.Header.Get(headerConsensusVersion))
+
+			data, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			var contents consensus.SignedBlockContentsDeneb
+			require.NoError(t, contents.UnmarshalSSZ(data))
+			require.Equal(t, uint64(1), contents.SignedBlock.Block.Slot)
+			require.Len(t, contents.Blobs, 1)
+		})
+		m.HandleFunc("/eth/v2/beacon/blinded_blocks", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "", r.URL.RawQuery)
+			require.Equal(t, "capella", r.Header.Get(headerConsensusVersion))
+			require.Equal(t, contentTypeJSON, r.Header.Get("Content-Type"))
+
+			data, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			var block consensus.SignedBlindedBeaconBlockCapella
+			require.NoError(t, Unmarshal(data, &block, false))
+			require.Equal(t, consensus.Signature{0x1}, block.Signature)
+		})
+	}
+
+	addr := newMockHttpServer(t, handler)
+
+	err = New("http://"+addr, WithSSZ()).Beacon().PublishSignedBlockContents(signedBlock, [][48]byte{{0x1}}, [][131072]byte{{0x2}}, BroadcastValidationConsensus)
+	require.NoError(t, err)
+
+	err = New("http://"+addr).Beacon().PublishBlindedBlock(signedBlinded, "")
+	require.NoError(t, err)
+
+	err = New("http://"+addr).Beacon().PublishSignedBlockContents(&consensus.SignedBeaconBlockCapella{}, nil, [][131072]byte{{0x2}}, "")
+	require.Error(t, err)
+}
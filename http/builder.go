@@ -1,8 +1,10 @@
 package http
 
 import (
+	"context"
 	"fmt"
 
+	ssz "github.com/ferranbt/fastssz"
 	consensus "github.com/umbracle/go-eth-consensus"
 )
 
@@ -14,8 +16,14 @@ func (c *Client) Builder() *BuilderEndpoint {
 	return &BuilderEndpoint{c: c}
 }
 
+// RegisterValidator sends the registrations of the validators to the relay.
+// The registrations are signed with SignValidatorRegistration.
 func (b *BuilderEndpoint) RegisterValidator(msg []*SignedValidatorRegistration) error {
-	err := b.c.Post("/eth/v1/builder/validators", msg, nil)
+	return b.RegisterValidatorWithContext(context.Background(), msg)
+}
+
+func (b *BuilderEndpoint) RegisterValidatorWithContext(ctx context.Context, msg []*SignedValidatorRegistration) error {
+	err := b.c.PostWithContext(ctx, "/eth/v1/builder/validators", msg, nil)
 	return err
 }
 
@@ -30,18 +38,414 @@ type SignedBuilderBid struct {
 	Signature [96]byte    `json:"signature" ssz-size:"96"`
 }
 
+type BuilderBidCapella struct {
+	Header *consensus.ExecutionPayloadHeaderCapella `json:"header"`
+	Value  consensus.Uint256                        `json:"value" ssz-size:"32"`
+	Pubkey [48]byte                                 `json:"pubkey" ssz-size:"48"`
+}
+
+type SignedBuilderBidCapella struct {
+	Message   *BuilderBidCapella `json:"message"`
+	Signature [96]byte           `json:"signature" ssz-size:"96"`
+}
+
+type BuilderBidDeneb struct {
+	Header             *consensus.ExecutionPayloadHeaderDeneb `json:"header"`
+	BlobKZGCommitments [][48]byte                             `json:"blob_kzg_commitments" ssz-max:"4096"`
+	Value              consensus.Uint256                      `json:"value" ssz-size:"32"`
+	Pubkey             [48]byte                               `json:"pubkey" ssz-size:"48"`
+}
+
+type SignedBuilderBidDeneb struct {
+	Message   *BuilderBidDeneb `json:"message"`
+	Signature [96]byte         `json:"signature" ssz-size:"96"`
+}
+
+type BuilderBidElectra struct {
+	Header             *consensus.ExecutionPayloadHeaderDeneb `json:"header"`
+	BlobKZGCommitments [][48]byte                             `json:"blob_kzg_commitments" ssz-max:"4096"`
+	ExecutionRequests  *consensus.ExecutionRequests           `json:"execution_requests"`
+	Value              consensus.Uint256                      `json:"value" ssz-size:"32"`
+	Pubkey             [48]byte                               `json:"pubkey" ssz-size:"48"`
+}
+
+type SignedBuilderBidElectra struct {
+	Message   *BuilderBidElectra `json:"message"`
+	Signature [96]byte           `json:"signature" ssz-size:"96"`
+}
+
+// BlobsBundle are the blobs of an execution payload with their commitments and proofs
+type BlobsBundle struct {
+	Commitments [][48]byte     `json:"commitments" ssz-max:"4096"`
+	Proofs      [][48]byte     `json:"proofs" ssz-max:"4096"`
+	Blobs       [][131072]byte `json:"blobs" ssz-max:"4096"`
+}
+
+// ExecutionPayloadAndBlobsBundle is the unblinded payload returned by the relay since Deneb
+type ExecutionPayloadAndBlobsBundle struct {
+	ExecutionPayload *consensus.ExecutionPayloadDeneb `json:"execution_payload"`
+	BlobsBundle      *BlobsBundle                     `json:"blobs_bundle"`
+}
+
+// GetExecutionPayload returns the Bellatrix bid of the relay.
+//
+// Deprecated: use GetHeader which decodes the bid of any fork.
 func (b *BuilderEndpoint) GetExecutionPayload(slot uint64, parentHash [32]byte, pubKey [48]byte) (*SignedBuilderBid, error) {
+	return b.GetExecutionPayloadWithContext(context.Background(), slot, parentHash, pubKey)
+}
+
+func (b *BuilderEndpoint) GetExecutionPayloadWithContext(ctx context.Context, slot uint64, parentHash [32]byte, pubKey [48]byte) (*SignedBuilderBid, error) {
 	var out *SignedBuilderBid
-	err := b.c.Get(fmt.Sprintf("/eth/v1/builder/header/%d/0x%x/0x%x", slot, parentHash[:], pubKey[:]), &out)
+	err := b.c.GetWithContext(ctx, headerPath(slot, parentHash, pubKey), &out)
 	return out, err
 }
 
+func headerPath(slot uint64, parentHash [32]byte, pubKey [48]byte) string {
+	return fmt.Sprintf("/eth/v1/builder/header/%d/0x%x/0x%x", slot, parentHash[:], pubKey[:])
+}
+
+// GetHeader returns the bid of the relay for the slot. The fork of the bid is
+// resolved with the version of the response. If the relay has no bid it returns ErrorNoContent.
+func (b *BuilderEndpoint) GetHeader(slot uint64, parentHash [32]byte, pubKey [48]byte) (*VersionedSignedBuilderBid, error) {
+	return b.GetHeaderWithContext(context.Background(), slot, parentHash, pubKey)
+}
+
+func (b *BuilderEndpoint) GetHeaderWithContext(ctx context.Context, slot uint64, parentHash [32]byte, pubKey [48]byte) (*VersionedSignedBuilderBid, error) {
+	var bid *VersionedSignedBuilderBid
+	newObj := func(version consensus.Version) (ssz.Unmarshaler, error) {
+		var err error
+		if bid, err = NewVersionedSignedBuilderBid(version); err != nil {
+			return nil, err
+		}
+		return bid.sszObject()
+	}
+	if _, _, err := b.c.getVersioned(ctx, headerPath(slot, parentHash, pubKey), newObj); err != nil {
+		return nil, err
+	}
+	return bid, nil
+}
+
+// SubmitBlindedBlock sends the Bellatrix blinded block to the relay.
+//
+// Deprecated: use SubmitVersionedBlindedBlock which supports the blocks of any fork.
 func (b *BuilderEndpoint) SubmitBlindedBlock(msg *consensus.SignedBlindedBeaconBlock) (*consensus.ExecutionPayload, error) {
+	return b.SubmitBlindedBlockWithContext(context.Background(), msg)
+}
+
+func (b *BuilderEndpoint) SubmitBlindedBlockWithContext(ctx context.Context, msg *consensus.SignedBlindedBeaconBlock) (*consensus.ExecutionPayload, error) {
 	var out *consensus.ExecutionPayload
-	err := b.c.Post("/eth/v1/builder/blinded_blocks", msg, &out)
+	err := b.c.PostWithContext(ctx, "/eth/v1/builder/blinded_blocks", msg, &out)
 	return out, err
 }
 
+// SubmitVersionedBlindedBlock sends the signed blinded block to the relay and returns
+// the unblinded payload. The fork of the payload is resolved with the version of the response.
+func (b *BuilderEndpoint) SubmitVersionedBlindedBlock(block *consensus.VersionedSignedBlindedBeaconBlock) (*VersionedUnblindedPayload, error) {
+	return b.SubmitVersionedBlindedBlockWithContext(context.Background(), block)
+}
+
+func (b *BuilderEndpoint) SubmitVersionedBlindedBlockWithContext(ctx context.Context, block *consensus.VersionedSignedBlindedBeaconBlock) (*VersionedUnblindedPayload, error) {
+	obj := block.Object()
+	if obj == nil {
+		return nil, fmt.Errorf("signed blinded block is empty")
+	}
+	headers := map[string]string{
+		headerConsensusVersion: block.Version.String(),
+	}
+
+	var payload *VersionedUnblindedPayload
+	newObj := func(version consensus.Version) (ssz.Unmarshaler, error) {
+		var err error
+		if payload, err = NewVersionedUnblindedPayload(version); err != nil {
+			return nil, err
+		}
+		return payload.sszObject()
+	}
+	if _, _, err := b.c.postVersioned(ctx, "/eth/v1/builder/blinded_blocks", obj, headers, newObj); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
 func (b *BuilderEndpoint) Status() (bool, error) {
-	return b.c.Status("/eth/v1/builder/status")
+	return b.StatusWithContext(context.Background())
+}
+
+func (b *BuilderEndpoint) StatusWithContext(ctx context.Context) (bool, error) {
+	return b.c.StatusWithContext(ctx, "/eth/v1/builder/status")
+}
+
+type sszObject interface {
+	ssz.Marshaler
+	ssz.Unmarshaler
+	ssz.HashRoot
+}
+
+// VersionedSignedBuilderBid is a signed builder bid of any fork. Only the field of
+// the fork in Version is set.
+type VersionedSignedBuilderBid struct {
+	Version   consensus.Version
+	Bellatrix *SignedBuilderBid
+	Capella   *SignedBuilderBidCapella
+	Deneb     *SignedBuilderBidDeneb
+	Electra   *SignedBuilderBidElectra
+}
+
+// NewVersionedSignedBuilderBid returns an empty signed builder bid of the given fork
+func NewVersionedSignedBuilderBid(version consensus.Version) (*VersionedSignedBuilderBid, error) {
+	v := &VersionedSignedBuilderBid{Version: version}
+	switch version {
+	case consensus.VersionBellatrix:
+		v.Bellatrix = &SignedBuilderBid{}
+	case consensus.VersionCapella:
+		v.Capella = &SignedBuilderBidCapella{}
+	case consensus.VersionDeneb:
+		v.Deneb = &SignedBuilderBidDeneb{}
+	case consensus.VersionElectra:
+		v.Electra = &SignedBuilderBidElectra{}
+	default:
+		return nil, fmt.Errorf("builder bid of version %s not supported", version)
+	}
+	return v, nil
+}
+
+// Object returns the signed builder bid of the fork
+func (v *VersionedSignedBuilderBid) Object() interface{} {
+	switch {
+	case v.Bellatrix != nil:
+		return v.Bellatrix
+	case v.Capella != nil:
+		return v.Capella
+	case v.Deneb != nil:
+		return v.Deneb
+	case v.Electra != nil:
+		return v.Electra
+	}
+	return nil
+}
+
+func (v *VersionedSignedBuilderBid) sszObject() (sszObject, error) {
+	obj, ok := v.Object().(sszObject)
+	if !ok {
+		return nil, fmt.Errorf("builder bid is empty")
+	}
+	return obj, nil
+}
+
+// MarshalSSZ encodes the signed builder bid
+func (v *VersionedSignedBuilderBid) MarshalSSZ() ([]byte, error) {
+	obj, err := v.sszObject()
+	if err != nil {
+		return nil, err
+	}
+	return obj.MarshalSSZ()
+}
+
+// UnmarshalSSZ decodes the signed builder bid of the fork of the version
+func (v *VersionedSignedBuilderBid) UnmarshalSSZ(buf []byte) error {
+	if v.Object() == nil {
+		obj, err := NewVersionedSignedBuilderBid(v.Version)
+		if err != nil {
+			return err
+		}
+		*v = *obj
+	}
+	obj, err := v.sszObject()
+	if err != nil {
+		return err
+	}
+	return obj.UnmarshalSSZ(buf)
+}
+
+// Message returns the unsigned builder bid
+func (v *VersionedSignedBuilderBid) Message() (ssz.HashRoot, error) {
+	switch {
+	case v.Bellatrix != nil && v.Bellatrix.Message != nil:
+		return v.Bellatrix.Message, nil
+	case v.Capella != nil && v.Capella.Message != nil:
+		return v.Capella.Message, nil
+	case v.Deneb != nil && v.Deneb.Message != nil:
+		return v.Deneb.Message, nil
+	case v.Electra != nil && v.Electra.Message != nil:
+		return v.Electra.Message, nil
+	}
+	return nil, fmt.Errorf("builder bid is empty")
+}
+
+// Signature returns the signature of the builder over the bid
+func (v *VersionedSignedBuilderBid) Signature() [96]byte {
+	switch {
+	case v.Bellatrix != nil:
+		return v.Bellatrix.Signature
+	case v.Capella != nil:
+		return v.Capella.Signature
+	case v.Deneb != nil:
+		return v.Deneb.Signature
+	case v.Electra != nil:
+		return v.Electra.Signature
+	}
+	return [96]byte{}
+}
+
+// Value returns the value of the bid in wei
+func (v *VersionedSignedBuilderBid) Value() consensus.Uint256 {
+	switch {
+	case v.Bellatrix != nil && v.Bellatrix.Message != nil:
+		return v.Bellatrix.Message.Value
+	case v.Capella != nil && v.Capella.Message != nil:
+		return v.Capella.Message.Value
+	case v.Deneb != nil && v.Deneb.Message != nil:
+		return v.Deneb.Message.Value
+	case v.Electra != nil && v.Electra.Message != nil:
+		return v.Electra.Message.Value
+	}
+	return consensus.Uint256{}
+}
+
+// Pubkey returns the public key of the builder
+func (v *VersionedSignedBuilderBid) Pubkey() [48]byte {
+	switch {
+	case v.Bellatrix != nil && v.Bellatrix.Message != nil:
+		return v.Bellatrix.Message.Pubkey
+	case v.Capella != nil && v.Capella.Message != nil:
+		return v.Capella.Message.Pubkey
+	case v.Deneb != nil && v.Deneb.Message != nil:
+		return v.Deneb.Message.Pubkey
+	case v.Electra != nil && v.Electra.Message != nil:
+		return v.Electra.Message.Pubkey
+	}
+	return [48]byte{}
+}
+
+// BlockHash returns the hash of the execution payload of the bid
+func (v *VersionedSignedBuilderBid) BlockHash() [32]byte {
+	switch {
+	case v.Bellatrix != nil && v.Bellatrix.Message != nil && v.Bellatrix.Message.Header != nil:
+		return v.Bellatrix.Message.Header.BlockHash
+	case v.Capella != nil && v.Capella.Message != nil && v.Capella.Message.Header != nil:
+		return v.Capella.Message.Header.BlockHash
+	case v.Deneb != nil && v.Deneb.Message != nil && v.Deneb.Message.Header != nil:
+		return v.Deneb.Message.Header.BlockHash
+	case v.Electra != nil && v.Electra.Message != nil && v.Electra.Message.Header != nil:
+		return v.Electra.Message.Header.BlockHash
+	}
+	return [32]byte{}
+}
+
+// ParentHash returns the hash of the parent of the execution payload of the bid
+func (v *VersionedSignedBuilderBid) ParentHash() [32]byte {
+	switch {
+	case v.Bellatrix != nil && v.Bellatrix.Message != nil && v.Bellatrix.Message.Header != nil:
+		return v.Bellatrix.Message.Header.ParentHash
+	case v.Capella != nil && v.Capella.Message != nil && v.Capella.Message.Header != nil:
+		return v.Capella.Message.Header.ParentHash
+	case v.Deneb != nil && v.Deneb.Message != nil && v.Deneb.Message.Header != nil:
+		return v.Deneb.Message.Header.ParentHash
+	case v.Electra != nil && v.Electra.Message != nil && v.Electra.Message.Header != nil:
+		return v.Electra.Message.Header.ParentHash
+	}
+	return [32]byte{}
+}
+
+// VersionedUnblindedPayload is the payload returned by the relay for a blinded block
+// of any fork. Only the field of the fork in Version is set. Since Deneb the payload
+// includes the blobs bundle.
+type VersionedUnblindedPayload struct {
+	Version   consensus.Version
+	Bellatrix *consensus.ExecutionPayload
+	Capella   *consensus.ExecutionPayloadCapella
+	Deneb     *ExecutionPayloadAndBlobsBundle
+	Electra   *ExecutionPayloadAndBlobsBundle
+}
+
+// NewVersionedUnblindedPayload returns an empty unblinded payload of the given fork
+func NewVersionedUnblindedPayload(version consensus.Version) (*VersionedUnblindedPayload, error) {
+	v := &VersionedUnblindedPayload{Version: version}
+	switch version {
+	case consensus.VersionBellatrix:
+		v.Bellatrix = &consensus.ExecutionPayload{}
+	case consensus.VersionCapella:
+		v.Capella = &consensus.ExecutionPayloadCapella{}
+	case consensus.VersionDeneb:
+		v.Deneb = &ExecutionPayloadAndBlobsBundle{}
+	case consensus.VersionElectra:
+		v.Electra = &ExecutionPayloadAndBlobsBundle{}
+	default:
+		return nil, fmt.Errorf("unblinded payload of version %s not supported", version)
+	}
+	return v, nil
+}
+
+// Object returns the unblinded payload of the fork
+func (v *VersionedUnblindedPayload) Object() interface{} {
+	switch {
+	case v.Bellatrix != nil:
+		return v.Bellatrix
+	case v.Capella != nil:
+		return v.Capella
+	case v.Deneb != nil:
+		return v.Deneb
+	case v.Electra != nil:
+		return v.Electra
+	}
+	return nil
+}
+
+func (v *VersionedUnblindedPayload) sszObject() (sszObject, error) {
+	obj, ok := v.Object().(sszObject)
+	if !ok {
+		return nil, fmt.Errorf("unblinded payload is empty")
+	}
+	return obj, nil
+}
+
+// MarshalSSZ encodes the unblinded payload
+func (v *VersionedUnblindedPayload) MarshalSSZ() ([]byte, error) {
+	obj, err := v.sszObject()
+	if err != nil {
+		return nil, err
+	}
+	return obj.MarshalSSZ()
+}
+
+// UnmarshalSSZ decodes the unblinded payload of the fork of the version
+func (v *VersionedUnblindedPayload) UnmarshalSSZ(buf []byte) error {
+	if v.Object() == nil {
+		obj, err := NewVersionedUnblindedPayload(v.Version)
+		if err != nil {
+			return err
+		}
+		*v = *obj
+	}
+	obj, err := v.sszObject()
+	if err != nil {
+		return err
+	}
+	return obj.UnmarshalSSZ(buf)
+}
+
+// BlockHash returns the hash of the execution payload
+func (v *VersionedUnblindedPayload) BlockHash() [32]byte {
+	switch {
+	case v.Bellatrix != nil:
+		return v.Bellatrix.BlockHash
+	case v.Capella != nil:
+		return v.Capella.BlockHash
+	case v.Deneb != nil && v.Deneb.ExecutionPayload != nil:
+		return v.Deneb.ExecutionPayload.BlockHash
+	case v.Electra != nil && v.Electra.ExecutionPayload != nil:
+		return v.Electra.ExecutionPayload.BlockHash
+	}
+	return [32]byte{}
+}
+
+// BlobsBundle returns the blobs of the payload. It is nil before Deneb.
+func (v *VersionedUnblindedPayload) BlobsBundle() *BlobsBundle {
+	switch {
+	case v.Deneb != nil:
+		return v.Deneb.BlobsBundle
+	case v.Electra != nil:
+		return v.Electra.BlobsBundle
+	}
+	return nil
 }
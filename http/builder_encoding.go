@@ -1,5 +1,5 @@
 // Code generated by fastssz. DO NOT EDIT.
-// Hash: 60c1a274a556ac9f9ed1cc9e50fdb5cf4e6c97d297106e385648c8306d4cb095
+// Hash: 6dcbf0feff0e94a15cfb1caa7443c821ed4b1ed04e3ad82bb3dbda0a01d70d56
 // Version: 0.1.3
 package http
 
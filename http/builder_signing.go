@@ -0,0 +1,81 @@
+package http
+
+import (
+	"fmt"
+
+	ssz "github.com/ferranbt/fastssz"
+	consensus "github.com/umbracle/go-eth-consensus"
+	"github.com/umbracle/go-eth-consensus/bls"
+)
+
+// builderDomain returns the domain of the builder api. It uses the genesis fork
+// version and an empty genesis validators root since it does not depend on the fork.
+func builderDomain(spec *consensus.Spec) ([32]byte, error) {
+	return consensus.ComputeDomain(consensus.DomainApplicationBuilder, spec.GenesisForkVersion, consensus.Root{})
+}
+
+func signBuilderMessage(spec *consensus.Spec, key *bls.Key, msg ssz.HashRoot) ([96]byte, error) {
+	domain, err := builderDomain(spec)
+	if err != nil {
+		return [96]byte{}, err
+	}
+	root, err := consensus.ComputeSigningRoot(domain, msg)
+	if err != nil {
+		return [96]byte{}, err
+	}
+	return key.Sign(root)
+}
+
+func verifyBuilderSignature(spec *consensus.Spec, msg ssz.HashRoot, pubkey [48]byte, signature [96]byte) error {
+	domain, err := builderDomain(spec)
+	if err != nil {
+		return err
+	}
+	root, err := consensus.ComputeSigningRoot(domain, msg)
+	if err != nil {
+		return err
+	}
+
+	pub := &bls.PublicKey{}
+	if err := pub.Deserialize(pubkey[:]); err != nil {
+		return err
+	}
+	sig := &bls.Signature{}
+	if err := sig.Deserialize(signature[:]); err != nil {
+		return err
+	}
+
+	ok, err := sig.VerifyByte(pub, root[:])
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("bad signature")
+	}
+	return nil
+}
+
+// SignValidatorRegistration returns the registration of the validator signed with
+// the builder domain. It is sent to the relays with the RegisterValidator
+// endpoint of the beacon node or the builder.
+func SignValidatorRegistration(key *bls.Key, feeRecipient [20]byte, gasLimit uint64, timestamp uint64, spec *consensus.Spec) (*SignedValidatorRegistration, error) {
+	msg := &RegisterValidatorRequest{
+		FeeRecipient: feeRecipient,
+		GasLimit:     gasLimit,
+		Timestamp:    timestamp,
+		Pubkey:       key.PubKey(),
+	}
+	signature, err := signBuilderMessage(spec, key, msg)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedValidatorRegistration{Message: msg, Signature: signature}, nil
+}
+
+// VerifyValidatorRegistration checks that the registration is signed by the validator
+func VerifyValidatorRegistration(reg *SignedValidatorRegistration, spec *consensus.Spec) error {
+	if reg.Message == nil {
+		return fmt.Errorf("registration is empty")
+	}
+	return verifyBuilderSignature(spec, reg.Message, reg.Message.Pubkey, reg.Signature)
+}
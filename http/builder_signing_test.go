@@ -0,0 +1,54 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	consensus "github.com/umbracle/go-eth-consensus"
+	"github.com/umbracle/go-eth-consensus/bls"
+)
+
+func TestSignValidatorRegistration(t *testing.T) {
+	key := bls.NewRandomKey()
+
+	reg, err := SignValidatorRegistration(key, [20]byte{0x1}, 30000000, 1700000000, relayTestSpec)
+	require.NoError(t, err)
+	require.Equal(t, key.PubKey(), reg.Message.Pubkey)
+	require.NoError(t, VerifyValidatorRegistration(reg, relayTestSpec))
+
+	// the registration is signed for another network
+	spec := *relayTestSpec
+	spec.GenesisForkVersion = consensus.Domain{0x2}
+	require.Error(t, VerifyValidatorRegistration(reg, &spec))
+
+	// the signature does not cover the modified gas limit
+	reg.Message.GasLimit++
+	require.Error(t, VerifyValidatorRegistration(reg, relayTestSpec))
+}
+
+func TestSignValidatorRegistration_Endpoints(t *testing.T) {
+	handler := func(m *http.ServeMux) {
+		verify := func(w http.ResponseWriter, r *http.Request) {
+			data, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			var regs []*SignedValidatorRegistration
+			require.NoError(t, Unmarshal(data, &regs, false))
+			require.Len(t, regs, 1)
+			require.NoError(t, VerifyValidatorRegistration(regs[0], relayTestSpec))
+		}
+		m.HandleFunc("/eth/v1/builder/validators", verify)
+		m.HandleFunc("/eth/v1/validator/register_validator", verify)
+	}
+
+	addr := newMockHttpServer(t, handler)
+	clt := New("http://" + addr)
+
+	reg, err := SignValidatorRegistration(bls.NewRandomKey(), [20]byte{0x1}, 30000000, 1700000000, relayTestSpec)
+	require.NoError(t, err)
+
+	require.NoError(t, clt.Builder().RegisterValidator([]*SignedValidatorRegistration{reg}))
+	require.NoError(t, clt.Validator().RegisterValidator([]*SignedValidatorRegistration{reg}))
+}
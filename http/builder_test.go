@@ -1,9 +1,15 @@
 package http
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	consensus "github.com/umbracle/go-eth-consensus"
 )
 
@@ -35,3 +41,127 @@ func TestBuilderEndpoint(t *testing.T) {
 		assert.True(t, ok)
 	})
 }
+
+func TestBuilderEndpoint_Versioned(t *testing.T) {
+	var value consensus.Uint256
+	require.NoError(t, value.UnmarshalText([]byte("1000")))
+
+	denebBid := &SignedBuilderBidDeneb{
+		Message: &BuilderBidDeneb{
+			Header:             &consensus.ExecutionPayloadHeaderDeneb{BlockHash: [32]byte{0x1}, ExtraData: []byte{0xa}},
+			BlobKZGCommitments: [][48]byte{{0x2}},
+			Value:              value,
+			Pubkey:             [48]byte{0x3},
+		},
+		Signature: [96]byte{0x4},
+	}
+	capellaBid := &SignedBuilderBidCapella{
+		Message: &BuilderBidCapella{
+			Header: &consensus.ExecutionPayloadHeaderCapella{BlockHash: [32]byte{0x5}},
+			Value:  value,
+		},
+	}
+	electraPayload := &ExecutionPayloadAndBlobsBundle{
+		ExecutionPayload: &consensus.ExecutionPayloadDeneb{BlockHash: [32]byte{0x6}},
+		BlobsBundle: &BlobsBundle{
+			Commitments: [][48]byte{{0x7}},
+			Proofs:      [][48]byte{{0x8}},
+			Blobs:       [][131072]byte{{0x9}},
+		},
+	}
+
+	handler := func(m *http.ServeMux) {
+		m.HandleFunc("/eth/v1/builder/header/1/", func(w http.ResponseWriter, r *http.Request) {
+			data, err := Marshal(denebBid)
+			require.NoError(t, err)
+
+			w.Header().Set("Content-Type", contentTypeJSON)
+			fmt.Fprintf(w, `{"version": "deneb", "data": %s}`, data)
+		})
+		m.HandleFunc("/eth/v1/builder/header/2/", func(w http.ResponseWriter, r *http.Request) {
+			data, err := capellaBid.MarshalSSZ()
+			require.NoError(t, err)
+
+			w.Header().Set("Content-Type", contentTypeSSZ)
+			w.Header().Set(headerConsensusVersion, "capella")
+			w.Write(data)
+		})
+		m.HandleFunc("/eth/v1/builder/header/3/", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+		m.HandleFunc("/eth/v1/builder/blinded_blocks", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "electra", r.Header.Get(headerConsensusVersion))
+
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			var block consensus.SignedBlindedBeaconBlockElectra
+			require.NoError(t, Unmarshal(body, &block, false))
+			require.Equal(t, uint64(10), block.Block.Slot)
+
+			data, err := Marshal(electraPayload)
+			require.NoError(t, err)
+
+			w.Header().Set("Content-Type", contentTypeJSON)
+			fmt.Fprintf(w, `{"version": "electra", "data": %s}`, data)
+		})
+	}
+
+	addr := newMockHttpServer(t, handler)
+	n := New("http://" + addr).Builder()
+
+	t.Run("GetHeader", func(t *testing.T) {
+		bid, err := n.GetHeader(1, [32]byte{}, [48]byte{})
+		require.NoError(t, err)
+		require.Equal(t, consensus.VersionDeneb, bid.Version)
+		require.Equal(t, denebBid, bid.Deneb)
+		require.Equal(t, value, bid.Value())
+		require.Equal(t, [48]byte{0x3}, bid.Pubkey())
+		require.Equal(t, [96]byte{0x4}, bid.Signature())
+		require.Equal(t, [32]byte{0x1}, bid.BlockHash())
+	})
+
+	t.Run("GetHeaderSSZ", func(t *testing.T) {
+		bid, err := New("http://"+addr, WithSSZ()).Builder().GetHeader(2, [32]byte{}, [48]byte{})
+		require.NoError(t, err)
+		require.Equal(t, consensus.VersionCapella, bid.Version)
+		require.Equal(t, [32]byte{0x5}, bid.BlockHash())
+		require.Equal(t, value, bid.Value())
+	})
+
+	t.Run("GetHeaderNoBid", func(t *testing.T) {
+		_, err := n.GetHeader(3, [32]byte{}, [48]byte{})
+		require.True(t, errors.Is(err, ErrorNoContent))
+	})
+
+	t.Run("SubmitVersionedBlindedBlock", func(t *testing.T) {
+		block := &consensus.VersionedSignedBlindedBeaconBlock{
+			Version: consensus.VersionElectra,
+			Electra: &consensus.SignedBlindedBeaconBlockElectra{
+				Block: &consensus.BlindedBeaconBlockElectra{Slot: 10},
+			},
+		}
+		payload, err := n.SubmitVersionedBlindedBlock(block)
+		require.NoError(t, err)
+		require.Equal(t, consensus.VersionElectra, payload.Version)
+		require.Equal(t, [32]byte{0x6}, payload.BlockHash())
+		require.Equal(t, electraPayload.BlobsBundle, payload.BlobsBundle())
+	})
+}
+
+func TestBuilderBid_ValueJSON(t *testing.T) {
+	var value consensus.Uint256
+	require.NoError(t, value.UnmarshalText([]byte("123456789")))
+
+	// the value of the bid is encoded as a decimal string
+	data, err := Marshal(&BuilderBid{Value: value})
+	require.NoError(t, err)
+
+	var obj map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &obj))
+	require.Equal(t, "123456789", obj["value"])
+
+	var bid BuilderBid
+	require.NoError(t, Unmarshal(data, &bid, false))
+	require.Equal(t, value, bid.Value)
+}
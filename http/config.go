@@ -1,6 +1,10 @@
 package http
 
-import consensus "github.com/umbracle/go-eth-consensus"
+import (
+	"context"
+
+	consensus "github.com/umbracle/go-eth-consensus"
+)
 
 type ConfigEndpoint struct {
 	c *Client
@@ -11,14 +15,22 @@ func (c *Client) Config() *ConfigEndpoint {
 }
 
 func (c *ConfigEndpoint) ForkSchedule() ([]*consensus.Fork, error) {
+	return c.ForkScheduleWithContext(context.Background())
+}
+
+func (c *ConfigEndpoint) ForkScheduleWithContext(ctx context.Context) ([]*consensus.Fork, error) {
 	var out []*consensus.Fork
-	err := c.c.Get("/eth/v1/config/fork_schedule", &out)
+	err := c.c.GetWithContext(ctx, "/eth/v1/config/fork_schedule", &out)
 	return out, err
 }
 
 func (c *ConfigEndpoint) Spec() (*consensus.Spec, error) {
+	return c.SpecWithContext(context.Background())
+}
+
+func (c *ConfigEndpoint) SpecWithContext(ctx context.Context) (*consensus.Spec, error) {
 	var spec *consensus.Spec
-	err := c.c.Get("/eth/v1/config/spec", &spec)
+	err := c.c.GetWithContext(ctx, "/eth/v1/config/spec", &spec)
 	return spec, err
 }
 
@@ -28,7 +40,11 @@ type DepositContract struct {
 }
 
 func (c *ConfigEndpoint) DepositContract() (*DepositContract, error) {
+	return c.DepositContractWithContext(context.Background())
+}
+
+func (c *ConfigEndpoint) DepositContractWithContext(ctx context.Context) (*DepositContract, error) {
 	var depositContract *DepositContract
-	err := c.c.Get("/eth/v1/config/deposit_contract", &depositContract)
+	err := c.c.GetWithContext(ctx, "/eth/v1/config/deposit_contract", &depositContract)
 	return depositContract, err
 }
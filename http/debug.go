@@ -0,0 +1,110 @@
+package http
+
+import (
+	"context"
+	"fmt"
+
+	ssz "github.com/ferranbt/fastssz"
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+type DebugEndpoint struct {
+	c *Client
+}
+
+func (c *Client) Debug() *DebugEndpoint {
+	return &DebugEndpoint{c: c}
+}
+
+// GetState returns the full beacon state. The type of the state
+// is the one of the fork of the state.
+func (d *DebugEndpoint) GetState(id StateId) (*consensus.VersionedBeaconState, error) {
+	return d.GetStateWithContext(context.Background(), id)
+}
+
+func (d *DebugEndpoint) GetStateWithContext(ctx context.Context, id StateId) (*consensus.VersionedBeaconState, error) {
+	newState := func(version consensus.Version) (ssz.Unmarshaler, error) {
+		state, err := consensus.NewBeaconState(version)
+		if err != nil {
+			return nil, err
+		}
+		obj, ok := state.(ssz.Unmarshaler)
+		if !ok {
+			return nil, fmt.Errorf("beacon state %T cannot be decoded", state)
+		}
+		return obj, nil
+	}
+
+	version, obj, err := d.c.getVersioned(ctx, "/eth/v2/debug/beacon/states/"+id.StateID(), newState)
+	if err != nil {
+		return nil, err
+	}
+	return &consensus.VersionedBeaconState{Version: version, State: obj.(consensus.BeaconState)}, nil
+}
+
+type ChainHead struct {
+	Root                [32]byte `json:"root"`
+	Slot                uint64   `json:"slot"`
+	ExecutionOptimistic bool     `json:"execution_optimistic"`
+}
+
+// GetHeads returns the heads of the fork choice tree
+func (d *DebugEndpoint) GetHeads() ([]*ChainHead, error) {
+	return d.GetHeadsWithContext(context.Background())
+}
+
+func (d *DebugEndpoint) GetHeadsWithContext(ctx context.Context) ([]*ChainHead, error) {
+	var out []*ChainHead
+	err := d.c.GetWithContext(ctx, "/eth/v2/debug/beacon/heads", &out)
+	return out, err
+}
+
+type ForkChoiceNodeValidity string
+
+const (
+	ForkChoiceNodeValid      ForkChoiceNodeValidity = "valid"
+	ForkChoiceNodeInvalid    ForkChoiceNodeValidity = "invalid"
+	ForkChoiceNodeOptimistic ForkChoiceNodeValidity = "optimistic"
+)
+
+type ForkChoiceNode struct {
+	Slot               uint64                 `json:"slot"`
+	BlockRoot          [32]byte               `json:"block_root"`
+	ParentRoot         [32]byte               `json:"parent_root"`
+	JustifiedEpoch     uint64                 `json:"justified_epoch"`
+	FinalizedEpoch     uint64                 `json:"finalized_epoch"`
+	Weight             uint64                 `json:"weight"`
+	Validity           ForkChoiceNodeValidity `json:"validity"`
+	ExecutionBlockHash [32]byte               `json:"execution_block_hash"`
+
+	// ExtraData are the client specific fields of the node
+	ExtraData map[string]interface{} `json:"extra_data"`
+}
+
+type ForkChoice struct {
+	JustifiedCheckpoint *consensus.Checkpoint `json:"justified_checkpoint"`
+	FinalizedCheckpoint *consensus.Checkpoint `json:"finalized_checkpoint"`
+	Nodes               []*ForkChoiceNode     `json:"fork_choice_nodes"`
+
+	// ExtraData are the client specific fields of the fork choice
+	ExtraData map[string]interface{} `json:"extra_data"`
+}
+
+// GetForkChoice returns the fork choice nodes of the node
+func (d *DebugEndpoint) GetForkChoice() (*ForkChoice, error) {
+	return d.GetForkChoiceWithContext(context.Background())
+}
+
+func (d *DebugEndpoint) GetForkChoiceWithContext(ctx context.Context) (*ForkChoice, error) {
+	raw, err := d.c.getRaw(ctx, "/eth/v1/debug/fork_choice", contentTypeJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	// the fork choice is not wrapped in a data field
+	var out *ForkChoice
+	if err := Unmarshal(raw.data, &out, d.c.config.untrackedKeys); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+func TestDebugEndpoint(t *testing.T) {
+	state := &consensus.BeaconStateAltair{
+		Slot:      10,
+		Slashings: make([]uint64, 8192),
+	}
+
+	handler := func(m *http.ServeMux) {
+		m.HandleFunc("/eth/v2/debug/beacon/states/head", func(w http.ResponseWriter, r *http.Request) {
+			data, err := state.MarshalSSZ()
+			require.NoError(t, err)
+
+			w.Header().Set("Content-Type", contentTypeSSZ)
+			w.Header().Set(headerConsensusVersion, "altair")
+			w.Write(data)
+		})
+		m.HandleFunc("/eth/v2/debug/beacon/states/finalized", func(w http.ResponseWriter, r *http.Request) {
+			data, err := Marshal(state)
+			require.NoError(t, err)
+
+			w.Header().Set("Content-Type", contentTypeJSON)
+			fmt.Fprintf(w, `{"version": "altair", "data": %s}`, data)
+		})
+		m.HandleFunc("/eth/v2/debug/beacon/heads", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"data": [{"root": "0x0100000000000000000000000000000000000000000000000000000000000000", "slot": "1", "execution_optimistic": true}]}`))
+		})
+		m.HandleFunc("/eth/v1/debug/fork_choice", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{
+				"justified_checkpoint": {"epoch": "1", "root": "0x0100000000000000000000000000000000000000000000000000000000000000"},
+				"finalized_checkpoint": {"epoch": "0", "root": "0x0200000000000000000000000000000000000000000000000000000000000000"},
+				"fork_choice_nodes": [{
+					"slot": "1",
+					"block_root": "0x0100000000000000000000000000000000000000000000000000000000000000",
+					"parent_root": null,
+					"justified_epoch": "1",
+					"finalized_epoch": "0",
+					"weight": "100",
+					"validity": "optimistic",
+					"execution_block_hash": "0x0300000000000000000000000000000000000000000000000000000000000000",
+					"extra_data": {"a": "b"}
+				}],
+				"extra_data": {}
+			}`))
+		})
+	}
+
+	addr := newMockHttpServer(t, handler)
+	n := New("http://" + addr).Debug()
+
+	t.Run("GetState", func(t *testing.T) {
+		obj, err := n.GetState(Head)
+		require.NoError(t, err)
+		require.Equal(t, consensus.VersionAltair, obj.Version)
+
+		// compare the roots since the decoded state has empty slices instead of nil ones
+		expectedRoot, err := state.HashTreeRoot()
+		require.NoError(t, err)
+		root, err := obj.HashTreeRoot()
+		require.NoError(t, err)
+		require.Equal(t, expectedRoot, root)
+
+		obj, err = n.GetState(Finalized)
+		require.NoError(t, err)
+		require.Equal(t, consensus.VersionAltair, obj.Version)
+		require.Equal(t, uint64(10), obj.Slot())
+	})
+
+	t.Run("GetHeads", func(t *testing.T) {
+		heads, err := n.GetHeads()
+		require.NoError(t, err)
+		require.Equal(t, []*ChainHead{{Root: [32]byte{0x1}, Slot: 1, ExecutionOptimistic: true}}, heads)
+	})
+
+	t.Run("GetForkChoice", func(t *testing.T) {
+		forkChoice, err := n.GetForkChoice()
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), forkChoice.JustifiedCheckpoint.Epoch)
+		require.Equal(t, consensus.Root{0x2}, forkChoice.FinalizedCheckpoint.Root)
+
+		require.Len(t, forkChoice.Nodes, 1)
+		node := forkChoice.Nodes[0]
+		require.Equal(t, uint64(100), node.Weight)
+		require.Equal(t, [32]byte{}, node.ParentRoot)
+		require.Equal(t, ForkChoiceNodeOptimistic, node.Validity)
+		require.Equal(t, [32]byte{0x3}, node.ExecutionBlockHash)
+		require.Equal(t, "b", node.ExtraData["a"])
+
+		// the extra data is encoded back as a free-form object
+		data, err := Marshal(forkChoice)
+		require.NoError(t, err)
+
+		var decoded ForkChoice
+		require.NoError(t, Unmarshal(data, &decoded, false))
+		require.Equal(t, forkChoice, &decoded)
+	})
+}
@@ -35,16 +35,8 @@ func marshalImpl(v reflect.Value) (interface{}, error) {
 		v = reflect.New(v.Type().Elem())
 	}
 	typ := v.Type()
-	if isByteArray(typ) {
-		// [n]byte
-		return "0x" + hex.EncodeToString(convertArrayToBytes(v).Bytes()), nil
-	}
-	if isByteSlice(typ) {
-		// []byte
-		return "0x" + hex.EncodeToString(v.Bytes()), nil
-	}
 
-	// marshal with encoding.TextUnmarshaler
+	// marshal with encoding.TextMarshaler (i.e. Uint256 as a decimal string)
 	result := v.Interface()
 	marshaller, ok := result.(encoding.TextMarshaler)
 	if ok {
@@ -52,7 +44,16 @@ func marshalImpl(v reflect.Value) (interface{}, error) {
 		if err != nil {
 			return nil, err
 		}
-		return res, nil
+		return string(res), nil
+	}
+
+	if isByteArray(typ) {
+		// [n]byte
+		return "0x" + hex.EncodeToString(convertArrayToBytes(v).Bytes()), nil
+	}
+	if isByteSlice(typ) {
+		// []byte
+		return "0x" + hex.EncodeToString(v.Bytes()), nil
 	}
 
 	switch v.Kind() {
@@ -86,19 +87,51 @@ func marshalImpl(v reflect.Value) (interface{}, error) {
 			}
 
 			name := f.Name
-			if tagValue != "" {
-				name = tagValue
+			tagName, tagOpts, _ := strings.Cut(tagValue, ",")
+			if tagName != "" {
+				name = tagName
+			}
+			if strings.Contains(tagOpts, "omitempty") && isEmptyValue(v.Field(i)) {
+				continue
 			}
 			out[name] = val
 		}
 		return out, nil
 
+	case reflect.Map:
+		// free-form objects (i.e. the extra data of the fork choice)
+		if v.IsNil() {
+			return nil, nil
+		}
+		out := map[string]interface{}{}
+		iter := v.MapRange()
+		for iter.Next() {
+			elem, err := marshalImpl(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			out[fmt.Sprint(iter.Key().Interface())] = elem
+		}
+		return out, nil
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return marshalImpl(v.Elem())
+
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+
 	case reflect.String:
 		return v.String(), nil
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		return strconv.Itoa(int(v.Uint())), nil
 
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+
 	case reflect.Bool:
 		return v.Bool(), nil
 
@@ -107,6 +140,23 @@ func marshalImpl(v reflect.Value) (interface{}, error) {
 	}
 }
 
+// isEmptyValue returns whether the value is omitted with the omitempty option
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice, reflect.Map, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
 func convertArrayToBytes(value reflect.Value) reflect.Value {
 	slice := reflect.MakeSlice(reflect.TypeOf([]byte{}), value.Len(), value.Len())
 	reflect.Copy(slice, value)
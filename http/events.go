@@ -2,9 +2,12 @@ package http
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 
+	ssz "github.com/ferranbt/fastssz"
 	"github.com/r3labs/sse"
 	consensus "github.com/umbracle/go-eth-consensus"
 )
@@ -43,33 +46,126 @@ type ChainReorgEvent struct {
 	ExecutionOptimistic bool     `json:"execution_optimistic"`
 }
 
-var eventObjMap = map[string]func() interface{}{
-	"head":                   func() interface{} { return new(HeadEvent) },
-	"block":                  func() interface{} { return new(BlockEvent) },
-	"attestation":            func() interface{} { return new(consensus.Attestation) },
-	"voluntary_exit":         func() interface{} { return new(consensus.SignedVoluntaryExit) },
-	"finalized_checkpoint":   func() interface{} { return new(FinalizedCheckpointEvent) },
-	"chain_reorg":            func() interface{} { return new(ChainReorgEvent) },
-	"contribution_and_proof": func() interface{} { return new(consensus.SignedContributionAndProof) },
+type BlobSidecarEvent struct {
+	BlockRoot     [32]byte `json:"block_root"`
+	Index         uint64   `json:"index"`
+	Slot          uint64   `json:"slot"`
+	KzgCommitment [48]byte `json:"kzg_commitment"`
+	VersionedHash [32]byte `json:"versioned_hash"`
 }
 
-func (c *Client) Events(ctx context.Context, topics []string, handler func(obj interface{})) error {
+type BlockGossipEvent struct {
+	Slot  uint64   `json:"slot"`
+	Block [32]byte `json:"block"`
+}
+
+type PayloadAttributesEvent struct {
+	Version string                      `json:"version"`
+	Data    *PayloadAttributesEventData `json:"data"`
+}
+
+type PayloadAttributesEventData struct {
+	ProposerIndex     uint64             `json:"proposer_index"`
+	ProposalSlot      uint64             `json:"proposal_slot"`
+	ParentBlockNumber uint64             `json:"parent_block_number"`
+	ParentBlockRoot   [32]byte           `json:"parent_block_root"`
+	ParentBlockHash   [32]byte           `json:"parent_block_hash"`
+	PayloadAttributes *PayloadAttributes `json:"payload_attributes"`
+}
+
+// PayloadAttributes are the attributes to build the execution payload. Withdrawals
+// are set since Capella and the parent beacon block root since Deneb.
+type PayloadAttributes struct {
+	Timestamp             uint64                  `json:"timestamp"`
+	PrevRandao            [32]byte                `json:"prev_randao"`
+	SuggestedFeeRecipient [20]byte                `json:"suggested_fee_recipient"`
+	Withdrawals           []*consensus.Withdrawal `json:"withdrawals"`
+	ParentBeaconBlockRoot [32]byte                `json:"parent_beacon_block_root"`
+}
+
+type eventDecoder func(data []byte, trackUnusedKeys bool) (interface{}, error)
+
+// decodeEvent decodes the json event into the object
+func decodeEvent(newObj func() interface{}) eventDecoder {
+	return func(data []byte, trackUnusedKeys bool) (interface{}, error) {
+		obj := newObj()
+		if err := Unmarshal(data, obj, trackUnusedKeys); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	}
+}
+
+// decodeLightClientEvent decodes a light client event of the fork of its version
+func decodeLightClientEvent(newObj func(consensus.Version) (ssz.Unmarshaler, error)) eventDecoder {
+	return func(data []byte, trackUnusedKeys bool) (interface{}, error) {
+		var resp versionedResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, err
+		}
+		version, err := consensus.ParseVersion(resp.Version)
+		if err != nil {
+			return nil, err
+		}
+		obj, err := newObj(version)
+		if err != nil {
+			return nil, err
+		}
+		if err := Unmarshal(resp.Data, obj, trackUnusedKeys); err != nil {
+			return nil, err
+		}
+		return &LightClientObject{Version: version, Data: obj}, nil
+	}
+}
+
+var eventDecoders = map[string]eventDecoder{
+	"head":                           decodeEvent(func() interface{} { return new(HeadEvent) }),
+	"block":                          decodeEvent(func() interface{} { return new(BlockEvent) }),
+	"attestation":                    decodeEvent(func() interface{} { return new(consensus.Attestation) }),
+	"voluntary_exit":                 decodeEvent(func() interface{} { return new(consensus.SignedVoluntaryExit) }),
+	"finalized_checkpoint":           decodeEvent(func() interface{} { return new(FinalizedCheckpointEvent) }),
+	"chain_reorg":                    decodeEvent(func() interface{} { return new(ChainReorgEvent) }),
+	"contribution_and_proof":         decodeEvent(func() interface{} { return new(consensus.SignedContributionAndProof) }),
+	"blob_sidecar":                   decodeEvent(func() interface{} { return new(BlobSidecarEvent) }),
+	"payload_attributes":             decodeEvent(func() interface{} { return new(PayloadAttributesEvent) }),
+	"light_client_finality_update":   decodeLightClientEvent(newLightClientFinalityUpdate),
+	"light_client_optimistic_update": decodeLightClientEvent(newLightClientOptimisticUpdate),
+	"bls_to_execution_change":        decodeEvent(func() interface{} { return new(consensus.SignedBLSToExecutionChange) }),
+	"proposer_slashing":              decodeEvent(func() interface{} { return new(consensus.ProposerSlashing) }),
+	"attester_slashing":              decodeEvent(func() interface{} { return new(consensus.AttesterSlashing) }),
+	"block_gossip":                   decodeEvent(func() interface{} { return new(BlockGossipEvent) }),
+}
+
+func validateTopics(topics []string) error {
 	for _, topic := range topics {
-		if _, ok := eventObjMap[topic]; !ok {
+		if _, ok := eventDecoders[topic]; !ok {
 			return fmt.Errorf("topic '%s' is not valid", topic)
 		}
 	}
+	return nil
+}
+
+func (c *Client) Events(ctx context.Context, topics []string, handler func(obj interface{})) error {
+	if err := validateTopics(topics); err != nil {
+		return err
+	}
 
 	client := sse.NewClient(c.url + "/eth/v1/events?topics=" + strings.Join(topics, ","))
+	// use only the transport of the http client since its timeout
+	// would close the stream
+	client.Connection = &http.Client{Transport: c.config.httpClient.Transport}
+	for k, v := range c.config.headers {
+		client.Headers[k] = v
+	}
 	if err := client.SubscribeRawWithContext(ctx, func(msg *sse.Event) {
-		codec, ok := eventObjMap[string(msg.Event)]
+		decode, ok := eventDecoders[string(msg.Event)]
 		if !ok {
 			c.config.logger.Printf("[DEBUG]: event not tracked: %s", string(msg.Event))
 			return
 		}
-		obj := codec()
 
-		if err := Unmarshal(msg.Data, obj, c.config.untrackedKeys); err != nil {
+		obj, err := decode(msg.Data, c.config.untrackedKeys)
+		if err != nil {
 			c.config.logger.Printf("[ERROR]: failed to decode %s event: %v", string(msg.Event), err)
 			return
 		}
@@ -2,11 +2,19 @@ package http
 
 import (
 	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"reflect"
+	"strings"
+
+	ssz "github.com/ferranbt/fastssz"
+	consensus "github.com/umbracle/go-eth-consensus"
 )
 
 // https://ethereum.github.io/beacon-APIs/#/
@@ -14,6 +22,9 @@ import (
 type Config struct {
 	logger        *log.Logger
 	untrackedKeys bool
+	ssz           bool
+	httpClient    *http.Client
+	headers       map[string]string
 }
 
 type ConfigOption func(*Config)
@@ -30,6 +41,46 @@ func WithUntrackedKeys() ConfigOption {
 	}
 }
 
+// WithSSZ requests and publishes the objects that support it (i.e. states and blocks)
+// with ssz encoding. It falls back to json if the node does not support ssz.
+func WithSSZ() ConfigOption {
+	return func(c *Config) {
+		c.ssz = true
+	}
+}
+
+// WithHTTPClient sets the http client used to send the requests. It can be used
+// to set a timeout, a custom transport or a tls config.
+func WithHTTPClient(client *http.Client) ConfigOption {
+	return func(c *Config) {
+		c.httpClient = client
+	}
+}
+
+// WithHeaders sets headers that are included in every request
+func WithHeaders(headers map[string]string) ConfigOption {
+	return func(c *Config) {
+		for k, v := range headers {
+			c.headers[k] = v
+		}
+	}
+}
+
+// WithBearerToken authenticates the requests with a bearer token
+func WithBearerToken(token string) ConfigOption {
+	return WithHeaders(map[string]string{
+		"Authorization": "Bearer " + token,
+	})
+}
+
+// WithBasicAuth authenticates the requests with basic auth
+func WithBasicAuth(username, password string) ConfigOption {
+	auth := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	return WithHeaders(map[string]string{
+		"Authorization": "Basic " + auth,
+	})
+}
+
 type Client struct {
 	url    string
 	config *Config
@@ -37,7 +88,9 @@ type Client struct {
 
 func New(url string, opts ...ConfigOption) *Client {
 	config := &Config{
-		logger: log.New(io.Discard, "", 0),
+		logger:     log.New(io.Discard, "", 0),
+		httpClient: http.DefaultClient,
+		headers:    map[string]string{},
 	}
 	for _, opt := range opts {
 		opt(config)
@@ -50,14 +103,59 @@ func (c *Client) SetLogger(logger *log.Logger) {
 	c.config.logger = logger
 }
 
+// newRequest creates a request with the headers of the client
+func (c *Client) newRequest(ctx context.Context, method string, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range c.config.headers {
+		req.Header.Set(k, v)
+	}
+	return req, nil
+}
+
 func (c *Client) Post(path string, input interface{}, out interface{}) error {
+	return c.PostWithContext(context.Background(), path, input, out)
+}
+
+func (c *Client) PostWithContext(ctx context.Context, path string, input interface{}, out interface{}) error {
+	return c.post(ctx, path, input, out, nil)
+}
+
+func (c *Client) post(ctx context.Context, path string, input interface{}, out interface{}, headers map[string]string) error {
+	if c.config.ssz {
+		if obj, ok := input.(ssz.Marshaler); ok {
+			postBody, err := obj.MarshalSSZ()
+			if err != nil {
+				return err
+			}
+			err = c.doPost(ctx, path, contentTypeSSZ, postBody, out, headers)
+			if !errors.Is(err, ErrorUnsupportedMediaType) {
+				return err
+			}
+			// the node does not support ssz, fall back to json
+		}
+	}
+
 	postBody, err := Marshal(input)
 	if err != nil {
 		return err
 	}
-	responseBody := bytes.NewBuffer(postBody)
+	return c.doPost(ctx, path, contentTypeJSON, postBody, out, headers)
+}
 
-	resp, err := http.Post(c.url+path, "application/json", responseBody)
+func (c *Client) doPost(ctx context.Context, path string, contentType string, postBody []byte, out interface{}, headers map[string]string) error {
+	req, err := c.newRequest(ctx, http.MethodPost, path, bytes.NewReader(postBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.config.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -70,7 +168,15 @@ func (c *Client) Post(path string, input interface{}, out interface{}) error {
 }
 
 func (c *Client) Status(path string) (bool, error) {
-	resp, err := http.Get(c.url + path)
+	return c.StatusWithContext(context.Background(), path)
+}
+
+func (c *Client) StatusWithContext(ctx context.Context, path string) (bool, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := c.config.httpClient.Do(req)
 	if err != nil {
 		return false, err
 	}
@@ -87,7 +193,21 @@ func (c *Client) Status(path string) (bool, error) {
 }
 
 func (c *Client) Get(path string, out interface{}) error {
-	resp, err := http.Get(c.url + path)
+	return c.GetWithContext(context.Background(), path, out)
+}
+
+func (c *Client) GetWithContext(ctx context.Context, path string, out interface{}) error {
+	if c.config.ssz {
+		if obj, ok := sszUnmarshaler(out); ok {
+			return c.getSSZ(ctx, path, obj, out)
+		}
+	}
+
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.config.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -101,20 +221,210 @@ func (c *Client) Get(path string, out interface{}) error {
 	return nil
 }
 
+// getSSZ requests the object with ssz encoding and decodes it as json if
+// the node does not support ssz
+func (c *Client) getSSZ(ctx context.Context, path string, obj ssz.Unmarshaler, out interface{}) error {
+	raw, err := c.getRaw(ctx, path, acceptSSZOrJSON)
+	if errors.Is(err, ErrorNotAcceptable) {
+		raw, err = c.getRaw(ctx, path, contentTypeJSON)
+	}
+	if err != nil {
+		return err
+	}
+	if raw.ssz {
+		return obj.UnmarshalSSZ(raw.data)
+	}
+	return c.decodeData(raw.data, out)
+}
+
+// sszUnmarshaler returns the ssz decoder of out if it has one. If out is a
+// pointer to a nil pointer the object is allocated.
+func sszUnmarshaler(out interface{}) (ssz.Unmarshaler, bool) {
+	if obj, ok := out.(ssz.Unmarshaler); ok {
+		return obj, true
+	}
+
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Ptr {
+		return nil, false
+	}
+	elem := v.Elem()
+	if !elem.Type().Implements(sszUnmarshalerType) {
+		return nil, false
+	}
+	if elem.IsNil() {
+		elem.Set(reflect.New(elem.Type().Elem()))
+	}
+	return elem.Interface().(ssz.Unmarshaler), true
+}
+
+var sszUnmarshalerType = reflect.TypeOf((*ssz.Unmarshaler)(nil)).Elem()
+
+const (
+	contentTypeJSON = "application/json"
+	contentTypeSSZ  = "application/octet-stream"
+
+	// acceptSSZOrJSON prefers a ssz response over a json one
+	acceptSSZOrJSON = contentTypeSSZ + ";q=1.0," + contentTypeJSON + ";q=0.9"
+
+	// headerConsensusVersion is the header with the fork of the object
+	headerConsensusVersion = "Eth-Consensus-Version"
+)
+
+// rawResponse is a successful response that has not been decoded
+type rawResponse struct {
+	// version is the value of the Eth-Consensus-Version header
+	version string
+
+	// ssz is set if the body is ssz encoded
+	ssz bool
+
+	header http.Header
+	data   []byte
+}
+
+// getRaw sends a get request with the given accept header and returns the response as is
+func (c *Client) getRaw(ctx context.Context, path string, accept string) (*rawResponse, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+
+	resp, err := c.config.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	c.config.logger.Printf("[TRACE] Get request: path, %s", path)
+
+	return readRaw(resp)
+}
+
+// readRaw reads the body of a response and returns it as is
+func readRaw(resp *http.Response) (*rawResponse, error) {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := decodeError(resp.StatusCode, data); err != nil {
+		return nil, err
+	}
+
+	raw := &rawResponse{
+		version: resp.Header.Get(headerConsensusVersion),
+		ssz:     strings.HasPrefix(resp.Header.Get("Content-Type"), contentTypeSSZ),
+		header:  resp.Header,
+		data:    data,
+	}
+	return raw, nil
+}
+
+// versionedResponse is the json response of an object of a given fork
+type versionedResponse struct {
+	Version string          `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// getVersioned requests an object whose type depends on the fork. The fork is
+// resolved with the Eth-Consensus-Version header or the version in the json response.
+func (c *Client) getVersioned(ctx context.Context, path string, newObj func(consensus.Version) (ssz.Unmarshaler, error)) (consensus.Version, ssz.Unmarshaler, error) {
+	raw, err := c.getRaw(ctx, path, acceptSSZOrJSON)
+	if errors.Is(err, ErrorNotAcceptable) {
+		raw, err = c.getRaw(ctx, path, contentTypeJSON)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return c.decodeVersioned(raw, newObj)
+}
+
+// postVersioned sends the input as json and decodes a response whose type depends on the fork
+func (c *Client) postVersioned(ctx context.Context, path string, input interface{}, headers map[string]string, newObj func(consensus.Version) (ssz.Unmarshaler, error)) (consensus.Version, ssz.Unmarshaler, error) {
+	postBody, err := Marshal(input)
+	if err != nil {
+		return 0, nil, err
+	}
+	req, err := c.newRequest(ctx, http.MethodPost, path, bytes.NewReader(postBody))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", contentTypeJSON)
+	req.Header.Set("Accept", acceptSSZOrJSON)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.config.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := readRaw(resp)
+	if err != nil {
+		return 0, nil, err
+	}
+	return c.decodeVersioned(raw, newObj)
+}
+
+// decodeVersioned decodes the response into the object of the fork. The fork is
+// resolved with the Eth-Consensus-Version header or the version in the json response.
+func (c *Client) decodeVersioned(raw *rawResponse, newObj func(consensus.Version) (ssz.Unmarshaler, error)) (consensus.Version, ssz.Unmarshaler, error) {
+	var resp versionedResponse
+	if !raw.ssz {
+		if err := json.Unmarshal(raw.data, &resp); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	// the header takes precedence over the version in the json response
+	versionStr := raw.version
+	if versionStr == "" {
+		versionStr = resp.Version
+	}
+	version, err := consensus.ParseVersion(versionStr)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	obj, err := newObj(version)
+	if err != nil {
+		return 0, nil, err
+	}
+	if raw.ssz {
+		err = obj.UnmarshalSSZ(raw.data)
+	} else {
+		err = Unmarshal(resp.Data, obj, c.config.untrackedKeys)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	return version, obj, nil
+}
+
 var (
-	ErrorIncompleteData      = fmt.Errorf("incomplete data (206)")
-	ErrorBadRequest          = fmt.Errorf("bad request (400)")
-	ErrorNotFound            = fmt.Errorf("not found (404)")
-	ErrorInternalServerError = fmt.Errorf("internal server error (500)")
-	ErrorServiceUnavailable  = fmt.Errorf("service unavailable (503)")
+	ErrorNoContent            = fmt.Errorf("no content (204)")
+	ErrorIncompleteData       = fmt.Errorf("incomplete data (206)")
+	ErrorBadRequest           = fmt.Errorf("bad request (400)")
+	ErrorNotFound             = fmt.Errorf("not found (404)")
+	ErrorNotAcceptable        = fmt.Errorf("not acceptable (406)")
+	ErrorUnsupportedMediaType = fmt.Errorf("unsupported media type (415)")
+	ErrorInternalServerError  = fmt.Errorf("internal server error (500)")
+	ErrorServiceUnavailable   = fmt.Errorf("service unavailable (503)")
 )
 
 var httpErrorMapping = map[int]error{
-	http.StatusPartialContent:      ErrorIncompleteData,
-	http.StatusBadRequest:          ErrorBadRequest,
-	http.StatusNotFound:            ErrorNotFound,
-	http.StatusInternalServerError: ErrorInternalServerError,
-	http.StatusServiceUnavailable:  ErrorServiceUnavailable,
+	http.StatusNoContent:            ErrorNoContent,
+	http.StatusPartialContent:       ErrorIncompleteData,
+	http.StatusBadRequest:           ErrorBadRequest,
+	http.StatusNotFound:             ErrorNotFound,
+	http.StatusNotAcceptable:        ErrorNotAcceptable,
+	http.StatusUnsupportedMediaType: ErrorUnsupportedMediaType,
+	http.StatusInternalServerError:  ErrorInternalServerError,
+	http.StatusServiceUnavailable:   ErrorServiceUnavailable,
 }
 
 type httpErrorMessage struct {
@@ -122,26 +432,37 @@ type httpErrorMessage struct {
 	Message string `json:"message"`
 }
 
-func (c *Client) decodeResp(resp *http.Response, out interface{}) error {
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
+func decodeError(statusCode int, data []byte) error {
+	if statusCode == http.StatusOK {
+		return nil
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		// decode the error message
-		var msg httpErrorMessage
-		if err := json.Unmarshal(data, &msg); err != nil {
+	// decode the error message
+	var msg httpErrorMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		if _, ok := httpErrorMapping[statusCode]; !ok {
 			return err
 		}
+		// the node does not return a json error (i.e. 415 from a proxy)
+		msg.Message = string(data)
+	}
 
-		errorMsgCode, ok := httpErrorMapping[resp.StatusCode]
-		if ok {
-			return fmt.Errorf("%w: %v", errorMsgCode, msg.Message)
-		}
+	errorMsgCode, ok := httpErrorMapping[statusCode]
+	if ok {
+		return fmt.Errorf("%w: %v", errorMsgCode, msg.Message)
+	}
 
-		// return the error message as is
-		return fmt.Errorf(msg.Message)
+	// return the error message as is
+	return fmt.Errorf(msg.Message)
+}
+
+func (c *Client) decodeResp(resp *http.Response, out interface{}) error {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err := decodeError(resp.StatusCode, data); err != nil {
+		return err
 	}
 
 	c.config.logger.Printf("[TRACE] Http response: data, %s", string(data))
@@ -159,7 +480,11 @@ func (c *Client) decodeResp(resp *http.Response, out interface{}) error {
 		}
 		return fmt.Errorf("json failed to decode post message: '%s'", string(data))
 	}
+	return c.decodeData(data, out)
+}
 
+// decodeData decodes the data field of a json response
+func (c *Client) decodeData(data []byte, out interface{}) error {
 	var output struct {
 		Data json.RawMessage `json:"data,omitempty"`
 	}
@@ -1,11 +1,15 @@
 package http
 
 import (
+	"context"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	consensus "github.com/umbracle/go-eth-consensus"
 )
 
 func TestHttp_Post(t *testing.T) {
@@ -86,3 +90,116 @@ func newMockHttpServer(t *testing.T, handler func(m *http.ServeMux)) string {
 	})
 	return addr
 }
+
+func TestHttp_SSZ(t *testing.T) {
+	checkpoint := &consensus.Checkpoint{Epoch: 10, Root: consensus.Root{0x1}}
+
+	handler := func(m *http.ServeMux) {
+		m.HandleFunc("/get", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("json") == "" && strings.Contains(r.Header.Get("Accept"), contentTypeSSZ) {
+				data, _ := checkpoint.MarshalSSZ()
+				w.Header().Set("Content-Type", contentTypeSSZ)
+				w.Write(data)
+				return
+			}
+			if r.Header.Get("Accept") != "" && !strings.Contains(r.Header.Get("Accept"), contentTypeJSON) {
+				w.WriteHeader(http.StatusNotAcceptable)
+				return
+			}
+			w.Header().Set("Content-Type", contentTypeJSON)
+			w.Write([]byte(`{"data": {"epoch": "10", "root": "0x0100000000000000000000000000000000000000000000000000000000000000"}}`))
+		})
+		m.HandleFunc("/post", func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+
+			obj := new(consensus.Checkpoint)
+			switch r.Header.Get("Content-Type") {
+			case contentTypeSSZ:
+				if r.URL.Query().Get("json") != "" {
+					w.WriteHeader(http.StatusUnsupportedMediaType)
+					return
+				}
+				require.NoError(t, obj.UnmarshalSSZ(data))
+			case contentTypeJSON:
+				require.NoError(t, Unmarshal(data, obj, true))
+			}
+			require.Equal(t, checkpoint, obj)
+		})
+	}
+
+	addr := newMockHttpServer(t, handler)
+
+	for _, clt := range []*Client{New("http://" + addr), New("http://"+addr, WithSSZ())} {
+		for _, path := range []string{"/get", "/get?json=true"} {
+			var out *consensus.Checkpoint
+			require.NoError(t, clt.Get(path, &out))
+			require.Equal(t, checkpoint, out)
+		}
+		for _, path := range []string{"/post", "/post?json=true"} {
+			require.NoError(t, clt.Post(path, checkpoint, nil))
+		}
+	}
+}
+
+type countTransport struct {
+	count int
+}
+
+func (c *countTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.count++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestHttp_Options(t *testing.T) {
+	handler := func(m *http.ServeMux) {
+		m.HandleFunc("/auth", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"data": "` + r.Header.Get("Authorization") + `"}`))
+		})
+		m.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		})
+	}
+
+	addr := newMockHttpServer(t, handler)
+
+	t.Run("Headers", func(t *testing.T) {
+		var out string
+		require.NoError(t, New("http://"+addr, WithBearerToken("token")).Get("/auth", &out))
+		require.Equal(t, "Bearer token", out)
+
+		require.NoError(t, New("http://"+addr, WithBasicAuth("user", "pass")).Get("/auth", &out))
+		require.Equal(t, "Basic dXNlcjpwYXNz", out)
+
+		require.NoError(t, New("http://"+addr, WithHeaders(map[string]string{"Authorization": "a"})).Get("/auth", &out))
+		require.Equal(t, "a", out)
+	})
+
+	t.Run("HTTPClient", func(t *testing.T) {
+		transport := &countTransport{}
+		clt := New("http://"+addr, WithHTTPClient(&http.Client{Transport: transport}))
+
+		var out string
+		require.NoError(t, clt.Get("/auth", &out))
+		require.Equal(t, 1, transport.count)
+
+		clt = New("http://"+addr, WithHTTPClient(&http.Client{Timeout: 100 * time.Millisecond}))
+		require.Error(t, clt.Get("/slow", &out))
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		clt := New("http://" + addr)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		var out string
+		err := clt.GetWithContext(ctx, "/slow", &out)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		_, err = clt.Node().HealthWithContext(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
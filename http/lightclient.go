@@ -0,0 +1,220 @@
+package http
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	ssz "github.com/ferranbt/fastssz"
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+type LightClientEndpoint struct {
+	c *Client
+
+	// forkDigests are the versions of the fork digests, it is used
+	// to decode the ssz light client updates
+	forkDigests map[[4]byte]consensus.Version
+}
+
+func (c *Client) LightClient() *LightClientEndpoint {
+	return &LightClientEndpoint{c: c}
+}
+
+// LightClientObject is a light client object of the fork in Version. Data is
+// the Altair (also used in Bellatrix), Capella or Deneb type of the object.
+type LightClientObject struct {
+	Version consensus.Version
+	Data    ssz.Unmarshaler
+}
+
+func newLightClientBootstrap(version consensus.Version) (ssz.Unmarshaler, error) {
+	switch version {
+	case consensus.VersionAltair, consensus.VersionBellatrix:
+		return new(consensus.LightClientBootstrap), nil
+	case consensus.VersionCapella:
+		return new(consensus.LightClientBootstrapCapella), nil
+	case consensus.VersionDeneb:
+		return new(consensus.LightClientBootstrapDeneb), nil
+	default:
+		return nil, fmt.Errorf("light client bootstrap for version '%s' not supported", version)
+	}
+}
+
+func newLightClientUpdate(version consensus.Version) (ssz.Unmarshaler, error) {
+	switch version {
+	case consensus.VersionAltair, consensus.VersionBellatrix:
+		return new(consensus.LightClientUpdate), nil
+	case consensus.VersionCapella:
+		return new(consensus.LightClientUpdateCapella), nil
+	case consensus.VersionDeneb:
+		return new(consensus.LightClientUpdateDeneb), nil
+	default:
+		return nil, fmt.Errorf("light client update for version '%s' not supported", version)
+	}
+}
+
+func newLightClientFinalityUpdate(version consensus.Version) (ssz.Unmarshaler, error) {
+	switch version {
+	case consensus.VersionAltair, consensus.VersionBellatrix:
+		return new(consensus.LightClientFinalityUpdate), nil
+	case consensus.VersionCapella:
+		return new(consensus.LightClientFinalityUpdateCapella), nil
+	case consensus.VersionDeneb:
+		return new(consensus.LightClientFinalityUpdateDeneb), nil
+	default:
+		return nil, fmt.Errorf("light client finality update for version '%s' not supported", version)
+	}
+}
+
+func newLightClientOptimisticUpdate(version consensus.Version) (ssz.Unmarshaler, error) {
+	switch version {
+	case consensus.VersionAltair, consensus.VersionBellatrix:
+		return new(consensus.LightClientOptimisticUpdate), nil
+	case consensus.VersionCapella:
+		return new(consensus.LightClientOptimisticUpdateCapella), nil
+	case consensus.VersionDeneb:
+		return new(consensus.LightClientOptimisticUpdateDeneb), nil
+	default:
+		return nil, fmt.Errorf("light client optimistic update for version '%s' not supported", version)
+	}
+}
+
+// Bootstrap returns the light client bootstrap of the given block root
+func (l *LightClientEndpoint) Bootstrap(blockRoot [32]byte) (*LightClientObject, error) {
+	return l.BootstrapWithContext(context.Background(), blockRoot)
+}
+
+func (l *LightClientEndpoint) BootstrapWithContext(ctx context.Context, blockRoot [32]byte) (*LightClientObject, error) {
+	return l.getObject(ctx, fmt.Sprintf("/eth/v1/beacon/light_client/bootstrap/0x%x", blockRoot), newLightClientBootstrap)
+}
+
+// FinalityUpdate returns the latest light client finality update
+func (l *LightClientEndpoint) FinalityUpdate() (*LightClientObject, error) {
+	return l.FinalityUpdateWithContext(context.Background())
+}
+
+func (l *LightClientEndpoint) FinalityUpdateWithContext(ctx context.Context) (*LightClientObject, error) {
+	return l.getObject(ctx, "/eth/v1/beacon/light_client/finality_update", newLightClientFinalityUpdate)
+}
+
+// OptimisticUpdate returns the latest light client optimistic update
+func (l *LightClientEndpoint) OptimisticUpdate() (*LightClientObject, error) {
+	return l.OptimisticUpdateWithContext(context.Background())
+}
+
+func (l *LightClientEndpoint) OptimisticUpdateWithContext(ctx context.Context) (*LightClientObject, error) {
+	return l.getObject(ctx, "/eth/v1/beacon/light_client/optimistic_update", newLightClientOptimisticUpdate)
+}
+
+func (l *LightClientEndpoint) getObject(ctx context.Context, path string, newObj func(consensus.Version) (ssz.Unmarshaler, error)) (*LightClientObject, error) {
+	version, obj, err := l.c.getVersioned(ctx, path, newObj)
+	if err != nil {
+		return nil, err
+	}
+	return &LightClientObject{Version: version, Data: obj}, nil
+}
+
+// Updates returns the light client updates of count sync committee periods
+// starting at startPeriod
+func (l *LightClientEndpoint) Updates(startPeriod, count uint64) ([]*LightClientObject, error) {
+	return l.UpdatesWithContext(context.Background(), startPeriod, count)
+}
+
+func (l *LightClientEndpoint) UpdatesWithContext(ctx context.Context, startPeriod, count uint64) ([]*LightClientObject, error) {
+	raw, err := l.c.getRaw(ctx, fmt.Sprintf("/eth/v1/beacon/light_client/updates?start_period=%d&count=%d", startPeriod, count), acceptSSZOrJSON)
+	if err != nil {
+		return nil, err
+	}
+	if raw.ssz {
+		return l.decodeUpdatesSSZ(ctx, raw.data)
+	}
+
+	var resp []*versionedResponse
+	if err := json.Unmarshal(raw.data, &resp); err != nil {
+		return nil, err
+	}
+
+	updates := []*LightClientObject{}
+	for _, elem := range resp {
+		version, err := consensus.ParseVersion(elem.Version)
+		if err != nil {
+			return nil, err
+		}
+		obj, err := newLightClientUpdate(version)
+		if err != nil {
+			return nil, err
+		}
+		if err := Unmarshal(elem.Data, obj, l.c.config.untrackedKeys); err != nil {
+			return nil, err
+		}
+		updates = append(updates, &LightClientObject{Version: version, Data: obj})
+	}
+	return updates, nil
+}
+
+// decodeUpdatesSSZ decodes a sequence of ssz updates. Each update is prefixed
+// with the length of the chunk (8 bytes) and the fork digest of the update (4 bytes).
+func (l *LightClientEndpoint) decodeUpdatesSSZ(ctx context.Context, buf []byte) ([]*LightClientObject, error) {
+	updates := []*LightClientObject{}
+	for len(buf) != 0 {
+		if len(buf) < 12 {
+			return nil, fmt.Errorf("light client update chunk too short: %d", len(buf))
+		}
+		size := binary.LittleEndian.Uint64(buf[:8])
+		if size < 4 || size > uint64(len(buf)-8) {
+			return nil, fmt.Errorf("incorrect light client update chunk size: %d", size)
+		}
+		chunk := buf[8 : 8+size]
+		buf = buf[8+size:]
+
+		var digest [4]byte
+		copy(digest[:], chunk[:4])
+
+		version, err := l.versionOfDigest(ctx, digest)
+		if err != nil {
+			return nil, err
+		}
+		obj, err := newLightClientUpdate(version)
+		if err != nil {
+			return nil, err
+		}
+		if err := obj.UnmarshalSSZ(chunk[4:]); err != nil {
+			return nil, err
+		}
+		updates = append(updates, &LightClientObject{Version: version, Data: obj})
+	}
+	return updates, nil
+}
+
+// versionOfDigest returns the version of the fork digest with
+// the genesis and the spec of the node
+func (l *LightClientEndpoint) versionOfDigest(ctx context.Context, digest [4]byte) (consensus.Version, error) {
+	if l.forkDigests == nil {
+		genesis, err := l.c.Beacon().GenesisWithContext(ctx)
+		if err != nil {
+			return 0, err
+		}
+		spec, err := l.c.Config().SpecWithContext(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		forkDigests := map[[4]byte]consensus.Version{}
+		for version := consensus.VersionPhase0; version <= consensus.VersionElectra; version++ {
+			forkDigest, err := consensus.ComputeForkDigest(spec.ForkVersion(version), genesis.Root)
+			if err != nil {
+				return 0, err
+			}
+			forkDigests[forkDigest] = version
+		}
+		l.forkDigests = forkDigests
+	}
+
+	version, ok := l.forkDigests[digest]
+	if !ok {
+		return 0, fmt.Errorf("fork digest 0x%x not found", digest)
+	}
+	return version, nil
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"testing"
+
+	ssz "github.com/ferranbt/fastssz"
+	"github.com/stretchr/testify/require"
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+func TestLightClientEndpoint(t *testing.T) {
+	genesisValidatorsRoot := consensus.Root{0x1}
+
+	bootstrap := &consensus.LightClientBootstrapCapella{
+		Header: &consensus.LightClientHeaderCapella{
+			Header:    &consensus.BeaconBlockHeader{Slot: 10},
+			Execution: &consensus.ExecutionPayloadHeaderCapella{BlockNumber: 1, ExtraData: []byte{0x1}},
+		},
+		CurrentSyncCommittee:       &consensus.SyncCommittee{},
+		CurrentSyncCommitteeBranch: make([][32]byte, 5),
+	}
+	finalityUpdate := &consensus.LightClientFinalityUpdate{
+		AttestedHeader:  &consensus.LightClientHeader{Header: &consensus.BeaconBlockHeader{Slot: 20}},
+		FinalizedHeader: &consensus.LightClientHeader{Header: &consensus.BeaconBlockHeader{Slot: 10}},
+		FinalityBranch:  make([][32]byte, 6),
+		SyncAggregate:   &consensus.SyncAggregate{},
+		SignatureSlot:   21,
+	}
+	updates := []struct {
+		version consensus.Version
+		obj     ssz.Marshaler
+	}{
+		{
+			consensus.VersionAltair,
+			&consensus.LightClientUpdate{
+				AttestedHeader:          &consensus.LightClientHeader{Header: &consensus.BeaconBlockHeader{Slot: 30}},
+				NextSyncCommittee:       &consensus.SyncCommittee{},
+				NextSyncCommitteeBranch: make([][32]byte, 5),
+				FinalizedHeader:         &consensus.LightClientHeader{Header: &consensus.BeaconBlockHeader{}},
+				FinalityBranch:          make([][32]byte, 6),
+				SyncAggregate:           &consensus.SyncAggregate{},
+			},
+		},
+		{
+			consensus.VersionDeneb,
+			&consensus.LightClientUpdateDeneb{
+				AttestedHeader:          &consensus.LightClientHeaderDeneb{Header: &consensus.BeaconBlockHeader{Slot: 40}, Execution: &consensus.ExecutionPayloadHeaderDeneb{ExtraData: []byte{}}},
+				NextSyncCommittee:       &consensus.SyncCommittee{},
+				NextSyncCommitteeBranch: make([][32]byte, 5),
+				FinalizedHeader:         &consensus.LightClientHeaderDeneb{Header: &consensus.BeaconBlockHeader{}, Execution: &consensus.ExecutionPayloadHeaderDeneb{ExtraData: []byte{}}},
+				FinalityBranch:          make([][32]byte, 6),
+				SyncAggregate:           &consensus.SyncAggregate{},
+			},
+		},
+	}
+
+	handler := func(m *http.ServeMux) {
+		m.HandleFunc("/eth/v1/beacon/genesis", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"data": {"genesis_time": "1", "genesis_validators_root": "0x%x", "genesis_fork_version": "0x00000000"}}`, genesisValidatorsRoot)
+		})
+		m.HandleFunc("/eth/v1/config/spec", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"data": {"ALTAIR_FORK_VERSION": "0x01000000", "DENEB_FORK_VERSION": "0x04000000"}}`))
+		})
+		m.HandleFunc(fmt.Sprintf("/eth/v1/beacon/light_client/bootstrap/0x%x", [32]byte{0x2}), func(w http.ResponseWriter, r *http.Request) {
+			data, err := Marshal(bootstrap)
+			require.NoError(t, err)
+
+			w.Header().Set("Content-Type", contentTypeJSON)
+			fmt.Fprintf(w, `{"version": "capella", "data": %s}`, data)
+		})
+		m.HandleFunc("/eth/v1/beacon/light_client/finality_update", func(w http.ResponseWriter, r *http.Request) {
+			data, err := finalityUpdate.MarshalSSZ()
+			require.NoError(t, err)
+
+			w.Header().Set("Content-Type", contentTypeSSZ)
+			w.Header().Set(headerConsensusVersion, "altair")
+			w.Write(data)
+		})
+		m.HandleFunc("/eth/v1/beacon/light_client/updates", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "1", r.URL.Query().Get("start_period"))
+			require.Equal(t, "2", r.URL.Query().Get("count"))
+
+			spec := &consensus.Spec{AltairForkVersion: consensus.Domain{1}, DenebForkVersion: consensus.Domain{4}}
+
+			buf := []byte{}
+			for _, update := range updates {
+				data, err := update.obj.MarshalSSZ()
+				require.NoError(t, err)
+
+				digest, err := consensus.ComputeForkDigest(spec.ForkVersion(update.version), genesisValidatorsRoot)
+				require.NoError(t, err)
+
+				size := make([]byte, 8)
+				binary.LittleEndian.PutUint64(size, uint64(len(data)+4))
+
+				buf = append(buf, size...)
+				buf = append(buf, digest[:]...)
+				buf = append(buf, data...)
+			}
+
+			w.Header().Set("Content-Type", contentTypeSSZ)
+			w.Write(buf)
+		})
+	}
+
+	addr := newMockHttpServer(t, handler)
+	n := New("http://" + addr).LightClient()
+
+	t.Run("Bootstrap", func(t *testing.T) {
+		obj, err := n.Bootstrap([32]byte{0x2})
+		require.NoError(t, err)
+		require.Equal(t, consensus.VersionCapella, obj.Version)
+		require.Equal(t, bootstrap, obj.Data)
+	})
+
+	t.Run("FinalityUpdate", func(t *testing.T) {
+		obj, err := n.FinalityUpdate()
+		require.NoError(t, err)
+		require.Equal(t, consensus.VersionAltair, obj.Version)
+		require.Equal(t, finalityUpdate, obj.Data)
+	})
+
+	t.Run("Updates", func(t *testing.T) {
+		objs, err := n.Updates(1, 2)
+		require.NoError(t, err)
+		require.Len(t, objs, 2)
+
+		for i, update := range updates {
+			require.Equal(t, update.version, objs[i].Version)
+			require.Equal(t, update.obj, objs[i].Data)
+		}
+	})
+
+	t.Run("OptimisticUpdate", func(t *testing.T) {
+		_, err := n.OptimisticUpdate()
+		require.Error(t, err)
+	})
+}
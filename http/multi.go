@@ -0,0 +1,470 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// multiClientURL is the url of the requests of the multi client. The
+// transport replaces it with the url of the node.
+const multiClientURL = "http://multi"
+
+// broadcastPaths are the endpoints that the multi client sends to all the nodes
+var broadcastPaths = map[string]struct{}{
+	"/eth/v1/beacon/blocks":                        {},
+	"/eth/v2/beacon/blocks":                        {},
+	"/eth/v2/beacon/blinded_blocks":                {},
+	"/eth/v1/beacon/pool/attestations":             {},
+	"/eth/v1/beacon/pool/sync_committees":          {},
+	"/eth/v1/beacon/pool/attester_slashings":       {},
+	"/eth/v1/beacon/pool/proposer_slashings":       {},
+	"/eth/v1/beacon/pool/voluntary_exits":          {},
+	"/eth/v1/beacon/pool/bls_to_execution_changes": {},
+}
+
+const (
+	// minNodeBackoff is the wait before a failed node is tried again. It doubles
+	// after each consecutive failure up to maxNodeBackoff.
+	minNodeBackoff = 1 * time.Second
+	maxNodeBackoff = 1 * time.Minute
+)
+
+var ErrorQuorumNotReached = fmt.Errorf("quorum not reached")
+
+type NodeStatus int
+
+const (
+	NodeStatusHealthy NodeStatus = iota
+	NodeStatusSyncing
+	NodeStatusUnhealthy
+)
+
+func (n NodeStatus) String() string {
+	switch n {
+	case NodeStatusHealthy:
+		return "healthy"
+	case NodeStatusSyncing:
+		return "syncing"
+	default:
+		return "unhealthy"
+	}
+}
+
+type multiNode struct {
+	url    string
+	client *Client
+	status NodeStatus
+
+	// failures is the number of consecutive failures of the node
+	failures int
+
+	// retryAt is the time after which an unhealthy node is tried again
+	retryAt time.Time
+}
+
+// rank returns the order in which the node is tried. An unhealthy node whose
+// backoff has expired is tried again as a healthy one.
+func (n *multiNode) rank(now time.Time) NodeStatus {
+	if n.status == NodeStatusUnhealthy && !now.Before(n.retryAt) {
+		return NodeStatusHealthy
+	}
+	return n.status
+}
+
+// MultiClient is a client over multiple beacon nodes. The requests of the
+// embedded Client are sent to the healthiest node and fail over to the next one
+// if the node is unavailable or times out. A failed node is tried again after a
+// backoff. Blocks and the operations of the pool (i.e. attestations) are published
+// to all the nodes.
+type MultiClient struct {
+	*Client
+
+	// httpClient sends the requests to the nodes
+	httpClient *http.Client
+
+	// streamClient sends the event stream requests to the nodes. It only uses
+	// the transport of httpClient since its timeout would close the stream.
+	streamClient *http.Client
+
+	lock  sync.Mutex
+	nodes []*multiNode
+}
+
+func NewMultiClient(urls []string, opts ...ConfigOption) (*MultiClient, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no beacon node urls")
+	}
+
+	m := &MultiClient{}
+	for _, url := range urls {
+		m.nodes = append(m.nodes, &multiNode{
+			url:    strings.TrimSuffix(url, "/"),
+			client: New(url, opts...),
+		})
+	}
+
+	clt := New(multiClientURL, opts...)
+	m.httpClient = clt.config.httpClient
+	m.streamClient = &http.Client{Transport: m.httpClient.Transport}
+	clt.config.httpClient = &http.Client{Transport: &multiTransport{m: m}}
+	m.Client = clt
+
+	return m, nil
+}
+
+// Quorum returns a client whose read requests only succeed if at
+// least k nodes return the same response.
+func (m *MultiClient) Quorum(k int) *Client {
+	config := *m.Client.config
+	config.httpClient = &http.Client{Transport: &multiTransport{m: m, quorum: k}}
+
+	return &Client{url: m.Client.url, config: &config}
+}
+
+// NodeStatus returns the status of each node
+func (m *MultiClient) NodeStatus() map[string]NodeStatus {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	res := map[string]NodeStatus{}
+	for _, node := range m.nodes {
+		res[node.url] = node.status
+	}
+	return res
+}
+
+// CheckHealth updates the status of the nodes with the health and syncing endpoints
+func (m *MultiClient) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, node := range m.nodes {
+		wg.Add(1)
+
+		go func(node *multiNode) {
+			defer wg.Done()
+			m.setStatus(node, checkNodeStatus(ctx, node.client))
+		}(node)
+	}
+	wg.Wait()
+}
+
+func checkNodeStatus(ctx context.Context, clt *Client) NodeStatus {
+	healthy, err := clt.Node().HealthWithContext(ctx)
+	if errors.Is(err, ErrorIncompleteData) {
+		return NodeStatusSyncing
+	}
+	if err != nil || !healthy {
+		return NodeStatusUnhealthy
+	}
+
+	syncing, err := clt.Node().SyncingWithContext(ctx)
+	if err != nil {
+		return NodeStatusUnhealthy
+	}
+	if syncing.IsSyncing || syncing.IsOptimistic {
+		return NodeStatusSyncing
+	}
+	return NodeStatusHealthy
+}
+
+func (m *MultiClient) setStatus(node *multiNode, status NodeStatus) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if status == NodeStatusUnhealthy {
+		m.markFailed(node)
+		return
+	}
+	node.status = status
+	node.failures = 0
+}
+
+// markFailed sets the node as unhealthy until its backoff expires. The lock must be held.
+func (m *MultiClient) markFailed(node *multiNode) {
+	backoff := minNodeBackoff << node.failures
+	if backoff > maxNodeBackoff || backoff <= 0 {
+		backoff = maxNodeBackoff
+	} else {
+		node.failures++
+	}
+
+	node.status = NodeStatusUnhealthy
+	node.retryAt = time.Now().Add(backoff)
+}
+
+// setAvailable admits back an unhealthy node once it serves a request
+func (m *MultiClient) setAvailable(node *multiNode) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if node.status == NodeStatusUnhealthy {
+		node.status = NodeStatusHealthy
+	}
+	node.failures = 0
+}
+
+// selectNodes returns the nodes sorted by their status
+func (m *MultiClient) selectNodes() []*multiNode {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	nodes := make([]*multiNode, len(m.nodes))
+	copy(nodes, m.nodes)
+
+	now := time.Now()
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].rank(now) < nodes[j].rank(now)
+	})
+	return nodes
+}
+
+// do sends the request to the node
+func (m *MultiClient) do(req *http.Request, node *multiNode) (*http.Response, error) {
+	u, err := url.Parse(node.url + req.URL.RequestURI())
+	if err != nil {
+		return nil, err
+	}
+
+	nodeReq := req.Clone(req.Context())
+	nodeReq.URL = u
+	nodeReq.Host = ""
+
+	if req.GetBody != nil {
+		// each node reads its own copy of the body
+		if nodeReq.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	if isEventStream(req) {
+		return m.streamClient.Do(nodeReq)
+	}
+	return m.httpClient.Do(nodeReq)
+}
+
+// isEventStream returns whether the request subscribes to the event stream
+func isEventStream(req *http.Request) bool {
+	return req.Header.Get("Accept") == "text/event-stream"
+}
+
+// failover sends the request to the nodes in order until one of them is available
+func (m *MultiClient) failover(req *http.Request, nodes []*multiNode) (*http.Response, error) {
+	var lastResp *http.Response
+	var lastErr error
+
+	for _, node := range nodes {
+		resp, err := m.do(req, node)
+		if err == nil && resp.StatusCode != http.StatusServiceUnavailable {
+			if lastResp != nil {
+				lastResp.Body.Close()
+			}
+			m.setAvailable(node)
+			return resp, nil
+		}
+		if req.Context().Err() != nil {
+			// the request was cancelled, not a failure of the node
+			if resp != nil {
+				resp.Body.Close()
+			}
+			return nil, req.Context().Err()
+		}
+
+		m.setStatus(node, NodeStatusUnhealthy)
+		if lastResp != nil {
+			lastResp.Body.Close()
+		}
+		lastResp, lastErr = resp, err
+	}
+
+	if lastResp != nil {
+		return lastResp, nil
+	}
+	return nil, lastErr
+}
+
+type multiResult struct {
+	index int
+	resp  *http.Response
+	data  []byte
+	err   error
+}
+
+// broadcast sends the request to all the nodes and returns the first successful
+// response. The requests to the other nodes finish in the background, even if the
+// context of the request is cancelled once the broadcast returns.
+func (m *MultiClient) broadcast(req *http.Request, nodes []*multiNode) (*http.Response, error) {
+	// each request has its own context so that the returned response
+	// is not cancelled with the others
+	cancelFns := make([]context.CancelFunc, len(nodes))
+
+	resCh := make(chan *multiResult, len(nodes))
+	for i, node := range nodes {
+		var ctx context.Context
+		ctx, cancelFns[i] = context.WithCancel(withoutCancel{req.Context()})
+
+		go func(i int, ctx context.Context, node *multiNode) {
+			resp, err := m.do(req.WithContext(ctx), node)
+			resCh <- &multiResult{index: i, resp: resp, err: err}
+		}(i, ctx, node)
+	}
+
+	// release closes the responses of the pending requests and cancels all
+	// the requests but the returned one
+	release := func(pending int, keep *multiResult) {
+		for i := 0; i < pending; i++ {
+			if res := <-resCh; res.resp != nil {
+				res.resp.Body.Close()
+			}
+		}
+		for i, cancelFn := range cancelFns {
+			if keep == nil || keep.resp == nil || keep.index != i {
+				cancelFn()
+			}
+		}
+	}
+
+	// if no node succeeds it returns the first response, otherwise the first error
+	var res *multiResult
+	for i := range nodes {
+		var result *multiResult
+		select {
+		case result = <-resCh:
+		case <-req.Context().Done():
+			// the request is cancelled before any node succeeds
+			for _, cancelFn := range cancelFns {
+				cancelFn()
+			}
+			if res != nil && res.resp != nil {
+				res.resp.Body.Close()
+			}
+			go release(len(nodes)-i, nil)
+			return nil, req.Context().Err()
+		}
+
+		if result.err == nil && result.resp.StatusCode/100 == 2 {
+			if res != nil && res.resp != nil {
+				res.resp.Body.Close()
+			}
+			go release(len(nodes)-i-1, result)
+			return result.resp, nil
+		}
+		if res == nil || (res.resp == nil && result.resp != nil) {
+			if res != nil && res.resp != nil {
+				res.resp.Body.Close()
+			}
+			res = result
+		} else if result.resp != nil {
+			result.resp.Body.Close()
+		}
+	}
+	release(0, res)
+
+	if res.resp == nil {
+		return nil, res.err
+	}
+	return res.resp, nil
+}
+
+// withoutCancel is a context with the values of the parent that is
+// never cancelled
+type withoutCancel struct {
+	ctx context.Context
+}
+
+func (withoutCancel) Deadline() (deadline time.Time, ok bool) { return }
+func (withoutCancel) Done() <-chan struct{}                   { return nil }
+func (withoutCancel) Err() error                              { return nil }
+func (c withoutCancel) Value(key interface{}) interface{}     { return c.ctx.Value(key) }
+
+// quorumRead sends the request to all the nodes and returns the response
+// once k of them return the same one
+func (m *MultiClient) quorumRead(req *http.Request, nodes []*multiNode, k int) (*http.Response, error) {
+	if k > len(nodes) {
+		return nil, fmt.Errorf("%w: quorum of %d with %d nodes", ErrorQuorumNotReached, k, len(nodes))
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	resCh := make(chan *multiResult, len(nodes))
+	for _, node := range nodes {
+		go func(node *multiNode) {
+			resp, err := m.do(req.WithContext(ctx), node)
+			if err != nil {
+				resCh <- &multiResult{err: err}
+				return
+			}
+			defer resp.Body.Close()
+
+			data, err := io.ReadAll(resp.Body)
+			resCh <- &multiResult{resp: resp, data: data, err: err}
+		}(node)
+	}
+
+	votes := map[string]int{}
+	for range nodes {
+		res := <-resCh
+		if res.err != nil || res.resp.StatusCode != http.StatusOK {
+			continue
+		}
+
+		key := quorumKey(res.resp, res.data)
+		votes[key]++
+
+		if votes[key] >= k {
+			res.resp.Body = io.NopCloser(bytes.NewReader(res.data))
+			return res.resp, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d nodes do not agree", ErrorQuorumNotReached, k)
+}
+
+// quorumKey returns the key to compare the responses of the nodes. Json
+// responses are normalized since each client formats them differently.
+func quorumKey(resp *http.Response, data []byte) string {
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), contentTypeSSZ) {
+		return string(data)
+	}
+
+	var obj interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return string(data)
+	}
+	key, err := json.Marshal(obj)
+	if err != nil {
+		return string(data)
+	}
+	return string(key)
+}
+
+// multiTransport routes the requests of the multi client to the nodes
+type multiTransport struct {
+	m      *MultiClient
+	quorum int
+}
+
+func (t *multiTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		// the nodes read the body with GetBody
+		defer req.Body.Close()
+	}
+	nodes := t.m.selectNodes()
+
+	if req.Method == http.MethodPost {
+		if _, ok := broadcastPaths[req.URL.Path]; ok {
+			return t.m.broadcast(req, nodes)
+		}
+	}
+	if req.Method == http.MethodGet && t.quorum > 1 && !isEventStream(req) {
+		return t.m.quorumRead(req, nodes, t.quorum)
+	}
+	return t.m.failover(req, nodes)
+}
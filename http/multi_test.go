@@ -0,0 +1,276 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+type mockNode struct {
+	*httptest.Server
+
+	hits   int32
+	status int
+}
+
+func newMockNode(t *testing.T, handler func(m *http.ServeMux)) *mockNode {
+	node := &mockNode{}
+
+	m := http.NewServeMux()
+	if handler != nil {
+		handler(m)
+	}
+	node.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&node.hits, 1)
+		if node.status != 0 {
+			w.WriteHeader(node.status)
+			w.Write([]byte(`{"code": 503, "message": "unavailable"}`))
+			return
+		}
+		m.ServeHTTP(w, r)
+	}))
+
+	t.Cleanup(node.Close)
+	return node
+}
+
+func versionHandler(version string) func(m *http.ServeMux) {
+	return func(m *http.ServeMux) {
+		m.HandleFunc("/eth/v1/node/version", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"data": {"version": "` + version + `"}}`))
+		})
+	}
+}
+
+func TestMultiClient_Failover(t *testing.T) {
+	node1 := newMockNode(t, versionHandler("a"))
+	node1.status = http.StatusServiceUnavailable
+	node2 := newMockNode(t, versionHandler("b"))
+
+	clt, err := NewMultiClient([]string{node1.URL, node2.URL})
+	require.NoError(t, err)
+
+	version, err := clt.Node().Version()
+	require.NoError(t, err)
+	require.Equal(t, "b", version)
+	require.Equal(t, NodeStatusUnhealthy, clt.NodeStatus()[node1.URL])
+
+	// the unhealthy node is not used first anymore
+	version, err = clt.Node().Version()
+	require.NoError(t, err)
+	require.Equal(t, "b", version)
+	require.Equal(t, int32(1), atomic.LoadInt32(&node1.hits))
+
+	// the node is tried again once its backoff expires and admitted back if it is available
+	node1.status = 0
+	clt.nodes[0].retryAt = time.Time{}
+
+	version, err = clt.Node().Version()
+	require.NoError(t, err)
+	require.Equal(t, "a", version)
+	require.Equal(t, NodeStatusHealthy, clt.NodeStatus()[node1.URL])
+
+	// all the nodes are unavailable
+	node1.status = http.StatusServiceUnavailable
+	node2.status = http.StatusServiceUnavailable
+	_, err = clt.Node().Version()
+	require.ErrorIs(t, err, ErrorServiceUnavailable)
+}
+
+func TestMultiClient_Timeout(t *testing.T) {
+	node1 := newMockNode(t, func(m *http.ServeMux) {
+		m.HandleFunc("/eth/v1/node/version", func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		})
+	})
+	node2 := newMockNode(t, versionHandler("b"))
+
+	clt, err := NewMultiClient([]string{node1.URL, node2.URL}, WithHTTPClient(&http.Client{Timeout: 100 * time.Millisecond}))
+	require.NoError(t, err)
+
+	version, err := clt.Node().Version()
+	require.NoError(t, err)
+	require.Equal(t, "b", version)
+
+	// the cancellation of the request is not a failover
+	clt, err = NewMultiClient([]string{node1.URL, node2.URL})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = clt.Node().VersionWithContext(ctx)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.Equal(t, NodeStatusHealthy, clt.NodeStatus()[node1.URL])
+}
+
+func TestMultiClient_CheckHealth(t *testing.T) {
+	healthHandler := func(code int, syncing bool) func(m *http.ServeMux) {
+		return func(m *http.ServeMux) {
+			m.HandleFunc("/eth/v1/node/health", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(code)
+			})
+			m.HandleFunc("/eth/v1/node/syncing", func(w http.ResponseWriter, r *http.Request) {
+				if syncing {
+					w.Write([]byte(`{"data": {"head_slot": "1", "sync_distance": "1", "is_syncing": true, "is_optimistic": false}}`))
+				} else {
+					w.Write([]byte(`{"data": {"head_slot": "1", "sync_distance": "0", "is_syncing": false, "is_optimistic": false}}`))
+				}
+			})
+			versionHandler("")(m)
+		}
+	}
+
+	node1 := newMockNode(t, healthHandler(http.StatusPartialContent, true))
+	node2 := newMockNode(t, healthHandler(http.StatusOK, true))
+	node3 := newMockNode(t, healthHandler(http.StatusOK, false))
+	node4 := newMockNode(t, healthHandler(http.StatusServiceUnavailable, false))
+
+	clt, err := NewMultiClient([]string{node1.URL, node2.URL, node3.URL, node4.URL})
+	require.NoError(t, err)
+
+	clt.CheckHealth(context.Background())
+	require.Equal(t, map[string]NodeStatus{
+		node1.URL: NodeStatusSyncing,
+		node2.URL: NodeStatusSyncing,
+		node3.URL: NodeStatusHealthy,
+		node4.URL: NodeStatusUnhealthy,
+	}, clt.NodeStatus())
+
+	// the requests go to the healthy node
+	_, err = clt.Node().Version()
+	require.NoError(t, err)
+	require.Equal(t, int32(3), atomic.LoadInt32(&node3.hits))
+}
+
+func TestMultiClient_Broadcast(t *testing.T) {
+	attestationsHandler := func(m *http.ServeMux) {
+		m.HandleFunc("/eth/v1/beacon/pool/attestations", func(w http.ResponseWriter, r *http.Request) {
+			var attestations []*consensus.Attestation
+			require.NoError(t, Unmarshal(readBody(t, r), &attestations, false))
+			require.Len(t, attestations, 1)
+		})
+	}
+
+	release := make(chan struct{})
+	published := make(chan struct{})
+
+	node1 := newMockNode(t, attestationsHandler)
+	node2 := newMockNode(t, func(m *http.ServeMux) {
+		m.HandleFunc("/eth/v1/beacon/pool/attestations", func(w http.ResponseWriter, r *http.Request) {
+			// the slow node publishes after the broadcast returns
+			<-release
+			require.NotEmpty(t, readBody(t, r))
+			close(published)
+		})
+	})
+	node3 := newMockNode(t, attestationsHandler)
+	node3.status = http.StatusServiceUnavailable
+
+	clt, err := NewMultiClient([]string{node1.URL, node2.URL, node3.URL})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	attestation := &consensus.Attestation{Data: &consensus.AttestationData{Source: &consensus.Checkpoint{}, Target: &consensus.Checkpoint{}}}
+	require.NoError(t, clt.Beacon().PublishAttestationsWithContext(ctx, []*consensus.Attestation{attestation}))
+
+	// the cancellation of the returned request does not stop the slow node
+	cancel()
+	close(release)
+
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("attestation not published to the slow node")
+	}
+	for _, node := range []*mockNode{node1, node2, node3} {
+		require.Equal(t, int32(1), atomic.LoadInt32(&node.hits))
+	}
+
+	// fails only if all the nodes fail
+	node1.status = http.StatusServiceUnavailable
+	node2.status = http.StatusServiceUnavailable
+	require.ErrorIs(t, clt.Beacon().PublishAttestations([]*consensus.Attestation{attestation}), ErrorServiceUnavailable)
+}
+
+func TestMultiClient_Subscribe(t *testing.T) {
+	node := newMockNode(t, func(m *http.ServeMux) {
+		m.HandleFunc("/eth/v1/events", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.(http.Flusher).Flush()
+
+			// the event is sent after the timeout of the http client
+			time.Sleep(200 * time.Millisecond)
+			fmt.Fprint(w, "id: 1\nevent: head\ndata: {\"slot\": \"10\"}\n\n")
+			w.(http.Flusher).Flush()
+
+			<-r.Context().Done()
+		})
+	})
+
+	clt, err := NewMultiClient([]string{node.URL}, WithHTTPClient(&http.Client{Timeout: 100 * time.Millisecond}))
+	require.NoError(t, err)
+
+	sub, err := clt.Subscribe(context.Background(), []string{"head"})
+	require.NoError(t, err)
+	defer sub.Close()
+
+	select {
+	case head := <-sub.Head:
+		require.Equal(t, uint64(10), head.Slot)
+	case err := <-sub.Err():
+		t.Fatal(err)
+	case <-time.After(time.Second):
+		t.Fatal("event not received")
+	}
+}
+
+func TestMultiClient_Quorum(t *testing.T) {
+	checkpointsHandler := func(epoch string, spaces bool) func(m *http.ServeMux) {
+		return func(m *http.ServeMux) {
+			m.HandleFunc("/eth/v1/beacon/states/head/finality_checkpoints", func(w http.ResponseWriter, r *http.Request) {
+				checkpoint := `{"epoch":"` + epoch + `","root":"0x0000000000000000000000000000000000000000000000000000000000000000"}`
+				if spaces {
+					w.Write([]byte(`{ "data": { "previous_justified": ` + checkpoint + `, "current_justified": ` + checkpoint + `, "finalized": ` + checkpoint + ` } }`))
+				} else {
+					w.Write([]byte(`{"data":{"finalized":` + checkpoint + `,"previous_justified":` + checkpoint + `,"current_justified":` + checkpoint + `}}`))
+				}
+			})
+		}
+	}
+
+	node1 := newMockNode(t, checkpointsHandler("1", false))
+	node2 := newMockNode(t, checkpointsHandler("2", false))
+	node3 := newMockNode(t, checkpointsHandler("1", true))
+
+	clt, err := NewMultiClient([]string{node1.URL, node2.URL, node3.URL})
+	require.NoError(t, err)
+
+	checkpoints, err := clt.Quorum(2).Beacon().GetFinalityCheckpoints(Head)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), checkpoints.FinalizedCheckpoint.Epoch)
+
+	_, err = clt.Quorum(3).Beacon().GetFinalityCheckpoints(Head)
+	require.ErrorIs(t, err, ErrorQuorumNotReached)
+
+	_, err = clt.Quorum(4).Beacon().GetFinalityCheckpoints(Head)
+	require.ErrorIs(t, err, ErrorQuorumNotReached)
+}
+
+func readBody(t *testing.T, r *http.Request) []byte {
+	data, err := io.ReadAll(r.Body)
+	require.NoError(t, err)
+	return data
+}
@@ -1,5 +1,7 @@
 package http
 
+import "context"
+
 type NodeEndpoint struct {
 	c *Client
 }
@@ -24,8 +26,12 @@ type IdentityMetadata struct {
 
 // Identity returns the node network identity
 func (n *NodeEndpoint) Identity() (*Identity, error) {
+	return n.IdentityWithContext(context.Background())
+}
+
+func (n *NodeEndpoint) IdentityWithContext(ctx context.Context) (*Identity, error) {
 	var out *Identity
-	err := n.c.Get("/eth/v1/node/identity", &out)
+	err := n.c.GetWithContext(ctx, "/eth/v1/node/identity", &out)
 	return out, err
 }
 
@@ -38,14 +44,22 @@ type Peer struct {
 }
 
 func (n *NodeEndpoint) Peers() ([]*Peer, error) {
+	return n.PeersWithContext(context.Background())
+}
+
+func (n *NodeEndpoint) PeersWithContext(ctx context.Context) ([]*Peer, error) {
 	var peers []*Peer
-	err := n.c.Get("/eth/v1/node/peers", &peers)
+	err := n.c.GetWithContext(ctx, "/eth/v1/node/peers", &peers)
 	return peers, err
 }
 
 func (n *NodeEndpoint) GetPeer(peerID string) (*Peer, error) {
+	return n.GetPeerWithContext(context.Background(), peerID)
+}
+
+func (n *NodeEndpoint) GetPeerWithContext(ctx context.Context, peerID string) (*Peer, error) {
 	var peer *Peer
-	err := n.c.Get("/eth/v1/node/peers/"+peerID, &peer)
+	err := n.c.GetWithContext(ctx, "/eth/v1/node/peers/"+peerID, &peer)
 	return peer, err
 }
 
@@ -57,16 +71,24 @@ type PeerCount struct {
 }
 
 func (n *NodeEndpoint) PeerCount() (*PeerCount, error) {
+	return n.PeerCountWithContext(context.Background())
+}
+
+func (n *NodeEndpoint) PeerCountWithContext(ctx context.Context) (*PeerCount, error) {
 	var peerCount *PeerCount
-	err := n.c.Get("/eth/v1/node/peer_count", &peerCount)
+	err := n.c.GetWithContext(ctx, "/eth/v1/node/peer_count", &peerCount)
 	return peerCount, err
 }
 
 func (n *NodeEndpoint) Version() (string, error) {
+	return n.VersionWithContext(context.Background())
+}
+
+func (n *NodeEndpoint) VersionWithContext(ctx context.Context) (string, error) {
 	var out struct {
 		Version string `json:"version"`
 	}
-	err := n.c.Get("/eth/v1/node/version", &out)
+	err := n.c.GetWithContext(ctx, "/eth/v1/node/version", &out)
 	return out.Version, err
 }
 
@@ -78,12 +100,20 @@ type Syncing struct {
 }
 
 func (n *NodeEndpoint) Syncing() (*Syncing, error) {
+	return n.SyncingWithContext(context.Background())
+}
+
+func (n *NodeEndpoint) SyncingWithContext(ctx context.Context) (*Syncing, error) {
 	var out Syncing
-	err := n.c.Get("/eth/v1/node/syncing", &out)
+	err := n.c.GetWithContext(ctx, "/eth/v1/node/syncing", &out)
 	return &out, err
 }
 
 func (n *NodeEndpoint) Health() (bool, error) {
-	status, err := n.c.Status("/eth/v1/node/health")
+	return n.HealthWithContext(context.Background())
+}
+
+func (n *NodeEndpoint) HealthWithContext(ctx context.Context) (bool, error) {
+	status, err := n.c.StatusWithContext(ctx, "/eth/v1/node/health")
 	return status, err
 }
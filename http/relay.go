@@ -0,0 +1,114 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	consensus "github.com/umbracle/go-eth-consensus"
+	"github.com/umbracle/go-eth-consensus/chaintime"
+)
+
+// VerifyBid checks that the bid is signed by the builder in the bid
+func VerifyBid(bid *VersionedSignedBuilderBid, spec *consensus.Spec) error {
+	msg, err := bid.Message()
+	if err != nil {
+		return err
+	}
+	return verifyBuilderSignature(spec, msg, bid.Pubkey(), bid.Signature())
+}
+
+var ErrorNoBid = fmt.Errorf("no valid bid")
+
+// defaultRelayCutoff is the time after the start of the slot to wait for the bids
+const defaultRelayCutoff = 1 * time.Second
+
+// RelaySet queries a set of relays for the bid of a slot and picks the most valuable one
+type RelaySet struct {
+	spec   *consensus.Spec
+	chain  *chaintime.Chaintime
+	relays []*BuilderEndpoint
+
+	// Cutoff is the time after the start of the slot after which the
+	// bids of the relays are discarded
+	Cutoff time.Duration
+}
+
+func NewRelaySet(urls []string, spec *consensus.Spec, genesis time.Time, opts ...ConfigOption) (*RelaySet, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no relay urls")
+	}
+
+	r := &RelaySet{
+		spec:   spec,
+		chain:  chaintime.New(genesis, spec.SecondsPerSlot, spec.SlotsPerEpoch),
+		Cutoff: defaultRelayCutoff,
+	}
+	for _, url := range urls {
+		r.relays = append(r.relays, New(url, opts...).Builder())
+	}
+	return r, nil
+}
+
+// GetHeader requests the bid of the slot to all the relays and returns the one with the
+// highest value. Bids that arrive after the cutoff, are signed by a different builder
+// or do not build on top of the parent hash are discarded. It returns ErrorNoBid if
+// there are no valid bids.
+func (r *RelaySet) GetHeader(ctx context.Context, slot uint64, parentHash [32]byte, pubKey [48]byte) (*VersionedSignedBuilderBid, error) {
+	deadline := r.chain.Slot(slot).Time.Add(r.Cutoff)
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		lock sync.Mutex
+		best *VersionedSignedBuilderBid
+	)
+	for _, relay := range r.relays {
+		wg.Add(1)
+
+		go func(relay *BuilderEndpoint) {
+			defer wg.Done()
+
+			bid, err := relay.GetHeaderWithContext(ctx, slot, parentHash, pubKey)
+			if err != nil {
+				return
+			}
+			if err := r.validateBid(bid, slot, parentHash); err != nil {
+				relay.c.config.logger.Printf("[DEBUG]: discarded bid for slot %d: %v", slot, err)
+				return
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			// the deadline might have passed while the bid was verified
+			if ctx.Err() != nil {
+				return
+			}
+			if best == nil || bid.Value().Big().Cmp(best.Value().Big()) > 0 {
+				best = bid
+			}
+		}(relay)
+	}
+	wg.Wait()
+
+	if best == nil {
+		return nil, ErrorNoBid
+	}
+	return best, nil
+}
+
+func (r *RelaySet) validateBid(bid *VersionedSignedBuilderBid, slot uint64, parentHash [32]byte) error {
+	if expected := r.spec.VersionAtSlot(slot); bid.Version != expected {
+		return fmt.Errorf("bid of version %s, expected %s", bid.Version, expected)
+	}
+	if bid.ParentHash() != parentHash {
+		return fmt.Errorf("bid for parent hash 0x%x", bid.ParentHash())
+	}
+	if bid.Value().Big().Sign() == 0 {
+		return fmt.Errorf("bid with zero value")
+	}
+	return VerifyBid(bid, r.spec)
+}
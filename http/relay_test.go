@@ -0,0 +1,108 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	consensus "github.com/umbracle/go-eth-consensus"
+	"github.com/umbracle/go-eth-consensus/bls"
+)
+
+var relayTestSpec = &consensus.Spec{
+	SecondsPerSlot:       12,
+	SlotsPerEpoch:        32,
+	GenesisForkVersion:   consensus.Domain{0x1},
+	AltairForkVersion:    consensus.Domain{0x2},
+	BellatrixForkVersion: consensus.Domain{0x3},
+	CapellaForkVersion:   consensus.Domain{0x4},
+	DenebForkVersion:     consensus.Domain{0x5},
+}
+
+func newSignedBid(t *testing.T, key *bls.Key, value uint64, parentHash [32]byte) *VersionedSignedBuilderBid {
+	var val consensus.Uint256
+	require.NoError(t, val.UnmarshalText([]byte(fmt.Sprint(value))))
+
+	msg := &BuilderBidDeneb{
+		Header: &consensus.ExecutionPayloadHeaderDeneb{ParentHash: parentHash, ExtraData: []byte{0x1}},
+		Value:  val,
+		Pubkey: key.PubKey(),
+	}
+
+	signature, err := signBuilderMessage(relayTestSpec, key, msg)
+	require.NoError(t, err)
+
+	return &VersionedSignedBuilderBid{
+		Version: consensus.VersionDeneb,
+		Deneb:   &SignedBuilderBidDeneb{Message: msg, Signature: signature},
+	}
+}
+
+func TestVerifyBid(t *testing.T) {
+	bid := newSignedBid(t, bls.NewRandomKey(), 10, [32]byte{})
+	require.NoError(t, VerifyBid(bid, relayTestSpec))
+
+	// the signature does not cover the modified value
+	bid.Deneb.Message.Value[0]++
+	require.Error(t, VerifyBid(bid, relayTestSpec))
+
+	// the bid is signed for another network
+	bid.Deneb.Message.Value[0]--
+	spec := *relayTestSpec
+	spec.GenesisForkVersion = consensus.Domain{0x2}
+	require.Error(t, VerifyBid(bid, &spec))
+}
+
+func relayHandler(t *testing.T, bid *VersionedSignedBuilderBid, delay time.Duration) func(m *http.ServeMux) {
+	return func(m *http.ServeMux) {
+		m.HandleFunc("/eth/v1/builder/header/", func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(delay)
+
+			data, err := Marshal(bid.Object())
+			require.NoError(t, err)
+			fmt.Fprintf(w, `{"version": "%s", "data": %s}`, bid.Version, data)
+		})
+	}
+}
+
+func TestRelaySet_GetHeader(t *testing.T) {
+	parentHash := [32]byte{0x1}
+
+	badSignature := newSignedBid(t, bls.NewRandomKey(), 30, parentHash)
+	badSignature.Deneb.Signature = newSignedBid(t, bls.NewRandomKey(), 30, parentHash).Deneb.Signature
+
+	relays := []*mockNode{
+		newMockNode(t, relayHandler(t, newSignedBid(t, bls.NewRandomKey(), 10, parentHash), 0)),
+		newMockNode(t, relayHandler(t, newSignedBid(t, bls.NewRandomKey(), 20, parentHash), 0)),
+		// invalid bids
+		newMockNode(t, relayHandler(t, badSignature, 0)),
+		newMockNode(t, relayHandler(t, newSignedBid(t, bls.NewRandomKey(), 40, [32]byte{0x2}), 0)),
+		// late bid
+		newMockNode(t, relayHandler(t, newSignedBid(t, bls.NewRandomKey(), 50, parentHash), time.Second)),
+	}
+	urls := []string{}
+	for _, relay := range relays {
+		urls = append(urls, relay.URL)
+	}
+
+	// the slot 1 starts now
+	genesis := time.Now().Add(-12 * time.Second)
+
+	set, err := NewRelaySet(urls, relayTestSpec, genesis)
+	require.NoError(t, err)
+	set.Cutoff = 500 * time.Millisecond
+
+	bid, err := set.GetHeader(context.Background(), 1, parentHash, [48]byte{})
+	require.NoError(t, err)
+	require.Equal(t, "20", bid.Value().Big().String())
+
+	// there are no bids after the cutoff
+	set.Cutoff = -1 * time.Second
+
+	_, err = set.GetHeader(context.Background(), 1, parentHash, [48]byte{})
+	require.True(t, errors.Is(err, ErrorNoBid))
+}
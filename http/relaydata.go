@@ -0,0 +1,209 @@
+package http
+
+import (
+	"context"
+	"encoding/hex"
+	"net/url"
+
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+// RelayDataEndpoint is the data api of the mev-boost relays. It exposes the
+// bids received and delivered by the relay and the registrations of the validators.
+type RelayDataEndpoint struct {
+	c *Client
+}
+
+func (c *Client) RelayData() *RelayDataEndpoint {
+	return &RelayDataEndpoint{c: c}
+}
+
+// BidTrace is a bid of a builder for a slot
+type BidTrace struct {
+	Slot                 uint64            `json:"slot"`
+	ParentHash           [32]byte          `json:"parent_hash"`
+	BlockHash            [32]byte          `json:"block_hash"`
+	BuilderPubkey        [48]byte          `json:"builder_pubkey"`
+	ProposerPubkey       [48]byte          `json:"proposer_pubkey"`
+	ProposerFeeRecipient [20]byte          `json:"proposer_fee_recipient"`
+	GasLimit             uint64            `json:"gas_limit"`
+	GasUsed              uint64            `json:"gas_used"`
+	Value                consensus.Uint256 `json:"value"`
+	NumTx                uint64            `json:"num_tx"`
+	BlockNumber          uint64            `json:"block_number"`
+}
+
+// ReceivedBidTrace is a bid submitted by a builder to the relay
+type ReceivedBidTrace struct {
+	BidTrace `json:",squash"`
+
+	Timestamp            uint64 `json:"timestamp"`
+	TimestampMs          uint64 `json:"timestamp_ms"`
+	OptimisticSubmission bool   `json:"optimistic_submission"`
+}
+
+// PayloadsDeliveredFilter filters the payloads delivered by the relay. Nil and empty fields do not filter.
+type PayloadsDeliveredFilter struct {
+	Slot        *uint64
+	BlockNumber *uint64
+	BlockHash   [32]byte
+
+	ProposerPubkey [48]byte
+	BuilderPubkey  [48]byte
+
+	// Cursor is the slot from which to return the payloads (inclusive, descending)
+	Cursor *uint64
+
+	// Limit is the maximum number of payloads to return
+	Limit *uint64
+
+	// OrderBy sorts the payloads by value ("value" or "-value") instead of by slot
+	OrderBy string
+}
+
+func (f *PayloadsDeliveredFilter) query() url.Values {
+	query := url.Values{}
+	setUintQuery(query, "slot", f.Slot)
+	setUintQuery(query, "block_number", f.BlockNumber)
+	setHexQuery(query, "block_hash", f.BlockHash[:])
+	setHexQuery(query, "proposer_pubkey", f.ProposerPubkey[:])
+	setHexQuery(query, "builder_pubkey", f.BuilderPubkey[:])
+	setUintQuery(query, "cursor", f.Cursor)
+	setUintQuery(query, "limit", f.Limit)
+	if f.OrderBy != "" {
+		query.Set("order_by", f.OrderBy)
+	}
+	return query
+}
+
+// BlocksReceivedFilter filters the blocks submitted to the relay. Nil and empty fields do not filter.
+type BlocksReceivedFilter struct {
+	Slot          *uint64
+	BlockNumber   *uint64
+	BlockHash     [32]byte
+	BuilderPubkey [48]byte
+
+	// Limit is the maximum number of blocks to return
+	Limit *uint64
+}
+
+func (f *BlocksReceivedFilter) query() url.Values {
+	query := url.Values{}
+	setUintQuery(query, "slot", f.Slot)
+	setUintQuery(query, "block_number", f.BlockNumber)
+	setHexQuery(query, "block_hash", f.BlockHash[:])
+	setHexQuery(query, "builder_pubkey", f.BuilderPubkey[:])
+	setUintQuery(query, "limit", f.Limit)
+	return query
+}
+
+// setHexQuery sets the value as a hex string unless it is empty
+func setHexQuery(query url.Values, key string, val []byte) {
+	for _, b := range val {
+		if b != 0 {
+			query.Set(key, "0x"+hex.EncodeToString(val))
+			return
+		}
+	}
+}
+
+// getRelayData requests an endpoint of the data api. The responses of the
+// data api are not wrapped in a data object.
+func (r *RelayDataEndpoint) getRelayData(ctx context.Context, path string, out interface{}) error {
+	raw, err := r.c.getRaw(ctx, path, contentTypeJSON)
+	if err != nil {
+		return err
+	}
+	return Unmarshal(raw.data, out, r.c.config.untrackedKeys)
+}
+
+// GetProposerPayloadsDelivered returns the payloads delivered by the relay to the proposers
+func (r *RelayDataEndpoint) GetProposerPayloadsDelivered(filter *PayloadsDeliveredFilter) ([]*BidTrace, error) {
+	return r.GetProposerPayloadsDeliveredWithContext(context.Background(), filter)
+}
+
+func (r *RelayDataEndpoint) GetProposerPayloadsDeliveredWithContext(ctx context.Context, filter *PayloadsDeliveredFilter) ([]*BidTrace, error) {
+	if filter == nil {
+		filter = &PayloadsDeliveredFilter{}
+	}
+	var out []*BidTrace
+	err := r.getRelayData(ctx, withQuery("/relay/v1/data/bidtraces/proposer_payload_delivered", filter.query()), &out)
+	return out, err
+}
+
+// GetBuilderBlocksReceived returns the blocks submitted by the builders to the relay
+func (r *RelayDataEndpoint) GetBuilderBlocksReceived(filter *BlocksReceivedFilter) ([]*ReceivedBidTrace, error) {
+	return r.GetBuilderBlocksReceivedWithContext(context.Background(), filter)
+}
+
+func (r *RelayDataEndpoint) GetBuilderBlocksReceivedWithContext(ctx context.Context, filter *BlocksReceivedFilter) ([]*ReceivedBidTrace, error) {
+	if filter == nil {
+		filter = &BlocksReceivedFilter{}
+	}
+	var out []*ReceivedBidTrace
+	err := r.getRelayData(ctx, withQuery("/relay/v1/data/bidtraces/builder_blocks_received", filter.query()), &out)
+	return out, err
+}
+
+// GetValidatorRegistration returns the latest registration of the validator in the relay
+func (r *RelayDataEndpoint) GetValidatorRegistration(pubkey [48]byte) (*SignedValidatorRegistration, error) {
+	return r.GetValidatorRegistrationWithContext(context.Background(), pubkey)
+}
+
+func (r *RelayDataEndpoint) GetValidatorRegistrationWithContext(ctx context.Context, pubkey [48]byte) (*SignedValidatorRegistration, error) {
+	query := url.Values{}
+	query.Set("pubkey", "0x"+hex.EncodeToString(pubkey[:]))
+
+	var out *SignedValidatorRegistration
+	err := r.getRelayData(ctx, withQuery("/relay/v1/data/validator_registration", query), &out)
+	return out, err
+}
+
+// PayloadsDeliveredIterator pages over the payloads delivered by the relay from
+// the newest to the oldest slot with the cursor of the data api.
+type PayloadsDeliveredIterator struct {
+	r      *RelayDataEndpoint
+	filter PayloadsDeliveredFilter
+	done   bool
+}
+
+// IterProposerPayloadsDelivered returns an iterator over the payloads delivered that
+// match the filter. The cursor of the filter is the slot of the first page and
+// OrderBy is ignored since the pages are sorted by slot.
+func (r *RelayDataEndpoint) IterProposerPayloadsDelivered(filter *PayloadsDeliveredFilter) *PayloadsDeliveredIterator {
+	it := &PayloadsDeliveredIterator{r: r}
+	if filter != nil {
+		it.filter = *filter
+	}
+	it.filter.OrderBy = ""
+	return it
+}
+
+// Next returns the next page of payloads. It returns an empty page once all
+// the payloads have been returned.
+func (i *PayloadsDeliveredIterator) Next() ([]*BidTrace, error) {
+	return i.NextWithContext(context.Background())
+}
+
+func (i *PayloadsDeliveredIterator) NextWithContext(ctx context.Context) ([]*BidTrace, error) {
+	if i.done {
+		return nil, nil
+	}
+	page, err := i.r.GetProposerPayloadsDeliveredWithContext(ctx, &i.filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(page) == 0 {
+		i.done = true
+		return nil, nil
+	}
+	last := page[len(page)-1].Slot
+	if last == 0 {
+		i.done = true
+	} else {
+		cursor := last - 1
+		i.filter.Cursor = &cursor
+	}
+	return page, nil
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func bidTraceJSON(slot uint64, value string) string {
+	return fmt.Sprintf(`{
+		"slot": "%d",
+		"parent_hash": "0x%s",
+		"block_hash": "0x01%s",
+		"builder_pubkey": "0x%s",
+		"proposer_pubkey": "0x02%s",
+		"proposer_fee_recipient": "0x%s",
+		"gas_limit": "30000000",
+		"gas_used": "100",
+		"value": "%s",
+		"num_tx": "5",
+		"block_number": "%d"
+	}`, slot, strings.Repeat("00", 32), strings.Repeat("00", 31), strings.Repeat("00", 48), strings.Repeat("00", 47), strings.Repeat("00", 20), value, slot+100)
+}
+
+func TestRelayDataEndpoint(t *testing.T) {
+	handler := func(m *http.ServeMux) {
+		m.HandleFunc("/relay/v1/data/bidtraces/proposer_payload_delivered", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "0x02"+strings.Repeat("00", 47), r.URL.Query().Get("proposer_pubkey"))
+			require.Equal(t, "2", r.URL.Query().Get("limit"))
+			require.Equal(t, "", r.URL.Query().Get("order_by"))
+
+			// the relay has payloads for the slots 1 to 5
+			cursor := uint64(5)
+			if str := r.URL.Query().Get("cursor"); str != "" {
+				var err error
+				cursor, err = strconv.ParseUint(str, 10, 64)
+				require.NoError(t, err)
+			}
+
+			items := []string{}
+			for slot := cursor; slot >= 1 && len(items) < 2; slot-- {
+				items = append(items, bidTraceJSON(slot, "1000000000000000000"))
+			}
+			fmt.Fprintf(w, "[%s]", strings.Join(items, ","))
+		})
+		m.HandleFunc("/relay/v1/data/bidtraces/builder_blocks_received", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "10", r.URL.Query().Get("slot"))
+			require.Equal(t, "", r.URL.Query().Get("block_hash"))
+
+			fmt.Fprintf(w, `[%s]`, strings.Replace(bidTraceJSON(10, "5"), "{", `{"timestamp": "1700000000", "timestamp_ms": "1700000000123", "optimistic_submission": true,`, 1))
+		})
+		m.HandleFunc("/relay/v1/data/validator_registration", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "0x01"+strings.Repeat("00", 47), r.URL.Query().Get("pubkey"))
+
+			fmt.Fprintf(w, `{"message": {"fee_recipient": "0x%s", "gas_limit": "30000000", "timestamp": "1700000000", "pubkey": "0x01%s"}, "signature": "0x%s"}`, strings.Repeat("00", 20), strings.Repeat("00", 47), strings.Repeat("00", 96))
+		})
+	}
+
+	addr := newMockHttpServer(t, handler)
+	n := New("http://" + addr).RelayData()
+
+	limit := uint64(2)
+	filter := &PayloadsDeliveredFilter{
+		ProposerPubkey: [48]byte{0x2},
+		Limit:          &limit,
+		OrderBy:        "-value",
+	}
+
+	t.Run("ProposerPayloadsDelivered", func(t *testing.T) {
+		filter := *filter
+		filter.OrderBy = ""
+
+		traces, err := n.GetProposerPayloadsDelivered(&filter)
+		require.NoError(t, err)
+		require.Len(t, traces, 2)
+		require.Equal(t, uint64(5), traces[0].Slot)
+		require.Equal(t, [32]byte{0x1}, traces[0].BlockHash)
+		require.Equal(t, uint64(30000000), traces[0].GasLimit)
+		require.Equal(t, uint64(105), traces[0].BlockNumber)
+		require.Equal(t, "1000000000000000000", traces[0].Value.Big().String())
+	})
+
+	t.Run("IterProposerPayloadsDelivered", func(t *testing.T) {
+		it := n.IterProposerPayloadsDelivered(filter)
+
+		slots := []uint64{}
+		for {
+			page, err := it.Next()
+			require.NoError(t, err)
+			if len(page) == 0 {
+				break
+			}
+			for _, trace := range page {
+				slots = append(slots, trace.Slot)
+			}
+		}
+		require.Equal(t, []uint64{5, 4, 3, 2, 1}, slots)
+	})
+
+	t.Run("BuilderBlocksReceived", func(t *testing.T) {
+		slot := uint64(10)
+		traces, err := n.GetBuilderBlocksReceived(&BlocksReceivedFilter{Slot: &slot})
+		require.NoError(t, err)
+		require.Len(t, traces, 1)
+		require.Equal(t, uint64(10), traces[0].Slot)
+		require.Equal(t, "5", traces[0].Value.Big().String())
+		require.Equal(t, uint64(1700000000123), traces[0].TimestampMs)
+		require.True(t, traces[0].OptimisticSubmission)
+	})
+
+	t.Run("ValidatorRegistration", func(t *testing.T) {
+		reg, err := n.GetValidatorRegistration([48]byte{0x1})
+		require.NoError(t, err)
+		require.Equal(t, uint64(30000000), reg.Message.GasLimit)
+		require.Equal(t, uint64(1700000000), reg.Message.Timestamp)
+		require.Equal(t, [48]byte{0x1}, reg.Message.Pubkey)
+	})
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"context"
+	"fmt"
+)
+
+type RewardsEndpoint struct {
+	c *Client
+}
+
+func (c *Client) Rewards() *RewardsEndpoint {
+	return &RewardsEndpoint{c: c}
+}
+
+// BlockRewards are the rewards in gwei of the proposer of a block
+type BlockRewards struct {
+	ProposerIndex     uint64 `json:"proposer_index"`
+	Total             uint64 `json:"total"`
+	Attestations      uint64 `json:"attestations"`
+	SyncAggregate     uint64 `json:"sync_aggregate"`
+	ProposerSlashings uint64 `json:"proposer_slashings"`
+	AttesterSlashings uint64 `json:"attester_slashings"`
+}
+
+func (r *RewardsEndpoint) GetBlockRewards(id BlockId) (*BlockRewards, error) {
+	return r.GetBlockRewardsWithContext(context.Background(), id)
+}
+
+func (r *RewardsEndpoint) GetBlockRewardsWithContext(ctx context.Context, id BlockId) (*BlockRewards, error) {
+	var out *BlockRewards
+	err := r.c.GetWithContext(ctx, "/eth/v1/beacon/rewards/blocks/"+id.BlockID(), &out)
+	return out, err
+}
+
+// IdealAttestationReward is the reward in gwei that a validator with the
+// effective balance gets for a perfect attestation
+type IdealAttestationReward struct {
+	EffectiveBalance uint64 `json:"effective_balance"`
+	Head             int64  `json:"head"`
+	Target           int64  `json:"target"`
+	Source           int64  `json:"source"`
+	InclusionDelay   uint64 `json:"inclusion_delay"`
+	Inactivity       int64  `json:"inactivity"`
+}
+
+// TotalAttestationReward is the reward in gwei of a validator for its
+// attestations. The penalties are negative.
+type TotalAttestationReward struct {
+	ValidatorIndex uint64 `json:"validator_index"`
+	Head           int64  `json:"head"`
+	Target         int64  `json:"target"`
+	Source         int64  `json:"source"`
+	InclusionDelay uint64 `json:"inclusion_delay"`
+	Inactivity     int64  `json:"inactivity"`
+}
+
+type AttestationRewards struct {
+	IdealRewards []*IdealAttestationReward `json:"ideal_rewards"`
+	TotalRewards []*TotalAttestationReward `json:"total_rewards"`
+}
+
+// GetAttestationRewards returns the attestation rewards of the epoch for the validators
+// with the given ids (indexes or public keys) or for all the validators if there are no ids
+func (r *RewardsEndpoint) GetAttestationRewards(epoch uint64, ids []string) (*AttestationRewards, error) {
+	return r.GetAttestationRewardsWithContext(context.Background(), epoch, ids)
+}
+
+func (r *RewardsEndpoint) GetAttestationRewardsWithContext(ctx context.Context, epoch uint64, ids []string) (*AttestationRewards, error) {
+	if ids == nil {
+		ids = []string{}
+	}
+	var out *AttestationRewards
+	err := r.c.PostWithContext(ctx, fmt.Sprintf("/eth/v1/beacon/rewards/attestations/%d", epoch), ids, &out)
+	return out, err
+}
+
+// SyncCommitteeReward is the reward in gwei of a validator of the sync committee.
+// The reward is negative if the validator missed the block.
+type SyncCommitteeReward struct {
+	ValidatorIndex uint64 `json:"validator_index"`
+	Reward         int64  `json:"reward"`
+}
+
+// GetSyncCommitteeRewards returns the sync committee rewards of the block for the validators
+// with the given ids (indexes or public keys) or for all the committee if there are no ids
+func (r *RewardsEndpoint) GetSyncCommitteeRewards(id BlockId, ids []string) ([]*SyncCommitteeReward, error) {
+	return r.GetSyncCommitteeRewardsWithContext(context.Background(), id, ids)
+}
+
+func (r *RewardsEndpoint) GetSyncCommitteeRewardsWithContext(ctx context.Context, id BlockId, ids []string) ([]*SyncCommitteeReward, error) {
+	if ids == nil {
+		ids = []string{}
+	}
+	var out []*SyncCommitteeReward
+	err := r.c.PostWithContext(ctx, "/eth/v1/beacon/rewards/sync_committee/"+id.BlockID(), ids, &out)
+	return out, err
+}
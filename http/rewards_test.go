@@ -0,0 +1,58 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewardsEndpoint(t *testing.T) {
+	handler := func(m *http.ServeMux) {
+		m.HandleFunc("/eth/v1/beacon/rewards/blocks/head", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"execution_optimistic": false, "finalized": false, "data": {"proposer_index": "1", "total": "10", "attestations": "6", "sync_aggregate": "2", "proposer_slashings": "1", "attester_slashings": "1"}}`))
+		})
+		m.HandleFunc("/eth/v1/beacon/rewards/attestations/5", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+
+			data, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.JSONEq(t, `["1", "0x01"]`, string(data))
+
+			w.Write([]byte(`{"execution_optimistic": false, "finalized": true, "data": {
+				"ideal_rewards": [{"effective_balance": "32000000000", "head": "2", "target": "4", "source": "3", "inactivity": "0"}],
+				"total_rewards": [{"validator_index": "1", "head": "2", "target": "-4", "source": "-3", "inactivity": "0"}]
+			}}`))
+		})
+		m.HandleFunc("/eth/v1/beacon/rewards/sync_committee/1", func(w http.ResponseWriter, r *http.Request) {
+			data, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.JSONEq(t, `[]`, string(data))
+
+			w.Write([]byte(`{"execution_optimistic": false, "finalized": true, "data": [{"validator_index": "1", "reward": "-5"}]}`))
+		})
+	}
+
+	addr := newMockHttpServer(t, handler)
+	n := New("http://" + addr).Rewards()
+
+	t.Run("GetBlockRewards", func(t *testing.T) {
+		rewards, err := n.GetBlockRewards(Head)
+		require.NoError(t, err)
+		require.Equal(t, &BlockRewards{ProposerIndex: 1, Total: 10, Attestations: 6, SyncAggregate: 2, ProposerSlashings: 1, AttesterSlashings: 1}, rewards)
+	})
+
+	t.Run("GetAttestationRewards", func(t *testing.T) {
+		rewards, err := n.GetAttestationRewards(5, []string{"1", "0x01"})
+		require.NoError(t, err)
+		require.Equal(t, []*IdealAttestationReward{{EffectiveBalance: 32000000000, Head: 2, Target: 4, Source: 3}}, rewards.IdealRewards)
+		require.Equal(t, []*TotalAttestationReward{{ValidatorIndex: 1, Head: 2, Target: -4, Source: -3}}, rewards.TotalRewards)
+	})
+
+	t.Run("GetSyncCommitteeRewards", func(t *testing.T) {
+		rewards, err := n.GetSyncCommitteeRewards(Slot(1), nil)
+		require.NoError(t, err)
+		require.Equal(t, []*SyncCommitteeReward{{ValidatorIndex: 1, Reward: -5}}, rewards)
+	})
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+
+	consensus "github.com/umbracle/go-eth-consensus"
+	client "github.com/umbracle/go-eth-consensus/http"
+)
+
+// BeaconBackend is the beacon node served by the server. The state and block ids
+// are the ones of the api: head, genesis, finalized, justified, a slot or a 0x
+// prefixed root. The errors of the http client (i.e. client.ErrorNotFound) are
+// returned with their status code.
+type BeaconBackend interface {
+	// Version returns the version of the node
+	Version(ctx context.Context) (string, error)
+
+	// Syncing returns the sync status of the node
+	Syncing(ctx context.Context) (*client.Syncing, error)
+
+	// Identity returns the network identity of the node
+	Identity(ctx context.Context) (*client.Identity, error)
+
+	// Peers returns the peers of the node
+	Peers(ctx context.Context) ([]*client.Peer, error)
+
+	// Spec returns the configuration of the chain
+	Spec(ctx context.Context) (*consensus.Spec, error)
+
+	// DepositContract returns the deposit contract of the chain
+	DepositContract(ctx context.Context) (*client.DepositContract, error)
+
+	// Genesis returns the genesis of the chain
+	Genesis(ctx context.Context) (*client.GenesisInfo, error)
+
+	// State returns the beacon state with the given id
+	State(ctx context.Context, stateID string) (*consensus.VersionedBeaconState, error)
+
+	// Block returns the signed beacon block with the given id
+	Block(ctx context.Context, blockID string) (*consensus.VersionedSignedBeaconBlock, error)
+
+	// Heads returns the heads of the fork choice
+	Heads(ctx context.Context) ([]*client.ChainHead, error)
+
+	// PublishBlock imports a signed beacon block
+	PublishBlock(ctx context.Context, block *consensus.VersionedSignedBeaconBlock) error
+
+	// PublishBlockContents imports a signed beacon block with its blobs and their proofs
+	// after the broadcast validation. Blocks before Deneb do not have blobs.
+	PublishBlockContents(ctx context.Context, block *consensus.VersionedSignedBeaconBlock, kzgProofs [][48]byte, blobs [][131072]byte, validation client.BroadcastValidation) error
+
+	// PublishBlindedBlock imports a signed blinded beacon block after the broadcast validation
+	PublishBlindedBlock(ctx context.Context, block *consensus.VersionedSignedBlindedBeaconBlock, validation client.BroadcastValidation) error
+
+	// BlobSidecars returns the blob sidecars of the block with the given id
+	BlobSidecars(ctx context.Context, blockID string) ([]*consensus.BlobSidecar, error)
+
+	// SubmitAttestations adds the attestations to the pool
+	SubmitAttestations(ctx context.Context, attestations []*consensus.Attestation) error
+
+	// SubmitSyncCommitteeMessages adds the sync committee messages to the pool
+	SubmitSyncCommitteeMessages(ctx context.Context, msgs []*consensus.SyncCommitteeMessage) error
+
+	// PoolAttestations returns the attestations of the pool
+	PoolAttestations(ctx context.Context) ([]*consensus.Attestation, error)
+
+	// SubmitAttesterSlashing adds the attester slashing to the pool
+	SubmitAttesterSlashing(ctx context.Context, slashing *consensus.AttesterSlashing) error
+
+	// PoolAttesterSlashings returns the attester slashings of the pool
+	PoolAttesterSlashings(ctx context.Context) ([]*consensus.AttesterSlashing, error)
+
+	// SubmitProposerSlashing adds the proposer slashing to the pool
+	SubmitProposerSlashing(ctx context.Context, slashing *consensus.ProposerSlashing) error
+
+	// PoolProposerSlashings returns the proposer slashings of the pool
+	PoolProposerSlashings(ctx context.Context) ([]*consensus.ProposerSlashing, error)
+
+	// SubmitVoluntaryExit adds the voluntary exit to the pool
+	SubmitVoluntaryExit(ctx context.Context, exit *consensus.SignedVoluntaryExit) error
+
+	// PoolVoluntaryExits returns the voluntary exits of the pool
+	PoolVoluntaryExits(ctx context.Context) ([]*consensus.SignedVoluntaryExit, error)
+
+	// SubmitBLSToExecutionChanges adds the bls to execution changes to the pool
+	SubmitBLSToExecutionChanges(ctx context.Context, changes []*consensus.SignedBLSToExecutionChange) error
+
+	// PoolBLSToExecutionChanges returns the bls to execution changes of the pool
+	PoolBLSToExecutionChanges(ctx context.Context) ([]*consensus.SignedBLSToExecutionChange, error)
+
+	// ForkChoice returns the nodes of the fork choice
+	ForkChoice(ctx context.Context) (*client.ForkChoice, error)
+
+	// Committees returns the beacon committees of the state at the epoch or at the
+	// epoch of the state if the epoch is nil
+	Committees(ctx context.Context, stateID string, epoch *uint64) ([]*client.Committee, error)
+
+	// SyncCommittees returns the sync committee of the state at the epoch or at the
+	// epoch of the state if the epoch is nil
+	SyncCommittees(ctx context.Context, stateID string, epoch *uint64) (*client.SyncCommittees, error)
+
+	// Randao returns the randao mix of the state at the epoch or at the epoch of the
+	// state if the epoch is nil
+	Randao(ctx context.Context, stateID string, epoch *uint64) ([32]byte, error)
+
+	// AttesterDuties returns the attester duties of the validators at the epoch
+	AttesterDuties(ctx context.Context, epoch uint64, indexes []uint64) ([]*client.AttesterDuty, error)
+
+	// ProposerDuties returns the proposers of the slots of the epoch
+	ProposerDuties(ctx context.Context, epoch uint64) ([]*client.ProposerDuty, error)
+
+	// SyncCommitteeDuties returns the sync committee duties of the validators at the epoch
+	SyncCommitteeDuties(ctx context.Context, epoch uint64, indexes []uint64) ([]*client.CommitteeSyncDuty, error)
+
+	// ProduceBlock returns an unsigned block for the slot
+	ProduceBlock(ctx context.Context, slot uint64, randaoReveal consensus.Signature, graffiti [32]byte) (*client.ProducedBlock, error)
+
+	// AttestationData returns the attestation data of the committee at the slot
+	AttestationData(ctx context.Context, slot, committeeIndex uint64) (*consensus.AttestationData, error)
+
+	// AggregateAttestation returns the aggregate of the attestations of the pool
+	// at the slot with the attestation data root
+	AggregateAttestation(ctx context.Context, slot uint64, dataRoot [32]byte) (*consensus.Attestation, error)
+
+	// SubmitAggregateAndProofs publishes the signed aggregates
+	SubmitAggregateAndProofs(ctx context.Context, aggregates []*consensus.SignedAggregateAndProof) error
+
+	// SubmitBeaconCommitteeSubscriptions subscribes the validators to the subnets of their committees
+	SubmitBeaconCommitteeSubscriptions(ctx context.Context, subs []*client.BeaconCommitteeSubscription) error
+
+	// SubmitSyncCommitteeSubscriptions subscribes the validators to the subnets of their sync committees
+	SubmitSyncCommitteeSubscriptions(ctx context.Context, subs []*client.SyncCommitteeSubscription) error
+
+	// SyncCommitteeContribution returns the aggregate of the sync committee messages of the
+	// pool for the subcommittee at the slot with the block root
+	SyncCommitteeContribution(ctx context.Context, slot, subcommitteeIndex uint64, blockRoot [32]byte) (*consensus.SyncCommitteeContribution, error)
+
+	// SubmitContributionAndProofs publishes the signed sync committee contributions
+	SubmitContributionAndProofs(ctx context.Context, contributions []*consensus.SignedContributionAndProof) error
+
+	// PrepareBeaconProposer sets the fee recipients of the validators
+	PrepareBeaconProposer(ctx context.Context, preparations []*client.ProposalPreparation) error
+
+	// RegisterValidator sends the validator registrations to the builder network
+	RegisterValidator(ctx context.Context, registrations []*client.SignedValidatorRegistration) error
+
+	// BlockRewards returns the rewards of the proposer of the block with the given id
+	BlockRewards(ctx context.Context, blockID string) (*client.BlockRewards, error)
+
+	// AttestationRewards returns the attestation rewards of the epoch for the validators
+	// with the given ids (indexes or public keys) or for all the validators if there are no ids
+	AttestationRewards(ctx context.Context, epoch uint64, ids []string) (*client.AttestationRewards, error)
+
+	// SyncCommitteeRewards returns the sync committee rewards of the block for the validators
+	// with the given ids (indexes or public keys) or for all the committee if there are no ids
+	SyncCommitteeRewards(ctx context.Context, blockID string, ids []string) ([]*client.SyncCommitteeReward, error)
+
+	// LightClientBootstrap returns the light client bootstrap of the block root
+	LightClientBootstrap(ctx context.Context, blockRoot [32]byte) (*client.LightClientObject, error)
+
+	// LightClientUpdates returns the best light client updates of count periods from startPeriod
+	LightClientUpdates(ctx context.Context, startPeriod, count uint64) ([]*client.LightClientObject, error)
+
+	// LightClientFinalityUpdate returns the latest light client finality update
+	LightClientFinalityUpdate(ctx context.Context) (*client.LightClientObject, error)
+
+	// LightClientOptimisticUpdate returns the latest light client optimistic update
+	LightClientOptimisticUpdate(ctx context.Context) (*client.LightClientObject, error)
+
+	// Events returns the events of the topics until the context is done. The channel
+	// is closed once the context is done.
+	Events(ctx context.Context, topics []string) (<-chan *Event, error)
+}
+
+// Event is an event of the event stream. Data is the object of the topic
+// as it is decoded by the client (i.e. client.HeadEvent for the head topic).
+type Event struct {
+	Topic string
+	Data  interface{}
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	client "github.com/umbracle/go-eth-consensus/http"
+)
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	topics := splitQuery(r, "topics")
+	if len(topics) == 0 {
+		writeError(w, http.StatusBadRequest, "no topics")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	events, err := s.backend.Events(r.Context(), topics)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := encodeEvent(event)
+			if err != nil {
+				s.config.logger.Printf("[ERROR] Failed to encode %s event: %v", event.Topic, err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Topic, data)
+			flusher.Flush()
+
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// encodeEvent encodes the data of the event. The light client
+// objects are sent with their version.
+func encodeEvent(event *Event) ([]byte, error) {
+	if obj, ok := event.Data.(*client.LightClientObject); ok {
+		data, err := client.Marshal(obj.Data)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(fmt.Sprintf(`{"version":"%s","data":%s}`, obj.Version, data)), nil
+	}
+	return client.Marshal(event.Data)
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	ssz "github.com/ferranbt/fastssz"
+	consensus "github.com/umbracle/go-eth-consensus"
+	client "github.com/umbracle/go-eth-consensus/http"
+)
+
+func (s *Server) handleLightClientBootstrap(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	blockRoot, err := decodeRoot(params["block_root"])
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	bootstrap, err := s.backend.LightClientBootstrap(r.Context(), blockRoot)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeVersioned(w, r, bootstrap.Version, bootstrap.Data)
+}
+
+func (s *Server) handleLightClientFinalityUpdate(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	update, err := s.backend.LightClientFinalityUpdate(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeVersioned(w, r, update.Version, update.Data)
+}
+
+func (s *Server) handleLightClientOptimisticUpdate(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	update, err := s.backend.LightClientOptimisticUpdate(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeVersioned(w, r, update.Version, update.Data)
+}
+
+func (s *Server) handleLightClientUpdates(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	startPeriod, err := strconv.ParseUint(r.URL.Query().Get("start_period"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid start_period")
+		return
+	}
+	count, err := strconv.ParseUint(r.URL.Query().Get("count"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid count")
+		return
+	}
+
+	updates, err := s.backend.LightClientUpdates(r.Context(), startPeriod, count)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+
+	if acceptsSSZ(r) {
+		data, err := s.encodeUpdatesSSZ(r, updates)
+		if err != nil {
+			s.writeBackendError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", contentTypeSSZ)
+		w.Write(data)
+		return
+	}
+
+	// each update is a versioned object
+	buf := bytes.NewBufferString("[")
+	for i, update := range updates {
+		data, err := client.Marshal(update.Data)
+		if err != nil {
+			s.writeBackendError(w, err)
+			return
+		}
+		if i != 0 {
+			buf.WriteString(",")
+		}
+		fmt.Fprintf(buf, `{"version":"%s","data":%s}`, update.Version, data)
+	}
+	buf.WriteString("]")
+
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.Write(buf.Bytes())
+}
+
+// encodeUpdatesSSZ encodes the updates as a sequence of chunks. Each chunk is prefixed
+// with its length (8 bytes) and the fork digest of the update (4 bytes).
+func (s *Server) encodeUpdatesSSZ(r *http.Request, updates []*client.LightClientObject) ([]byte, error) {
+	spec, err := s.backend.Spec(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	genesis, err := s.backend.Genesis(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	buf := []byte{}
+	for _, update := range updates {
+		obj, ok := update.Data.(ssz.Marshaler)
+		if !ok {
+			return nil, fmt.Errorf("light client update %T cannot be encoded", update.Data)
+		}
+		data, err := obj.MarshalSSZ()
+		if err != nil {
+			return nil, err
+		}
+		forkDigest, err := consensus.ComputeForkDigest(spec.ForkVersion(update.Version), genesis.Root)
+		if err != nil {
+			return nil, err
+		}
+
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(4+len(data)))
+		buf = append(buf, forkDigest[:]...)
+		buf = append(buf, data...)
+	}
+	return buf, nil
+}
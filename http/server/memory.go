@@ -0,0 +1,1504 @@
+package server
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	consensus "github.com/umbracle/go-eth-consensus"
+	"github.com/umbracle/go-eth-consensus/bitlist"
+	"github.com/umbracle/go-eth-consensus/bls"
+	client "github.com/umbracle/go-eth-consensus/http"
+	"github.com/umbracle/go-eth-consensus/spec"
+)
+
+type memoryBlock struct {
+	root  [32]byte
+	block *consensus.VersionedSignedBeaconBlock
+
+	// state is the post state of the block. It is nil
+	// for the published blocks.
+	state *consensus.VersionedBeaconState
+}
+
+// memoryBlobs are the blobs and the kzg proofs published along with a block
+type memoryBlobs struct {
+	kzgProofs [][48]byte
+	blobs     [][131072]byte
+}
+
+// memorySubscriber is a subscriber to the events of the memory backend
+type memorySubscriber struct {
+	topics map[string]struct{}
+	ch     chan *Event
+}
+
+// MemoryBackend is an in-memory beacon node. The chain starts at the genesis state
+// and it is extended with AddBlock. The published blocks and the submitted pool and
+// validator objects are stored as they are and are not processed. The objects that
+// the node does not compute (i.e. light client objects, rewards, blob sidecars and
+// peers) are set with the Set functions. The duties are computed with the head
+// state and, as the committees and the randao of the states, they require a full
+// spec (i.e. spec.MinimalSpec). The attestation aggregates and the sync committee
+// contributions are aggregated from the pool.
+type MemoryBackend struct {
+	lock sync.RWMutex
+
+	spec            *consensus.Spec
+	genesis         *consensus.VersionedBeaconState
+	depositContract *client.DepositContract
+	syncing         bool
+	identity        *client.Identity
+	peers           []*client.Peer
+
+	// blocks are the blocks by block root
+	blocks map[[32]byte]*memoryBlock
+
+	// states are the post states of the blocks by state root
+	states map[[32]byte]*consensus.VersionedBeaconState
+
+	// canonical are the roots of the blocks of the chain by slot
+	canonical map[uint64][32]byte
+
+	head *memoryBlock
+
+	published             []*consensus.VersionedSignedBeaconBlock
+	publishedBlinded      []*consensus.VersionedSignedBlindedBeaconBlock
+	attestations          []*consensus.Attestation
+	syncCommitteeMessages []*consensus.SyncCommitteeMessage
+	attesterSlashings     []*consensus.AttesterSlashing
+	proposerSlashings     []*consensus.ProposerSlashing
+	voluntaryExits        []*consensus.SignedVoluntaryExit
+	blsToExecutionChanges []*consensus.SignedBLSToExecutionChange
+
+	// publishedBlobs are the blobs published along with the blocks by block root
+	publishedBlobs map[[32]byte]*memoryBlobs
+
+	aggregateAndProofs           []*consensus.SignedAggregateAndProof
+	contributionAndProofs        []*consensus.SignedContributionAndProof
+	beaconCommitteeSubscriptions []*client.BeaconCommitteeSubscription
+	syncCommitteeSubscriptions   []*client.SyncCommitteeSubscription
+	proposalPreparations         []*client.ProposalPreparation
+	validatorRegistrations       []*client.SignedValidatorRegistration
+
+	// blobSidecars are the blob sidecars by block root
+	blobSidecars map[[32]byte][]*consensus.BlobSidecar
+
+	// blockRewards and syncCommitteeRewards are the rewards by block root
+	blockRewards         map[[32]byte]*client.BlockRewards
+	syncCommitteeRewards map[[32]byte][]*client.SyncCommitteeReward
+
+	// attestationRewards are the attestation rewards by epoch
+	attestationRewards map[uint64]*client.AttestationRewards
+
+	// lightClientBootstraps are the bootstraps by block root
+	lightClientBootstraps map[[32]byte]*client.LightClientObject
+
+	// lightClientUpdates are the best updates by sync committee period
+	lightClientUpdates map[uint64]*client.LightClientObject
+
+	lightClientFinalityUpdate   *client.LightClientObject
+	lightClientOptimisticUpdate *client.LightClientObject
+
+	subscribers []*memorySubscriber
+}
+
+func NewMemoryBackend(spec *consensus.Spec, genesis *consensus.VersionedBeaconState) *MemoryBackend {
+	return &MemoryBackend{
+		spec:            spec,
+		genesis:         genesis,
+		depositContract: &client.DepositContract{},
+		blocks:          map[[32]byte]*memoryBlock{},
+		states:          map[[32]byte]*consensus.VersionedBeaconState{},
+		canonical:       map[uint64][32]byte{},
+
+		lightClientBootstraps: map[[32]byte]*client.LightClientObject{},
+		lightClientUpdates:    map[uint64]*client.LightClientObject{},
+
+		publishedBlobs:       map[[32]byte]*memoryBlobs{},
+		blobSidecars:         map[[32]byte][]*consensus.BlobSidecar{},
+		blockRewards:         map[[32]byte]*client.BlockRewards{},
+		syncCommitteeRewards: map[[32]byte][]*client.SyncCommitteeReward{},
+		attestationRewards:   map[uint64]*client.AttestationRewards{},
+	}
+}
+
+// AddBlock adds a block and its post state as the new head of the chain
+func (m *MemoryBackend) AddBlock(block *consensus.VersionedSignedBeaconBlock, state *consensus.VersionedBeaconState) error {
+	root, err := block.BlockRoot()
+	if err != nil {
+		return err
+	}
+	stateRoot, err := state.HashTreeRoot()
+	if err != nil {
+		return err
+	}
+	if stateRoot != block.StateRoot() {
+		return fmt.Errorf("state root 0x%x does not match the block state root 0x%x", stateRoot, block.StateRoot())
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	obj := &memoryBlock{root: root, block: block, state: state}
+	m.blocks[root] = obj
+	m.states[stateRoot] = state
+	m.canonical[block.Slot()] = root
+	m.head = obj
+
+	m.emit("head", &client.HeadEvent{
+		Slot:            block.Slot(),
+		Block:           root,
+		State:           stateRoot,
+		EpochTransition: m.spec.SlotsPerEpoch != 0 && block.Slot()%m.spec.SlotsPerEpoch == 0,
+	})
+	m.emit("block", &client.BlockEvent{
+		Slot:  block.Slot(),
+		Block: root,
+	})
+	return nil
+}
+
+// SetLightClientBootstrap sets the light client bootstrap of the block root
+func (m *MemoryBackend) SetLightClientBootstrap(blockRoot [32]byte, bootstrap *client.LightClientObject) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.lightClientBootstraps[blockRoot] = bootstrap
+}
+
+// SetLightClientUpdate sets the best light client update of the sync committee period
+func (m *MemoryBackend) SetLightClientUpdate(period uint64, update *client.LightClientObject) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.lightClientUpdates[period] = update
+}
+
+// SetLightClientFinalityUpdate sets the latest light client finality update
+func (m *MemoryBackend) SetLightClientFinalityUpdate(update *client.LightClientObject) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.lightClientFinalityUpdate = update
+	m.emit("light_client_finality_update", update)
+}
+
+// SetLightClientOptimisticUpdate sets the latest light client optimistic update
+func (m *MemoryBackend) SetLightClientOptimisticUpdate(update *client.LightClientObject) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.lightClientOptimisticUpdate = update
+	m.emit("light_client_optimistic_update", update)
+}
+
+// SetSyncing sets whether the node is syncing
+func (m *MemoryBackend) SetSyncing(syncing bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.syncing = syncing
+}
+
+// SetDepositContract sets the deposit contract of the chain
+func (m *MemoryBackend) SetDepositContract(depositContract *client.DepositContract) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.depositContract = depositContract
+}
+
+// SetIdentity sets the network identity of the node
+func (m *MemoryBackend) SetIdentity(identity *client.Identity) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.identity = identity
+}
+
+// SetPeers sets the peers of the node
+func (m *MemoryBackend) SetPeers(peers []*client.Peer) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.peers = peers
+}
+
+// SetBlobSidecars sets the blob sidecars of the block root
+func (m *MemoryBackend) SetBlobSidecars(blockRoot [32]byte, sidecars []*consensus.BlobSidecar) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.blobSidecars[blockRoot] = sidecars
+}
+
+// SetBlockRewards sets the proposer rewards of the block root
+func (m *MemoryBackend) SetBlockRewards(blockRoot [32]byte, rewards *client.BlockRewards) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.blockRewards[blockRoot] = rewards
+}
+
+// SetAttestationRewards sets the attestation rewards of the epoch
+func (m *MemoryBackend) SetAttestationRewards(epoch uint64, rewards *client.AttestationRewards) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.attestationRewards[epoch] = rewards
+}
+
+// SetSyncCommitteeRewards sets the sync committee rewards of the block root
+func (m *MemoryBackend) SetSyncCommitteeRewards(blockRoot [32]byte, rewards []*client.SyncCommitteeReward) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.syncCommitteeRewards[blockRoot] = rewards
+}
+
+// PublishedBlocks returns the blocks published to the node
+func (m *MemoryBackend) PublishedBlocks() []*consensus.VersionedSignedBeaconBlock {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return append([]*consensus.VersionedSignedBeaconBlock{}, m.published...)
+}
+
+// Attestations returns the attestations of the pool
+func (m *MemoryBackend) Attestations() []*consensus.Attestation {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return append([]*consensus.Attestation{}, m.attestations...)
+}
+
+// SyncCommitteeMessages returns the sync committee messages of the pool
+func (m *MemoryBackend) SyncCommitteeMessages() []*consensus.SyncCommitteeMessage {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return append([]*consensus.SyncCommitteeMessage{}, m.syncCommitteeMessages...)
+}
+
+// PublishedBlobs returns the kzg proofs and the blobs published along with the block root
+func (m *MemoryBackend) PublishedBlobs(blockRoot [32]byte) ([][48]byte, [][131072]byte) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	blobs, ok := m.publishedBlobs[blockRoot]
+	if !ok {
+		return nil, nil
+	}
+	return blobs.kzgProofs, blobs.blobs
+}
+
+// PublishedBlindedBlocks returns the blinded blocks published to the node
+func (m *MemoryBackend) PublishedBlindedBlocks() []*consensus.VersionedSignedBlindedBeaconBlock {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return append([]*consensus.VersionedSignedBlindedBeaconBlock{}, m.publishedBlinded...)
+}
+
+// AggregateAndProofs returns the aggregates submitted to the node
+func (m *MemoryBackend) AggregateAndProofs() []*consensus.SignedAggregateAndProof {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return append([]*consensus.SignedAggregateAndProof{}, m.aggregateAndProofs...)
+}
+
+// ContributionAndProofs returns the sync committee contributions submitted to the node
+func (m *MemoryBackend) ContributionAndProofs() []*consensus.SignedContributionAndProof {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return append([]*consensus.SignedContributionAndProof{}, m.contributionAndProofs...)
+}
+
+// BeaconCommitteeSubscriptions returns the beacon committee subscriptions of the validators
+func (m *MemoryBackend) BeaconCommitteeSubscriptions() []*client.BeaconCommitteeSubscription {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return append([]*client.BeaconCommitteeSubscription{}, m.beaconCommitteeSubscriptions...)
+}
+
+// SyncCommitteeSubscriptions returns the sync committee subscriptions of the validators
+func (m *MemoryBackend) SyncCommitteeSubscriptions() []*client.SyncCommitteeSubscription {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return append([]*client.SyncCommitteeSubscription{}, m.syncCommitteeSubscriptions...)
+}
+
+// ProposalPreparations returns the fee recipients prepared by the validators
+func (m *MemoryBackend) ProposalPreparations() []*client.ProposalPreparation {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return append([]*client.ProposalPreparation{}, m.proposalPreparations...)
+}
+
+// ValidatorRegistrations returns the builder registrations of the validators
+func (m *MemoryBackend) ValidatorRegistrations() []*client.SignedValidatorRegistration {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return append([]*client.SignedValidatorRegistration{}, m.validatorRegistrations...)
+}
+
+func (m *MemoryBackend) Version(ctx context.Context) (string, error) {
+	return "go-eth-consensus/memory", nil
+}
+
+func (m *MemoryBackend) Syncing(ctx context.Context) (*client.Syncing, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	syncing := &client.Syncing{
+		HeadSlot:     m.headState().Slot(),
+		SyncDistance: "0",
+		IsSyncing:    m.syncing,
+	}
+	return syncing, nil
+}
+
+func (m *MemoryBackend) Identity(ctx context.Context) (*client.Identity, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if m.identity != nil {
+		return m.identity, nil
+	}
+	identity := &client.Identity{
+		P2PAddresses:       []string{},
+		DiscoveryAddresses: []string{},
+		Metadata: &client.IdentityMetadata{
+			AttNets:  "0x0000000000000000",
+			SyncNets: "0x00",
+		},
+	}
+	return identity, nil
+}
+
+func (m *MemoryBackend) Peers(ctx context.Context) ([]*client.Peer, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return append([]*client.Peer{}, m.peers...), nil
+}
+
+func (m *MemoryBackend) Spec(ctx context.Context) (*consensus.Spec, error) {
+	return m.spec, nil
+}
+
+func (m *MemoryBackend) DepositContract(ctx context.Context) (*client.DepositContract, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.depositContract, nil
+}
+
+func (m *MemoryBackend) Genesis(ctx context.Context) (*client.GenesisInfo, error) {
+	genesis := &client.GenesisInfo{
+		Time: m.genesis.GenesisTime(),
+		Root: m.genesis.GenesisValidatorsRoot(),
+		Fork: fmt.Sprintf("0x%x", m.spec.GenesisForkVersion),
+	}
+	return genesis, nil
+}
+
+func (m *MemoryBackend) headState() *consensus.VersionedBeaconState {
+	if m.head == nil {
+		return m.genesis
+	}
+	return m.head.state
+}
+
+func (m *MemoryBackend) State(ctx context.Context, stateID string) (*consensus.VersionedBeaconState, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	switch stateID {
+	case "head":
+		return m.headState(), nil
+	case "genesis":
+		return m.genesis, nil
+	}
+
+	if strings.HasPrefix(stateID, "0x") {
+		root, err := decodeRoot(stateID)
+		if err != nil {
+			return nil, err
+		}
+		if state, ok := m.states[root]; ok {
+			return state, nil
+		}
+		if genesisRoot, err := m.genesis.HashTreeRoot(); err == nil && genesisRoot == root {
+			return m.genesis, nil
+		}
+		return nil, fmt.Errorf("%w: state %s", client.ErrorNotFound, stateID)
+	}
+
+	block, err := m.resolveBlock(stateID)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		// the chain has no block at that id
+		return m.genesis, nil
+	}
+	if block.state == nil {
+		return nil, fmt.Errorf("%w: state of block 0x%x", client.ErrorNotFound, block.root)
+	}
+	return block.state, nil
+}
+
+func (m *MemoryBackend) Block(ctx context.Context, blockID string) (*consensus.VersionedSignedBeaconBlock, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if strings.HasPrefix(blockID, "0x") {
+		root, err := decodeRoot(blockID)
+		if err != nil {
+			return nil, err
+		}
+		block, ok := m.blocks[root]
+		if !ok {
+			return nil, fmt.Errorf("%w: block %s", client.ErrorNotFound, blockID)
+		}
+		return block.block, nil
+	}
+
+	block, err := m.resolveBlock(blockID)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("%w: block %s", client.ErrorNotFound, blockID)
+	}
+	return block.block, nil
+}
+
+// resolveBlock returns the canonical block of the id or nil if the id
+// refers to a block before the first block added.
+func (m *MemoryBackend) resolveBlock(id string) (*memoryBlock, error) {
+	switch id {
+	case "head":
+		return m.head, nil
+
+	case "genesis":
+		return m.blocks[m.canonical[0]], nil
+
+	case "finalized", "justified":
+		state := m.headState()
+
+		checkpoint := state.FinalizedCheckpoint()
+		if id == "justified" {
+			checkpoint = state.CurrentJustifiedCheckpoint()
+		}
+		if checkpoint == nil {
+			return nil, nil
+		}
+		return m.blocks[checkpoint.Root], nil
+	}
+
+	slot, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid id '%s'", client.ErrorBadRequest, id)
+	}
+	root, ok := m.canonical[slot]
+	if !ok {
+		if slot == 0 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: no block at slot %d", client.ErrorNotFound, slot)
+	}
+	return m.blocks[root], nil
+}
+
+func decodeRoot(str string) ([32]byte, error) {
+	var root [32]byte
+
+	buf, err := hex.DecodeString(strings.TrimPrefix(str, "0x"))
+	if err != nil || len(buf) != 32 {
+		return root, fmt.Errorf("%w: invalid root '%s'", client.ErrorBadRequest, str)
+	}
+	copy(root[:], buf)
+	return root, nil
+}
+
+func (m *MemoryBackend) Heads(ctx context.Context) ([]*client.ChainHead, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if m.head == nil {
+		return []*client.ChainHead{}, nil
+	}
+	head := &client.ChainHead{
+		Root: m.head.root,
+		Slot: m.head.block.Slot(),
+	}
+	return []*client.ChainHead{head}, nil
+}
+
+func (m *MemoryBackend) PublishBlock(ctx context.Context, block *consensus.VersionedSignedBeaconBlock) error {
+	root, err := block.BlockRoot()
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.published = append(m.published, block)
+	if _, ok := m.blocks[root]; !ok {
+		m.blocks[root] = &memoryBlock{root: root, block: block}
+	}
+	return nil
+}
+
+// PublishBlockContents publishes the block and stores the blobs published along with it.
+// The block is not validated.
+func (m *MemoryBackend) PublishBlockContents(ctx context.Context, block *consensus.VersionedSignedBeaconBlock, kzgProofs [][48]byte, blobs [][131072]byte, validation client.BroadcastValidation) error {
+	root, err := block.BlockRoot()
+	if err != nil {
+		return err
+	}
+	if err := m.PublishBlock(ctx, block); err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if len(blobs) != 0 {
+		m.publishedBlobs[root] = &memoryBlobs{kzgProofs: kzgProofs, blobs: blobs}
+	}
+	return nil
+}
+
+func (m *MemoryBackend) PublishBlindedBlock(ctx context.Context, block *consensus.VersionedSignedBlindedBeaconBlock, validation client.BroadcastValidation) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.publishedBlinded = append(m.publishedBlinded, block)
+	return nil
+}
+
+func (m *MemoryBackend) BlobSidecars(ctx context.Context, blockID string) ([]*consensus.BlobSidecar, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	root, err := m.resolveBlockRoot(blockID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*consensus.BlobSidecar{}, m.blobSidecars[root]...), nil
+}
+
+// resolveBlockRoot returns the root of the block id. The roots are
+// returned as they are even if the block is unknown.
+func (m *MemoryBackend) resolveBlockRoot(blockID string) ([32]byte, error) {
+	if strings.HasPrefix(blockID, "0x") {
+		return decodeRoot(blockID)
+	}
+	block, err := m.resolveBlock(blockID)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if block == nil {
+		return [32]byte{}, fmt.Errorf("%w: block %s", client.ErrorNotFound, blockID)
+	}
+	return block.root, nil
+}
+
+func (m *MemoryBackend) SubmitAttestations(ctx context.Context, attestations []*consensus.Attestation) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.attestations = append(m.attestations, attestations...)
+	for _, attestation := range attestations {
+		m.emit("attestation", attestation)
+	}
+	return nil
+}
+
+func (m *MemoryBackend) SubmitSyncCommitteeMessages(ctx context.Context, msgs []*consensus.SyncCommitteeMessage) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.syncCommitteeMessages = append(m.syncCommitteeMessages, msgs...)
+	return nil
+}
+
+func (m *MemoryBackend) PoolAttestations(ctx context.Context) ([]*consensus.Attestation, error) {
+	return m.Attestations(), nil
+}
+
+func (m *MemoryBackend) SubmitAttesterSlashing(ctx context.Context, slashing *consensus.AttesterSlashing) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.attesterSlashings = append(m.attesterSlashings, slashing)
+	m.emit("attester_slashing", slashing)
+	return nil
+}
+
+func (m *MemoryBackend) PoolAttesterSlashings(ctx context.Context) ([]*consensus.AttesterSlashing, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return append([]*consensus.AttesterSlashing{}, m.attesterSlashings...), nil
+}
+
+func (m *MemoryBackend) SubmitProposerSlashing(ctx context.Context, slashing *consensus.ProposerSlashing) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.proposerSlashings = append(m.proposerSlashings, slashing)
+	m.emit("proposer_slashing", slashing)
+	return nil
+}
+
+func (m *MemoryBackend) PoolProposerSlashings(ctx context.Context) ([]*consensus.ProposerSlashing, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return append([]*consensus.ProposerSlashing{}, m.proposerSlashings...), nil
+}
+
+func (m *MemoryBackend) SubmitVoluntaryExit(ctx context.Context, exit *consensus.SignedVoluntaryExit) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.voluntaryExits = append(m.voluntaryExits, exit)
+	m.emit("voluntary_exit", exit)
+	return nil
+}
+
+func (m *MemoryBackend) PoolVoluntaryExits(ctx context.Context) ([]*consensus.SignedVoluntaryExit, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return append([]*consensus.SignedVoluntaryExit{}, m.voluntaryExits...), nil
+}
+
+func (m *MemoryBackend) SubmitBLSToExecutionChanges(ctx context.Context, changes []*consensus.SignedBLSToExecutionChange) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.blsToExecutionChanges = append(m.blsToExecutionChanges, changes...)
+	for _, change := range changes {
+		m.emit("bls_to_execution_change", change)
+	}
+	return nil
+}
+
+func (m *MemoryBackend) PoolBLSToExecutionChanges(ctx context.Context) ([]*consensus.SignedBLSToExecutionChange, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return append([]*consensus.SignedBLSToExecutionChange{}, m.blsToExecutionChanges...), nil
+}
+
+func (m *MemoryBackend) ForkChoice(ctx context.Context) (*client.ForkChoice, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	state := m.headState()
+	forkChoice := &client.ForkChoice{
+		JustifiedCheckpoint: state.CurrentJustifiedCheckpoint(),
+		FinalizedCheckpoint: state.FinalizedCheckpoint(),
+		Nodes:               []*client.ForkChoiceNode{},
+	}
+
+	// the nodes are the blocks added to the chain, the published
+	// blocks are not processed
+	for _, block := range m.blocks {
+		if block.state == nil {
+			continue
+		}
+		forkChoice.Nodes = append(forkChoice.Nodes, &client.ForkChoiceNode{
+			Slot:           block.block.Slot(),
+			BlockRoot:      block.root,
+			ParentRoot:     block.block.ParentRoot(),
+			JustifiedEpoch: checkpointEpoch(block.state.CurrentJustifiedCheckpoint()),
+			FinalizedEpoch: checkpointEpoch(block.state.FinalizedCheckpoint()),
+			Validity:       client.ForkChoiceNodeValid,
+		})
+	}
+	sort.Slice(forkChoice.Nodes, func(i, j int) bool {
+		return forkChoice.Nodes[i].Slot < forkChoice.Nodes[j].Slot
+	})
+	return forkChoice, nil
+}
+
+func checkpointEpoch(checkpoint *consensus.Checkpoint) uint64 {
+	if checkpoint == nil {
+		return 0
+	}
+	return checkpoint.Epoch
+}
+
+// stateAtEpoch returns the head state or a copy of the head state processed
+// up to the epoch if the epoch is the next one
+func (m *MemoryBackend) stateAtEpoch(st *spec.StateTransitioner, epoch uint64) (*consensus.VersionedBeaconState, error) {
+	state := m.headState()
+
+	currentEpoch := state.Slot() / m.spec.SlotsPerEpoch
+	switch epoch {
+	case currentEpoch:
+		return state, nil
+	case currentEpoch + 1:
+		return m.stateAtSlot(st, epoch*m.spec.SlotsPerEpoch)
+	default:
+		return nil, fmt.Errorf("%w: epoch %d is not the current or the next epoch", client.ErrorBadRequest, epoch)
+	}
+}
+
+// stateAtSlot returns a copy of the head state processed up to the slot
+func (m *MemoryBackend) stateAtSlot(st *spec.StateTransitioner, slot uint64) (*consensus.VersionedBeaconState, error) {
+	head := m.headState()
+
+	buf, err := m.spec.EncodeSSZ(head.State)
+	if err != nil {
+		return nil, err
+	}
+	state, err := consensus.NewBeaconState(head.Version)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.spec.DecodeSSZ(buf, state); err != nil {
+		return nil, err
+	}
+
+	if state, err = st.ProcessSlots(state, slot); err != nil {
+		return nil, err
+	}
+	return consensus.NewVersionedBeaconState(state)
+}
+
+func (m *MemoryBackend) Committees(ctx context.Context, stateID string, epoch *uint64) ([]*client.Committee, error) {
+	st, err := spec.NewStateTransitioner(m.spec)
+	if err != nil {
+		return nil, err
+	}
+	state, err := m.State(ctx, stateID)
+	if err != nil {
+		return nil, err
+	}
+
+	stateEpoch := state.Slot() / m.spec.SlotsPerEpoch
+	if epoch == nil {
+		epoch = &stateEpoch
+	}
+	committees, err := st.GetEpochCommittees(state.State, *epoch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", client.ErrorBadRequest, err)
+	}
+
+	res := []*client.Committee{}
+	for slot, slotCommittees := range committees {
+		for index, committee := range slotCommittees {
+			res = append(res, &client.Committee{
+				Index:      uint64(index),
+				Slot:       *epoch*m.spec.SlotsPerEpoch + uint64(slot),
+				Validators: committee,
+			})
+		}
+	}
+	return res, nil
+}
+
+// syncCommitteeSubnetCount is the number of subcommittees of the sync committee
+const syncCommitteeSubnetCount = 4
+
+func (m *MemoryBackend) SyncCommittees(ctx context.Context, stateID string, epoch *uint64) (*client.SyncCommittees, error) {
+	if _, err := spec.NewStateTransitioner(m.spec); err != nil {
+		return nil, err
+	}
+	state, err := m.State(ctx, stateID)
+	if err != nil {
+		return nil, err
+	}
+
+	stateEpoch := state.Slot() / m.spec.SlotsPerEpoch
+	if epoch == nil {
+		epoch = &stateEpoch
+	}
+	committee, err := m.syncCommitteeAt(state, *epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := map[[48]byte]uint64{}
+	for indx, validator := range state.Validators() {
+		indexes[validator.Pubkey] = uint64(indx)
+	}
+
+	res := &client.SyncCommittees{
+		Validators:          []uint64{},
+		ValidatorAggregates: make([][]uint64, syncCommitteeSubnetCount),
+	}
+	subcommitteeSize := m.spec.SyncCommitteeSize / syncCommitteeSubnetCount
+	for position, pubKey := range committee.PubKeys[:m.spec.SyncCommitteeSize] {
+		indx, ok := indexes[pubKey]
+		if !ok {
+			return nil, fmt.Errorf("sync committee member 0x%x is not a validator", pubKey)
+		}
+		res.Validators = append(res.Validators, indx)
+
+		subnet := uint64(position) / subcommitteeSize
+		res.ValidatorAggregates[subnet] = append(res.ValidatorAggregates[subnet], indx)
+	}
+	return res, nil
+}
+
+func (m *MemoryBackend) Randao(ctx context.Context, stateID string, epoch *uint64) ([32]byte, error) {
+	if _, err := spec.NewStateTransitioner(m.spec); err != nil {
+		return [32]byte{}, err
+	}
+	state, err := m.State(ctx, stateID)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	// the state only has the mixes of the last EPOCHS_PER_HISTORICAL_VECTOR epochs
+	stateEpoch := state.Slot() / m.spec.SlotsPerEpoch
+	if epoch == nil {
+		epoch = &stateEpoch
+	}
+	if *epoch > stateEpoch || *epoch+m.spec.EpochsPerHistoricalVector <= stateEpoch {
+		return [32]byte{}, fmt.Errorf("%w: randao of epoch %d is not in the state", client.ErrorBadRequest, *epoch)
+	}
+	return state.RandaoMix(*epoch % m.spec.EpochsPerHistoricalVector), nil
+}
+
+func (m *MemoryBackend) AttesterDuties(ctx context.Context, epoch uint64, indexes []uint64) ([]*client.AttesterDuty, error) {
+	st, err := spec.NewStateTransitioner(m.spec)
+	if err != nil {
+		return nil, err
+	}
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	state := m.headState()
+	committees, err := st.GetEpochCommittees(state.State, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", client.ErrorBadRequest, err)
+	}
+
+	validators := map[uint64]struct{}{}
+	for _, indx := range indexes {
+		validators[indx] = struct{}{}
+	}
+
+	duties := []*client.AttesterDuty{}
+	for slot, slotCommittees := range committees {
+		for committeeIndex, committee := range slotCommittees {
+			for position, indx := range committee {
+				if _, ok := validators[indx]; !ok {
+					continue
+				}
+				duties = append(duties, &client.AttesterDuty{
+					PubKey:                  fmt.Sprintf("0x%x", state.Validators()[indx].Pubkey),
+					ValidatorIndex:          uint(indx),
+					Slot:                    epoch*m.spec.SlotsPerEpoch + uint64(slot),
+					CommitteeIndex:          uint64(committeeIndex),
+					CommitteeLength:         uint64(len(committee)),
+					CommitteeAtSlot:         uint64(len(slotCommittees)),
+					ValidatorCommitteeIndex: uint64(position),
+				})
+			}
+		}
+	}
+	return duties, nil
+}
+
+func (m *MemoryBackend) ProposerDuties(ctx context.Context, epoch uint64) ([]*client.ProposerDuty, error) {
+	st, err := spec.NewStateTransitioner(m.spec)
+	if err != nil {
+		return nil, err
+	}
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	state, err := m.stateAtEpoch(st, epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	duties := []*client.ProposerDuty{}
+	for slot := epoch * m.spec.SlotsPerEpoch; slot < (epoch+1)*m.spec.SlotsPerEpoch; slot++ {
+		indx, err := st.GetBeaconProposerIndex(state.State, slot)
+		if err != nil {
+			return nil, err
+		}
+		duties = append(duties, &client.ProposerDuty{
+			PubKey:         fmt.Sprintf("0x%x", state.Validators()[indx].Pubkey),
+			ValidatorIndex: uint(indx),
+			Slot:           slot,
+		})
+	}
+	return duties, nil
+}
+
+func (m *MemoryBackend) SyncCommitteeDuties(ctx context.Context, epoch uint64, indexes []uint64) ([]*client.CommitteeSyncDuty, error) {
+	if _, err := spec.NewStateTransitioner(m.spec); err != nil {
+		return nil, err
+	}
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	state := m.headState()
+
+	committee, err := m.syncCommitteeAt(state, epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	validators := state.Validators()
+
+	duties := []*client.CommitteeSyncDuty{}
+	for _, indx := range indexes {
+		if indx >= uint64(len(validators)) {
+			continue
+		}
+		pubKey := validators[indx].Pubkey
+
+		positions := []string{}
+		for position, member := range committee.PubKeys[:m.spec.SyncCommitteeSize] {
+			if member == pubKey {
+				positions = append(positions, strconv.Itoa(position))
+			}
+		}
+		if len(positions) == 0 {
+			continue
+		}
+		duties = append(duties, &client.CommitteeSyncDuty{
+			PubKey:                        fmt.Sprintf("0x%x", pubKey),
+			ValidatorIndex:                uint(indx),
+			ValidatorSyncCommitteeIndices: positions,
+		})
+	}
+	return duties, nil
+}
+
+// syncCommitteeAt returns the sync committee of the state at the epoch. The state
+// has the committees of the current and the next period.
+func (m *MemoryBackend) syncCommitteeAt(state *consensus.VersionedBeaconState, epoch uint64) (*consensus.SyncCommittee, error) {
+	period := epoch / m.spec.EpochsPerSyncCommitteePeriod
+	currentPeriod := state.Slot() / m.spec.SlotsPerEpoch / m.spec.EpochsPerSyncCommitteePeriod
+
+	var committee *consensus.SyncCommittee
+	switch period {
+	case currentPeriod:
+		committee = state.CurrentSyncCommittee()
+	case currentPeriod + 1:
+		committee = state.NextSyncCommittee()
+	default:
+		return nil, fmt.Errorf("%w: epoch %d is not in the current or the next sync committee period", client.ErrorBadRequest, epoch)
+	}
+	if committee == nil {
+		return nil, fmt.Errorf("%w: state %s does not have sync committees", client.ErrorBadRequest, state.Version)
+	}
+	return committee, nil
+}
+
+// ProduceBlock returns a block on top of the head with the randao reveal and the graffiti.
+// The block does not include any operation or execution payload and its state root is
+// not computed.
+func (m *MemoryBackend) ProduceBlock(ctx context.Context, slot uint64, randaoReveal consensus.Signature, graffiti [32]byte) (*client.ProducedBlock, error) {
+	st, err := spec.NewStateTransitioner(m.spec)
+	if err != nil {
+		return nil, err
+	}
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if headSlot := m.headState().Slot(); slot <= headSlot {
+		return nil, fmt.Errorf("%w: slot %d is not after the head slot %d", client.ErrorBadRequest, slot, headSlot)
+	}
+	state, err := m.stateAtSlot(st, slot)
+	if err != nil {
+		return nil, err
+	}
+
+	proposerIndex, err := st.GetBeaconProposerIndex(state.State, slot)
+	if err != nil {
+		return nil, err
+	}
+	// the state root of the latest header is set once the slot is processed
+	parentRoot, err := state.LatestBlockHeader().HashTreeRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := consensus.NewBeaconBlock(state.Version)
+	if err != nil {
+		return nil, err
+	}
+	eth1Data := &consensus.Eth1Data{}
+	syncAggregate := &consensus.SyncAggregate{SyncCommiteeSignature: consensus.Signature{0xc0}}
+
+	switch obj := block.(type) {
+	case *consensus.BeaconBlockPhase0:
+		obj.Slot, obj.ProposerIndex, obj.ParentRoot = slot, proposerIndex, parentRoot
+		obj.Body = &consensus.BeaconBlockBodyPhase0{RandaoReveal: randaoReveal, Eth1Data: eth1Data, Graffiti: graffiti}
+	case *consensus.BeaconBlockAltair:
+		obj.Slot, obj.ProposerIndex, obj.ParentRoot = slot, proposerIndex, parentRoot
+		obj.Body = &consensus.BeaconBlockBodyAltair{RandaoReveal: randaoReveal, Eth1Data: eth1Data, Graffiti: graffiti, SyncAggregate: syncAggregate}
+	case *consensus.BeaconBlockBellatrix:
+		obj.Slot, obj.ProposerIndex, obj.ParentRoot = slot, proposerIndex, parentRoot
+		obj.Body = &consensus.BeaconBlockBodyBellatrix{RandaoReveal: randaoReveal, Eth1Data: eth1Data, Graffiti: graffiti, SyncAggregate: syncAggregate}
+	case *consensus.BeaconBlockCapella:
+		obj.Slot, obj.ProposerIndex, obj.ParentRoot = slot, proposerIndex, parentRoot
+		obj.Body = &consensus.BeaconBlockBodyCapella{RandaoReveal: randaoReveal, Eth1Data: eth1Data, Graffiti: graffiti, SyncAggregate: syncAggregate}
+	case *consensus.BeaconBlockDeneb:
+		obj.Slot, obj.ProposerIndex, obj.ParentRoot = slot, proposerIndex, parentRoot
+		obj.Body = &consensus.BeaconBlockBodyDeneb{RandaoReveal: randaoReveal, Eth1Data: eth1Data, Graffiti: graffiti, SyncAggregate: syncAggregate}
+	case *consensus.BeaconBlockElectra:
+		obj.Slot, obj.ProposerIndex, obj.ParentRoot = slot, proposerIndex, parentRoot
+		obj.Body = &consensus.BeaconBlockBodyElectra{RandaoReveal: randaoReveal, Eth1Data: eth1Data, Graffiti: graffiti, SyncAggregate: syncAggregate}
+	}
+
+	produced := &client.ProducedBlock{
+		Version:               state.Version,
+		ExecutionPayloadValue: new(big.Int),
+		ConsensusBlockValue:   new(big.Int),
+		Block:                 block,
+		KZGProofs:             [][48]byte{},
+		Blobs:                 [][131072]byte{},
+	}
+	return produced, nil
+}
+
+// AttestationData returns the attestation data of the slot on top of the latest
+// canonical block at or before the slot
+func (m *MemoryBackend) AttestationData(ctx context.Context, slot, committeeIndex uint64) (*consensus.AttestationData, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	epoch := slot / m.spec.SlotsPerEpoch
+
+	blockRoot, err := m.canonicalRootAt(slot)
+	if err != nil {
+		return nil, err
+	}
+	targetRoot, err := m.canonicalRootAt(epoch * m.spec.SlotsPerEpoch)
+	if err != nil {
+		return nil, err
+	}
+	source := m.headState().CurrentJustifiedCheckpoint()
+	if source == nil {
+		source = &consensus.Checkpoint{}
+	}
+	// from Electra the committee index is set in the committee bits
+	if m.spec.VersionAtSlot(slot) >= consensus.VersionElectra {
+		committeeIndex = 0
+	}
+
+	data := &consensus.AttestationData{
+		Slot:            slot,
+		Index:           committeeIndex,
+		BeaconBlockHash: blockRoot,
+		Source:          source,
+		Target:          &consensus.Checkpoint{Epoch: epoch, Root: targetRoot},
+	}
+	return data, nil
+}
+
+// canonicalRootAt returns the root of the latest canonical block at or before the
+// slot or the root of the genesis block if there is none
+func (m *MemoryBackend) canonicalRootAt(slot uint64) ([32]byte, error) {
+	found := false
+	var latest uint64
+	for blockSlot := range m.canonical {
+		if blockSlot <= slot && (!found || blockSlot > latest) {
+			latest, found = blockSlot, true
+		}
+	}
+	if found {
+		return m.canonical[latest], nil
+	}
+
+	// the state root of the genesis header is only set once the first slot is processed
+	header := *m.genesis.LatestBlockHeader()
+	if header.StateRoot == [32]byte{} {
+		stateRoot, err := m.spec.HashTreeRoot(m.genesis.State)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		header.StateRoot = stateRoot
+	}
+	return header.HashTreeRoot()
+}
+
+// AggregateAttestation aggregates the attestations of the pool with the slot and
+// the attestation data root. The attestations that overlap with the aggregate are
+// not included.
+func (m *MemoryBackend) AggregateAttestation(ctx context.Context, slot uint64, dataRoot [32]byte) (*consensus.Attestation, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	attestations := []*consensus.Attestation{}
+	for _, attestation := range m.attestations {
+		if attestation.Data == nil || attestation.Data.Slot != slot {
+			continue
+		}
+		root, err := attestation.Data.HashTreeRoot()
+		if err != nil {
+			return nil, err
+		}
+		if root == dataRoot {
+			attestations = append(attestations, attestation)
+		}
+	}
+	if len(attestations) == 0 {
+		return nil, fmt.Errorf("%w: no attestations at slot %d with data root 0x%x", client.ErrorNotFound, slot, dataRoot)
+	}
+
+	// start with the attestation with the most votes
+	sort.SliceStable(attestations, func(i, j int) bool {
+		return countBits(attestations[i].AggregationBits) > countBits(attestations[j].AggregationBits)
+	})
+
+	bits := bitlist.BitList(attestations[0].AggregationBits).Copy()
+	signatures := []*bls.Signature{}
+	for _, attestation := range attestations {
+		other := bitlist.BitList(attestation.AggregationBits)
+		if len(signatures) != 0 && (other.Len() != bits.Len() || overlaps(bits, other)) {
+			continue
+		}
+		signature := new(bls.Signature)
+		if err := signature.Deserialize(attestation.Signature[:]); err != nil {
+			return nil, fmt.Errorf("%w: invalid attestation signature: %v", client.ErrorBadRequest, err)
+		}
+		signatures = append(signatures, signature)
+
+		for indx := uint64(0); indx < other.Len(); indx++ {
+			if other.BitAt(indx) {
+				bits.SetBitAt(indx, true)
+			}
+		}
+	}
+
+	aggregate := &consensus.Attestation{
+		AggregationBits: bits,
+		Data:            attestations[0].Data,
+		Signature:       bls.AggregateSignatures(signatures).Serialize(),
+	}
+	return aggregate, nil
+}
+
+func countBits(bits bitlist.BitList) (count int) {
+	for indx := uint64(0); indx < bits.Len(); indx++ {
+		if bits.BitAt(indx) {
+			count++
+		}
+	}
+	return
+}
+
+func overlaps(a, b bitlist.BitList) bool {
+	for indx := uint64(0); indx < a.Len(); indx++ {
+		if a.BitAt(indx) && b.BitAt(indx) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MemoryBackend) SubmitAggregateAndProofs(ctx context.Context, aggregates []*consensus.SignedAggregateAndProof) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.aggregateAndProofs = append(m.aggregateAndProofs, aggregates...)
+	return nil
+}
+
+func (m *MemoryBackend) SubmitBeaconCommitteeSubscriptions(ctx context.Context, subs []*client.BeaconCommitteeSubscription) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.beaconCommitteeSubscriptions = append(m.beaconCommitteeSubscriptions, subs...)
+	return nil
+}
+
+func (m *MemoryBackend) SubmitSyncCommitteeSubscriptions(ctx context.Context, subs []*client.SyncCommitteeSubscription) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.syncCommitteeSubscriptions = append(m.syncCommitteeSubscriptions, subs...)
+	return nil
+}
+
+// SyncCommitteeContribution aggregates the sync committee messages of the pool with the
+// slot and the block root of the members of the subcommittee
+func (m *MemoryBackend) SyncCommitteeContribution(ctx context.Context, slot, subcommitteeIndex uint64, blockRoot [32]byte) (*consensus.SyncCommitteeContribution, error) {
+	if subcommitteeIndex >= syncCommitteeSubnetCount {
+		return nil, fmt.Errorf("%w: invalid subcommittee index %d", client.ErrorBadRequest, subcommitteeIndex)
+	}
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	state := m.headState()
+
+	committee, err := m.syncCommitteeAt(state, slot/m.spec.SlotsPerEpoch)
+	if err != nil {
+		return nil, err
+	}
+	validators := state.Validators()
+
+	size := m.spec.SyncCommitteeSize / syncCommitteeSubnetCount
+	members := committee.PubKeys[subcommitteeIndex*size : (subcommitteeIndex+1)*size]
+
+	// the aggregation bits are a bitvector of the subcommittee
+	bits := make([]byte, (size+7)/8)
+	signatures := []*bls.Signature{}
+	for _, msg := range m.syncCommitteeMessages {
+		if msg.Slot != slot || msg.BlockRoot != blockRoot || msg.ValidatorIndex >= uint64(len(validators)) {
+			continue
+		}
+		pubKey := validators[msg.ValidatorIndex].Pubkey
+
+		// a validator signs once for each of its positions in the subcommittee
+		for position, member := range members {
+			if member != pubKey || bits[position/8]&(1<<(position%8)) != 0 {
+				continue
+			}
+			signature := new(bls.Signature)
+			if err := signature.Deserialize(msg.Signature[:]); err != nil {
+				return nil, fmt.Errorf("%w: invalid sync committee signature: %v", client.ErrorBadRequest, err)
+			}
+			signatures = append(signatures, signature)
+			bits[position/8] |= 1 << (position % 8)
+		}
+	}
+	if len(signatures) == 0 {
+		return nil, fmt.Errorf("%w: no sync committee messages at slot %d with block root 0x%x", client.ErrorNotFound, slot, blockRoot)
+	}
+
+	contribution := &consensus.SyncCommitteeContribution{
+		Slot:              slot,
+		BeaconBlockRoot:   blockRoot,
+		SubcommitteeIndex: subcommitteeIndex,
+		AggregationBits:   bits,
+		Signature:         bls.AggregateSignatures(signatures).Serialize(),
+	}
+	return contribution, nil
+}
+
+func (m *MemoryBackend) SubmitContributionAndProofs(ctx context.Context, contributions []*consensus.SignedContributionAndProof) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.contributionAndProofs = append(m.contributionAndProofs, contributions...)
+	for _, contribution := range contributions {
+		m.emit("contribution_and_proof", contribution)
+	}
+	return nil
+}
+
+func (m *MemoryBackend) PrepareBeaconProposer(ctx context.Context, preparations []*client.ProposalPreparation) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.proposalPreparations = append(m.proposalPreparations, preparations...)
+	return nil
+}
+
+func (m *MemoryBackend) RegisterValidator(ctx context.Context, registrations []*client.SignedValidatorRegistration) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.validatorRegistrations = append(m.validatorRegistrations, registrations...)
+	return nil
+}
+
+func (m *MemoryBackend) BlockRewards(ctx context.Context, blockID string) (*client.BlockRewards, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	root, err := m.resolveBlockRoot(blockID)
+	if err != nil {
+		return nil, err
+	}
+	rewards, ok := m.blockRewards[root]
+	if !ok {
+		return nil, fmt.Errorf("%w: rewards of block %s", client.ErrorNotFound, blockID)
+	}
+	return rewards, nil
+}
+
+// AttestationRewards returns the attestation rewards of the epoch of the validators
+// with the ids. The ids are matched with the head state.
+func (m *MemoryBackend) AttestationRewards(ctx context.Context, epoch uint64, ids []string) (*client.AttestationRewards, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	rewards, ok := m.attestationRewards[epoch]
+	if !ok {
+		return nil, fmt.Errorf("%w: attestation rewards of epoch %d", client.ErrorNotFound, epoch)
+	}
+	if len(ids) == 0 {
+		return rewards, nil
+	}
+
+	validators := m.matchValidatorSet(ids)
+
+	res := &client.AttestationRewards{
+		IdealRewards: rewards.IdealRewards,
+		TotalRewards: []*client.TotalAttestationReward{},
+	}
+	for _, reward := range rewards.TotalRewards {
+		if _, ok := validators[reward.ValidatorIndex]; ok {
+			res.TotalRewards = append(res.TotalRewards, reward)
+		}
+	}
+	return res, nil
+}
+
+// SyncCommitteeRewards returns the sync committee rewards of the block of the validators
+// with the ids. The ids are matched with the head state.
+func (m *MemoryBackend) SyncCommitteeRewards(ctx context.Context, blockID string, ids []string) ([]*client.SyncCommitteeReward, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	root, err := m.resolveBlockRoot(blockID)
+	if err != nil {
+		return nil, err
+	}
+	rewards, ok := m.syncCommitteeRewards[root]
+	if !ok {
+		return nil, fmt.Errorf("%w: sync committee rewards of block %s", client.ErrorNotFound, blockID)
+	}
+	if len(ids) == 0 {
+		return rewards, nil
+	}
+
+	validators := m.matchValidatorSet(ids)
+
+	res := []*client.SyncCommitteeReward{}
+	for _, reward := range rewards {
+		if _, ok := validators[reward.ValidatorIndex]; ok {
+			res = append(res, reward)
+		}
+	}
+	return res, nil
+}
+
+// matchValidatorSet returns the indexes of the validators of the head state with the ids
+func (m *MemoryBackend) matchValidatorSet(ids []string) map[uint64]struct{} {
+	validators := map[uint64]struct{}{}
+	for _, indx := range matchValidators(m.headState(), ids) {
+		validators[indx] = struct{}{}
+	}
+	return validators
+}
+
+func (m *MemoryBackend) LightClientBootstrap(ctx context.Context, blockRoot [32]byte) (*client.LightClientObject, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	bootstrap, ok := m.lightClientBootstraps[blockRoot]
+	if !ok {
+		return nil, fmt.Errorf("%w: light client bootstrap of block 0x%x", client.ErrorNotFound, blockRoot)
+	}
+	return bootstrap, nil
+}
+
+func (m *MemoryBackend) LightClientUpdates(ctx context.Context, startPeriod, count uint64) ([]*client.LightClientObject, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	updates := []*client.LightClientObject{}
+	for period := startPeriod; period < startPeriod+count; period++ {
+		if update, ok := m.lightClientUpdates[period]; ok {
+			updates = append(updates, update)
+		}
+	}
+	return updates, nil
+}
+
+func (m *MemoryBackend) LightClientFinalityUpdate(ctx context.Context) (*client.LightClientObject, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if m.lightClientFinalityUpdate == nil {
+		return nil, fmt.Errorf("%w: light client finality update", client.ErrorNotFound)
+	}
+	return m.lightClientFinalityUpdate, nil
+}
+
+func (m *MemoryBackend) LightClientOptimisticUpdate(ctx context.Context) (*client.LightClientObject, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if m.lightClientOptimisticUpdate == nil {
+		return nil, fmt.Errorf("%w: light client optimistic update", client.ErrorNotFound)
+	}
+	return m.lightClientOptimisticUpdate, nil
+}
+
+// Events notifies the head and block events of the added blocks, the submitted pool
+// objects and the light client updates. The events are dropped if the subscriber
+// does not keep up with them.
+func (m *MemoryBackend) Events(ctx context.Context, topics []string) (<-chan *Event, error) {
+	sub := &memorySubscriber{
+		topics: map[string]struct{}{},
+		ch:     make(chan *Event, 64),
+	}
+	for _, topic := range topics {
+		sub.topics[topic] = struct{}{}
+	}
+
+	m.lock.Lock()
+	m.subscribers = append(m.subscribers, sub)
+	m.lock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		m.lock.Lock()
+		defer m.lock.Unlock()
+
+		for i, elem := range m.subscribers {
+			if elem == sub {
+				m.subscribers = append(m.subscribers[:i], m.subscribers[i+1:]...)
+				break
+			}
+		}
+		close(sub.ch)
+	}()
+
+	return sub.ch, nil
+}
+
+// emit sends the event to the subscribers of the topic. The lock must be held.
+func (m *MemoryBackend) emit(topic string, data interface{}) {
+	event := &Event{Topic: topic, Data: data}
+
+	for _, sub := range m.subscribers {
+		if _, ok := sub.topics[topic]; !ok {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+		}
+	}
+}
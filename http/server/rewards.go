@@ -0,0 +1,68 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+func (s *Server) handleBlockRewards(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	rewards, err := s.backend.BlockRewards(r.Context(), params["block_id"])
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, rewards)
+}
+
+func (s *Server) handleAttestationRewards(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	epoch, err := parseUintParam(params, "epoch")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ids, err := decodeValidatorIDs(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rewards, err := s.backend.AttestationRewards(r.Context(), epoch, ids)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, rewards)
+}
+
+func (s *Server) handleSyncCommitteeRewards(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	ids, err := decodeValidatorIDs(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rewards, err := s.backend.SyncCommitteeRewards(r.Context(), params["block_id"], ids)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, rewards)
+}
+
+// decodeValidatorIDs decodes the validator ids (indexes or public keys) of the
+// body of the rewards requests. The body is optional.
+func decodeValidatorIDs(r *http.Request) ([]string, error) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	if len(data) == 0 {
+		return ids, nil
+	}
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
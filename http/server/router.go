@@ -0,0 +1,67 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request, params map[string]string)
+
+type route struct {
+	method   string
+	segments []string
+	handler  handlerFunc
+}
+
+// router matches the requests with the routes of the api. The parameters
+// of the routes are the segments with the form {name}.
+type router struct {
+	routes []*route
+}
+
+func (r *router) add(method string, pattern string, handler handlerFunc) {
+	r.routes = append(r.routes, &route{
+		method:   method,
+		segments: strings.Split(strings.Trim(pattern, "/"), "/"),
+		handler:  handler,
+	})
+}
+
+// match returns the handler of the route and the value of its parameters. The
+// result is nil if there is no route or allowed is false if the route exists
+// with another method.
+func (r *router) match(method string, path string) (handlerFunc, map[string]string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	allowed := true
+	for _, route := range r.routes {
+		params, ok := route.match(segments)
+		if !ok {
+			continue
+		}
+		if route.method != method {
+			allowed = false
+			continue
+		}
+		return route.handler, params, true
+	}
+	return nil, nil, allowed
+}
+
+func (r *route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+
+	params := map[string]string{}
+	for i, segment := range r.segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params[segment[1:len(segment)-1]] = segments[i]
+			continue
+		}
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
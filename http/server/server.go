@@ -0,0 +1,1240 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	ssz "github.com/ferranbt/fastssz"
+	consensus "github.com/umbracle/go-eth-consensus"
+	client "github.com/umbracle/go-eth-consensus/http"
+)
+
+const (
+	contentTypeJSON = "application/json"
+	contentTypeSSZ  = "application/octet-stream"
+
+	headerConsensusVersion = "Eth-Consensus-Version"
+)
+
+type Config struct {
+	logger *log.Logger
+}
+
+type ConfigOption func(*Config)
+
+func WithLogger(logger *log.Logger) ConfigOption {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// Server serves the beacon api routes consumed by the http client on top of a
+// BeaconBackend. The objects are encoded with the same rules as the client and
+// blocks, states and light client objects are also served with ssz if the request
+// accepts it. All the routes of the node, config, beacon, rewards, light client,
+// validator, debug and events endpoints are served. The builder api is not served
+// since it is served by the relays and not by the beacon node.
+type Server struct {
+	backend BeaconBackend
+	config  *Config
+	router  *router
+}
+
+func New(backend BeaconBackend, opts ...ConfigOption) *Server {
+	config := &Config{
+		logger: log.New(io.Discard, "", 0),
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	s := &Server{
+		backend: backend,
+		config:  config,
+		router:  &router{},
+	}
+	s.registerRoutes()
+	return s
+}
+
+func (s *Server) registerRoutes() {
+	r := s.router
+
+	// node
+	r.add(http.MethodGet, "/eth/v1/node/version", s.handleVersion)
+	r.add(http.MethodGet, "/eth/v1/node/syncing", s.handleSyncing)
+	r.add(http.MethodGet, "/eth/v1/node/health", s.handleHealth)
+	r.add(http.MethodGet, "/eth/v1/node/identity", s.handleIdentity)
+	r.add(http.MethodGet, "/eth/v1/node/peers", s.handlePeers)
+	r.add(http.MethodGet, "/eth/v1/node/peers/{peer_id}", s.handlePeer)
+	r.add(http.MethodGet, "/eth/v1/node/peer_count", s.handlePeerCount)
+
+	// config
+	r.add(http.MethodGet, "/eth/v1/config/spec", s.handleSpec)
+	r.add(http.MethodGet, "/eth/v1/config/fork_schedule", s.handleForkSchedule)
+	r.add(http.MethodGet, "/eth/v1/config/deposit_contract", s.handleDepositContract)
+
+	// beacon
+	r.add(http.MethodGet, "/eth/v1/beacon/genesis", s.handleGenesis)
+	r.add(http.MethodGet, "/eth/v1/beacon/states/{state_id}/root", s.handleStateRoot)
+	r.add(http.MethodGet, "/eth/v1/beacon/states/{state_id}/fork", s.handleStateFork)
+	r.add(http.MethodGet, "/eth/v1/beacon/states/{state_id}/finality_checkpoints", s.handleFinalityCheckpoints)
+	r.add(http.MethodGet, "/eth/v1/beacon/states/{state_id}/validators", s.handleValidators)
+	r.add(http.MethodPost, "/eth/v1/beacon/states/{state_id}/validators", s.handleValidators)
+	r.add(http.MethodGet, "/eth/v1/beacon/states/{state_id}/validators/{validator_id}", s.handleValidator)
+	r.add(http.MethodGet, "/eth/v1/beacon/states/{state_id}/validator_balances", s.handleValidatorBalances)
+	r.add(http.MethodGet, "/eth/v1/beacon/states/{state_id}/committees", s.handleCommittees)
+	r.add(http.MethodGet, "/eth/v1/beacon/states/{state_id}/sync_committees", s.handleSyncCommittees)
+	r.add(http.MethodGet, "/eth/v1/beacon/states/{state_id}/randao", s.handleRandao)
+	r.add(http.MethodGet, "/eth/v1/beacon/headers/{block_id}", s.handleBlockHeader)
+	r.add(http.MethodGet, "/eth/v2/beacon/blocks/{block_id}", s.handleBlock)
+	r.add(http.MethodGet, "/eth/v1/beacon/blocks/{block_id}/root", s.handleBlockRoot)
+	r.add(http.MethodGet, "/eth/v1/beacon/blocks/{block_id}/attestations", s.handleBlockAttestations)
+	r.add(http.MethodPost, "/eth/v1/beacon/blocks", s.handlePublishBlock)
+	r.add(http.MethodPost, "/eth/v2/beacon/blocks", s.handlePublishBlockContents)
+	r.add(http.MethodPost, "/eth/v2/beacon/blinded_blocks", s.handlePublishBlindedBlock)
+	r.add(http.MethodGet, "/eth/v1/beacon/blob_sidecars/{block_id}", s.handleBlobSidecars)
+	r.add(http.MethodGet, "/eth/v1/beacon/pool/attestations", s.handlePoolAttestations)
+	r.add(http.MethodPost, "/eth/v1/beacon/pool/attestations", s.handleSubmitAttestations)
+	r.add(http.MethodPost, "/eth/v1/beacon/pool/sync_committees", s.handleSubmitSyncCommitteeMessages)
+	r.add(http.MethodGet, "/eth/v1/beacon/pool/attester_slashings", s.handlePoolAttesterSlashings)
+	r.add(http.MethodPost, "/eth/v1/beacon/pool/attester_slashings", s.handleSubmitAttesterSlashing)
+	r.add(http.MethodGet, "/eth/v1/beacon/pool/proposer_slashings", s.handlePoolProposerSlashings)
+	r.add(http.MethodPost, "/eth/v1/beacon/pool/proposer_slashings", s.handleSubmitProposerSlashing)
+	r.add(http.MethodGet, "/eth/v1/beacon/pool/voluntary_exits", s.handlePoolVoluntaryExits)
+	r.add(http.MethodPost, "/eth/v1/beacon/pool/voluntary_exits", s.handleSubmitVoluntaryExit)
+	r.add(http.MethodGet, "/eth/v1/beacon/pool/bls_to_execution_changes", s.handlePoolBLSToExecutionChanges)
+	r.add(http.MethodPost, "/eth/v1/beacon/pool/bls_to_execution_changes", s.handleSubmitBLSToExecutionChanges)
+
+	// light client
+	r.add(http.MethodGet, "/eth/v1/beacon/light_client/bootstrap/{block_root}", s.handleLightClientBootstrap)
+	r.add(http.MethodGet, "/eth/v1/beacon/light_client/updates", s.handleLightClientUpdates)
+	r.add(http.MethodGet, "/eth/v1/beacon/light_client/finality_update", s.handleLightClientFinalityUpdate)
+	r.add(http.MethodGet, "/eth/v1/beacon/light_client/optimistic_update", s.handleLightClientOptimisticUpdate)
+
+	// validator
+	r.add(http.MethodPost, "/eth/v1/validator/duties/attester/{epoch}", s.handleAttesterDuties)
+	r.add(http.MethodGet, "/eth/v1/validator/duties/proposer/{epoch}", s.handleProposerDuties)
+	r.add(http.MethodPost, "/eth/v1/validator/duties/sync/{epoch}", s.handleSyncCommitteeDuties)
+	r.add(http.MethodGet, "/eth/v2/validator/blocks/{slot}", s.handleProduceBlockV2)
+	r.add(http.MethodGet, "/eth/v3/validator/blocks/{slot}", s.handleProduceBlock)
+	r.add(http.MethodGet, "/eth/v1/validator/attestation_data", s.handleAttestationData)
+	r.add(http.MethodGet, "/eth/v1/validator/aggregate_attestation", s.handleAggregateAttestation)
+	r.add(http.MethodPost, "/eth/v1/validator/aggregate_and_proofs", s.handleSubmitAggregateAndProofs)
+	r.add(http.MethodPost, "/eth/v1/validator/beacon_committee_subscriptions", s.handleBeaconCommitteeSubscriptions)
+	r.add(http.MethodPost, "/eth/v1/validator/sync_committee_subscriptions", s.handleSyncCommitteeSubscriptions)
+	r.add(http.MethodGet, "/eth/v1/validator/sync_committee_contribution", s.handleSyncCommitteeContribution)
+	r.add(http.MethodPost, "/eth/v1/validator/contribution_and_proofs", s.handleSubmitContributionAndProofs)
+	r.add(http.MethodPost, "/eth/v1/validator/prepare_beacon_proposer", s.handlePrepareBeaconProposer)
+	r.add(http.MethodPost, "/eth/v1/validator/register_validator", s.handleRegisterValidator)
+
+	// rewards
+	r.add(http.MethodGet, "/eth/v1/beacon/rewards/blocks/{block_id}", s.handleBlockRewards)
+	r.add(http.MethodPost, "/eth/v1/beacon/rewards/attestations/{epoch}", s.handleAttestationRewards)
+	r.add(http.MethodPost, "/eth/v1/beacon/rewards/sync_committee/{block_id}", s.handleSyncCommitteeRewards)
+
+	// events
+	r.add(http.MethodGet, "/eth/v1/events", s.handleEvents)
+
+	// debug
+	r.add(http.MethodGet, "/eth/v2/debug/beacon/states/{state_id}", s.handleDebugState)
+	r.add(http.MethodGet, "/eth/v2/debug/beacon/heads", s.handleHeads)
+	r.add(http.MethodGet, "/eth/v1/debug/fork_choice", s.handleForkChoice)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.config.logger.Printf("[TRACE] Http request: method, %s, path, %s", r.Method, r.URL.Path)
+
+	handler, params, allowed := s.router.match(r.Method, r.URL.Path)
+	if handler == nil {
+		if !allowed {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		} else {
+			writeError(w, http.StatusNotFound, "route not found")
+		}
+		return
+	}
+	handler(w, r, params)
+}
+
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	version, err := s.backend.Version(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, &struct {
+		Version string `json:"version"`
+	}{Version: version})
+}
+
+func (s *Server) handleSyncing(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	syncing, err := s.backend.Syncing(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, syncing)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	syncing, err := s.backend.Syncing(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	if syncing.IsSyncing {
+		w.WriteHeader(http.StatusPartialContent)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleIdentity(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	identity, err := s.backend.Identity(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, identity)
+}
+
+func (s *Server) handlePeers(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	peers, err := s.backend.Peers(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+
+	states, directions := splitQuery(r, "state"), splitQuery(r, "direction")
+
+	res := []*client.Peer{}
+	for _, peer := range peers {
+		if len(states) != 0 && !contains(states, peer.State) {
+			continue
+		}
+		if len(directions) != 0 && !contains(directions, peer.Direction) {
+			continue
+		}
+		res = append(res, peer)
+	}
+	s.writeData(w, res)
+}
+
+func (s *Server) handlePeer(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	peers, err := s.backend.Peers(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	for _, peer := range peers {
+		if peer.PeerID == params["peer_id"] {
+			s.writeData(w, peer)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "peer not found")
+}
+
+func (s *Server) handlePeerCount(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	peers, err := s.backend.Peers(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+
+	count := &client.PeerCount{}
+	for _, peer := range peers {
+		switch peer.State {
+		case "disconnected":
+			count.Disconnected++
+		case "connecting":
+			count.Connecting++
+		case "connected":
+			count.Connected++
+		case "disconnecting":
+			count.Disconnecting++
+		}
+	}
+	s.writeData(w, count)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) handleSpec(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	spec, err := s.backend.Spec(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, spec)
+}
+
+func (s *Server) handleForkSchedule(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	spec, err := s.backend.Spec(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, forkSchedule(spec))
+}
+
+// forkSchedule returns the scheduled forks of the spec
+func forkSchedule(spec *consensus.Spec) []*consensus.Fork {
+	forks := []*consensus.Fork{}
+
+	previous := spec.GenesisForkVersion
+	for version := consensus.VersionPhase0; version <= consensus.VersionElectra; version++ {
+		forkVersion := spec.ForkVersion(version)
+		if version != consensus.VersionPhase0 && forkVersion == (consensus.Domain{}) {
+			// the fork is not scheduled
+			break
+		}
+		forks = append(forks, &consensus.Fork{
+			PreviousVersion: previous,
+			CurrentVersion:  forkVersion,
+			Epoch:           spec.ForkEpoch(version),
+		})
+		previous = forkVersion
+	}
+	return forks
+}
+
+func (s *Server) handleDepositContract(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	depositContract, err := s.backend.DepositContract(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, depositContract)
+}
+
+func (s *Server) handleGenesis(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	genesis, err := s.backend.Genesis(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, genesis)
+}
+
+func (s *Server) handleStateRoot(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	state, err := s.backend.State(r.Context(), params["state_id"])
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	root, err := state.HashTreeRoot()
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, &rootResponse{Root: root})
+}
+
+type rootResponse struct {
+	Root [32]byte `json:"root"`
+}
+
+func (s *Server) handleStateFork(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	state, err := s.backend.State(r.Context(), params["state_id"])
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, state.Fork())
+}
+
+func (s *Server) handleFinalityCheckpoints(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	state, err := s.backend.State(r.Context(), params["state_id"])
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, &client.FinalizedCheckpoints{
+		PreviousJustifiedCheckpoint: state.PreviousJustifiedCheckpoint(),
+		CurrentJustifiedCheckpoint:  state.CurrentJustifiedCheckpoint(),
+		FinalizedCheckpoint:         state.FinalizedCheckpoint(),
+	})
+}
+
+func (s *Server) handleValidators(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	// the filter is in the query of the GET requests and in the body of the POST ones
+	filter := &client.ValidatorsFilter{
+		IDs: splitQuery(r, "id"),
+	}
+	for _, status := range splitQuery(r, "status") {
+		filter.Statuses = append(filter.Statuses, client.ValidatorStatus(status))
+	}
+	if r.Method == http.MethodPost {
+		if err := decodeBody(r, filter); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	state, err := s.backend.State(r.Context(), params["state_id"])
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	spec, err := s.backend.Spec(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+
+	validators := []*client.Validator{}
+	for _, indx := range matchValidators(state, filter.IDs) {
+		val := newValidator(spec, state, indx)
+		if matchStatus(val.Status, filter.Statuses) {
+			validators = append(validators, val)
+		}
+	}
+	s.writeData(w, validators)
+}
+
+func (s *Server) handleValidatorBalances(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	state, err := s.backend.State(r.Context(), params["state_id"])
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+
+	balances := []*client.ValidatorBalance{}
+	for _, indx := range matchValidators(state, splitQuery(r, "id")) {
+		val := &client.ValidatorBalance{
+			Index: indx,
+		}
+		if stateBalances := state.Balances(); indx < uint64(len(stateBalances)) {
+			val.Balance = stateBalances[indx]
+		}
+		balances = append(balances, val)
+	}
+	s.writeData(w, balances)
+}
+
+func (s *Server) handleCommittees(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	epoch, err := parseUintQuery(r, "epoch")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	index, err := parseUintQuery(r, "index")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	slot, err := parseUintQuery(r, "slot")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	committees, err := s.backend.Committees(r.Context(), params["state_id"], epoch)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+
+	res := []*client.Committee{}
+	for _, committee := range committees {
+		if index != nil && committee.Index != *index {
+			continue
+		}
+		if slot != nil && committee.Slot != *slot {
+			continue
+		}
+		res = append(res, committee)
+	}
+	s.writeData(w, res)
+}
+
+func (s *Server) handleSyncCommittees(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	epoch, err := parseUintQuery(r, "epoch")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	committees, err := s.backend.SyncCommittees(r.Context(), params["state_id"], epoch)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, committees)
+}
+
+func (s *Server) handleRandao(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	epoch, err := parseUintQuery(r, "epoch")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	randao, err := s.backend.Randao(r.Context(), params["state_id"], epoch)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, &struct {
+		Randao [32]byte `json:"randao"`
+	}{Randao: randao})
+}
+
+// splitQuery returns the values of a query parameter that is either
+// repeated or a comma separated list
+func splitQuery(r *http.Request, key string) []string {
+	res := []string{}
+	for _, value := range r.URL.Query()[key] {
+		for _, item := range strings.Split(value, ",") {
+			if item != "" {
+				res = append(res, item)
+			}
+		}
+	}
+	return res
+}
+
+// matchValidators returns the indexes of the validators with the given ids
+// (index or public key) or all the indexes if there are no ids
+func matchValidators(state *consensus.VersionedBeaconState, ids []string) []uint64 {
+	res := []uint64{}
+	for indx, val := range state.Validators() {
+		if len(ids) == 0 || matchValidatorID(uint64(indx), val, ids) {
+			res = append(res, uint64(indx))
+		}
+	}
+	return res
+}
+
+func matchValidatorID(indx uint64, val *consensus.Validator, ids []string) bool {
+	for _, id := range ids {
+		if strconv.FormatUint(indx, 10) == id || fmt.Sprintf("0x%x", val.Pubkey) == strings.ToLower(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchStatus returns whether the status matches either a specific status
+// or a general one of the filter
+func matchStatus(status client.ValidatorStatus, statuses []client.ValidatorStatus) bool {
+	if len(statuses) == 0 {
+		return true
+	}
+	for _, s := range statuses {
+		if s == status || s == status.Group() {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) handleValidator(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	state, err := s.backend.State(r.Context(), params["state_id"])
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	spec, err := s.backend.Spec(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+
+	// the validator id is either the index or the public key
+	if indexes := matchValidators(state, []string{params["validator_id"]}); len(indexes) != 0 {
+		s.writeData(w, newValidator(spec, state, indexes[0]))
+		return
+	}
+	writeError(w, http.StatusNotFound, "validator not found")
+}
+
+func newValidator(spec *consensus.Spec, state *consensus.VersionedBeaconState, indx uint64) *client.Validator {
+	val := state.Validators()[indx]
+
+	var balance uint64
+	if balances := state.Balances(); indx < uint64(len(balances)) {
+		balance = balances[indx]
+	}
+
+	var epoch uint64
+	if spec.SlotsPerEpoch != 0 {
+		epoch = state.Slot() / spec.SlotsPerEpoch
+	}
+
+	return &client.Validator{
+		Index:   indx,
+		Balance: balance,
+		Status:  validatorStatus(val, epoch),
+		Validator: &client.ValidatorMetadata{
+			PubKey:                     fmt.Sprintf("0x%x", val.Pubkey),
+			WithdrawalCredentials:      fmt.Sprintf("0x%x", val.WithdrawalCredentials),
+			EffectiveBalance:           val.EffectiveBalance,
+			Slashed:                    val.Slashed,
+			ActivationElegibilityEpoch: val.ActivationEligibilityEpoch,
+			ActivationEpoch:            val.ActivationEpoch,
+			ExitEpoch:                  val.ExitEpoch,
+			WithdrawableEpoch:          val.WithdrawableEpoch,
+		},
+	}
+}
+
+// farFutureEpoch is the epoch of the validator events that are not scheduled
+const farFutureEpoch = math.MaxUint64
+
+// validatorStatus returns the status of the validator at the given epoch
+func validatorStatus(val *consensus.Validator, epoch uint64) client.ValidatorStatus {
+	switch {
+	case epoch < val.ActivationEpoch:
+		if val.ActivationEligibilityEpoch == farFutureEpoch {
+			return client.ValidatorStatusPendingInitialized
+		}
+		return client.ValidatorStatusPendingQueued
+
+	case epoch < val.ExitEpoch:
+		if val.ExitEpoch == farFutureEpoch {
+			return client.ValidatorStatusActiveOngoing
+		}
+		if val.Slashed {
+			return client.ValidatorStatusActiveSlashed
+		}
+		return client.ValidatorStatusActiveExiting
+
+	case epoch < val.WithdrawableEpoch:
+		if val.Slashed {
+			return client.ValidatorStatusExitedSlashed
+		}
+		return client.ValidatorStatusExitedUnslashed
+
+	default:
+		if val.EffectiveBalance != 0 {
+			return client.ValidatorStatusWithdrawalPossible
+		}
+		return client.ValidatorStatusWithdrawalDone
+	}
+}
+
+func (s *Server) handleBlockHeader(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	block, err := s.backend.Block(r.Context(), params["block_id"])
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	header, err := block.Header()
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	root, err := header.HashTreeRoot()
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, &client.BlockHeaderResponse{
+		Root:      root,
+		Canonical: true,
+		Header: &client.BlockHeader{
+			Message:   header,
+			Signature: block.Signature(),
+		},
+	})
+}
+
+func (s *Server) handleBlock(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	block, err := s.backend.Block(r.Context(), params["block_id"])
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeVersioned(w, r, block.Version, block.Block)
+}
+
+func (s *Server) handleBlockRoot(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	block, err := s.backend.Block(r.Context(), params["block_id"])
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	root, err := block.BlockRoot()
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, &rootResponse{Root: root})
+}
+
+func (s *Server) handleBlockAttestations(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	block, err := s.backend.Block(r.Context(), params["block_id"])
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+
+	var attestations interface{}
+	switch obj := block.Block.(type) {
+	case *consensus.SignedBeaconBlockPhase0:
+		attestations = obj.Block.Body.Attestations
+	case *consensus.SignedBeaconBlockAltair:
+		attestations = obj.Block.Body.Attestations
+	case *consensus.SignedBeaconBlockBellatrix:
+		attestations = obj.Block.Body.Attestations
+	case *consensus.SignedBeaconBlockCapella:
+		attestations = obj.Block.Body.Attestations
+	case *consensus.SignedBeaconBlockDeneb:
+		attestations = obj.Block.Body.Attestations
+	case *consensus.SignedBeaconBlockElectra:
+		attestations = obj.Block.Body.Attestations
+	default:
+		s.writeBackendError(w, fmt.Errorf("signed beacon block %T not supported", block.Block))
+		return
+	}
+	s.writeData(w, attestations)
+}
+
+func (s *Server) handlePublishBlock(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, err := s.blockVersion(r, data)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	block, err := consensus.NewSignedBeaconBlock(version)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := decodeObject(r, data, block); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if err := s.backend.PublishBlock(r.Context(), &consensus.VersionedSignedBeaconBlock{Version: version, Block: block}); err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handlePublishBlockContents(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	validation, err := parseBroadcastValidation(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	version, err := s.blockVersion(r, data)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	block, kzgProofs, blobs, err := decodeBlockContents(r, data, version)
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	versioned := &consensus.VersionedSignedBeaconBlock{Version: version, Block: block}
+	if err := s.backend.PublishBlockContents(r.Context(), versioned, kzgProofs, blobs, validation); err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// decodeBlockContents decodes a published block. From Deneb the block is
+// published along with its blobs and their proofs.
+func decodeBlockContents(r *http.Request, data []byte, version consensus.Version) (consensus.SignedBeaconBlock, [][48]byte, [][131072]byte, error) {
+	switch version {
+	case consensus.VersionDeneb:
+		contents := &consensus.SignedBlockContentsDeneb{}
+		if err := decodeObject(r, data, contents); err != nil {
+			return nil, nil, nil, err
+		}
+		if contents.SignedBlock == nil {
+			return nil, nil, nil, fmt.Errorf("signed block not found")
+		}
+		return contents.SignedBlock, contents.KZGProofs, contents.Blobs, nil
+
+	case consensus.VersionElectra:
+		contents := &consensus.SignedBlockContentsElectra{}
+		if err := decodeObject(r, data, contents); err != nil {
+			return nil, nil, nil, err
+		}
+		if contents.SignedBlock == nil {
+			return nil, nil, nil, fmt.Errorf("signed block not found")
+		}
+		return contents.SignedBlock, contents.KZGProofs, contents.Blobs, nil
+
+	default:
+		block, err := consensus.NewSignedBeaconBlock(version)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if err := decodeObject(r, data, block); err != nil {
+			return nil, nil, nil, err
+		}
+		return block, nil, nil, nil
+	}
+}
+
+func (s *Server) handlePublishBlindedBlock(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	validation, err := parseBroadcastValidation(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	version, err := s.blockVersion(r, data)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	block, err := consensus.NewVersionedSignedBlindedBeaconBlock(version)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := decodeObject(r, data, block.Object()); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if err := s.backend.PublishBlindedBlock(r.Context(), block, validation); err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// parseBroadcastValidation returns the broadcast validation of the query. The
+// validation is gossip if it is not set.
+func parseBroadcastValidation(r *http.Request) (client.BroadcastValidation, error) {
+	validation := client.BroadcastValidation(r.URL.Query().Get("broadcast_validation"))
+	switch validation {
+	case "":
+		return client.BroadcastValidationGossip, nil
+	case client.BroadcastValidationGossip, client.BroadcastValidationConsensus, client.BroadcastValidationConsensusAndEquivocation:
+		return validation, nil
+	default:
+		return "", fmt.Errorf("invalid broadcast_validation '%s'", validation)
+	}
+}
+
+var errSSZNotSupported = errors.New("ssz not supported")
+
+// decodeObject decodes the published object with ssz or json depending
+// on the content type of the request
+func decodeObject(r *http.Request, data []byte, obj interface{}) error {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), contentTypeSSZ) {
+		unmarshaler, ok := obj.(ssz.Unmarshaler)
+		if !ok {
+			return errSSZNotSupported
+		}
+		return unmarshaler.UnmarshalSSZ(data)
+	}
+	return client.Unmarshal(data, obj, false)
+}
+
+func writeDecodeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errSSZNotSupported) {
+		writeError(w, http.StatusUnsupportedMediaType, err.Error())
+		return
+	}
+	writeError(w, http.StatusBadRequest, err.Error())
+}
+
+func (s *Server) handleBlobSidecars(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	indices := map[uint64]struct{}{}
+	for _, str := range splitQuery(r, "indices") {
+		indx, err := strconv.ParseUint(str, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid index '%s'", str))
+			return
+		}
+		indices[indx] = struct{}{}
+	}
+
+	sidecars, err := s.backend.BlobSidecars(r.Context(), params["block_id"])
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+
+	res := []*consensus.BlobSidecar{}
+	for _, sidecar := range sidecars {
+		if _, ok := indices[sidecar.Index]; len(indices) != 0 && !ok {
+			continue
+		}
+		res = append(res, sidecar)
+	}
+	s.writeData(w, res)
+}
+
+// blockVersion returns the fork of a published block. It uses the consensus
+// version header or the slot of the block if the header is not set.
+func (s *Server) blockVersion(r *http.Request, data []byte) (consensus.Version, error) {
+	if versionStr := r.Header.Get(headerConsensusVersion); versionStr != "" {
+		return consensus.ParseVersion(versionStr)
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), contentTypeSSZ) {
+		return 0, fmt.Errorf("%s header not found", headerConsensusVersion)
+	}
+
+	// the block is wrapped along with its blobs from deneb
+	type signedBlock struct {
+		Message struct {
+			Slot uint64 `json:"slot,string"`
+		} `json:"message"`
+	}
+	var block struct {
+		signedBlock
+		SignedBlock *signedBlock `json:"signed_block"`
+	}
+	if err := json.Unmarshal(data, &block); err != nil {
+		return 0, err
+	}
+	spec, err := s.backend.Spec(r.Context())
+	if err != nil {
+		return 0, err
+	}
+	if block.SignedBlock != nil {
+		return spec.VersionAtSlot(block.SignedBlock.Message.Slot), nil
+	}
+	return spec.VersionAtSlot(block.Message.Slot), nil
+}
+
+func (s *Server) handleSubmitAttestations(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var attestations []*consensus.Attestation
+	if err := decodeBody(r, &attestations); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.backend.SubmitAttestations(r.Context(), attestations); err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleSubmitSyncCommitteeMessages(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var msgs []*consensus.SyncCommitteeMessage
+	if err := decodeBody(r, &msgs); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.backend.SubmitSyncCommitteeMessages(r.Context(), msgs); err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handlePoolAttestations(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	slot, err := parseUintQuery(r, "slot")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	committeeIndex, err := parseUintQuery(r, "committee_index")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	attestations, err := s.backend.PoolAttestations(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+
+	res := []*consensus.Attestation{}
+	for _, attestation := range attestations {
+		if slot != nil && attestation.Data.Slot != *slot {
+			continue
+		}
+		if committeeIndex != nil && attestation.Data.Index != *committeeIndex {
+			continue
+		}
+		res = append(res, attestation)
+	}
+	s.writeData(w, res)
+}
+
+func (s *Server) handlePoolAttesterSlashings(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	slashings, err := s.backend.PoolAttesterSlashings(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, slashings)
+}
+
+func (s *Server) handleSubmitAttesterSlashing(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	slashing := &consensus.AttesterSlashing{}
+	if err := decodeBody(r, slashing); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.backend.SubmitAttesterSlashing(r.Context(), slashing); err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handlePoolProposerSlashings(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	slashings, err := s.backend.PoolProposerSlashings(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, slashings)
+}
+
+func (s *Server) handleSubmitProposerSlashing(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	slashing := &consensus.ProposerSlashing{}
+	if err := decodeBody(r, slashing); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.backend.SubmitProposerSlashing(r.Context(), slashing); err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handlePoolVoluntaryExits(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	exits, err := s.backend.PoolVoluntaryExits(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, exits)
+}
+
+func (s *Server) handleSubmitVoluntaryExit(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	exit := &consensus.SignedVoluntaryExit{}
+	if err := decodeBody(r, exit); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.backend.SubmitVoluntaryExit(r.Context(), exit); err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handlePoolBLSToExecutionChanges(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	changes, err := s.backend.PoolBLSToExecutionChanges(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, changes)
+}
+
+func (s *Server) handleSubmitBLSToExecutionChanges(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var changes []*consensus.SignedBLSToExecutionChange
+	if err := decodeBody(r, &changes); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.backend.SubmitBLSToExecutionChanges(r.Context(), changes); err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleDebugState(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	state, err := s.backend.State(r.Context(), params["state_id"])
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeVersioned(w, r, state.Version, state.State)
+}
+
+func (s *Server) handleHeads(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	heads, err := s.backend.Heads(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, heads)
+}
+
+func (s *Server) handleForkChoice(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	forkChoice, err := s.backend.ForkChoice(r.Context())
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+
+	// the fork choice is not wrapped in a data field
+	data, err := client.Marshal(forkChoice)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.Write(data)
+}
+
+// parseUintQuery returns the value of an optional uint query parameter
+func parseUintQuery(r *http.Request, key string) (*uint64, error) {
+	str := r.URL.Query().Get(key)
+	if str == "" {
+		return nil, nil
+	}
+	num, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s '%s'", key, str)
+	}
+	return &num, nil
+}
+
+// parseUintParam returns the value of a uint parameter of the route
+func parseUintParam(params map[string]string, key string) (uint64, error) {
+	num, err := strconv.ParseUint(params[key], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s '%s'", key, params[key])
+	}
+	return num, nil
+}
+
+func decodeBody(r *http.Request, obj interface{}) error {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	return client.Unmarshal(data, obj, false)
+}
+
+// writeData writes the object in the data field of the json response
+func (s *Server) writeData(w http.ResponseWriter, obj interface{}) {
+	data, err := client.Marshal(obj)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", contentTypeJSON)
+	fmt.Fprintf(w, `{"data":%s}`, data)
+}
+
+// writeVersioned writes an object of the given fork with ssz if
+// the request accepts it or with json otherwise
+func (s *Server) writeVersioned(w http.ResponseWriter, r *http.Request, version consensus.Version, obj interface{}) {
+	w.Header().Set(headerConsensusVersion, version.String())
+
+	if acceptsSSZ(r) {
+		if marshaler, ok := obj.(ssz.Marshaler); ok {
+			data, err := marshaler.MarshalSSZ()
+			if err != nil {
+				s.writeBackendError(w, err)
+				return
+			}
+			w.Header().Set("Content-Type", contentTypeSSZ)
+			w.Write(data)
+			return
+		}
+	}
+
+	data, err := client.Marshal(obj)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", contentTypeJSON)
+	fmt.Fprintf(w, `{"version":"%s","execution_optimistic":false,"finalized":false,"data":%s}`, version, data)
+}
+
+// acceptsSSZ returns whether the request prefers ssz over json
+func acceptsSSZ(r *http.Request) bool {
+	var sszQuality, jsonQuality float64
+	for _, elem := range strings.Split(r.Header.Get("Accept"), ",") {
+		parts := strings.Split(elem, ";")
+
+		quality := 1.0
+		for _, param := range parts[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+
+		switch strings.TrimSpace(parts[0]) {
+		case contentTypeSSZ:
+			sszQuality = quality
+		case contentTypeJSON, "*/*":
+			if quality > jsonQuality {
+				jsonQuality = quality
+			}
+		}
+	}
+	return sszQuality > 0 && sszQuality >= jsonQuality
+}
+
+// writeBackendError writes the error with the status code of the client error it wraps
+func (s *Server) writeBackendError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, client.ErrorNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, client.ErrorBadRequest):
+		code = http.StatusBadRequest
+	case errors.Is(err, client.ErrorServiceUnavailable):
+		code = http.StatusServiceUnavailable
+	}
+	if code == http.StatusInternalServerError {
+		s.config.logger.Printf("[ERROR] Http request failed: %v", err)
+	}
+	writeError(w, code, err.Error())
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	data, err := json.Marshal(&struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}{Code: code, Message: msg})
+	if err != nil {
+		panic(err)
+	}
+
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(code)
+	w.Write(data)
+}
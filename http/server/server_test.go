@@ -0,0 +1,863 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	consensus "github.com/umbracle/go-eth-consensus"
+	"github.com/umbracle/go-eth-consensus/bitlist"
+	"github.com/umbracle/go-eth-consensus/bls"
+	client "github.com/umbracle/go-eth-consensus/http"
+	"github.com/umbracle/go-eth-consensus/spec"
+)
+
+type testChain struct {
+	spec    *consensus.Spec
+	genesis *consensus.VersionedBeaconState
+	block   *consensus.VersionedSignedBeaconBlock
+	state   *consensus.VersionedBeaconState
+}
+
+func newTestState(slot uint64, finalized consensus.Root) *consensus.BeaconStateAltair {
+	return &consensus.BeaconStateAltair{
+		GenesisTime:           10,
+		GenesisValidatorsRoot: consensus.Root{0x1},
+		Slot:                  slot,
+		Fork:                  &consensus.Fork{CurrentVersion: [4]byte{0x1}},
+		Slashings:             make([]uint64, 8192),
+		Validators: []*consensus.Validator{
+			{Pubkey: [48]byte{0x1}, ActivationEpoch: 0, ExitEpoch: 100},
+			{Pubkey: [48]byte{0x2}, ActivationEpoch: 100, ExitEpoch: 200},
+		},
+		Balances:            []uint64{1, 2},
+		FinalizedCheckpoint: &consensus.Checkpoint{Epoch: 1, Root: finalized},
+	}
+}
+
+func newTestChain(t *testing.T) *testChain {
+	spec := &consensus.Spec{
+		SlotsPerEpoch:      32,
+		GenesisForkVersion: consensus.Domain{0x0},
+		AltairForkVersion:  consensus.Domain{0x1},
+		AltairForkEpoch:    0,
+	}
+
+	genesis := &consensus.VersionedBeaconState{Version: consensus.VersionAltair, State: newTestState(0, consensus.Root{})}
+	state := &consensus.VersionedBeaconState{Version: consensus.VersionAltair, State: newTestState(1, consensus.Root{})}
+
+	stateRoot, err := state.HashTreeRoot()
+	require.NoError(t, err)
+
+	block := &consensus.VersionedSignedBeaconBlock{
+		Version: consensus.VersionAltair,
+		Block: &consensus.SignedBeaconBlockAltair{
+			Block: &consensus.BeaconBlockAltair{
+				Slot:      1,
+				StateRoot: stateRoot,
+				Body: &consensus.BeaconBlockBodyAltair{
+					Eth1Data:      &consensus.Eth1Data{},
+					SyncAggregate: &consensus.SyncAggregate{},
+					Graffiti:      [32]byte{0x1},
+				},
+			},
+		},
+	}
+	return &testChain{spec: spec, genesis: genesis, block: block, state: state}
+}
+
+func TestServer(t *testing.T) {
+	chain := newTestChain(t)
+
+	backend := NewMemoryBackend(chain.spec, chain.genesis)
+	require.NoError(t, backend.AddBlock(chain.block, chain.state))
+
+	srv := httptest.NewServer(New(backend))
+	defer srv.Close()
+
+	clt := client.New(srv.URL, client.WithUntrackedKeys())
+
+	blockRoot, err := chain.block.BlockRoot()
+	require.NoError(t, err)
+
+	t.Run("Node", func(t *testing.T) {
+		version, err := clt.Node().Version()
+		require.NoError(t, err)
+		require.Equal(t, "go-eth-consensus/memory", version)
+
+		syncing, err := clt.Node().Syncing()
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), syncing.HeadSlot)
+
+		healthy, err := clt.Node().Health()
+		require.NoError(t, err)
+		require.True(t, healthy)
+
+		backend.SetSyncing(true)
+		defer backend.SetSyncing(false)
+
+		_, err = clt.Node().Health()
+		require.ErrorIs(t, err, client.ErrorIncompleteData)
+	})
+
+	t.Run("Config", func(t *testing.T) {
+		spec, err := clt.Config().Spec()
+		require.NoError(t, err)
+		require.Equal(t, chain.spec, spec)
+
+		forks, err := clt.Config().ForkSchedule()
+		require.NoError(t, err)
+		require.Len(t, forks, 2)
+		require.Equal(t, [4]byte{0x1}, forks[1].CurrentVersion)
+	})
+
+	t.Run("Genesis", func(t *testing.T) {
+		genesis, err := clt.Beacon().Genesis()
+		require.NoError(t, err)
+		require.Equal(t, uint64(10), genesis.Time)
+		require.Equal(t, [32]byte{0x1}, genesis.Root)
+	})
+
+	t.Run("State", func(t *testing.T) {
+		root, err := clt.Beacon().GetRoot(client.Head)
+		require.NoError(t, err)
+		require.Equal(t, [32]byte(chain.block.StateRoot()), root)
+
+		fork, err := clt.Beacon().GetFork(client.Slot(1))
+		require.NoError(t, err)
+		require.Equal(t, [4]byte{0x1}, fork.CurrentVersion)
+
+		checkpoints, err := clt.Beacon().GetFinalityCheckpoints(client.Finalized)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), checkpoints.FinalizedCheckpoint.Epoch)
+
+		validators, err := clt.Beacon().GetValidators(client.Head)
+		require.NoError(t, err)
+		require.Len(t, validators, 2)
+		require.Equal(t, client.ValidatorStatusActiveExiting, validators[0].Status)
+		require.Equal(t, client.ValidatorStatusPendingQueued, validators[1].Status)
+		require.Equal(t, uint64(2), validators[1].Balance)
+
+		filtered, err := clt.Beacon().FilterValidators(client.Head, &client.ValidatorsFilter{Statuses: []client.ValidatorStatus{client.ValidatorStatusPending}})
+		require.NoError(t, err)
+		require.Len(t, filtered, 1)
+		require.Equal(t, uint64(1), filtered[0].Index)
+
+		filtered, err = clt.Beacon().FilterValidators(client.Head, &client.ValidatorsFilter{IDs: []string{"0", validators[1].Validator.PubKey}})
+		require.NoError(t, err)
+		require.Len(t, filtered, 2)
+
+		balances, err := clt.Beacon().GetValidatorBalances(client.Head, []string{"1"})
+		require.NoError(t, err)
+		require.Equal(t, []*client.ValidatorBalance{{Index: 1, Balance: 2}}, balances)
+
+		validator, err := clt.Beacon().GetValidatorByPubKey(validators[1].Validator.PubKey, client.Head)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), validator.Index)
+
+		_, err = clt.Beacon().GetValidatorByPubKey("0x01", client.Head)
+		require.ErrorIs(t, err, client.ErrorNotFound)
+
+		_, err = clt.Beacon().GetRoot(client.Slot(5))
+		require.ErrorIs(t, err, client.ErrorNotFound)
+	})
+
+	t.Run("Block", func(t *testing.T) {
+		root, err := clt.Beacon().GetBlockRoot(client.Head)
+		require.NoError(t, err)
+		require.Equal(t, blockRoot, root)
+
+		header, err := clt.Beacon().GetBlockHeader(client.Slot(1))
+		require.NoError(t, err)
+		require.Equal(t, blockRoot, header.Root)
+		require.Equal(t, uint64(1), header.Header.Message.Slot)
+
+		block, err := clt.Beacon().GetBlock(client.Head, &consensus.BeaconBlockAltair{})
+		require.NoError(t, err)
+		require.Equal(t, [32]byte{0x1}, block.Message.(*consensus.BeaconBlockAltair).Body.Graffiti)
+
+		attestations, err := clt.Beacon().GetBlockAttestations(client.Head)
+		require.NoError(t, err)
+		require.Empty(t, attestations)
+	})
+
+	t.Run("Debug", func(t *testing.T) {
+		for _, clt := range []*client.Client{clt, client.New(srv.URL, client.WithSSZ())} {
+			state, err := clt.Debug().GetState(client.Head)
+			require.NoError(t, err)
+			require.Equal(t, consensus.VersionAltair, state.Version)
+			require.Equal(t, uint64(1), state.Slot())
+		}
+
+		heads, err := clt.Debug().GetHeads()
+		require.NoError(t, err)
+		require.Len(t, heads, 1)
+		require.Equal(t, blockRoot, heads[0].Root)
+	})
+
+	t.Run("Publish", func(t *testing.T) {
+		for _, clt := range []*client.Client{clt, client.New(srv.URL, client.WithSSZ())} {
+			require.NoError(t, clt.Beacon().PublishSignedBlock(chain.block.Block))
+		}
+		published := backend.PublishedBlocks()
+		require.Len(t, published, 2)
+		for _, block := range published {
+			root, err := block.BlockRoot()
+			require.NoError(t, err)
+			require.Equal(t, blockRoot, root)
+		}
+
+		attestation := &consensus.Attestation{
+			AggregationBits: []byte{0x1},
+			Data: &consensus.AttestationData{
+				Slot:   1,
+				Source: &consensus.Checkpoint{},
+				Target: &consensus.Checkpoint{},
+			},
+		}
+		require.NoError(t, clt.Beacon().PublishAttestations([]*consensus.Attestation{attestation}))
+		require.Equal(t, []*consensus.Attestation{attestation}, backend.Attestations())
+
+		msg := &consensus.SyncCommitteeMessage{Slot: 1, ValidatorIndex: 2}
+		require.NoError(t, clt.Beacon().SubmitCommitteeDuties([]*consensus.SyncCommitteeMessage{msg}))
+		require.Equal(t, []*consensus.SyncCommitteeMessage{msg}, backend.SyncCommitteeMessages())
+	})
+
+	t.Run("Pool", func(t *testing.T) {
+		attestations, err := clt.Beacon().GetPoolAttestations(&client.PoolAttestationsFilter{Slot: uint64Ptr(1)})
+		require.NoError(t, err)
+		require.Len(t, attestations, 1)
+
+		attestations, err = clt.Beacon().GetPoolAttestations(&client.PoolAttestationsFilter{Slot: uint64Ptr(2)})
+		require.NoError(t, err)
+		require.Empty(t, attestations)
+
+		exit := &consensus.SignedVoluntaryExit{Exit: &consensus.VoluntaryExit{Epoch: 1, ValidatorIndex: 1}}
+		require.NoError(t, clt.Beacon().SubmitVoluntaryExit(exit))
+
+		exits, err := clt.Beacon().GetPoolVoluntaryExits()
+		require.NoError(t, err)
+		require.Equal(t, []*consensus.SignedVoluntaryExit{exit}, exits)
+
+		header := &consensus.SignedBeaconBlockHeader{Header: &consensus.BeaconBlockHeader{Slot: 1}}
+		slashing := &consensus.ProposerSlashing{Header1: header, Header2: header}
+		require.NoError(t, clt.Beacon().SubmitProposerSlashing(slashing))
+
+		slashings, err := clt.Beacon().GetPoolProposerSlashings()
+		require.NoError(t, err)
+		require.Equal(t, []*consensus.ProposerSlashing{slashing}, slashings)
+
+		changes, err := clt.Beacon().GetPoolBLSToExecutionChanges()
+		require.NoError(t, err)
+		require.Empty(t, changes)
+	})
+
+	t.Run("Peers", func(t *testing.T) {
+		identity, err := clt.Node().Identity()
+		require.NoError(t, err)
+		require.Equal(t, "0x00", identity.Metadata.SyncNets)
+
+		identity = &client.Identity{
+			PeerID:             "a",
+			P2PAddresses:       []string{"/ip4/127.0.0.1/tcp/9000"},
+			DiscoveryAddresses: []string{},
+			Metadata:           &client.IdentityMetadata{SeqNumber: 1, AttNets: "0x0100000000000000", SyncNets: "0x01"},
+		}
+		backend.SetIdentity(identity)
+
+		res, err := clt.Node().Identity()
+		require.NoError(t, err)
+		require.Equal(t, identity, res)
+
+		peers := []*client.Peer{
+			{PeerID: "b", State: "connected", Direction: "inbound"},
+			{PeerID: "c", State: "disconnected", Direction: "outbound"},
+		}
+		backend.SetPeers(peers)
+
+		found, err := clt.Node().Peers()
+		require.NoError(t, err)
+		require.Equal(t, peers, found)
+
+		peer, err := clt.Node().GetPeer("c")
+		require.NoError(t, err)
+		require.Equal(t, peers[1], peer)
+
+		_, err = clt.Node().GetPeer("d")
+		require.ErrorIs(t, err, client.ErrorNotFound)
+
+		count, err := clt.Node().PeerCount()
+		require.NoError(t, err)
+		require.Equal(t, &client.PeerCount{Connected: 1, Disconnected: 1}, count)
+	})
+
+	t.Run("PublishBlockContents", func(t *testing.T) {
+		require.NoError(t, clt.Beacon().PublishSignedBlockContents(chain.block.Block, nil, nil, client.BroadcastValidationConsensus))
+
+		published := backend.PublishedBlocks()
+		root, err := published[len(published)-1].BlockRoot()
+		require.NoError(t, err)
+		require.Equal(t, blockRoot, root)
+
+		// from deneb the blobs are published along with the block
+		block := &consensus.SignedBeaconBlockDeneb{
+			Block: &consensus.BeaconBlockDeneb{
+				Slot: 2,
+				Body: &consensus.BeaconBlockBodyDeneb{
+					Eth1Data:         &consensus.Eth1Data{},
+					SyncAggregate:    &consensus.SyncAggregate{},
+					ExecutionPayload: &consensus.ExecutionPayloadDeneb{},
+				},
+			},
+		}
+		kzgProofs, blobs := [][48]byte{{0x1}}, [][131072]byte{{0x2}}
+		require.NoError(t, clt.Beacon().PublishSignedBlockContents(block, kzgProofs, blobs, ""))
+
+		root, err = block.Block.HashTreeRoot()
+		require.NoError(t, err)
+
+		foundProofs, foundBlobs := backend.PublishedBlobs(root)
+		require.Equal(t, kzgProofs, foundProofs)
+		require.Equal(t, blobs, foundBlobs)
+
+		blinded := &consensus.VersionedSignedBlindedBeaconBlock{
+			Version: consensus.VersionBellatrix,
+			Bellatrix: &consensus.SignedBlindedBeaconBlock{
+				Block: &consensus.BlindedBeaconBlock{
+					Slot: 3,
+					Body: &consensus.BlindedBeaconBlockBody{
+						Eth1Data:               &consensus.Eth1Data{},
+						SyncAggregate:          &consensus.SyncAggregate{},
+						ExecutionPayloadHeader: &consensus.ExecutionPayloadHeader{},
+					},
+				},
+			},
+		}
+		require.NoError(t, clt.Beacon().PublishBlindedBlock(blinded, client.BroadcastValidationGossip))
+
+		publishedBlinded := backend.PublishedBlindedBlocks()
+		require.Len(t, publishedBlinded, 1)
+		require.Equal(t, consensus.VersionBellatrix, publishedBlinded[0].Version)
+		require.Equal(t, uint64(3), publishedBlinded[0].Bellatrix.Block.Slot)
+
+		resp, err := http.Post(srv.URL+"/eth/v2/beacon/blocks?broadcast_validation=none", "application/json", nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("BlobSidecars", func(t *testing.T) {
+		header := &consensus.SignedBeaconBlockHeader{Header: &consensus.BeaconBlockHeader{Slot: 1}}
+		sidecars := []*consensus.BlobSidecar{
+			{Index: 0, Blob: [131072]byte{0x1}, SignedBlockHeader: header},
+			{Index: 1, Blob: [131072]byte{0x2}, SignedBlockHeader: header},
+		}
+		backend.SetBlobSidecars(blockRoot, sidecars)
+
+		found, err := clt.Beacon().GetBlobSidecars(client.Head, nil)
+		require.NoError(t, err)
+		require.Equal(t, sidecars, found)
+
+		found, err = clt.Beacon().GetBlobSidecars(client.Slot(1), []uint64{1})
+		require.NoError(t, err)
+		require.Equal(t, sidecars[1:], found)
+
+		_, err = clt.Beacon().GetBlobSidecars(client.Genesis, nil)
+		require.ErrorIs(t, err, client.ErrorNotFound)
+	})
+
+	t.Run("Rewards", func(t *testing.T) {
+		_, err := clt.Rewards().GetBlockRewards(client.Head)
+		require.ErrorIs(t, err, client.ErrorNotFound)
+
+		blockRewards := &client.BlockRewards{ProposerIndex: 1, Total: 10, Attestations: 8, SyncAggregate: 2}
+		backend.SetBlockRewards(blockRoot, blockRewards)
+
+		found, err := clt.Rewards().GetBlockRewards(client.Head)
+		require.NoError(t, err)
+		require.Equal(t, blockRewards, found)
+
+		attestationRewards := &client.AttestationRewards{
+			IdealRewards: []*client.IdealAttestationReward{{EffectiveBalance: 1, Head: 2, Target: 2, Source: 2}},
+			TotalRewards: []*client.TotalAttestationReward{{ValidatorIndex: 0, Head: 2}, {ValidatorIndex: 1, Head: -2}},
+		}
+		backend.SetAttestationRewards(1, attestationRewards)
+
+		rewards, err := clt.Rewards().GetAttestationRewards(1, nil)
+		require.NoError(t, err)
+		require.Equal(t, attestationRewards, rewards)
+
+		rewards, err = clt.Rewards().GetAttestationRewards(1, []string{"0x02" + strings.Repeat("00", 47)})
+		require.NoError(t, err)
+		require.Equal(t, attestationRewards.TotalRewards[1:], rewards.TotalRewards)
+
+		_, err = clt.Rewards().GetAttestationRewards(2, nil)
+		require.ErrorIs(t, err, client.ErrorNotFound)
+
+		syncCommitteeRewards := []*client.SyncCommitteeReward{{ValidatorIndex: 0, Reward: 1}, {ValidatorIndex: 1, Reward: -1}}
+		backend.SetSyncCommitteeRewards(blockRoot, syncCommitteeRewards)
+
+		syncRewards, err := clt.Rewards().GetSyncCommitteeRewards(client.Slot(1), []string{"0"})
+		require.NoError(t, err)
+		require.Equal(t, syncCommitteeRewards[:1], syncRewards)
+	})
+
+	t.Run("ForkChoice", func(t *testing.T) {
+		forkChoice, err := clt.Debug().GetForkChoice()
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), forkChoice.FinalizedCheckpoint.Epoch)
+		require.Len(t, forkChoice.Nodes, 1)
+		require.Equal(t, blockRoot, forkChoice.Nodes[0].BlockRoot)
+		require.Equal(t, client.ForkChoiceNodeValid, forkChoice.Nodes[0].Validity)
+	})
+
+	t.Run("LightClient", func(t *testing.T) {
+		_, err := clt.LightClient().FinalityUpdate()
+		require.ErrorIs(t, err, client.ErrorNotFound)
+
+		lightClientHeader := &consensus.LightClientHeader{Header: &consensus.BeaconBlockHeader{Slot: 1}}
+		update := &client.LightClientObject{
+			Version: consensus.VersionAltair,
+			Data: &consensus.LightClientOptimisticUpdate{
+				AttestedHeader: lightClientHeader,
+				SyncAggregate:  &consensus.SyncAggregate{},
+				SignatureSlot:  2,
+			},
+		}
+		backend.SetLightClientOptimisticUpdate(update)
+
+		for _, clt := range []*client.Client{clt, client.New(srv.URL, client.WithSSZ())} {
+			obj, err := clt.LightClient().OptimisticUpdate()
+			require.NoError(t, err)
+			require.Equal(t, update, obj)
+		}
+
+		bootstrap := &client.LightClientObject{
+			Version: consensus.VersionAltair,
+			Data: &consensus.LightClientBootstrap{
+				Header:                     lightClientHeader,
+				CurrentSyncCommittee:       &consensus.SyncCommittee{},
+				CurrentSyncCommitteeBranch: make([][32]byte, 5),
+			},
+		}
+		backend.SetLightClientBootstrap(blockRoot, bootstrap)
+
+		obj, err := clt.LightClient().Bootstrap(blockRoot)
+		require.NoError(t, err)
+		require.Equal(t, bootstrap, obj)
+
+		updates, err := clt.LightClient().Updates(0, 4)
+		require.NoError(t, err)
+		require.Empty(t, updates)
+	})
+
+	t.Run("Events", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		eventCh := make(chan interface{}, 4)
+		go clt.Events(ctx, []string{"voluntary_exit", "light_client_finality_update"}, func(obj interface{}) {
+			eventCh <- obj
+		})
+
+		// wait for the subscription before the events are emitted
+		require.Eventually(t, func() bool {
+			backend.lock.RLock()
+			defer backend.lock.RUnlock()
+			return len(backend.subscribers) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		exit := &consensus.SignedVoluntaryExit{Exit: &consensus.VoluntaryExit{Epoch: 2}}
+		require.NoError(t, clt.Beacon().SubmitVoluntaryExit(exit))
+
+		update := &client.LightClientObject{
+			Version: consensus.VersionAltair,
+			Data: &consensus.LightClientFinalityUpdate{
+				AttestedHeader:  &consensus.LightClientHeader{Header: &consensus.BeaconBlockHeader{}},
+				FinalizedHeader: &consensus.LightClientHeader{Header: &consensus.BeaconBlockHeader{}},
+				FinalityBranch:  make([][32]byte, 6),
+				SyncAggregate:   &consensus.SyncAggregate{},
+			},
+		}
+		backend.SetLightClientFinalityUpdate(update)
+
+		for _, expected := range []interface{}{exit, update} {
+			select {
+			case obj := <-eventCh:
+				require.Equal(t, expected, obj)
+			case <-time.After(5 * time.Second):
+				t.Fatal("event not received")
+			}
+		}
+
+		// the subscriber is removed once the stream is closed
+		cancel()
+		require.Eventually(t, func() bool {
+			backend.lock.RLock()
+			defer backend.lock.RUnlock()
+			return len(backend.subscribers) == 0
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Routes", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/eth/v1/unknown")
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		resp, err = http.Post(srv.URL+"/eth/v1/beacon/genesis", "application/json", nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+}
+
+func TestServerValidator(t *testing.T) {
+	chainSpec := *spec.MinimalSpec
+	chainSpec.AltairForkEpoch = 0
+
+	numValidators := 64
+
+	state := &consensus.BeaconStateAltair{
+		Slot:                        0,
+		Fork:                        &consensus.Fork{CurrentVersion: chainSpec.AltairForkVersion},
+		LatestBlockHeader:           &consensus.BeaconBlockHeader{BodyRoot: consensus.Root{0x1}},
+		Eth1Data:                    &consensus.Eth1Data{},
+		Slashings:                   make([]uint64, chainSpec.EpochsPerSlashingsVector),
+		PreviousJustifiedCheckpoint: &consensus.Checkpoint{},
+		CurrentJustifiedCheckpoint:  &consensus.Checkpoint{},
+		FinalizedCheckpoint:         &consensus.Checkpoint{},
+		CurrentSyncCommittee:        &consensus.SyncCommittee{},
+		NextSyncCommittee:           &consensus.SyncCommittee{},
+	}
+	for i := 0; i < numValidators; i++ {
+		state.Validators = append(state.Validators, &consensus.Validator{
+			Pubkey:           [48]byte{byte(i + 1)},
+			EffectiveBalance: chainSpec.MaxEffectiveBalance,
+			ExitEpoch:        ^uint64(0),
+		})
+		state.Balances = append(state.Balances, chainSpec.MaxEffectiveBalance)
+		state.PreviousEpochParticipation = append(state.PreviousEpochParticipation, 0)
+		state.CurrentEpochParticipation = append(state.CurrentEpochParticipation, 0)
+		state.InactivityScores = append(state.InactivityScores, 0)
+		state.RandaoMixes[i] = [32]byte{byte(i + 1)}
+	}
+	for i := uint64(0); i < chainSpec.SyncCommitteeSize; i++ {
+		// the validator 3 is twice in the current sync committee
+		state.CurrentSyncCommittee.PubKeys[i] = [48]byte{byte(i%uint64(numValidators-1) + 1)}
+		state.NextSyncCommittee.PubKeys[i] = [48]byte{byte(i + 10)}
+	}
+	state.CurrentSyncCommittee.PubKeys[5] = [48]byte{0x3}
+
+	genesis, err := consensus.NewVersionedBeaconState(state)
+	require.NoError(t, err)
+
+	backend := NewMemoryBackend(&chainSpec, genesis)
+
+	srv := httptest.NewServer(New(backend))
+	defer srv.Close()
+
+	clt := client.New(srv.URL, client.WithUntrackedKeys())
+
+	st, err := spec.NewStateTransitioner(&chainSpec)
+	require.NoError(t, err)
+
+	t.Run("AttesterDuties", func(t *testing.T) {
+		committees, err := st.GetEpochCommittees(state, 0)
+		require.NoError(t, err)
+
+		duties, err := clt.Validator().GetAttesterDuties(0, []string{"0", "1", "1000"})
+		require.NoError(t, err)
+		require.Len(t, duties, 2)
+
+		for _, duty := range duties {
+			committee := committees[duty.Slot][duty.CommitteeIndex]
+			require.Equal(t, uint64(len(committee)), duty.CommitteeLength)
+			require.Equal(t, uint64(duty.ValidatorIndex), committee[duty.ValidatorCommitteeIndex])
+			require.Equal(t, uint64(len(committees[duty.Slot])), duty.CommitteeAtSlot)
+		}
+
+		_, err = clt.Validator().GetAttesterDuties(5, []string{"0"})
+		require.ErrorIs(t, err, client.ErrorBadRequest)
+	})
+
+	t.Run("ProposerDuties", func(t *testing.T) {
+		for _, epoch := range []uint64{0, 1} {
+			duties, err := clt.Validator().GetProposerDuties(epoch)
+			require.NoError(t, err)
+			require.Len(t, duties, int(chainSpec.SlotsPerEpoch))
+
+			for i, duty := range duties {
+				require.Equal(t, epoch*chainSpec.SlotsPerEpoch+uint64(i), duty.Slot)
+			}
+			if epoch == 0 {
+				proposer, err := st.GetBeaconProposerIndex(state, 3)
+				require.NoError(t, err)
+				require.Equal(t, uint(proposer), duties[3].ValidatorIndex)
+			}
+		}
+
+		_, err := clt.Validator().GetProposerDuties(2)
+		require.ErrorIs(t, err, client.ErrorBadRequest)
+	})
+
+	t.Run("SyncCommitteeDuties", func(t *testing.T) {
+		duties, err := clt.Validator().GetCommitteeSyncDuties(0, []string{"2", "1000"})
+		require.NoError(t, err)
+		require.Len(t, duties, 1)
+		require.Equal(t, uint(2), duties[0].ValidatorIndex)
+		require.Equal(t, []string{"2", "5"}, duties[0].ValidatorSyncCommitteeIndices)
+
+		// the validator 9 is only in the next sync committee
+		duties, err = clt.Validator().GetCommitteeSyncDuties(chainSpec.EpochsPerSyncCommitteePeriod, []string{"9"})
+		require.NoError(t, err)
+		require.Len(t, duties, 1)
+		require.Equal(t, []string{"0"}, duties[0].ValidatorSyncCommitteeIndices)
+	})
+
+	t.Run("Committees", func(t *testing.T) {
+		committees, err := st.GetEpochCommittees(state, 1)
+		require.NoError(t, err)
+
+		epoch, slot := uint64(1), chainSpec.SlotsPerEpoch+2
+		res, err := clt.Beacon().GetCommittees(client.Head, &client.CommitteeFilter{Epoch: &epoch, Slot: &slot})
+		require.NoError(t, err)
+		require.Len(t, res, len(committees[2]))
+		for i, committee := range res {
+			require.Equal(t, &client.Committee{Index: uint64(i), Slot: slot, Validators: committees[2][i]}, committee)
+		}
+
+		index := uint64(0)
+		res, err = clt.Beacon().GetCommittees(client.Genesis, &client.CommitteeFilter{Index: &index})
+		require.NoError(t, err)
+		require.Len(t, res, int(chainSpec.SlotsPerEpoch))
+
+		epoch = 5
+		_, err = clt.Beacon().GetCommittees(client.Head, &client.CommitteeFilter{Epoch: &epoch})
+		require.ErrorIs(t, err, client.ErrorBadRequest)
+	})
+
+	t.Run("SyncCommittees", func(t *testing.T) {
+		committees, err := clt.Beacon().GetSyncCommittees(client.Head, nil)
+		require.NoError(t, err)
+		require.Len(t, committees.Validators, int(chainSpec.SyncCommitteeSize))
+		require.Equal(t, uint64(2), committees.Validators[5])
+		require.Len(t, committees.ValidatorAggregates, 4)
+		require.Equal(t, committees.Validators[:chainSpec.SyncCommitteeSize/4], committees.ValidatorAggregates[0])
+
+		// the next sync committee
+		epoch := chainSpec.EpochsPerSyncCommitteePeriod
+		committees, err = clt.Beacon().GetSyncCommittees(client.Head, &epoch)
+		require.NoError(t, err)
+		require.Equal(t, uint64(9), committees.Validators[0])
+	})
+
+	t.Run("Randao", func(t *testing.T) {
+		randao, err := clt.Beacon().GetRandao(client.Head, nil)
+		require.NoError(t, err)
+		require.Equal(t, state.RandaoMixes[0], randao)
+
+		epoch := uint64(1)
+		_, err = clt.Beacon().GetRandao(client.Head, &epoch)
+		require.ErrorIs(t, err, client.ErrorBadRequest)
+	})
+
+	t.Run("ProduceBlock", func(t *testing.T) {
+		randao := [96]byte{0x1}
+		graffiti := [32]byte{0x2}
+
+		for _, clt := range []*client.Client{clt, client.New(srv.URL, client.WithSSZ())} {
+			block, err := clt.Validator().ProduceBlock(1, randao, &client.ProduceBlockOptions{Graffiti: graffiti})
+			require.NoError(t, err)
+			require.Equal(t, consensus.VersionAltair, block.Version)
+			require.False(t, block.Blinded)
+
+			obj := block.Block.(*consensus.BeaconBlockAltair)
+			require.Equal(t, uint64(1), obj.Slot)
+			require.Equal(t, consensus.Signature(randao), obj.Body.RandaoReveal)
+			require.Equal(t, graffiti, obj.Body.Graffiti)
+
+			proposer, err := st.GetBeaconProposerIndex(state, 1)
+			require.NoError(t, err)
+			require.Equal(t, proposer, obj.ProposerIndex)
+		}
+
+		_, err := clt.Validator().ProduceBlock(0, randao, nil)
+		require.ErrorIs(t, err, client.ErrorBadRequest)
+	})
+
+	// the chain has no blocks, the attestations vote for the genesis block
+	genesisHeader := *state.LatestBlockHeader
+	genesisHeader.StateRoot, err = chainSpec.HashTreeRoot(state)
+	require.NoError(t, err)
+	genesisRoot, err := genesisHeader.HashTreeRoot()
+	require.NoError(t, err)
+
+	t.Run("AttestationData", func(t *testing.T) {
+		data, err := clt.Validator().RequestAttestationData(3, 1)
+		require.NoError(t, err)
+
+		expected := &consensus.AttestationData{
+			Slot:            3,
+			Index:           1,
+			BeaconBlockHash: genesisRoot,
+			Source:          &consensus.Checkpoint{},
+			Target:          &consensus.Checkpoint{Epoch: 0, Root: genesisRoot},
+		}
+		require.Equal(t, expected, data)
+
+		resp, err := http.Get(srv.URL + "/eth/v1/validator/attestation_data?slot=3")
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("AggregateAttestation", func(t *testing.T) {
+		data := &consensus.AttestationData{Slot: 2, Source: &consensus.Checkpoint{}, Target: &consensus.Checkpoint{}}
+		dataRoot, err := data.HashTreeRoot()
+		require.NoError(t, err)
+
+		newAttestation := func(bits ...uint64) (*consensus.Attestation, *bls.Signature) {
+			aggregationBits := bitlist.NewBitlist(4)
+			for _, bit := range bits {
+				aggregationBits.SetBitAt(bit, true)
+			}
+			signature, err := bls.NewRandomKey().Sign(dataRoot)
+			require.NoError(t, err)
+
+			sig := new(bls.Signature)
+			require.NoError(t, sig.Deserialize(signature[:]))
+			return &consensus.Attestation{AggregationBits: aggregationBits, Data: data, Signature: signature}, sig
+		}
+
+		// the second attestation overlaps with the third one which has more votes
+		attestation0, sig0 := newAttestation(2)
+		attestation1, _ := newAttestation(0)
+		attestation2, sig2 := newAttestation(0, 1)
+		require.NoError(t, clt.Beacon().PublishAttestations([]*consensus.Attestation{attestation0, attestation1, attestation2}))
+
+		aggregate, err := clt.Validator().AggregateAttestation(2, dataRoot)
+		require.NoError(t, err)
+		require.Equal(t, data, aggregate.Data)
+
+		bits := bitlist.BitList(aggregate.AggregationBits)
+		require.Equal(t, uint64(4), bits.Len())
+		for i, set := range []bool{true, true, true, false} {
+			require.Equal(t, set, bits.BitAt(uint64(i)))
+		}
+		require.Equal(t, consensus.Signature(bls.AggregateSignatures([]*bls.Signature{sig2, sig0}).Serialize()), aggregate.Signature)
+
+		_, err = clt.Validator().AggregateAttestation(3, dataRoot)
+		require.ErrorIs(t, err, client.ErrorNotFound)
+
+		aggregates := []*consensus.SignedAggregateAndProof{
+			{Message: &consensus.AggregateAndProof{Index: 1, Aggregate: aggregate, SelectionProof: [96]byte{0x1}}},
+		}
+		require.NoError(t, clt.Validator().PublishAggregateAndProof(aggregates))
+		require.Equal(t, aggregates, backend.AggregateAndProofs())
+	})
+
+	t.Run("SyncCommitteeContribution", func(t *testing.T) {
+		blockRoot := [32]byte{0x1}
+
+		newMessage := func(validatorIndex uint64) (*consensus.SyncCommitteeMessage, *bls.Signature) {
+			signature, err := bls.NewRandomKey().Sign(blockRoot)
+			require.NoError(t, err)
+
+			sig := new(bls.Signature)
+			require.NoError(t, sig.Deserialize(signature[:]))
+			return &consensus.SyncCommitteeMessage{Slot: 1, BlockRoot: blockRoot, ValidatorIndex: validatorIndex, Signature: signature}, sig
+		}
+
+		// the validator 2 is twice in the first subcommittee
+		msg0, sig0 := newMessage(0)
+		msg2, sig2 := newMessage(2)
+		require.NoError(t, clt.Beacon().SubmitCommitteeDuties([]*consensus.SyncCommitteeMessage{msg0, msg2}))
+
+		contribution, err := clt.Validator().SyncCommitteeContribution(1, 0, blockRoot)
+		require.NoError(t, err)
+		require.Equal(t, []byte{0x25}, contribution.AggregationBits)
+		require.Equal(t, consensus.Signature(bls.AggregateSignatures([]*bls.Signature{sig0, sig2, sig2}).Serialize()), contribution.Signature)
+
+		_, err = clt.Validator().SyncCommitteeContribution(1, 1, blockRoot)
+		require.ErrorIs(t, err, client.ErrorNotFound)
+
+		_, err = clt.Validator().SyncCommitteeContribution(1, 4, blockRoot)
+		require.ErrorIs(t, err, client.ErrorBadRequest)
+
+		contributions := []*consensus.SignedContributionAndProof{
+			{Message: &consensus.ContributionAndProof{AggregatorIndex: 2, Contribution: contribution}},
+		}
+		require.NoError(t, clt.Validator().SubmitSignedContributionAndProof(contributions))
+		require.Equal(t, contributions, backend.ContributionAndProofs())
+	})
+
+	t.Run("Subscriptions", func(t *testing.T) {
+		beaconSubs := []*client.BeaconCommitteeSubscription{{ValidatorIndex: 1, Slot: 2, CommitteeIndex: 3, CommitteesAtSlot: 4, IsAggregator: true}}
+		require.NoError(t, clt.Validator().BeaconCommitteeSubscriptions(beaconSubs))
+		require.Equal(t, beaconSubs, backend.BeaconCommitteeSubscriptions())
+
+		syncSubs := []*client.SyncCommitteeSubscription{{ValidatorIndex: 2, SyncCommitteeIndices: []uint64{2, 5}, UntilEpoch: 8}}
+		require.NoError(t, clt.Validator().SyncCommitteeSubscriptions(syncSubs))
+		require.Equal(t, syncSubs, backend.SyncCommitteeSubscriptions())
+
+		preparations := []*client.ProposalPreparation{{ValidatorIndex: 1, FeeRecipient: [20]byte{0x1}}}
+		require.NoError(t, clt.Validator().PrepareBeaconProposer(preparations))
+		require.Equal(t, preparations, backend.ProposalPreparations())
+
+		registrations := []*client.SignedValidatorRegistration{
+			{Message: &client.RegisterValidatorRequest{FeeRecipient: [20]byte{0x1}, GasLimit: 30000000, Timestamp: 10, Pubkey: [48]byte{0x1}}},
+		}
+		require.NoError(t, clt.Validator().RegisterValidator(registrations))
+		require.Equal(t, registrations, backend.ValidatorRegistrations())
+	})
+}
+
+func uint64Ptr(i uint64) *uint64 {
+	return &i
+}
+
+func TestAcceptsSSZ(t *testing.T) {
+	cases := []struct {
+		accept string
+		ssz    bool
+	}{
+		{"", false},
+		{"application/json", false},
+		{"application/octet-stream", true},
+		{"application/octet-stream;q=1.0,application/json;q=0.9", true},
+		{"application/octet-stream;q=0.5,application/json", false},
+		{"*/*", false},
+	}
+	for _, c := range cases {
+		r := &http.Request{Header: http.Header{}}
+		r.Header.Set("Accept", c.accept)
+		require.Equal(t, c.ssz, acceptsSSZ(r), c.accept)
+	}
+}
+
+func TestValidatorStatus(t *testing.T) {
+	cases := []struct {
+		val    *consensus.Validator
+		status client.ValidatorStatus
+	}{
+		{&consensus.Validator{ActivationEligibilityEpoch: farFutureEpoch, ActivationEpoch: farFutureEpoch}, client.ValidatorStatusPendingInitialized},
+		{&consensus.Validator{ActivationEligibilityEpoch: 5, ActivationEpoch: farFutureEpoch}, client.ValidatorStatusPendingQueued},
+		{&consensus.Validator{ExitEpoch: farFutureEpoch}, client.ValidatorStatusActiveOngoing},
+		{&consensus.Validator{ExitEpoch: 20}, client.ValidatorStatusActiveExiting},
+		{&consensus.Validator{ExitEpoch: 20, Slashed: true}, client.ValidatorStatusActiveSlashed},
+		{&consensus.Validator{ExitEpoch: 5, WithdrawableEpoch: 20}, client.ValidatorStatusExitedUnslashed},
+		{&consensus.Validator{ExitEpoch: 5, WithdrawableEpoch: 20, Slashed: true}, client.ValidatorStatusExitedSlashed},
+		{&consensus.Validator{ExitEpoch: 5, WithdrawableEpoch: 5, EffectiveBalance: 1}, client.ValidatorStatusWithdrawalPossible},
+		{&consensus.Validator{ExitEpoch: 5, WithdrawableEpoch: 5}, client.ValidatorStatusWithdrawalDone},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.status, validatorStatus(c.val, 10))
+	}
+	require.Equal(t, client.ValidatorStatusActive, client.ValidatorStatusActiveSlashed.Group())
+}
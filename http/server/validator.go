@@ -0,0 +1,358 @@
+package server
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	ssz "github.com/ferranbt/fastssz"
+	consensus "github.com/umbracle/go-eth-consensus"
+	client "github.com/umbracle/go-eth-consensus/http"
+)
+
+const (
+	headerExecutionPayloadBlinded = "Eth-Execution-Payload-Blinded"
+	headerExecutionPayloadValue   = "Eth-Execution-Payload-Value"
+	headerConsensusBlockValue     = "Eth-Consensus-Block-Value"
+)
+
+func (s *Server) handleAttesterDuties(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	epoch, err := parseUintParam(params, "epoch")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	indexes, err := decodeIndexes(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	duties, err := s.backend.AttesterDuties(r.Context(), epoch, indexes)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, duties)
+}
+
+func (s *Server) handleProposerDuties(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	epoch, err := parseUintParam(params, "epoch")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	duties, err := s.backend.ProposerDuties(r.Context(), epoch)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, duties)
+}
+
+func (s *Server) handleSyncCommitteeDuties(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	epoch, err := parseUintParam(params, "epoch")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	indexes, err := decodeIndexes(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	duties, err := s.backend.SyncCommitteeDuties(r.Context(), epoch, indexes)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, duties)
+}
+
+// decodeIndexes decodes the validator indexes of the body of the duties requests
+func decodeIndexes(r *http.Request) ([]uint64, error) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, err
+	}
+
+	indexes := []uint64{}
+	for _, id := range ids {
+		indx, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid validator index '%s'", id)
+		}
+		indexes = append(indexes, indx)
+	}
+	return indexes, nil
+}
+
+// produceBlock requests the block of the slot to the backend with
+// the randao reveal and the graffiti of the query
+func (s *Server) produceBlock(r *http.Request, params map[string]string) (*client.ProducedBlock, error) {
+	slot, err := parseUintParam(params, "slot")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", client.ErrorBadRequest, err)
+	}
+
+	var randaoReveal consensus.Signature
+	if err := decodeHexQuery(r, "randao_reveal", randaoReveal[:]); err != nil {
+		return nil, err
+	}
+	var graffiti [32]byte
+	if r.URL.Query().Get("graffiti") != "" {
+		if err := decodeHexQuery(r, "graffiti", graffiti[:]); err != nil {
+			return nil, err
+		}
+	}
+	return s.backend.ProduceBlock(r.Context(), slot, randaoReveal, graffiti)
+}
+
+// decodeHexQuery decodes the hex value of the query parameter in buf
+func decodeHexQuery(r *http.Request, key string, buf []byte) error {
+	str := r.URL.Query().Get(key)
+
+	data, err := hex.DecodeString(strings.TrimPrefix(str, "0x"))
+	if err != nil || len(data) != len(buf) {
+		return fmt.Errorf("%w: invalid %s '%s'", client.ErrorBadRequest, key, str)
+	}
+	copy(buf, data)
+	return nil
+}
+
+func (s *Server) handleProduceBlock(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	block, err := s.produceBlock(r, params)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+
+	// from Deneb the full block is sent along with the blobs
+	var obj interface{}
+	switch {
+	case block.Blinded:
+		obj = block.BlindedBlock.Object()
+	case block.Version == consensus.VersionDeneb:
+		obj = &consensus.BlockContentsDeneb{Block: block.Block.(*consensus.BeaconBlockDeneb), KZGProofs: block.KZGProofs, Blobs: block.Blobs}
+	case block.Version == consensus.VersionElectra:
+		obj = &consensus.BlockContentsElectra{Block: block.Block.(*consensus.BeaconBlockElectra), KZGProofs: block.KZGProofs, Blobs: block.Blobs}
+	default:
+		obj = block.Block
+	}
+
+	executionPayloadValue, consensusBlockValue := "0", "0"
+	if block.ExecutionPayloadValue != nil {
+		executionPayloadValue = block.ExecutionPayloadValue.String()
+	}
+	if block.ConsensusBlockValue != nil {
+		consensusBlockValue = block.ConsensusBlockValue.String()
+	}
+
+	w.Header().Set(headerConsensusVersion, block.Version.String())
+	w.Header().Set(headerExecutionPayloadBlinded, strconv.FormatBool(block.Blinded))
+	w.Header().Set(headerExecutionPayloadValue, executionPayloadValue)
+	w.Header().Set(headerConsensusBlockValue, consensusBlockValue)
+
+	if acceptsSSZ(r) {
+		if marshaler, ok := obj.(ssz.Marshaler); ok {
+			data, err := marshaler.MarshalSSZ()
+			if err != nil {
+				s.writeBackendError(w, err)
+				return
+			}
+			w.Header().Set("Content-Type", contentTypeSSZ)
+			w.Write(data)
+			return
+		}
+	}
+
+	data, err := client.Marshal(obj)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", contentTypeJSON)
+	fmt.Fprintf(w, `{"version":"%s","execution_payload_blinded":%t,"execution_payload_value":"%s","consensus_block_value":"%s","data":%s}`,
+		block.Version, block.Blinded, executionPayloadValue, consensusBlockValue, data)
+}
+
+// handleProduceBlockV2 serves the deprecated block production route
+// which only returns full blocks
+func (s *Server) handleProduceBlockV2(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	block, err := s.produceBlock(r, params)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	if block.Blinded {
+		s.writeBackendError(w, fmt.Errorf("blinded block not supported"))
+		return
+	}
+	s.writeVersioned(w, r, block.Version, block.Block)
+}
+
+func (s *Server) handleAttestationData(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	slot, err := requireUintQuery(r, "slot")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	committeeIndex, err := requireUintQuery(r, "committee_index")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := s.backend.AttestationData(r.Context(), slot, committeeIndex)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, data)
+}
+
+func (s *Server) handleAggregateAttestation(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	slot, err := requireUintQuery(r, "slot")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var dataRoot [32]byte
+	if err := decodeHexQuery(r, "attestation_data_root", dataRoot[:]); err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+
+	aggregate, err := s.backend.AggregateAttestation(r.Context(), slot, dataRoot)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, aggregate)
+}
+
+func (s *Server) handleSubmitAggregateAndProofs(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var aggregates []*consensus.SignedAggregateAndProof
+	if err := decodeBody(r, &aggregates); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.backend.SubmitAggregateAndProofs(r.Context(), aggregates); err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleBeaconCommitteeSubscriptions(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var subs []*client.BeaconCommitteeSubscription
+	if err := decodeBody(r, &subs); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.backend.SubmitBeaconCommitteeSubscriptions(r.Context(), subs); err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleSyncCommitteeSubscriptions(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var subs []*client.SyncCommitteeSubscription
+	if err := decodeBody(r, &subs); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.backend.SubmitSyncCommitteeSubscriptions(r.Context(), subs); err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleSyncCommitteeContribution(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	slot, err := requireUintQuery(r, "slot")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	subcommitteeIndex, err := requireUintQuery(r, "subcommittee_index")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var blockRoot [32]byte
+	if err := decodeHexQuery(r, "beacon_block_root", blockRoot[:]); err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+
+	contribution, err := s.backend.SyncCommitteeContribution(r.Context(), slot, subcommitteeIndex, blockRoot)
+	if err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	s.writeData(w, contribution)
+}
+
+func (s *Server) handleSubmitContributionAndProofs(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var contributions []*consensus.SignedContributionAndProof
+	if err := decodeBody(r, &contributions); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.backend.SubmitContributionAndProofs(r.Context(), contributions); err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handlePrepareBeaconProposer(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var preparations []*client.ProposalPreparation
+	if err := decodeBody(r, &preparations); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.backend.PrepareBeaconProposer(r.Context(), preparations); err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleRegisterValidator(w http.ResponseWriter, r *http.Request, params map[string]string) {
+	var registrations []*client.SignedValidatorRegistration
+	if err := decodeBody(r, &registrations); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.backend.RegisterValidator(r.Context(), registrations); err != nil {
+		s.writeBackendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// requireUintQuery returns the value of a uint query parameter that
+// must be set in the request
+func requireUintQuery(r *http.Request, key string) (uint64, error) {
+	num, err := parseUintQuery(r, key)
+	if err != nil {
+		return 0, err
+	}
+	if num == nil {
+		return 0, fmt.Errorf("%s not found", key)
+	}
+	return *num, nil
+}
@@ -0,0 +1,364 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+type subscriptionConfig struct {
+	bufferSize int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+type SubscriptionOption func(*subscriptionConfig)
+
+// WithBufferSize sets the size of the event channels of the subscription
+func WithBufferSize(size int) SubscriptionOption {
+	return func(c *subscriptionConfig) {
+		c.bufferSize = size
+	}
+}
+
+// WithReconnectBackoff sets the minimum and maximum wait between reconnections. The
+// wait doubles after each failed attempt. The reconnection time sent by the node
+// replaces the minimum wait, up to the maximum one.
+func WithReconnectBackoff(min, max time.Duration) SubscriptionOption {
+	return func(c *subscriptionConfig) {
+		c.minBackoff = min
+		c.maxBackoff = max
+	}
+}
+
+// Subscription is a subscription to the event stream of the node. It reconnects
+// with backoff when the stream is closed and resumes from the last received event.
+// Each subscribed topic has a typed channel, the channels of the topics not
+// subscribed are nil. The channels are closed once the subscription is closed.
+type Subscription struct {
+	Head                        chan *HeadEvent
+	Block                       chan *BlockEvent
+	BlockGossip                 chan *BlockGossipEvent
+	Attestation                 chan *consensus.Attestation
+	VoluntaryExit               chan *consensus.SignedVoluntaryExit
+	FinalizedCheckpoint         chan *FinalizedCheckpointEvent
+	ChainReorg                  chan *ChainReorgEvent
+	ContributionAndProof        chan *consensus.SignedContributionAndProof
+	BlobSidecar                 chan *BlobSidecarEvent
+	PayloadAttributes           chan *PayloadAttributesEvent
+	LightClientFinalityUpdate   chan *LightClientObject
+	LightClientOptimisticUpdate chan *LightClientObject
+	BlsToExecutionChange        chan *consensus.SignedBLSToExecutionChange
+	ProposerSlashing            chan *consensus.ProposerSlashing
+	AttesterSlashing            chan *consensus.AttesterSlashing
+
+	c      *Client
+	config *subscriptionConfig
+	topics []string
+
+	// channels are the channels of the subscribed topics
+	channels map[string]reflect.Value
+
+	// lastEventID is the id of the last event received
+	lastEventID string
+
+	// retry is the reconnection time sent by the node
+	retry time.Duration
+
+	errCh    chan error
+	cancelFn context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// Subscribe subscribes to the topics of the event stream until the context is done
+// or the subscription is closed.
+func (c *Client) Subscribe(ctx context.Context, topics []string, opts ...SubscriptionOption) (*Subscription, error) {
+	if err := validateTopics(topics); err != nil {
+		return nil, err
+	}
+
+	config := &subscriptionConfig{
+		bufferSize: 16,
+		minBackoff: 500 * time.Millisecond,
+		maxBackoff: 30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	s := &Subscription{
+		c:        c,
+		config:   config,
+		topics:   topics,
+		channels: map[string]reflect.Value{},
+		errCh:    make(chan error, 1),
+	}
+	for _, topic := range topics {
+		s.makeChannel(topic)
+	}
+
+	ctx, cancelFn := context.WithCancel(ctx)
+	s.cancelFn = cancelFn
+
+	s.wg.Add(1)
+	go s.run(ctx)
+
+	return s, nil
+}
+
+func (s *Subscription) makeChannel(topic string) {
+	size := s.config.bufferSize
+
+	var ch interface{}
+	switch topic {
+	case "head":
+		s.Head = make(chan *HeadEvent, size)
+		ch = s.Head
+	case "block":
+		s.Block = make(chan *BlockEvent, size)
+		ch = s.Block
+	case "block_gossip":
+		s.BlockGossip = make(chan *BlockGossipEvent, size)
+		ch = s.BlockGossip
+	case "attestation":
+		s.Attestation = make(chan *consensus.Attestation, size)
+		ch = s.Attestation
+	case "voluntary_exit":
+		s.VoluntaryExit = make(chan *consensus.SignedVoluntaryExit, size)
+		ch = s.VoluntaryExit
+	case "finalized_checkpoint":
+		s.FinalizedCheckpoint = make(chan *FinalizedCheckpointEvent, size)
+		ch = s.FinalizedCheckpoint
+	case "chain_reorg":
+		s.ChainReorg = make(chan *ChainReorgEvent, size)
+		ch = s.ChainReorg
+	case "contribution_and_proof":
+		s.ContributionAndProof = make(chan *consensus.SignedContributionAndProof, size)
+		ch = s.ContributionAndProof
+	case "blob_sidecar":
+		s.BlobSidecar = make(chan *BlobSidecarEvent, size)
+		ch = s.BlobSidecar
+	case "payload_attributes":
+		s.PayloadAttributes = make(chan *PayloadAttributesEvent, size)
+		ch = s.PayloadAttributes
+	case "light_client_finality_update":
+		s.LightClientFinalityUpdate = make(chan *LightClientObject, size)
+		ch = s.LightClientFinalityUpdate
+	case "light_client_optimistic_update":
+		s.LightClientOptimisticUpdate = make(chan *LightClientObject, size)
+		ch = s.LightClientOptimisticUpdate
+	case "bls_to_execution_change":
+		s.BlsToExecutionChange = make(chan *consensus.SignedBLSToExecutionChange, size)
+		ch = s.BlsToExecutionChange
+	case "proposer_slashing":
+		s.ProposerSlashing = make(chan *consensus.ProposerSlashing, size)
+		ch = s.ProposerSlashing
+	case "attester_slashing":
+		s.AttesterSlashing = make(chan *consensus.AttesterSlashing, size)
+		ch = s.AttesterSlashing
+	default:
+		panic(fmt.Sprintf("BUG: topic %s without channel", topic))
+	}
+	s.channels[topic] = reflect.ValueOf(ch)
+}
+
+// Err returns the errors of the subscription (i.e. connection and decoding errors).
+// The subscription keeps running after an error.
+func (s *Subscription) Err() <-chan error {
+	return s.errCh
+}
+
+// Close closes the subscription and its channels
+func (s *Subscription) Close() {
+	s.cancelFn()
+	s.wg.Wait()
+}
+
+func (s *Subscription) run(ctx context.Context) {
+	defer s.wg.Done()
+	defer func() {
+		for _, ch := range s.channels {
+			ch.Close()
+		}
+	}()
+
+	backoff := s.config.minBackoff
+	for {
+		connected, err := s.stream(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			backoff = s.reconnectBackoff()
+		}
+		if err == nil {
+			err = fmt.Errorf("event stream closed")
+		}
+		s.notifyErr(err)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		if backoff *= 2; backoff > s.config.maxBackoff {
+			backoff = s.config.maxBackoff
+		}
+	}
+}
+
+// reconnectBackoff returns the wait after a closed stream. It is the reconnection
+// time of the node, if any, up to the maximum backoff.
+func (s *Subscription) reconnectBackoff() time.Duration {
+	backoff := s.config.minBackoff
+	if s.retry != 0 {
+		backoff = s.retry
+	}
+	if backoff > s.config.maxBackoff {
+		backoff = s.config.maxBackoff
+	}
+	return backoff
+}
+
+// stream connects to the event stream and delivers the events until the stream is closed
+func (s *Subscription) stream(ctx context.Context) (bool, error) {
+	req, err := s.c.newRequest(ctx, http.MethodGet, "/eth/v1/events?topics="+strings.Join(s.topics, ","), nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	if s.lastEventID != "" {
+		req.Header.Set("Last-Event-ID", s.lastEventID)
+	}
+
+	// use only the transport of the http client since its timeout
+	// would close the stream
+	client := &http.Client{Transport: s.c.config.httpClient.Transport}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return false, err
+		}
+		if err := decodeError(resp.StatusCode, data); err != nil {
+			return false, err
+		}
+		return false, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	for {
+		event, err := readEvent(reader)
+		if err != nil {
+			return true, err
+		}
+		if event.id != "" {
+			s.lastEventID = event.id
+		}
+		if event.retry != 0 {
+			s.retry = event.retry
+		}
+		if event.event == "" || len(event.data) == 0 {
+			continue
+		}
+		s.deliver(ctx, event)
+	}
+}
+
+func (s *Subscription) deliver(ctx context.Context, event *sseEvent) {
+	ch, ok := s.channels[event.event]
+	if !ok {
+		s.c.config.logger.Printf("[DEBUG]: event not tracked: %s", event.event)
+		return
+	}
+
+	obj, err := eventDecoders[event.event](event.data, s.c.config.untrackedKeys)
+	if err != nil {
+		s.notifyErr(fmt.Errorf("failed to decode %s event: %v", event.event, err))
+		return
+	}
+
+	reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectSend, Chan: ch, Send: reflect.ValueOf(obj)},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+	})
+}
+
+// notifyErr sends the error if there is a listener, otherwise it is only logged
+func (s *Subscription) notifyErr(err error) {
+	s.c.config.logger.Printf("[ERROR]: event subscription: %v", err)
+
+	select {
+	case s.errCh <- err:
+	default:
+	}
+}
+
+type sseEvent struct {
+	id    string
+	event string
+	data  []byte
+	retry time.Duration
+}
+
+// readEvent reads the next event of the stream
+func readEvent(reader *bufio.Reader) (*sseEvent, error) {
+	event := &sseEvent{}
+	data := [][]byte{}
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = bytes.TrimRight(line, "\r\n")
+
+		if len(line) == 0 {
+			// an empty line dispatches the event
+			if len(data) == 0 && event.id == "" && event.retry == 0 {
+				continue
+			}
+			event.data = bytes.Join(data, []byte("\n"))
+			return event, nil
+		}
+		if line[0] == ':' {
+			// comment
+			continue
+		}
+
+		field, value := line, []byte{}
+		if indx := bytes.IndexByte(line, ':'); indx != -1 {
+			field, value = line[:indx], bytes.TrimPrefix(line[indx+1:], []byte(" "))
+		}
+
+		switch string(field) {
+		case "id":
+			event.id = string(value)
+		case "event":
+			event.event = string(value)
+		case "data":
+			data = append(data, value)
+		case "retry":
+			if retry, err := strconv.ParseUint(string(value), 10, 64); err == nil {
+				event.retry = time.Duration(retry) * time.Millisecond
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+func TestSubscription(t *testing.T) {
+	lastEventIDs := make(chan string, 10)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/eth/v1/events", r.URL.Path)
+		require.Equal(t, "head,light_client_optimistic_update", r.URL.Query().Get("topics"))
+
+		lastEventID := r.Header.Get("Last-Event-ID")
+		lastEventIDs <- lastEventID
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+
+		if lastEventID == "" {
+			// first connection, send a head event and an invalid one and drop the connection
+			fmt.Fprint(w, ": comment\n\n")
+			fmt.Fprint(w, "id: 1\nevent: head\ndata: {\"slot\": \"10\", \"block\": \"0x0100000000000000000000000000000000000000000000000000000000000000\"}\n\n")
+			fmt.Fprint(w, "id: 2\nevent: head\ndata: {\"slot\": \"a\"}\n\n")
+			flusher.Flush()
+			return
+		}
+
+		fmt.Fprint(w, "id: 3\nevent: light_client_optimistic_update\n")
+		fmt.Fprint(w, "data: {\"version\": \"altair\",\n")
+		fmt.Fprintf(w, "data: \"data\": {\"attested_header\": {\"beacon\": {\"slot\": \"5\"}}, \"signature_slot\": \"6\", \"sync_aggregate\": {\"sync_committee_bits\": \"0x%s\", \"sync_committee_signature\": \"0x%s\"}}}\n\n", strings.Repeat("00", 64), strings.Repeat("00", 96))
+		flusher.Flush()
+
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL).Subscribe(context.Background(), []string{"head", "a"})
+	require.Error(t, err)
+
+	sub, err := New(server.URL).Subscribe(context.Background(), []string{"head", "light_client_optimistic_update"}, WithReconnectBackoff(10*time.Millisecond, 100*time.Millisecond))
+	require.NoError(t, err)
+	require.Nil(t, sub.Block)
+
+	head := <-sub.Head
+	require.Equal(t, uint64(10), head.Slot)
+	require.Equal(t, [32]byte{0x1}, head.Block)
+
+	// the invalid event is notified and the stream resumes from the last event
+	select {
+	case err := <-sub.Err():
+		require.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("error not received")
+	}
+
+	update := <-sub.LightClientOptimisticUpdate
+	require.Equal(t, consensus.VersionAltair, update.Version)
+	require.Equal(t, uint64(6), update.Data.(*consensus.LightClientOptimisticUpdate).SignatureSlot)
+
+	require.Equal(t, "", <-lastEventIDs)
+	require.Equal(t, "2", <-lastEventIDs)
+
+	sub.Close()
+
+	_, ok := <-sub.Head
+	require.False(t, ok)
+}
+
+func TestSubscription_Retry(t *testing.T) {
+	connections := make(chan struct{}, 10)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		connections <- struct{}{}
+
+		// the reconnection time of the node is above the maximum backoff
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "retry: 99999999999\n\n")
+		w.(http.Flusher).Flush()
+	}))
+	defer server.Close()
+
+	sub, err := New(server.URL).Subscribe(context.Background(), []string{"head"}, WithReconnectBackoff(10*time.Millisecond, 100*time.Millisecond))
+	require.NoError(t, err)
+	defer sub.Close()
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-connections:
+		case <-time.After(time.Second):
+			t.Fatal("the subscription did not reconnect")
+		}
+	}
+	require.Equal(t, 10*time.Millisecond, sub.config.minBackoff)
+}
+
+func TestSubscription_ReadEvent(t *testing.T) {
+	reader := bufio.NewReader(strings.NewReader("retry: 100\n\nid: 1\r\nevent: a\r\ndata: b\r\ndata:c\r\n\r\n"))
+
+	event, err := readEvent(reader)
+	require.NoError(t, err)
+	require.Equal(t, 100*time.Millisecond, event.retry)
+
+	event, err = readEvent(reader)
+	require.NoError(t, err)
+	require.Equal(t, &sseEvent{id: "1", event: "a", data: []byte("b\nc")}, event)
+
+	_, err = readEvent(reader)
+	require.Error(t, err)
+}
@@ -1,9 +1,16 @@
 package http
 
 import (
+	"context"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
 
+	ssz "github.com/ferranbt/fastssz"
 	consensus "github.com/umbracle/go-eth-consensus"
 )
 
@@ -26,8 +33,12 @@ type AttesterDuty struct {
 }
 
 func (v *ValidatorEndpoint) GetAttesterDuties(epoch uint64, indexes []string) ([]*AttesterDuty, error) {
+	return v.GetAttesterDutiesWithContext(context.Background(), epoch, indexes)
+}
+
+func (v *ValidatorEndpoint) GetAttesterDutiesWithContext(ctx context.Context, epoch uint64, indexes []string) ([]*AttesterDuty, error) {
 	var out []*AttesterDuty
-	err := v.c.Post(fmt.Sprintf("/eth/v1/validator/duties/attester/%d", epoch), indexes, &out)
+	err := v.c.PostWithContext(ctx, fmt.Sprintf("/eth/v1/validator/duties/attester/%d", epoch), indexes, &out)
 	return out, err
 }
 
@@ -38,8 +49,12 @@ type ProposerDuty struct {
 }
 
 func (v *ValidatorEndpoint) GetProposerDuties(epoch uint64) ([]*ProposerDuty, error) {
+	return v.GetProposerDutiesWithContext(context.Background(), epoch)
+}
+
+func (v *ValidatorEndpoint) GetProposerDutiesWithContext(ctx context.Context, epoch uint64) ([]*ProposerDuty, error) {
 	var out []*ProposerDuty
-	err := v.c.Get(fmt.Sprintf("/eth/v1/validator/duties/proposer/%d", epoch), &out)
+	err := v.c.GetWithContext(ctx, fmt.Sprintf("/eth/v1/validator/duties/proposer/%d", epoch), &out)
 	return out, err
 }
 
@@ -50,31 +65,227 @@ type CommitteeSyncDuty struct {
 }
 
 func (v *ValidatorEndpoint) GetCommitteeSyncDuties(epoch uint64, indexes []string) ([]*CommitteeSyncDuty, error) {
+	return v.GetCommitteeSyncDutiesWithContext(context.Background(), epoch, indexes)
+}
+
+func (v *ValidatorEndpoint) GetCommitteeSyncDutiesWithContext(ctx context.Context, epoch uint64, indexes []string) ([]*CommitteeSyncDuty, error) {
 	var out []*CommitteeSyncDuty
-	err := v.c.Post(fmt.Sprintf("/eth/v1/validator/duties/sync/%d", epoch), indexes, &out)
+	err := v.c.PostWithContext(ctx, fmt.Sprintf("/eth/v1/validator/duties/sync/%d", epoch), indexes, &out)
 	return out, err
 }
 
+// GetBlock requests a full block of the fork of out.
+//
+// Deprecated: use ProduceBlock which resolves the fork and supports blinded blocks.
 func (v *ValidatorEndpoint) GetBlock(out consensus.BeaconBlock, slot uint64, randao [96]byte) error {
+	return v.GetBlockWithContext(context.Background(), out, slot, randao)
+}
+
+func (v *ValidatorEndpoint) GetBlockWithContext(ctx context.Context, out consensus.BeaconBlock, slot uint64, randao [96]byte) error {
 	buf := "0x" + hex.EncodeToString(randao[:])
-	err := v.c.Get(fmt.Sprintf("/eth/v2/validator/blocks/%d?randao_reveal=%s", slot, buf), &out)
+	err := v.c.GetWithContext(ctx, fmt.Sprintf("/eth/v2/validator/blocks/%d?randao_reveal=%s", slot, buf), &out)
 	return err
 }
 
+const (
+	headerExecutionPayloadBlinded = "Eth-Execution-Payload-Blinded"
+	headerExecutionPayloadValue   = "Eth-Execution-Payload-Value"
+	headerConsensusBlockValue     = "Eth-Consensus-Block-Value"
+)
+
+type ProduceBlockOptions struct {
+	Graffiti [32]byte
+
+	// BuilderBoostFactor is the percentage that multiplies the value of the builder
+	// payload when compared with the local one. Zero always uses the local payload.
+	BuilderBoostFactor *uint64
+}
+
+// ProducedBlock is a block produced by the node. The block is either a full
+// block (with the blobs and their proofs from Deneb) or a blinded block
+// with the execution payload header of a builder.
+type ProducedBlock struct {
+	Version consensus.Version
+	Blinded bool
+
+	// ExecutionPayloadValue is the value in wei of the execution payload for the proposer
+	ExecutionPayloadValue *big.Int
+
+	// ConsensusBlockValue is the value in wei of the consensus rewards of the block
+	ConsensusBlockValue *big.Int
+
+	Block     consensus.BeaconBlock
+	KZGProofs [][48]byte
+	Blobs     [][131072]byte
+
+	BlindedBlock *consensus.VersionedBlindedBeaconBlock
+}
+
+type produceBlockResponse struct {
+	Version                 string          `json:"version"`
+	ExecutionPayloadBlinded bool            `json:"execution_payload_blinded"`
+	ExecutionPayloadValue   string          `json:"execution_payload_value"`
+	ConsensusBlockValue     string          `json:"consensus_block_value"`
+	Data                    json.RawMessage `json:"data"`
+}
+
+// ProduceBlock requests a block to the node which picks either the local execution
+// payload or the one of a builder (blinded block)
+func (v *ValidatorEndpoint) ProduceBlock(slot uint64, randao [96]byte, opts *ProduceBlockOptions) (*ProducedBlock, error) {
+	return v.ProduceBlockWithContext(context.Background(), slot, randao, opts)
+}
+
+func (v *ValidatorEndpoint) ProduceBlockWithContext(ctx context.Context, slot uint64, randao [96]byte, opts *ProduceBlockOptions) (*ProducedBlock, error) {
+	if opts == nil {
+		opts = &ProduceBlockOptions{}
+	}
+	query := url.Values{}
+	query.Set("randao_reveal", "0x"+hex.EncodeToString(randao[:]))
+	if opts.Graffiti != [32]byte{} {
+		query.Set("graffiti", "0x"+hex.EncodeToString(opts.Graffiti[:]))
+	}
+	setUintQuery(query, "builder_boost_factor", opts.BuilderBoostFactor)
+
+	path := withQuery(fmt.Sprintf("/eth/v3/validator/blocks/%d", slot), query)
+	raw, err := v.c.getRaw(ctx, path, acceptSSZOrJSON)
+	if errors.Is(err, ErrorNotAcceptable) {
+		raw, err = v.c.getRaw(ctx, path, contentTypeJSON)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var resp produceBlockResponse
+	if !raw.ssz {
+		if err := json.Unmarshal(raw.data, &resp); err != nil {
+			return nil, err
+		}
+	}
+
+	// the headers take precedence over the fields of the json response
+	if version := raw.header.Get(headerConsensusVersion); version != "" {
+		resp.Version = version
+	}
+	if blinded := raw.header.Get(headerExecutionPayloadBlinded); blinded != "" {
+		resp.ExecutionPayloadBlinded = strings.EqualFold(blinded, "true")
+	}
+	if value := raw.header.Get(headerExecutionPayloadValue); value != "" {
+		resp.ExecutionPayloadValue = value
+	}
+	if value := raw.header.Get(headerConsensusBlockValue); value != "" {
+		resp.ConsensusBlockValue = value
+	}
+
+	version, err := consensus.ParseVersion(resp.Version)
+	if err != nil {
+		return nil, err
+	}
+	out := &ProducedBlock{
+		Version: version,
+		Blinded: resp.ExecutionPayloadBlinded,
+	}
+	if out.ExecutionPayloadValue, err = parseWei(resp.ExecutionPayloadValue); err != nil {
+		return nil, err
+	}
+	if out.ConsensusBlockValue, err = parseWei(resp.ConsensusBlockValue); err != nil {
+		return nil, err
+	}
+
+	decode := func(obj ssz.Unmarshaler) error {
+		if raw.ssz {
+			return obj.UnmarshalSSZ(raw.data)
+		}
+		return Unmarshal(resp.Data, obj, v.c.config.untrackedKeys)
+	}
+
+	if out.Blinded {
+		block, err := consensus.NewVersionedBlindedBeaconBlock(version)
+		if err != nil {
+			return nil, err
+		}
+		if raw.ssz {
+			err = block.UnmarshalSSZ(raw.data)
+		} else {
+			err = Unmarshal(resp.Data, block.Object(), v.c.config.untrackedKeys)
+		}
+		if err != nil {
+			return nil, err
+		}
+		out.BlindedBlock = block
+		return out, nil
+	}
+
+	// from Deneb the full block is sent along with the blobs
+	switch version {
+	case consensus.VersionDeneb:
+		contents := &consensus.BlockContentsDeneb{}
+		if err := decode(contents); err != nil {
+			return nil, err
+		}
+		out.Block, out.KZGProofs, out.Blobs = contents.Block, contents.KZGProofs, contents.Blobs
+
+	case consensus.VersionElectra:
+		contents := &consensus.BlockContentsElectra{}
+		if err := decode(contents); err != nil {
+			return nil, err
+		}
+		out.Block, out.KZGProofs, out.Blobs = contents.Block, contents.KZGProofs, contents.Blobs
+
+	default:
+		block, err := consensus.NewBeaconBlock(version)
+		if err != nil {
+			return nil, err
+		}
+		obj, ok := block.(ssz.Unmarshaler)
+		if !ok {
+			return nil, fmt.Errorf("beacon block %T cannot be decoded", block)
+		}
+		if err := decode(obj); err != nil {
+			return nil, err
+		}
+		out.Block = block
+	}
+	return out, nil
+}
+
+// parseWei parses a decimal amount of wei. An empty amount is zero.
+func parseWei(str string) (*big.Int, error) {
+	if str == "" {
+		return new(big.Int), nil
+	}
+	num, ok := new(big.Int).SetString(str, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount '%s'", str)
+	}
+	return num, nil
+}
+
 func (v *ValidatorEndpoint) RequestAttestationData(slot uint64, committeeIndex uint64) (*consensus.AttestationData, error) {
+	return v.RequestAttestationDataWithContext(context.Background(), slot, committeeIndex)
+}
+
+func (v *ValidatorEndpoint) RequestAttestationDataWithContext(ctx context.Context, slot uint64, committeeIndex uint64) (*consensus.AttestationData, error) {
 	var out *consensus.AttestationData
-	err := v.c.Get(fmt.Sprintf("/eth/v1/validator/attestation_data?slot=%d&committee_index=%d", slot, committeeIndex), &out)
+	err := v.c.GetWithContext(ctx, fmt.Sprintf("/eth/v1/validator/attestation_data?slot=%d&committee_index=%d", slot, committeeIndex), &out)
 	return out, err
 }
 
 func (v *ValidatorEndpoint) AggregateAttestation(slot uint64, root [32]byte) (*consensus.Attestation, error) {
+	return v.AggregateAttestationWithContext(context.Background(), slot, root)
+}
+
+func (v *ValidatorEndpoint) AggregateAttestationWithContext(ctx context.Context, slot uint64, root [32]byte) (*consensus.Attestation, error) {
 	var out *consensus.Attestation
-	err := v.c.Get(fmt.Sprintf("/eth/v1/validator/aggregate_attestation?slot=%d&attestation_data_root=0x%s", slot, hex.EncodeToString(root[:])), &out)
+	err := v.c.GetWithContext(ctx, fmt.Sprintf("/eth/v1/validator/aggregate_attestation?slot=%d&attestation_data_root=0x%s", slot, hex.EncodeToString(root[:])), &out)
 	return out, err
 }
 
 func (v *ValidatorEndpoint) PublishAggregateAndProof(data []*consensus.SignedAggregateAndProof) error {
-	err := v.c.Post("/eth/v1/validator/aggregate_and_proofs", data, nil)
+	return v.PublishAggregateAndProofWithContext(context.Background(), data)
+}
+
+func (v *ValidatorEndpoint) PublishAggregateAndProofWithContext(ctx context.Context, data []*consensus.SignedAggregateAndProof) error {
+	err := v.c.PostWithContext(ctx, "/eth/v1/validator/aggregate_and_proofs", data, nil)
 	return err
 }
 
@@ -87,7 +298,11 @@ type BeaconCommitteeSubscription struct {
 }
 
 func (v *ValidatorEndpoint) BeaconCommitteeSubscriptions(subs []*BeaconCommitteeSubscription) error {
-	err := v.c.Post("/eth/v1/validator/beacon_committee_subscriptions", subs, nil)
+	return v.BeaconCommitteeSubscriptionsWithContext(context.Background(), subs)
+}
+
+func (v *ValidatorEndpoint) BeaconCommitteeSubscriptionsWithContext(ctx context.Context, subs []*BeaconCommitteeSubscription) error {
+	err := v.c.PostWithContext(ctx, "/eth/v1/validator/beacon_committee_subscriptions", subs, nil)
 	return err
 }
 
@@ -98,19 +313,31 @@ type SyncCommitteeSubscription struct {
 }
 
 func (v *ValidatorEndpoint) SyncCommitteeSubscriptions(subs []*SyncCommitteeSubscription) error {
-	err := v.c.Post("/eth/v1/validator/sync_committee_subscriptions", subs, nil)
+	return v.SyncCommitteeSubscriptionsWithContext(context.Background(), subs)
+}
+
+func (v *ValidatorEndpoint) SyncCommitteeSubscriptionsWithContext(ctx context.Context, subs []*SyncCommitteeSubscription) error {
+	err := v.c.PostWithContext(ctx, "/eth/v1/validator/sync_committee_subscriptions", subs, nil)
 	return err
 }
 
 // produces a sync committee contribution
 func (v *ValidatorEndpoint) SyncCommitteeContribution(slot uint64, subCommitteeIndex uint64, root [32]byte) (*consensus.SyncCommitteeContribution, error) {
+	return v.SyncCommitteeContributionWithContext(context.Background(), slot, subCommitteeIndex, root)
+}
+
+func (v *ValidatorEndpoint) SyncCommitteeContributionWithContext(ctx context.Context, slot uint64, subCommitteeIndex uint64, root [32]byte) (*consensus.SyncCommitteeContribution, error) {
 	var out *consensus.SyncCommitteeContribution
-	err := v.c.Get(fmt.Sprintf("/eth/v1/validator/sync_committee_contribution?slot=%d&subcommittee_index=%d&beacon_block_root=0x%s", slot, subCommitteeIndex, hex.EncodeToString(root[:])), &out)
+	err := v.c.GetWithContext(ctx, fmt.Sprintf("/eth/v1/validator/sync_committee_contribution?slot=%d&subcommittee_index=%d&beacon_block_root=0x%s", slot, subCommitteeIndex, hex.EncodeToString(root[:])), &out)
 	return out, err
 }
 
 func (v *ValidatorEndpoint) SubmitSignedContributionAndProof(signedContribution []*consensus.SignedContributionAndProof) error {
-	err := v.c.Post("/eth/v1/validator/contribution_and_proofs", signedContribution, nil)
+	return v.SubmitSignedContributionAndProofWithContext(context.Background(), signedContribution)
+}
+
+func (v *ValidatorEndpoint) SubmitSignedContributionAndProofWithContext(ctx context.Context, signedContribution []*consensus.SignedContributionAndProof) error {
+	err := v.c.PostWithContext(ctx, "/eth/v1/validator/contribution_and_proofs", signedContribution, nil)
 	return err
 }
 
@@ -120,7 +347,11 @@ type ProposalPreparation struct {
 }
 
 func (v *ValidatorEndpoint) PrepareBeaconProposer(input []*ProposalPreparation) error {
-	err := v.c.Post("/eth/v1/validator/prepare_beacon_proposer", input, nil)
+	return v.PrepareBeaconProposerWithContext(context.Background(), input)
+}
+
+func (v *ValidatorEndpoint) PrepareBeaconProposerWithContext(ctx context.Context, input []*ProposalPreparation) error {
+	err := v.c.PostWithContext(ctx, "/eth/v1/validator/prepare_beacon_proposer", input, nil)
 	return err
 }
 
@@ -136,7 +367,13 @@ type SignedValidatorRegistration struct {
 	Signature [96]byte                  `json:"signature" ssz-size:"96"`
 }
 
+// RegisterValidator sends the registrations of the validators to the builder network
+// through the beacon node. The registrations are signed with SignValidatorRegistration.
 func (v *ValidatorEndpoint) RegisterValidator(msg []*SignedValidatorRegistration) error {
-	err := v.c.Post("/eth/v1/validator/register_validator", msg, nil)
+	return v.RegisterValidatorWithContext(context.Background(), msg)
+}
+
+func (v *ValidatorEndpoint) RegisterValidatorWithContext(ctx context.Context, msg []*SignedValidatorRegistration) error {
+	err := v.c.PostWithContext(ctx, "/eth/v1/validator/register_validator", msg, nil)
 	return err
 }
@@ -1,9 +1,13 @@
 package http
 
 import (
+	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	consensus "github.com/umbracle/go-eth-consensus"
 )
 
@@ -71,3 +75,91 @@ func TestValidatorEndpoint(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestValidatorEndpoint_ProduceBlock(t *testing.T) {
+	capellaBlock := &consensus.BeaconBlockCapella{Slot: 1}
+
+	denebContents := &consensus.BlockContentsDeneb{
+		Block:     &consensus.BeaconBlockDeneb{Slot: 2},
+		KZGProofs: [][48]byte{{0x1}},
+		Blobs:     [][131072]byte{{0x2}},
+	}
+
+	blindedBlock, err := consensus.NewVersionedBlindedBeaconBlock(consensus.VersionDeneb)
+	require.NoError(t, err)
+	blindedBlock.Deneb.Slot = 3
+
+	handler := func(m *http.ServeMux) {
+		m.HandleFunc("/eth/v3/validator/blocks/1", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "0x"+strings.Repeat("00", 96), r.URL.Query().Get("randao_reveal"))
+			require.Equal(t, "0x01"+strings.Repeat("00", 31), r.URL.Query().Get("graffiti"))
+			require.Equal(t, "", r.URL.Query().Get("builder_boost_factor"))
+
+			data, err := Marshal(capellaBlock)
+			require.NoError(t, err)
+
+			w.Header().Set("Content-Type", contentTypeJSON)
+			fmt.Fprintf(w, `{"version": "capella", "execution_payload_blinded": false, "execution_payload_value": "10", "consensus_block_value": "20", "data": %s}`, data)
+		})
+		m.HandleFunc("/eth/v3/validator/blocks/2", func(w http.ResponseWriter, r *http.Request) {
+			data, err := denebContents.MarshalSSZ()
+			require.NoError(t, err)
+
+			w.Header().Set("Content-Type", contentTypeSSZ)
+			w.Header().Set(headerConsensusVersion, "deneb")
+			w.Header().Set(headerExecutionPayloadBlinded, "false")
+			w.Header().Set(headerExecutionPayloadValue, "30")
+			w.Header().Set(headerConsensusBlockValue, "40")
+			w.Write(data)
+		})
+		m.HandleFunc("/eth/v3/validator/blocks/3", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "100", r.URL.Query().Get("builder_boost_factor"))
+
+			data, err := Marshal(blindedBlock.Deneb)
+			require.NoError(t, err)
+
+			// the headers take precedence over the json fields
+			w.Header().Set("Content-Type", contentTypeJSON)
+			w.Header().Set(headerExecutionPayloadBlinded, "true")
+			w.Header().Set(headerExecutionPayloadValue, "50")
+			fmt.Fprintf(w, `{"version": "deneb", "execution_payload_blinded": false, "execution_payload_value": "0", "consensus_block_value": "60", "data": %s}`, data)
+		})
+	}
+
+	addr := newMockHttpServer(t, handler)
+	n := New("http://"+addr, WithSSZ()).Validator()
+
+	t.Run("Full", func(t *testing.T) {
+		block, err := n.ProduceBlock(1, [96]byte{}, &ProduceBlockOptions{Graffiti: [32]byte{0x1}})
+		require.NoError(t, err)
+		require.Equal(t, consensus.VersionCapella, block.Version)
+		require.False(t, block.Blinded)
+		require.Equal(t, "10", block.ExecutionPayloadValue.String())
+		require.Equal(t, "20", block.ConsensusBlockValue.String())
+		require.Equal(t, uint64(1), block.Block.(*consensus.BeaconBlockCapella).Slot)
+		require.Nil(t, block.BlindedBlock)
+	})
+
+	t.Run("BlockContents", func(t *testing.T) {
+		block, err := n.ProduceBlock(2, [96]byte{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, consensus.VersionDeneb, block.Version)
+		require.Equal(t, "30", block.ExecutionPayloadValue.String())
+		require.Equal(t, "40", block.ConsensusBlockValue.String())
+		require.Equal(t, uint64(2), block.Block.(*consensus.BeaconBlockDeneb).Slot)
+		require.Equal(t, denebContents.KZGProofs, block.KZGProofs)
+		require.Equal(t, denebContents.Blobs, block.Blobs)
+	})
+
+	t.Run("Blinded", func(t *testing.T) {
+		boost := uint64(100)
+		block, err := n.ProduceBlock(3, [96]byte{}, &ProduceBlockOptions{BuilderBoostFactor: &boost})
+		require.NoError(t, err)
+		require.True(t, block.Blinded)
+		require.Equal(t, "50", block.ExecutionPayloadValue.String())
+		require.Equal(t, "60", block.ConsensusBlockValue.String())
+		require.Nil(t, block.Block)
+		require.Equal(t, consensus.VersionDeneb, block.BlindedBlock.Version)
+		require.Equal(t, uint64(3), block.BlindedBlock.Slot())
+	})
+}
@@ -19,6 +19,9 @@ func (s *SignedBeaconBlockCapella) isSignedBeaconBlock() {
 func (s *SignedBeaconBlockDeneb) isSignedBeaconBlock() {
 }
 
+func (s *SignedBeaconBlockElectra) isSignedBeaconBlock() {
+}
+
 type BeaconBlock interface {
 	isBeaconBlock()
 }
@@ -38,6 +41,9 @@ func (s *BeaconBlockCapella) isBeaconBlock() {
 func (s *BeaconBlockDeneb) isBeaconBlock() {
 }
 
+func (s *BeaconBlockElectra) isBeaconBlock() {
+}
+
 type BeaconState interface {
 	isBeaconState()
 }
@@ -56,3 +62,6 @@ func (s *BeaconStateCapella) isBeaconState() {
 
 func (s *BeaconStateDeneb) isBeaconState() {
 }
+
+func (s *BeaconStateElectra) isBeaconState() {
+}
@@ -0,0 +1,81 @@
+package kzg
+
+import (
+	"fmt"
+	"runtime"
+
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+// Verifier checks the KZG proofs of the blobs with the trusted setup
+// of the Ethereum KZG ceremony
+type Verifier struct {
+	ctx *gokzg4844.Context
+}
+
+// NewVerifier loads the trusted setup. It takes a few seconds and the
+// verifier should be reused.
+func NewVerifier() (*Verifier, error) {
+	ctx, err := gokzg4844.NewContext4096Secure()
+	if err != nil {
+		return nil, err
+	}
+	return &Verifier{ctx: ctx}, nil
+}
+
+// BlobToCommitment computes the KZG commitment of the blob
+func (v *Verifier) BlobToCommitment(blob *[131072]byte) ([48]byte, error) {
+	commitment, err := v.ctx.BlobToKZGCommitment((*gokzg4844.Blob)(blob), runtime.NumCPU())
+	if err != nil {
+		return [48]byte{}, err
+	}
+	return commitment, nil
+}
+
+// ComputeBlobProof computes the KZG proof of the blob for its commitment
+func (v *Verifier) ComputeBlobProof(blob *[131072]byte, commitment [48]byte) ([48]byte, error) {
+	proof, err := v.ctx.ComputeBlobKZGProof((*gokzg4844.Blob)(blob), commitment, runtime.NumCPU())
+	if err != nil {
+		return [48]byte{}, err
+	}
+	return proof, nil
+}
+
+// VerifyBlobProof checks the KZG proof of the blob for its commitment
+func (v *Verifier) VerifyBlobProof(blob *[131072]byte, commitment [48]byte, proof [48]byte) error {
+	return v.ctx.VerifyBlobKZGProof((*gokzg4844.Blob)(blob), commitment, proof)
+}
+
+// VerifyBlobSidecar checks the inclusion proof of the commitment of the
+// sidecar in the block and the KZG proof of the blob
+func (v *Verifier) VerifyBlobSidecar(sidecar *consensus.BlobSidecar) error {
+	if !sidecar.VerifyInclusionProof() {
+		return fmt.Errorf("invalid inclusion proof for blob %d", sidecar.Index)
+	}
+	if err := v.VerifyBlobProof(&sidecar.Blob, sidecar.KZGCommitment, sidecar.KZGProof); err != nil {
+		return fmt.Errorf("invalid kzg proof for blob %d: %v", sidecar.Index, err)
+	}
+	return nil
+}
+
+// VerifyBlobSidecars checks the sidecars like VerifyBlobSidecar but the KZG
+// proofs are verified as a batch
+func (v *Verifier) VerifyBlobSidecars(sidecars []*consensus.BlobSidecar) error {
+	blobs := make([]gokzg4844.Blob, len(sidecars))
+	commitments := make([]gokzg4844.KZGCommitment, len(sidecars))
+	proofs := make([]gokzg4844.KZGProof, len(sidecars))
+
+	for indx, sidecar := range sidecars {
+		if !sidecar.VerifyInclusionProof() {
+			return fmt.Errorf("invalid inclusion proof for blob %d", sidecar.Index)
+		}
+		blobs[indx] = sidecar.Blob
+		commitments[indx] = sidecar.KZGCommitment
+		proofs[indx] = sidecar.KZGProof
+	}
+	if err := v.ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs); err != nil {
+		return fmt.Errorf("invalid kzg proofs: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package kzg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+// newTestBlob returns a blob whose field elements are small enough to be valid
+func newTestBlob(seed byte) *[131072]byte {
+	blob := new([131072]byte)
+	for i := 0; i < 4096; i++ {
+		blob[i*32+31] = seed + byte(i)
+	}
+	return blob
+}
+
+func newTestSidecars(t *testing.T, v *Verifier, num int) []*consensus.BlobSidecar {
+	body := &consensus.BeaconBlockBodyDeneb{
+		Eth1Data:         &consensus.Eth1Data{},
+		SyncAggregate:    &consensus.SyncAggregate{},
+		ExecutionPayload: &consensus.ExecutionPayloadDeneb{},
+	}
+
+	sidecars := []*consensus.BlobSidecar{}
+	for i := 0; i < num; i++ {
+		blob := newTestBlob(byte(i))
+
+		commitment, err := v.BlobToCommitment(blob)
+		require.NoError(t, err)
+
+		proof, err := v.ComputeBlobProof(blob, commitment)
+		require.NoError(t, err)
+
+		body.BlobKZGCommitments = append(body.BlobKZGCommitments, commitment)
+		sidecars = append(sidecars, &consensus.BlobSidecar{
+			Index:         uint64(i),
+			Blob:          *blob,
+			KZGCommitment: commitment,
+			KZGProof:      proof,
+		})
+	}
+
+	bodyRoot, err := body.HashTreeRoot()
+	require.NoError(t, err)
+
+	for _, sidecar := range sidecars {
+		gindex, err := consensus.BlobCommitmentGIndex(sidecar.Index)
+		require.NoError(t, err)
+
+		proof, err := consensus.Prove(body, gindex)
+		require.NoError(t, err)
+
+		copy(sidecar.KZGCommitmentInclusionProof[:], proof.Branch)
+		sidecar.SignedBlockHeader = &consensus.SignedBeaconBlockHeader{
+			Header: &consensus.BeaconBlockHeader{BodyRoot: bodyRoot},
+		}
+	}
+	return sidecars
+}
+
+func TestVerifier_BlobSidecars(t *testing.T) {
+	v, err := NewVerifier()
+	require.NoError(t, err)
+
+	sidecars := newTestSidecars(t, v, 2)
+	for _, sidecar := range sidecars {
+		require.NoError(t, v.VerifyBlobSidecar(sidecar))
+	}
+	require.NoError(t, v.VerifyBlobSidecars(sidecars))
+
+	// the proof of another blob
+	sidecars[0].KZGProof = sidecars[1].KZGProof
+	require.Error(t, v.VerifyBlobSidecar(sidecars[0]))
+	require.Error(t, v.VerifyBlobSidecars(sidecars))
+
+	// a commitment that is not in the block
+	sidecars = newTestSidecars(t, v, 2)
+	sidecars[1].KZGCommitment = sidecars[0].KZGCommitment
+	require.Error(t, v.VerifyBlobSidecar(sidecars[1]))
+}
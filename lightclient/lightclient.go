@@ -0,0 +1,257 @@
+package lightclient
+
+import (
+	"fmt"
+	"reflect"
+
+	ssz "github.com/ferranbt/fastssz"
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+const (
+	// finalizedRootGIndex is the generalized index of finalized_checkpoint.root in the beacon state
+	finalizedRootGIndex = 105
+
+	// currentSyncCommitteeGIndex is the generalized index of current_sync_committee in the beacon state
+	currentSyncCommitteeGIndex = 54
+
+	// nextSyncCommitteeGIndex is the generalized index of next_sync_committee in the beacon state
+	nextSyncCommitteeGIndex = 55
+
+	// executionPayloadGIndex is the generalized index of execution_payload in the beacon block body
+	executionPayloadGIndex = 25
+)
+
+const (
+	finalityBranchDepth      = 6
+	syncCommitteeBranchDepth = 5
+)
+
+// Header is a light client header of any fork up to Deneb
+type Header struct {
+	// Beacon is the beacon block header
+	Beacon *consensus.BeaconBlockHeader
+
+	// Execution is the execution payload header of the block. It is either a
+	// *consensus.ExecutionPayloadHeaderCapella or a *consensus.ExecutionPayloadHeaderDeneb
+	// and it is nil for Altair headers.
+	Execution ssz.HashRoot
+
+	// ExecutionBranch is the merkle branch of the execution payload in the block body
+	ExecutionBranch [4][32]byte
+}
+
+// NewHeader converts an Altair, Capella or Deneb light client header
+func NewHeader(obj interface{}) (*Header, error) {
+	switch obj := obj.(type) {
+	case *consensus.LightClientHeader:
+		return &Header{Beacon: obj.Header}, nil
+	case *consensus.LightClientHeaderCapella:
+		return &Header{Beacon: obj.Header, Execution: obj.Execution, ExecutionBranch: obj.ExecutionBranch}, nil
+	case *consensus.LightClientHeaderDeneb:
+		return &Header{Beacon: obj.Header, Execution: obj.Execution, ExecutionBranch: obj.ExecutionBranch}, nil
+	default:
+		return nil, fmt.Errorf("light client header type %T not expected", obj)
+	}
+}
+
+// BlockRoot returns the hash tree root of the beacon block header
+func (h *Header) BlockRoot() ([32]byte, error) {
+	return h.Beacon.HashTreeRoot()
+}
+
+// isEmpty returns true if the header is the default value of its type
+func (h *Header) isEmpty() bool {
+	if h.Beacon != nil && *h.Beacon != (consensus.BeaconBlockHeader{}) {
+		return false
+	}
+	if h.ExecutionBranch != ([4][32]byte{}) {
+		return false
+	}
+	if h.Execution == nil {
+		return true
+	}
+	return isEmptyExecution(h.Execution)
+}
+
+func isEmptyExecution(execution ssz.HashRoot) bool {
+	empty := reflect.New(reflect.TypeOf(execution).Elem()).Interface().(ssz.HashRoot)
+
+	root, err := execution.HashTreeRoot()
+	if err != nil {
+		return false
+	}
+	emptyRoot, err := empty.HashTreeRoot()
+	if err != nil {
+		return false
+	}
+	return root == emptyRoot
+}
+
+// emptyHeader returns an empty header of the same fork as h
+func (h *Header) emptyHeader() *Header {
+	header := &Header{
+		Beacon: &consensus.BeaconBlockHeader{},
+	}
+	if h.Execution != nil {
+		header.Execution = reflect.New(reflect.TypeOf(h.Execution).Elem()).Interface().(ssz.HashRoot)
+	}
+	return header
+}
+
+// Bootstrap is a light client bootstrap of any fork up to Deneb
+type Bootstrap struct {
+	Header                     *Header
+	CurrentSyncCommittee       *consensus.SyncCommittee
+	CurrentSyncCommitteeBranch [][32]byte
+}
+
+// NewBootstrap converts an Altair, Capella or Deneb light client bootstrap
+func NewBootstrap(obj interface{}) (*Bootstrap, error) {
+	var (
+		header        interface{}
+		syncCommittee *consensus.SyncCommittee
+		branch        [][32]byte
+	)
+
+	switch obj := obj.(type) {
+	case *consensus.LightClientBootstrap:
+		header, syncCommittee, branch = obj.Header, obj.CurrentSyncCommittee, obj.CurrentSyncCommitteeBranch
+	case *consensus.LightClientBootstrapCapella:
+		header, syncCommittee, branch = obj.Header, obj.CurrentSyncCommittee, obj.CurrentSyncCommitteeBranch
+	case *consensus.LightClientBootstrapDeneb:
+		header, syncCommittee, branch = obj.Header, obj.CurrentSyncCommittee, obj.CurrentSyncCommitteeBranch
+	default:
+		return nil, fmt.Errorf("light client bootstrap type %T not expected", obj)
+	}
+
+	h, err := NewHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	bootstrap := &Bootstrap{
+		Header:                     h,
+		CurrentSyncCommittee:       syncCommittee,
+		CurrentSyncCommitteeBranch: branch,
+	}
+	return bootstrap, nil
+}
+
+// Update is a light client update of any fork up to Deneb. Finality and optimistic
+// updates are represented as updates with empty fields.
+type Update struct {
+	AttestedHeader          *Header
+	NextSyncCommittee       *consensus.SyncCommittee
+	NextSyncCommitteeBranch [][32]byte
+	FinalizedHeader         *Header
+	FinalityBranch          [][32]byte
+	SyncAggregate           *consensus.SyncAggregate
+	SignatureSlot           uint64
+}
+
+// NewUpdate converts an Altair, Capella or Deneb light client update, finality
+// update or optimistic update
+func NewUpdate(obj interface{}) (*Update, error) {
+	var (
+		attestedHeader, finalizedHeader interface{}
+		update                          = &Update{}
+	)
+
+	switch obj := obj.(type) {
+	case *consensus.LightClientUpdate:
+		attestedHeader, finalizedHeader = obj.AttestedHeader, obj.FinalizedHeader
+		update.NextSyncCommittee, update.NextSyncCommitteeBranch = obj.NextSyncCommittee, obj.NextSyncCommitteeBranch
+		update.FinalityBranch, update.SyncAggregate, update.SignatureSlot = obj.FinalityBranch, obj.SyncAggregate, obj.SignatureSlot
+
+	case *consensus.LightClientUpdateCapella:
+		attestedHeader, finalizedHeader = obj.AttestedHeader, obj.FinalizedHeader
+		update.NextSyncCommittee, update.NextSyncCommitteeBranch = obj.NextSyncCommittee, obj.NextSyncCommitteeBranch
+		update.FinalityBranch, update.SyncAggregate, update.SignatureSlot = obj.FinalityBranch, obj.SyncAggregate, obj.SignatureSlot
+
+	case *consensus.LightClientUpdateDeneb:
+		attestedHeader, finalizedHeader = obj.AttestedHeader, obj.FinalizedHeader
+		update.NextSyncCommittee, update.NextSyncCommitteeBranch = obj.NextSyncCommittee, obj.NextSyncCommitteeBranch
+		update.FinalityBranch, update.SyncAggregate, update.SignatureSlot = obj.FinalityBranch, obj.SyncAggregate, obj.SignatureSlot
+
+	case *consensus.LightClientFinalityUpdate:
+		attestedHeader, finalizedHeader = obj.AttestedHeader, obj.FinalizedHeader
+		update.FinalityBranch, update.SyncAggregate, update.SignatureSlot = obj.FinalityBranch, obj.SyncAggregate, obj.SignatureSlot
+
+	case *consensus.LightClientFinalityUpdateCapella:
+		attestedHeader, finalizedHeader = obj.AttestedHeader, obj.FinalizedHeader
+		update.FinalityBranch, update.SyncAggregate, update.SignatureSlot = obj.FinalityBranch, obj.SyncAggregate, obj.SignatureSlot
+
+	case *consensus.LightClientFinalityUpdateDeneb:
+		attestedHeader, finalizedHeader = obj.AttestedHeader, obj.FinalizedHeader
+		update.FinalityBranch, update.SyncAggregate, update.SignatureSlot = obj.FinalityBranch, obj.SyncAggregate, obj.SignatureSlot
+
+	case *consensus.LightClientOptimisticUpdate:
+		attestedHeader = obj.AttestedHeader
+		update.SyncAggregate, update.SignatureSlot = obj.SyncAggregate, obj.SignatureSlot
+
+	case *consensus.LightClientOptimisticUpdateCapella:
+		attestedHeader = obj.AttestedHeader
+		update.SyncAggregate, update.SignatureSlot = obj.SyncAggregate, obj.SignatureSlot
+
+	case *consensus.LightClientOptimisticUpdateDeneb:
+		attestedHeader = obj.AttestedHeader
+		update.SyncAggregate, update.SignatureSlot = obj.SyncAggregate, obj.SignatureSlot
+
+	default:
+		return nil, fmt.Errorf("light client update type %T not expected", obj)
+	}
+
+	var err error
+	if update.AttestedHeader, err = NewHeader(attestedHeader); err != nil {
+		return nil, err
+	}
+	if finalizedHeader != nil {
+		if update.FinalizedHeader, err = NewHeader(finalizedHeader); err != nil {
+			return nil, err
+		}
+	} else {
+		update.FinalizedHeader = update.AttestedHeader.emptyHeader()
+	}
+
+	// fill the fields that are not part of finality and optimistic updates
+	if update.NextSyncCommittee == nil {
+		update.NextSyncCommittee = &consensus.SyncCommittee{}
+	}
+	if update.NextSyncCommitteeBranch == nil {
+		update.NextSyncCommitteeBranch = make([][32]byte, syncCommitteeBranchDepth)
+	}
+	if update.FinalityBranch == nil {
+		update.FinalityBranch = make([][32]byte, finalityBranchDepth)
+	}
+	return update, nil
+}
+
+// isSyncCommitteeUpdate returns true if the update includes the next sync committee
+func (u *Update) isSyncCommitteeUpdate() bool {
+	return !isZeroBranch(u.NextSyncCommitteeBranch)
+}
+
+// isFinalityUpdate returns true if the update includes the finalized header
+func (u *Update) isFinalityUpdate() bool {
+	return !isZeroBranch(u.FinalityBranch)
+}
+
+// numActiveParticipants returns the number of sync committee members that signed the update
+func (u *Update) numActiveParticipants() uint64 {
+	num := uint64(0)
+	for _, b := range u.SyncAggregate.SyncCommiteeBits {
+		for ; b != 0; b &= b - 1 {
+			num++
+		}
+	}
+	return num
+}
+
+func isZeroBranch(branch [][32]byte) bool {
+	for _, node := range branch {
+		if node != ([32]byte{}) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,490 @@
+package lightclient
+
+import (
+	"fmt"
+
+	consensus "github.com/umbracle/go-eth-consensus"
+	"github.com/umbracle/go-eth-consensus/bls"
+)
+
+// Store is the state of a light client that follows the chain with
+// the sync committee signatures.
+type Store struct {
+	spec                  *consensus.Spec
+	genesisValidatorsRoot consensus.Root
+
+	// FinalizedHeader is the latest finalized header
+	FinalizedHeader *Header
+
+	// CurrentSyncCommittee is the sync committee of the period of the finalized header
+	CurrentSyncCommittee *consensus.SyncCommittee
+
+	// NextSyncCommittee is the sync committee of the next period, it is empty if it is not known yet
+	NextSyncCommittee *consensus.SyncCommittee
+
+	// BestValidUpdate is the best update seen that was not applied, it is used
+	// by ProcessForceUpdate if the finalization stalls
+	BestValidUpdate *Update
+
+	// OptimisticHeader is the most recent header signed by the sync committee
+	OptimisticHeader *Header
+
+	// PreviousMaxActiveParticipants is the max number of active participants in the previous period
+	PreviousMaxActiveParticipants uint64
+
+	// CurrentMaxActiveParticipants is the max number of active participants in the current period
+	CurrentMaxActiveParticipants uint64
+}
+
+// NewStore initializes a light client store from a bootstrap of the trusted block root
+// (initialize_light_client_store).
+func NewStore(spec *consensus.Spec, genesisValidatorsRoot consensus.Root, trustedBlockRoot [32]byte, bootstrap *Bootstrap) (*Store, error) {
+	if spec.SlotsPerEpoch == 0 || spec.EpochsPerSyncCommitteePeriod == 0 {
+		return nil, fmt.Errorf("sync committee period is zero")
+	}
+	if spec.SyncCommitteeSize == 0 || spec.SyncCommitteeSize > uint64(len(consensus.SyncCommittee{}.PubKeys)) {
+		return nil, fmt.Errorf("sync committee size %d is not valid", spec.SyncCommitteeSize)
+	}
+
+	store := &Store{
+		spec:                  spec,
+		genesisValidatorsRoot: genesisValidatorsRoot,
+	}
+
+	if err := store.validateHeader(bootstrap.Header); err != nil {
+		return nil, fmt.Errorf("invalid bootstrap header: %v", err)
+	}
+	blockRoot, err := bootstrap.Header.BlockRoot()
+	if err != nil {
+		return nil, err
+	}
+	if blockRoot != trustedBlockRoot {
+		return nil, fmt.Errorf("bootstrap header root %x does not match trusted block root %x", blockRoot, trustedBlockRoot)
+	}
+
+	// the sync committee is sized with the preset of the spec
+	syncCommitteeRoot, err := spec.HashTreeRoot(bootstrap.CurrentSyncCommittee)
+	if err != nil {
+		return nil, err
+	}
+	if !consensus.VerifyProof(bootstrap.Header.Beacon.StateRoot, syncCommitteeRoot, bootstrap.CurrentSyncCommitteeBranch, currentSyncCommitteeGIndex) {
+		return nil, fmt.Errorf("invalid current sync committee branch")
+	}
+
+	store.FinalizedHeader = bootstrap.Header
+	store.CurrentSyncCommittee = bootstrap.CurrentSyncCommittee
+	store.NextSyncCommittee = &consensus.SyncCommittee{}
+	store.OptimisticHeader = bootstrap.Header
+	return store, nil
+}
+
+// Spec returns the spec used by the store
+func (s *Store) Spec() *consensus.Spec {
+	return s.spec
+}
+
+func (s *Store) computeSyncCommitteePeriodAtSlot(slot uint64) uint64 {
+	return slot / s.spec.SlotsPerEpoch / s.spec.EpochsPerSyncCommitteePeriod
+}
+
+func (s *Store) isNextSyncCommitteeKnown() bool {
+	return *s.NextSyncCommittee != (consensus.SyncCommittee{})
+}
+
+func (s *Store) getSafetyThreshold() uint64 {
+	return (s.PreviousMaxActiveParticipants + s.CurrentMaxActiveParticipants) / 2
+}
+
+// validateHeader checks that the execution payload header matches the fork of the
+// beacon block (is_valid_light_client_header)
+func (s *Store) validateHeader(header *Header) error {
+	if header == nil || header.Beacon == nil {
+		return fmt.Errorf("beacon block header is empty")
+	}
+
+	version := s.spec.VersionAtSlot(header.Beacon.Slot)
+	if version >= consensus.VersionElectra {
+		return fmt.Errorf("light client headers of fork '%s' are not supported", version)
+	}
+
+	var (
+		executionRoot [32]byte
+		err           error
+	)
+	switch execution := header.Execution.(type) {
+	case nil:
+		if version >= consensus.VersionCapella {
+			return fmt.Errorf("execution payload header not found")
+		}
+		if header.ExecutionBranch != ([4][32]byte{}) {
+			return fmt.Errorf("execution branch is not empty")
+		}
+		return nil
+
+	case *consensus.ExecutionPayloadHeaderCapella:
+		if version >= consensus.VersionDeneb {
+			return fmt.Errorf("capella execution payload header in fork '%s'", version)
+		}
+		if version < consensus.VersionCapella {
+			return validateEmptyExecution(header)
+		}
+		if executionRoot, err = execution.HashTreeRoot(); err != nil {
+			return err
+		}
+
+	case *consensus.ExecutionPayloadHeaderDeneb:
+		if version < consensus.VersionDeneb && (execution.BlobGasUsed != 0 || execution.ExcessBlobGas != 0) {
+			return fmt.Errorf("blob gas fields are set before deneb")
+		}
+		if version < consensus.VersionCapella {
+			return validateEmptyExecution(header)
+		}
+		if executionRoot, err = getExecutionRoot(execution, version); err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("execution payload header type %T not expected", execution)
+	}
+
+	if !consensus.VerifyProof(header.Beacon.BodyRoot, executionRoot, header.ExecutionBranch[:], executionPayloadGIndex) {
+		return fmt.Errorf("invalid execution branch")
+	}
+	return nil
+}
+
+func validateEmptyExecution(header *Header) error {
+	if !isEmptyExecution(header.Execution) || header.ExecutionBranch != ([4][32]byte{}) {
+		return fmt.Errorf("execution payload header is set before capella")
+	}
+	return nil
+}
+
+// getExecutionRoot returns the root of the execution payload header as it is
+// in the block body of the given fork (get_lc_execution_root)
+func getExecutionRoot(execution *consensus.ExecutionPayloadHeaderDeneb, version consensus.Version) ([32]byte, error) {
+	if version >= consensus.VersionDeneb {
+		return execution.HashTreeRoot()
+	}
+	capella := &consensus.ExecutionPayloadHeaderCapella{
+		ParentHash:       execution.ParentHash,
+		FeeRecipient:     execution.FeeRecipient,
+		StateRoot:        execution.StateRoot,
+		ReceiptsRoot:     execution.ReceiptsRoot,
+		LogsBloom:        execution.LogsBloom,
+		PrevRandao:       execution.PrevRandao,
+		BlockNumber:      execution.BlockNumber,
+		GasLimit:         execution.GasLimit,
+		GasUsed:          execution.GasUsed,
+		Timestamp:        execution.Timestamp,
+		ExtraData:        execution.ExtraData,
+		BaseFeePerGas:    execution.BaseFeePerGas,
+		BlockHash:        execution.BlockHash,
+		TransactionsRoot: execution.TransactionsRoot,
+		WithdrawalRoot:   execution.WithdrawalRoot,
+	}
+	return capella.HashTreeRoot()
+}
+
+// ExecutionRoot returns the root of the execution payload header of the light
+// client header or an empty root before Capella
+func (s *Store) ExecutionRoot(header *Header) ([32]byte, error) {
+	version := s.spec.VersionAtSlot(header.Beacon.Slot)
+	if version < consensus.VersionCapella {
+		return [32]byte{}, nil
+	}
+	switch execution := header.Execution.(type) {
+	case *consensus.ExecutionPayloadHeaderCapella:
+		return execution.HashTreeRoot()
+	case *consensus.ExecutionPayloadHeaderDeneb:
+		return getExecutionRoot(execution, version)
+	default:
+		return [32]byte{}, fmt.Errorf("execution payload header type %T not expected", execution)
+	}
+}
+
+// ValidateUpdate checks an update against the store (validate_light_client_update)
+func (s *Store) ValidateUpdate(update *Update, currentSlot uint64) error {
+	// verify sync committee has sufficient participants
+	if update.SyncAggregate == nil {
+		return fmt.Errorf("sync aggregate is empty")
+	}
+	if update.numActiveParticipants() < s.spec.MinSyncCommitteeParticipants {
+		return fmt.Errorf("not enough sync committee participants")
+	}
+
+	if err := s.validateHeader(update.AttestedHeader); err != nil {
+		return fmt.Errorf("invalid attested header: %v", err)
+	}
+	if update.FinalizedHeader == nil || update.FinalizedHeader.Beacon == nil {
+		return fmt.Errorf("finalized header is empty")
+	}
+
+	attestedSlot := update.AttestedHeader.Beacon.Slot
+	finalizedSlot := update.FinalizedHeader.Beacon.Slot
+	if !(currentSlot >= update.SignatureSlot && update.SignatureSlot > attestedSlot && attestedSlot >= finalizedSlot) {
+		return fmt.Errorf("invalid update slots: current %d, signature %d, attested %d, finalized %d", currentSlot, update.SignatureSlot, attestedSlot, finalizedSlot)
+	}
+
+	// verify update does not skip a sync committee period
+	storePeriod := s.computeSyncCommitteePeriodAtSlot(s.FinalizedHeader.Beacon.Slot)
+	signaturePeriod := s.computeSyncCommitteePeriodAtSlot(update.SignatureSlot)
+	if s.isNextSyncCommitteeKnown() {
+		if signaturePeriod != storePeriod && signaturePeriod != storePeriod+1 {
+			return fmt.Errorf("signature period %d is not the store period %d or the next one", signaturePeriod, storePeriod)
+		}
+	} else if signaturePeriod != storePeriod {
+		return fmt.Errorf("signature period %d is not the store period %d", signaturePeriod, storePeriod)
+	}
+
+	// verify update is relevant
+	attestedPeriod := s.computeSyncCommitteePeriodAtSlot(attestedSlot)
+	hasNextSyncCommittee := !s.isNextSyncCommitteeKnown() && update.isSyncCommitteeUpdate() && attestedPeriod == storePeriod
+	if attestedSlot <= s.FinalizedHeader.Beacon.Slot && !hasNextSyncCommittee {
+		return fmt.Errorf("update is not relevant")
+	}
+
+	// verify that the finality branch, if present, confirms finalized header
+	// to match the finalized checkpoint root saved in the state of attested header.
+	if !update.isFinalityUpdate() {
+		if !update.FinalizedHeader.isEmpty() {
+			return fmt.Errorf("finalized header is set without a finality branch")
+		}
+	} else {
+		var finalizedRoot [32]byte
+		if finalizedSlot == s.spec.GenesisSlot {
+			if !update.FinalizedHeader.isEmpty() {
+				return fmt.Errorf("genesis finalized header is not empty")
+			}
+		} else {
+			if err := s.validateHeader(update.FinalizedHeader); err != nil {
+				return fmt.Errorf("invalid finalized header: %v", err)
+			}
+			root, err := update.FinalizedHeader.BlockRoot()
+			if err != nil {
+				return err
+			}
+			finalizedRoot = root
+		}
+		if !consensus.VerifyProof(update.AttestedHeader.Beacon.StateRoot, finalizedRoot, update.FinalityBranch, finalizedRootGIndex) {
+			return fmt.Errorf("invalid finality branch")
+		}
+	}
+
+	// verify that the next_sync_committee, if present, actually is the next sync committee
+	// saved in the state of the attested header
+	if !update.isSyncCommitteeUpdate() {
+		if *update.NextSyncCommittee != (consensus.SyncCommittee{}) {
+			return fmt.Errorf("next sync committee is set without a branch")
+		}
+	} else {
+		if attestedPeriod == storePeriod && s.isNextSyncCommitteeKnown() {
+			if *update.NextSyncCommittee != *s.NextSyncCommittee {
+				return fmt.Errorf("next sync committee does not match the store")
+			}
+		}
+		root, err := s.spec.HashTreeRoot(update.NextSyncCommittee)
+		if err != nil {
+			return err
+		}
+		if !consensus.VerifyProof(update.AttestedHeader.Beacon.StateRoot, root, update.NextSyncCommitteeBranch, nextSyncCommitteeGIndex) {
+			return fmt.Errorf("invalid next sync committee branch")
+		}
+	}
+
+	// verify sync committee aggregate signature
+	syncCommittee := s.CurrentSyncCommittee
+	if signaturePeriod != storePeriod {
+		syncCommittee = s.NextSyncCommittee
+	}
+	return s.verifySyncAggregate(syncCommittee, update)
+}
+
+func (s *Store) verifySyncAggregate(syncCommittee *consensus.SyncCommittee, update *Update) error {
+	bits := update.SyncAggregate.SyncCommiteeBits
+
+	pubKeys := []*bls.PublicKey{}
+	for indx, pubKey := range syncCommittee.PubKeys[:s.spec.SyncCommitteeSize] {
+		if bits[indx/8]&(1<<(indx%8)) == 0 {
+			continue
+		}
+		pub := new(bls.PublicKey)
+		if err := pub.Deserialize(pubKey[:]); err != nil {
+			return err
+		}
+		pubKeys = append(pubKeys, pub)
+	}
+
+	forkVersionSlot := update.SignatureSlot
+	if forkVersionSlot > 0 {
+		forkVersionSlot--
+	}
+	forkVersion := s.spec.ForkVersion(s.spec.VersionAtSlot(forkVersionSlot))
+
+	domain, err := consensus.ComputeDomain(consensus.DomainSyncCommitteeType, forkVersion, s.genesisValidatorsRoot)
+	if err != nil {
+		return err
+	}
+	signingRoot, err := consensus.ComputeSigningRoot(domain, update.AttestedHeader.Beacon)
+	if err != nil {
+		return err
+	}
+
+	signature := new(bls.Signature)
+	if err := signature.Deserialize(update.SyncAggregate.SyncCommiteeSignature[:]); err != nil {
+		return err
+	}
+	ok, err := signature.FastAggregateVerify(pubKeys, signingRoot[:])
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("invalid sync committee signature")
+	}
+	return nil
+}
+
+// isBetterUpdate returns true if newUpdate is better than oldUpdate (is_better_update)
+func (s *Store) isBetterUpdate(newUpdate, oldUpdate *Update) bool {
+	// compare supermajority (> 2/3) sync committee participation
+	maxActiveParticipants := s.spec.SyncCommitteeSize
+	newNumActive := newUpdate.numActiveParticipants()
+	oldNumActive := oldUpdate.numActiveParticipants()
+	newHasSupermajority := newNumActive*3 >= maxActiveParticipants*2
+	oldHasSupermajority := oldNumActive*3 >= maxActiveParticipants*2
+	if newHasSupermajority != oldHasSupermajority {
+		return newHasSupermajority
+	}
+	if !newHasSupermajority && newNumActive != oldNumActive {
+		return newNumActive > oldNumActive
+	}
+
+	// compare presence of relevant sync committee
+	newHasRelevantSyncCommittee := newUpdate.isSyncCommitteeUpdate() &&
+		s.computeSyncCommitteePeriodAtSlot(newUpdate.AttestedHeader.Beacon.Slot) == s.computeSyncCommitteePeriodAtSlot(newUpdate.SignatureSlot)
+	oldHasRelevantSyncCommittee := oldUpdate.isSyncCommitteeUpdate() &&
+		s.computeSyncCommitteePeriodAtSlot(oldUpdate.AttestedHeader.Beacon.Slot) == s.computeSyncCommitteePeriodAtSlot(oldUpdate.SignatureSlot)
+	if newHasRelevantSyncCommittee != oldHasRelevantSyncCommittee {
+		return newHasRelevantSyncCommittee
+	}
+
+	// compare indication of any finality
+	newHasFinality := newUpdate.isFinalityUpdate()
+	oldHasFinality := oldUpdate.isFinalityUpdate()
+	if newHasFinality != oldHasFinality {
+		return newHasFinality
+	}
+
+	// compare sync committee finality
+	if newHasFinality {
+		newHasSyncCommitteeFinality := s.computeSyncCommitteePeriodAtSlot(newUpdate.FinalizedHeader.Beacon.Slot) ==
+			s.computeSyncCommitteePeriodAtSlot(newUpdate.AttestedHeader.Beacon.Slot)
+		oldHasSyncCommitteeFinality := s.computeSyncCommitteePeriodAtSlot(oldUpdate.FinalizedHeader.Beacon.Slot) ==
+			s.computeSyncCommitteePeriodAtSlot(oldUpdate.AttestedHeader.Beacon.Slot)
+		if newHasSyncCommitteeFinality != oldHasSyncCommitteeFinality {
+			return newHasSyncCommitteeFinality
+		}
+	}
+
+	// tiebreaker 1: sync committee participation beyond supermajority
+	if newNumActive != oldNumActive {
+		return newNumActive > oldNumActive
+	}
+
+	// tiebreaker 2: prefer older data (fewer changes to best)
+	if newUpdate.AttestedHeader.Beacon.Slot != oldUpdate.AttestedHeader.Beacon.Slot {
+		return newUpdate.AttestedHeader.Beacon.Slot < oldUpdate.AttestedHeader.Beacon.Slot
+	}
+	return newUpdate.SignatureSlot < oldUpdate.SignatureSlot
+}
+
+// applyUpdate moves the store to the finalized header of the update (apply_light_client_update)
+func (s *Store) applyUpdate(update *Update) error {
+	storePeriod := s.computeSyncCommitteePeriodAtSlot(s.FinalizedHeader.Beacon.Slot)
+	finalizedPeriod := s.computeSyncCommitteePeriodAtSlot(update.FinalizedHeader.Beacon.Slot)
+
+	if !s.isNextSyncCommitteeKnown() {
+		if finalizedPeriod != storePeriod {
+			return fmt.Errorf("finalized period %d is not the store period %d", finalizedPeriod, storePeriod)
+		}
+		s.NextSyncCommittee = update.NextSyncCommittee
+	} else if finalizedPeriod == storePeriod+1 {
+		s.CurrentSyncCommittee = s.NextSyncCommittee
+		s.NextSyncCommittee = update.NextSyncCommittee
+		s.PreviousMaxActiveParticipants = s.CurrentMaxActiveParticipants
+		s.CurrentMaxActiveParticipants = 0
+	}
+
+	if update.FinalizedHeader.Beacon.Slot > s.FinalizedHeader.Beacon.Slot {
+		s.FinalizedHeader = update.FinalizedHeader
+		if s.FinalizedHeader.Beacon.Slot > s.OptimisticHeader.Beacon.Slot {
+			s.OptimisticHeader = s.FinalizedHeader
+		}
+	}
+	return nil
+}
+
+// ProcessForceUpdate applies the best valid update if there has not been any
+// finality update during a whole sync committee period
+// (process_light_client_store_force_update).
+func (s *Store) ProcessForceUpdate(currentSlot uint64) error {
+	updateTimeout := s.spec.SlotsPerEpoch * s.spec.EpochsPerSyncCommitteePeriod
+	if currentSlot <= s.FinalizedHeader.Beacon.Slot+updateTimeout || s.BestValidUpdate == nil {
+		return nil
+	}
+
+	// forced best update when the update timeout has elapsed. Because the apply logic waits for
+	// finalized_header.slot to indicate sync committee finality, the attested_header may be
+	// treated as finalized_header in extended periods of non-finality to guarantee progression
+	// into later sync committee periods according to is_better_update.
+	update := s.BestValidUpdate
+	if update.FinalizedHeader.Beacon.Slot <= s.FinalizedHeader.Beacon.Slot {
+		forced := *update
+		forced.FinalizedHeader = update.AttestedHeader
+		update = &forced
+	}
+	if err := s.applyUpdate(update); err != nil {
+		return err
+	}
+	s.BestValidUpdate = nil
+	return nil
+}
+
+// ProcessUpdate validates and applies an update (process_light_client_update)
+func (s *Store) ProcessUpdate(update *Update, currentSlot uint64) error {
+	if err := s.ValidateUpdate(update, currentSlot); err != nil {
+		return err
+	}
+
+	// update the best update in case we have to force-update to it if the timeout elapses
+	if s.BestValidUpdate == nil || s.isBetterUpdate(update, s.BestValidUpdate) {
+		s.BestValidUpdate = update
+	}
+
+	// track the maximum number of active participants in the committee signatures
+	numActive := update.numActiveParticipants()
+	if numActive > s.CurrentMaxActiveParticipants {
+		s.CurrentMaxActiveParticipants = numActive
+	}
+
+	// update the optimistic header
+	if numActive > s.getSafetyThreshold() && update.AttestedHeader.Beacon.Slot > s.OptimisticHeader.Beacon.Slot {
+		s.OptimisticHeader = update.AttestedHeader
+	}
+
+	// update finalized header
+	hasFinalizedNextSyncCommittee := !s.isNextSyncCommitteeKnown() &&
+		update.isSyncCommitteeUpdate() && update.isFinalityUpdate() &&
+		s.computeSyncCommitteePeriodAtSlot(update.FinalizedHeader.Beacon.Slot) == s.computeSyncCommitteePeriodAtSlot(update.AttestedHeader.Beacon.Slot)
+
+	maxActiveParticipants := s.spec.SyncCommitteeSize
+	if numActive*3 >= maxActiveParticipants*2 &&
+		(update.FinalizedHeader.Beacon.Slot > s.FinalizedHeader.Beacon.Slot || hasFinalizedNextSyncCommittee) {
+		// normal update through 2/3 threshold
+		if err := s.applyUpdate(update); err != nil {
+			return err
+		}
+		s.BestValidUpdate = nil
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package lightclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	consensus "github.com/umbracle/go-eth-consensus"
+	"github.com/umbracle/go-eth-consensus/bls"
+	"github.com/umbracle/go-eth-consensus/spec"
+)
+
+// testChain builds light client objects signed by a sync committee
+// where every member uses the same key
+type testChain struct {
+	t                     *testing.T
+	key                   *bls.Key
+	committee             *consensus.SyncCommittee
+	genesisValidatorsRoot consensus.Root
+}
+
+func newTestChain(t *testing.T) *testChain {
+	key := bls.NewRandomKey()
+
+	committee := &consensus.SyncCommittee{}
+	for i := range committee.PubKeys {
+		committee.PubKeys[i] = key.PubKey()
+	}
+	committee.AggregatePubKey = key.PubKey()
+
+	return &testChain{
+		t:                     t,
+		key:                   key,
+		committee:             committee,
+		genesisValidatorsRoot: consensus.Root{0x1},
+	}
+}
+
+// header returns a beacon block header at slot with a state that includes
+// the sync committees and the finalized header
+func (c *testChain) header(slot uint64, finalized *consensus.BeaconBlockHeader) (*consensus.BeaconBlockHeader, *consensus.BeaconStateAltair) {
+	state := &consensus.BeaconStateAltair{
+		Slot:                 slot,
+		Slashings:            make([]uint64, 8192),
+		CurrentSyncCommittee: c.committee,
+		NextSyncCommittee:    c.committee,
+		FinalizedCheckpoint:  &consensus.Checkpoint{},
+	}
+	if finalized != nil {
+		root, err := finalized.HashTreeRoot()
+		require.NoError(c.t, err)
+		state.FinalizedCheckpoint.Root = root
+	}
+
+	stateRoot, err := state.HashTreeRoot()
+	require.NoError(c.t, err)
+
+	header := &consensus.BeaconBlockHeader{
+		Slot:      slot,
+		StateRoot: stateRoot,
+		BodyRoot:  consensus.Root{0x2},
+	}
+	return header, state
+}
+
+func (c *testChain) prove(state *consensus.BeaconStateAltair, gindex uint64) [][32]byte {
+	proof, err := consensus.Prove(state, gindex)
+	require.NoError(c.t, err)
+	return proof.Branch
+}
+
+// sign returns the sync aggregate of the first num members of the committee
+func (c *testChain) sign(header *consensus.BeaconBlockHeader, signatureSlot uint64, num int) *consensus.SyncAggregate {
+	forkVersion := spec.Spec.ForkVersion(spec.Spec.VersionAtSlot(signatureSlot - 1))
+	domain, err := consensus.ComputeDomain(consensus.DomainSyncCommitteeType, forkVersion, c.genesisValidatorsRoot)
+	require.NoError(c.t, err)
+	root, err := consensus.ComputeSigningRoot(domain, header)
+	require.NoError(c.t, err)
+
+	signature, err := c.key.Prv.Sign(root[:])
+	require.NoError(c.t, err)
+
+	aggregate := &consensus.SyncAggregate{}
+	signatures := []*bls.Signature{}
+	for i := 0; i < num; i++ {
+		aggregate.SyncCommiteeBits[i/8] |= 1 << (i % 8)
+		signatures = append(signatures, signature)
+	}
+	aggregate.SyncCommiteeSignature = bls.AggregateSignatures(signatures).Serialize()
+	return aggregate
+}
+
+func (c *testChain) update(attestedSlot, finalizedSlot uint64, participants int) *Update {
+	finalized, _ := c.header(finalizedSlot, nil)
+	attested, state := c.header(attestedSlot, finalized)
+
+	obj := &consensus.LightClientUpdate{
+		AttestedHeader:          &consensus.LightClientHeader{Header: attested},
+		NextSyncCommittee:       c.committee,
+		NextSyncCommitteeBranch: c.prove(state, nextSyncCommitteeGIndex),
+		FinalizedHeader:         &consensus.LightClientHeader{Header: finalized},
+		FinalityBranch:          c.prove(state, finalizedRootGIndex),
+		SyncAggregate:           c.sign(attested, attestedSlot+1, participants),
+		SignatureSlot:           attestedSlot + 1,
+	}
+	update, err := NewUpdate(obj)
+	require.NoError(c.t, err)
+	return update
+}
+
+func TestStore(t *testing.T) {
+	c := newTestChain(t)
+
+	// first slot of an altair sync committee period
+	period := spec.Spec.SlotsPerEpoch * spec.Spec.EpochsPerSyncCommitteePeriod
+	base := (spec.Spec.AltairForkEpoch*spec.Spec.SlotsPerEpoch/period + 1) * period
+
+	header, state := c.header(base, nil)
+	bootstrap, err := NewBootstrap(&consensus.LightClientBootstrap{
+		Header:                     &consensus.LightClientHeader{Header: header},
+		CurrentSyncCommittee:       c.committee,
+		CurrentSyncCommitteeBranch: c.prove(state, currentSyncCommitteeGIndex),
+	})
+	require.NoError(t, err)
+
+	trustedRoot, err := header.HashTreeRoot()
+	require.NoError(t, err)
+
+	_, err = NewStore(spec.Spec, c.genesisValidatorsRoot, [32]byte{0x1}, bootstrap)
+	require.Error(t, err)
+
+	store, err := NewStore(spec.Spec, c.genesisValidatorsRoot, trustedRoot, bootstrap)
+	require.NoError(t, err)
+	require.False(t, store.isNextSyncCommitteeKnown())
+
+	t.Run("invalid updates", func(t *testing.T) {
+		update := c.update(base+20, base+10, 512)
+
+		// signature slot in the future
+		require.Error(t, store.ValidateUpdate(update, base+20))
+
+		// signature from another network
+		other := *update
+		other.SyncAggregate = newTestChain(t).sign(update.AttestedHeader.Beacon, update.SignatureSlot, 512)
+		require.Error(t, store.ValidateUpdate(&other, base+21))
+
+		// finalized header that is not part of the state
+		other = *update
+		other.FinalizedHeader = &Header{Beacon: &consensus.BeaconBlockHeader{Slot: base + 11}}
+		require.Error(t, store.ValidateUpdate(&other, base+21))
+	})
+
+	// finality update with the next sync committee
+	update := c.update(base+20, base+10, 512)
+	require.NoError(t, store.ProcessUpdate(update, base+21))
+
+	require.Equal(t, base+10, store.FinalizedHeader.Beacon.Slot)
+	require.Equal(t, base+20, store.OptimisticHeader.Beacon.Slot)
+	require.True(t, store.isNextSyncCommitteeKnown())
+	require.Nil(t, store.BestValidUpdate)
+
+	// optimistic update without supermajority is not applied
+	update = c.update(base+30, base+25, 300)
+	optimistic, err := NewUpdate(&consensus.LightClientOptimisticUpdate{
+		AttestedHeader: &consensus.LightClientHeader{Header: update.AttestedHeader.Beacon},
+		SyncAggregate:  update.SyncAggregate,
+		SignatureSlot:  update.SignatureSlot,
+	})
+	require.NoError(t, err)
+	require.True(t, optimistic.FinalizedHeader.isEmpty())
+
+	require.NoError(t, store.ProcessUpdate(optimistic, base+31))
+	require.Equal(t, base+10, store.FinalizedHeader.Beacon.Slot)
+	require.Equal(t, base+30, store.OptimisticHeader.Beacon.Slot)
+	require.Equal(t, optimistic, store.BestValidUpdate)
+
+	// the store does not move until the update timeout
+	require.NoError(t, store.ProcessForceUpdate(base+10+period))
+	require.Equal(t, base+10, store.FinalizedHeader.Beacon.Slot)
+
+	require.NoError(t, store.ProcessForceUpdate(base+10+period+1))
+	require.Equal(t, base+30, store.FinalizedHeader.Beacon.Slot)
+	require.Nil(t, store.BestValidUpdate)
+}
@@ -0,0 +1,224 @@
+package lightclient
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ssz "github.com/ferranbt/fastssz"
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/require"
+	consensus "github.com/umbracle/go-eth-consensus"
+	"github.com/umbracle/go-eth-consensus/spec"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	testDataFolder = "../eth2.0-spec-tests/tests"
+)
+
+// syncForks are the forks with light client types
+var syncForks = []struct {
+	name         string
+	version      consensus.Version
+	newBootstrap func() ssz.Unmarshaler
+	newUpdate    func() ssz.Unmarshaler
+}{
+	{
+		name:         "altair",
+		version:      consensus.VersionAltair,
+		newBootstrap: func() ssz.Unmarshaler { return new(consensus.LightClientBootstrap) },
+		newUpdate:    func() ssz.Unmarshaler { return new(consensus.LightClientUpdate) },
+	},
+	{
+		name:         "bellatrix",
+		version:      consensus.VersionBellatrix,
+		newBootstrap: func() ssz.Unmarshaler { return new(consensus.LightClientBootstrap) },
+		newUpdate:    func() ssz.Unmarshaler { return new(consensus.LightClientUpdate) },
+	},
+	{
+		name:         "capella",
+		version:      consensus.VersionCapella,
+		newBootstrap: func() ssz.Unmarshaler { return new(consensus.LightClientBootstrapCapella) },
+		newUpdate:    func() ssz.Unmarshaler { return new(consensus.LightClientUpdateCapella) },
+	},
+	{
+		name:         "deneb",
+		version:      consensus.VersionDeneb,
+		newBootstrap: func() ssz.Unmarshaler { return new(consensus.LightClientBootstrapDeneb) },
+		newUpdate:    func() ssz.Unmarshaler { return new(consensus.LightClientUpdateDeneb) },
+	},
+}
+
+type syncMeta struct {
+	GenesisValidatorsRoot string `yaml:"genesis_validators_root"`
+	TrustedBlockRoot      string `yaml:"trusted_block_root"`
+	BootstrapForkDigest   string `yaml:"bootstrap_fork_digest"`
+	StoreForkDigest       string `yaml:"store_fork_digest"`
+}
+
+type syncHeaderCheck struct {
+	Slot          uint64 `yaml:"slot"`
+	BeaconRoot    string `yaml:"beacon_root"`
+	ExecutionRoot string `yaml:"execution_root"`
+}
+
+type syncChecks struct {
+	FinalizedHeader  *syncHeaderCheck `yaml:"finalized_header"`
+	OptimisticHeader *syncHeaderCheck `yaml:"optimistic_header"`
+}
+
+type syncStep struct {
+	ProcessUpdate *struct {
+		UpdateForkDigest string     `yaml:"update_fork_digest"`
+		Update           string     `yaml:"update"`
+		CurrentSlot      uint64     `yaml:"current_slot"`
+		Checks           syncChecks `yaml:"checks"`
+	} `yaml:"process_update"`
+	ForceUpdate *struct {
+		CurrentSlot uint64     `yaml:"current_slot"`
+		Checks      syncChecks `yaml:"checks"`
+	} `yaml:"force_update"`
+	UpgradeStore *struct {
+		StoreForkDigest string     `yaml:"store_fork_digest"`
+		Checks          syncChecks `yaml:"checks"`
+	} `yaml:"upgrade_store"`
+}
+
+func TestSpecSync(t *testing.T) {
+	// the sync tests run with the ssz types sized for the preset of the test data
+	presets := []struct {
+		name string
+		spec *consensus.Spec
+	}{
+		{"mainnet", spec.Spec},
+		{"minimal", spec.MinimalSpec},
+	}
+
+	found := false
+	for _, preset := range presets {
+		for _, fork := range syncForks {
+			matches, err := filepath.Glob(filepath.Join(testDataFolder, preset.name, fork.name, "light_client/sync/pyspec_tests/*"))
+			require.NoError(t, err)
+
+			for _, path := range matches {
+				found = true
+				t.Run(preset.name+"/"+fork.name+"/"+filepath.Base(path), func(t *testing.T) {
+					runSyncTest(t, preset.spec, path)
+				})
+			}
+		}
+	}
+	if !found {
+		t.Fatal("no light client sync tests found")
+	}
+}
+
+func runSyncTest(t *testing.T, config *consensus.Spec, path string) {
+	if _, err := os.Stat(filepath.Join(path, "config.yaml")); err == nil {
+		config, err = spec.LoadSpec(filepath.Join(path, "config.yaml"), "")
+		require.NoError(t, err)
+	}
+
+	var meta syncMeta
+	readYaml(t, filepath.Join(path, "meta.yaml"), &meta)
+
+	genesisValidatorsRoot := consensus.Root(decodeRoot(t, meta.GenesisValidatorsRoot))
+
+	// the fork of the objects is identified by their fork digest
+	forkByDigest := func(digest string) int {
+		for indx, fork := range syncForks {
+			forkData := &consensus.ForkData{
+				CurrentVersion:        config.ForkVersion(fork.version),
+				GenesisValidatorsRoot: genesisValidatorsRoot,
+			}
+			root, err := forkData.HashTreeRoot()
+			require.NoError(t, err)
+
+			if "0x"+hex.EncodeToString(root[:4]) == digest {
+				return indx
+			}
+		}
+		t.Fatalf("fork digest %s not found", digest)
+		return 0
+	}
+
+	bootstrapObj := syncForks[forkByDigest(meta.BootstrapForkDigest)].newBootstrap()
+	decodeSnappy(t, config, filepath.Join(path, "bootstrap.ssz_snappy"), bootstrapObj)
+
+	bootstrap, err := NewBootstrap(bootstrapObj)
+	require.NoError(t, err)
+
+	store, err := NewStore(config, genesisValidatorsRoot, decodeRoot(t, meta.TrustedBlockRoot), bootstrap)
+	require.NoError(t, err)
+
+	var steps []syncStep
+	readYaml(t, filepath.Join(path, "steps.yaml"), &steps)
+
+	for _, step := range steps {
+		var checks syncChecks
+
+		if step.ProcessUpdate != nil {
+			updateObj := syncForks[forkByDigest(step.ProcessUpdate.UpdateForkDigest)].newUpdate()
+			decodeSnappy(t, config, filepath.Join(path, step.ProcessUpdate.Update+".ssz_snappy"), updateObj)
+
+			update, err := NewUpdate(updateObj)
+			require.NoError(t, err)
+			require.NoError(t, store.ProcessUpdate(update, step.ProcessUpdate.CurrentSlot))
+
+			checks = step.ProcessUpdate.Checks
+		} else if step.ForceUpdate != nil {
+			require.NoError(t, store.ProcessForceUpdate(step.ForceUpdate.CurrentSlot))
+
+			checks = step.ForceUpdate.Checks
+		} else if step.UpgradeStore != nil {
+			// the store keeps the headers of any fork
+			checks = step.UpgradeStore.Checks
+		}
+
+		checkHeader(t, store, store.FinalizedHeader, checks.FinalizedHeader)
+		checkHeader(t, store, store.OptimisticHeader, checks.OptimisticHeader)
+	}
+}
+
+func checkHeader(t *testing.T, store *Store, header *Header, check *syncHeaderCheck) {
+	if check == nil {
+		return
+	}
+	require.Equal(t, check.Slot, header.Beacon.Slot)
+
+	root, err := header.BlockRoot()
+	require.NoError(t, err)
+	require.Equal(t, decodeRoot(t, check.BeaconRoot), root)
+
+	if check.ExecutionRoot != "" {
+		executionRoot, err := store.ExecutionRoot(header)
+		require.NoError(t, err)
+		require.Equal(t, decodeRoot(t, check.ExecutionRoot), executionRoot)
+	}
+}
+
+func readYaml(t *testing.T, path string, obj interface{}) {
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(data, obj))
+}
+
+func decodeSnappy(t *testing.T, config *consensus.Spec, path string, obj ssz.Unmarshaler) {
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	content, err := snappy.Decode(nil, data)
+	require.NoError(t, err)
+	require.NoError(t, config.DecodeSSZ(content, obj))
+}
+
+func decodeRoot(t *testing.T, str string) (root [32]byte) {
+	buf, err := hex.DecodeString(strings.TrimPrefix(str, "0x"))
+	require.NoError(t, err)
+	copy(root[:], buf)
+	return
+}
@@ -0,0 +1,725 @@
+package consensus
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	ssz "github.com/ferranbt/fastssz"
+)
+
+// The ssz types of the package are sized with the mainnet preset and their generated
+// methods (MarshalSSZ, UnmarshalSSZ and HashTreeRoot) only work with that preset. The
+// fields whose size depends on the preset have an 'ssz-preset' tag with the expression
+// of spec values that sizes the outer list or vector of the field (i.e.
+// 'EPOCHS_PER_ETH1_VOTING_PERIOD*SLOTS_PER_EPOCH'). The expressions are products and
+// divisions of spec values and numbers. Divisions round up, a bitvector of
+// 'MAX_COMMITTEES_PER_SLOT/8' bytes takes one byte with four committees.
+//
+// EncodeSSZ, DecodeSSZ and HashTreeRoot use the sizes of the spec instead. The vectors
+// smaller than the mainnet arrays of the types (i.e. the 64 block roots of the minimal
+// preset) only use the first items of the array.
+
+// EncodeSSZ encodes the object with the sizes of the spec
+func (s *Spec) EncodeSSZ(obj interface{}) ([]byte, error) {
+	v, node, err := presetObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	if s.mainnetSizes(node.typ) {
+		if m, ok := obj.(ssz.Marshaler); ok {
+			return m.MarshalSSZ()
+		}
+	}
+	return s.marshalSSZ(nil, v, node)
+}
+
+// DecodeSSZ decodes the object with the sizes of the spec
+func (s *Spec) DecodeSSZ(buf []byte, obj interface{}) error {
+	v, node, err := presetObject(obj)
+	if err != nil {
+		return err
+	}
+	if s.mainnetSizes(node.typ) {
+		if m, ok := obj.(ssz.Unmarshaler); ok {
+			return m.UnmarshalSSZ(buf)
+		}
+	}
+	return s.unmarshalSSZ(buf, v, node)
+}
+
+// HashTreeRoot returns the hash tree root of the object with the sizes of the spec
+func (s *Spec) HashTreeRoot(obj interface{}) ([32]byte, error) {
+	v, node, err := presetObject(obj)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if s.mainnetSizes(node.typ) {
+		if m, ok := obj.(ssz.HashRoot); ok {
+			return m.HashTreeRoot()
+		}
+	}
+	hh := ssz.NewHasher()
+	if err := s.hashTreeRootWith(hh, v, node); err != nil {
+		return [32]byte{}, err
+	}
+	return hh.HashRoot()
+}
+
+func presetObject(obj interface{}) (reflect.Value, *sszNode, error) {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return reflect.Value{}, nil, fmt.Errorf("object %T is not a pointer", obj)
+	}
+	return v.Elem(), &sszNode{typ: v.Type().Elem()}, nil
+}
+
+// presetSize is a size of the mainnet types that depends on the preset
+type presetSize struct {
+	expr    string
+	mainnet uint64
+}
+
+// presetSizesCache caches the preset sizes of each type
+var presetSizesCache sync.Map
+
+// presetSizes returns the sizes that depend on the preset in the ssz tree of the type
+func presetSizes(typ reflect.Type) []presetSize {
+	if res, ok := presetSizesCache.Load(typ); ok {
+		return res.([]presetSize)
+	}
+
+	res := []presetSize{}
+	var walk func(n *sszNode)
+	walk = func(n *sszNode) {
+		switch n.typ.Kind() {
+		case reflect.Struct:
+			for _, field := range sszFields(n.typ) {
+				walk(sszFieldNode(field))
+			}
+		case reflect.Slice, reflect.Array:
+			if n.preset != "" {
+				size, _ := sszTagSize(n)
+				res = append(res, presetSize{expr: n.preset, mainnet: size})
+			}
+			walk(n.elem())
+		}
+	}
+	walk(&sszNode{typ: typ})
+
+	presetSizesCache.Store(typ, res)
+	return res
+}
+
+// generatedSSZ enables the generated ssz methods for the types sized like the
+// mainnet preset. The tests disable it to check the reflection codec.
+var generatedSSZ = true
+
+// mainnetSizes returns whether the spec sizes the type like the mainnet preset
+// and the generated ssz methods of the type can be used
+func (s *Spec) mainnetSizes(typ reflect.Type) bool {
+	if !generatedSSZ {
+		return false
+	}
+	for _, size := range presetSizes(typ) {
+		val, err := s.presetValue(size.expr)
+		if err != nil || val != size.mainnet {
+			return false
+		}
+	}
+	return true
+}
+
+// specFieldsCache maps the json names of the spec values to their field index
+var specFieldsCache sync.Map
+
+// presetValue evaluates an 'ssz-preset' expression with the values of the spec
+func (s *Spec) presetValue(expr string) (uint64, error) {
+	var (
+		res  uint64
+		op   = byte('*')
+		rest = expr
+	)
+	for i := 0; ; i++ {
+		indx := strings.IndexAny(rest, "*/")
+
+		term := rest
+		if indx != -1 {
+			term = rest[:indx]
+		}
+		val, err := s.presetTerm(strings.TrimSpace(term))
+		if err != nil {
+			return 0, fmt.Errorf("preset '%s': %v", expr, err)
+		}
+
+		switch {
+		case i == 0:
+			res = val
+		case op == '*':
+			res *= val
+		case val == 0:
+			return 0, fmt.Errorf("preset '%s': division by zero", expr)
+		default:
+			res = (res + val - 1) / val
+		}
+
+		if indx == -1 {
+			break
+		}
+		op, rest = rest[indx], rest[indx+1:]
+	}
+	if res == 0 {
+		return 0, fmt.Errorf("preset '%s' is zero", expr)
+	}
+	return res, nil
+}
+
+func (s *Spec) presetTerm(term string) (uint64, error) {
+	if num, err := strconv.ParseUint(term, 10, 64); err == nil {
+		return num, nil
+	}
+
+	var fields map[string]int
+	if res, ok := specFieldsCache.Load("fields"); ok {
+		fields = res.(map[string]int)
+	} else {
+		fields = map[string]int{}
+		typ := reflect.TypeOf(Spec{})
+		for i := 0; i < typ.NumField(); i++ {
+			if typ.Field(i).Type.Kind() == reflect.Uint64 {
+				fields[typ.Field(i).Tag.Get("json")] = i
+			}
+		}
+		specFieldsCache.Store("fields", fields)
+	}
+
+	indx, ok := fields[term]
+	if !ok {
+		return 0, fmt.Errorf("spec value '%s' not found", term)
+	}
+	return reflect.ValueOf(s).Elem().Field(indx).Uint(), nil
+}
+
+// sszTagSize returns the size of the outer list or vector of the node from the ssz tags
+func sszTagSize(n *sszNode) (uint64, error) {
+	var size, max string
+	if len(n.sizes) != 0 {
+		size = n.sizes[0]
+	}
+	if len(n.maxes) != 0 {
+		max = n.maxes[0]
+	}
+
+	switch {
+	case isSSZList(n):
+		return strconv.ParseUint(max, 10, 64)
+	case size != "" && size != "?":
+		return strconv.ParseUint(size, 10, 64)
+	case n.typ.Kind() == reflect.Array:
+		return uint64(n.typ.Len()), nil
+	default:
+		return 0, fmt.Errorf("size of %s not found", n.typ)
+	}
+}
+
+func isSSZList(n *sszNode) bool {
+	return len(n.maxes) != 0 && n.maxes[0] != "" && n.maxes[0] != "?"
+}
+
+// sszSize returns the size of the outer list or vector of the node with the spec. It is the
+// length of a vector and the maximum number of items (or bits for a bitlist) of a list.
+func (s *Spec) sszSize(n *sszNode) (uint64, error) {
+	var (
+		size uint64
+		err  error
+	)
+	if n.preset != "" {
+		size, err = s.presetValue(n.preset)
+	} else {
+		size, err = sszTagSize(n)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !isSSZList(n) && n.typ.Kind() == reflect.Array && size > uint64(n.typ.Len()) {
+		return 0, fmt.Errorf("vector of %d items does not fit in %s", size, n.typ)
+	}
+	return size, nil
+}
+
+// sszFixedSize returns the encoded size of the node or false if the size is variable
+func (s *Spec) sszFixedSize(n *sszNode) (uint64, bool, error) {
+	switch n.typ.Kind() {
+	case reflect.Struct:
+		total := uint64(0)
+		for _, field := range sszFields(n.typ) {
+			size, fixed, err := s.sszFixedSize(sszFieldNode(field))
+			if err != nil || !fixed {
+				return 0, false, err
+			}
+			total += size
+		}
+		return total, true, nil
+
+	case reflect.Slice, reflect.Array:
+		if isSSZList(n) {
+			return 0, false, nil
+		}
+		size, err := s.sszSize(n)
+		if err != nil {
+			return 0, false, err
+		}
+		elemSize, fixed, err := s.sszFixedSize(n.elem())
+		if err != nil || !fixed {
+			return 0, false, err
+		}
+		return size * elemSize, true, nil
+
+	default:
+		if size := sszBasicSize(n.typ); size != 0 {
+			return size, true, nil
+		}
+		return 0, false, fmt.Errorf("type %s not supported", n.typ)
+	}
+}
+
+// sszValue dereferences the pointers of the value. Nil pointers are
+// encoded as the zero value.
+func sszValue(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v = reflect.New(v.Type().Elem())
+		}
+		v = v.Elem()
+	}
+	if !v.CanAddr() {
+		// arrays have to be addressable to slice their bytes
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		v = ptr.Elem()
+	}
+	return v
+}
+
+func (s *Spec) marshalSSZ(dst []byte, v reflect.Value, n *sszNode) ([]byte, error) {
+	v = sszValue(v)
+
+	switch n.typ.Kind() {
+	case reflect.Bool:
+		return ssz.MarshalBool(dst, v.Bool()), nil
+	case reflect.Uint8:
+		return ssz.MarshalUint8(dst, uint8(v.Uint())), nil
+	case reflect.Uint16:
+		return ssz.MarshalUint16(dst, uint16(v.Uint())), nil
+	case reflect.Uint32:
+		return ssz.MarshalUint32(dst, uint32(v.Uint())), nil
+	case reflect.Uint64:
+		return ssz.MarshalUint64(dst, v.Uint()), nil
+
+	case reflect.Struct:
+		if s.mainnetSizes(n.typ) {
+			if m, ok := v.Addr().Interface().(ssz.Marshaler); ok {
+				return m.MarshalSSZTo(dst)
+			}
+		}
+		return s.marshalContainer(dst, v, n)
+
+	case reflect.Slice, reflect.Array:
+		return s.marshalSequence(dst, v, n)
+
+	default:
+		return nil, fmt.Errorf("type %s not supported", n.typ)
+	}
+}
+
+func (s *Spec) marshalContainer(dst []byte, v reflect.Value, n *sszNode) ([]byte, error) {
+	fixedSize := uint64(0)
+	for _, field := range sszFields(n.typ) {
+		size, fixed, err := s.sszFixedSize(sszFieldNode(field))
+		if err != nil {
+			return nil, err
+		}
+		if !fixed {
+			size = 4
+		}
+		fixedSize += size
+	}
+
+	offset := int(fixedSize)
+	variable := [][]byte{}
+	for _, field := range sszFields(n.typ) {
+		fieldNode := sszFieldNode(field)
+
+		_, fixed, err := s.sszFixedSize(fieldNode)
+		if err != nil {
+			return nil, err
+		}
+		if fixed {
+			if dst, err = s.marshalSSZ(dst, v.FieldByIndex(field.Index), fieldNode); err != nil {
+				return nil, fmt.Errorf("%s.%s: %v", n.typ.Name(), field.Name, err)
+			}
+			continue
+		}
+
+		buf, err := s.marshalSSZ(nil, v.FieldByIndex(field.Index), fieldNode)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %v", n.typ.Name(), field.Name, err)
+		}
+		dst = ssz.WriteOffset(dst, offset)
+		offset += len(buf)
+		variable = append(variable, buf)
+	}
+
+	for _, buf := range variable {
+		dst = append(dst, buf...)
+	}
+	return dst, nil
+}
+
+// sequenceLen returns the number of items to encode of a list or vector
+func (s *Spec) sequenceLen(v reflect.Value, n *sszNode) (int, error) {
+	size, err := s.sszSize(n)
+	if err != nil {
+		return 0, err
+	}
+
+	num := v.Len()
+	switch {
+	case n.bitlist:
+		if uint64(num) > size/8+1 {
+			return 0, fmt.Errorf("bitlist of %d bytes is too big for %d bits", num, size)
+		}
+	case isSSZList(n):
+		if uint64(num) > size {
+			return 0, fmt.Errorf("list of %d items is too big for %d items", num, size)
+		}
+	case n.typ.Kind() == reflect.Array:
+		// only the first items of the array are part of the vector
+		num = int(size)
+	default:
+		if uint64(num) != size {
+			return 0, fmt.Errorf("vector of %d items, expected %d", num, size)
+		}
+	}
+	return num, nil
+}
+
+func (s *Spec) marshalSequence(dst []byte, v reflect.Value, n *sszNode) ([]byte, error) {
+	num, err := s.sequenceLen(v, n)
+	if err != nil {
+		return nil, err
+	}
+
+	elem := n.elem()
+	if elem.typ.Kind() == reflect.Uint8 {
+		return append(dst, v.Slice(0, num).Bytes()...), nil
+	}
+
+	_, fixed, err := s.sszFixedSize(elem)
+	if err != nil {
+		return nil, err
+	}
+	if fixed {
+		for i := 0; i < num; i++ {
+			if dst, err = s.marshalSSZ(dst, v.Index(i), elem); err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	}
+
+	offset := 4 * num
+	items := make([][]byte, num)
+	for i := 0; i < num; i++ {
+		if items[i], err = s.marshalSSZ(nil, v.Index(i), elem); err != nil {
+			return nil, err
+		}
+		dst = ssz.WriteOffset(dst, offset)
+		offset += len(items[i])
+	}
+	for _, item := range items {
+		dst = append(dst, item...)
+	}
+	return dst, nil
+}
+
+func (s *Spec) unmarshalSSZ(buf []byte, v reflect.Value, n *sszNode) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	switch n.typ.Kind() {
+	case reflect.Bool, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if size := sszBasicSize(n.typ); uint64(len(buf)) != size {
+			return ssz.ErrSize
+		}
+		switch n.typ.Kind() {
+		case reflect.Bool:
+			if buf[0] > 1 {
+				return fmt.Errorf("invalid boolean %d", buf[0])
+			}
+			v.SetBool(ssz.UnmarshalBool(buf))
+		case reflect.Uint8:
+			v.SetUint(uint64(ssz.UnmarshallUint8(buf)))
+		case reflect.Uint16:
+			v.SetUint(uint64(ssz.UnmarshallUint16(buf)))
+		case reflect.Uint32:
+			v.SetUint(uint64(ssz.UnmarshallUint32(buf)))
+		default:
+			v.SetUint(ssz.UnmarshallUint64(buf))
+		}
+		return nil
+
+	case reflect.Struct:
+		if s.mainnetSizes(n.typ) {
+			if m, ok := v.Addr().Interface().(ssz.Unmarshaler); ok {
+				return m.UnmarshalSSZ(buf)
+			}
+		}
+		return s.unmarshalContainer(buf, v, n)
+
+	case reflect.Slice, reflect.Array:
+		return s.unmarshalSequence(buf, v, n)
+
+	default:
+		return fmt.Errorf("type %s not supported", n.typ)
+	}
+}
+
+func (s *Spec) unmarshalContainer(buf []byte, v reflect.Value, n *sszNode) error {
+	type variableField struct {
+		field  reflect.StructField
+		offset uint64
+	}
+
+	var (
+		pos      = uint64(0)
+		size     = uint64(len(buf))
+		variable = []variableField{}
+	)
+	for _, field := range sszFields(n.typ) {
+		fieldNode := sszFieldNode(field)
+
+		fieldSize, fixed, err := s.sszFixedSize(fieldNode)
+		if err != nil {
+			return err
+		}
+		if !fixed {
+			fieldSize = 4
+		}
+		if pos+fieldSize > size {
+			return ssz.ErrSize
+		}
+
+		if fixed {
+			if err := s.unmarshalSSZ(buf[pos:pos+fieldSize], v.FieldByIndex(field.Index), fieldNode); err != nil {
+				return fmt.Errorf("%s.%s: %v", n.typ.Name(), field.Name, err)
+			}
+		} else {
+			offset := ssz.ReadOffset(buf[pos : pos+4])
+			if len(variable) == 0 && offset != fixedSizeOf(n, s) {
+				return ssz.ErrOffset
+			}
+			if len(variable) != 0 && offset < variable[len(variable)-1].offset {
+				return ssz.ErrOffset
+			}
+			if offset > size {
+				return ssz.ErrOffset
+			}
+			variable = append(variable, variableField{field: field, offset: offset})
+		}
+		pos += fieldSize
+	}
+	if len(variable) == 0 && pos != size {
+		return ssz.ErrSize
+	}
+
+	for i, item := range variable {
+		end := size
+		if i+1 < len(variable) {
+			end = variable[i+1].offset
+		}
+		if err := s.unmarshalSSZ(buf[item.offset:end], v.FieldByIndex(item.field.Index), sszFieldNode(item.field)); err != nil {
+			return fmt.Errorf("%s.%s: %v", n.typ.Name(), item.field.Name, err)
+		}
+	}
+	return nil
+}
+
+// fixedSizeOf returns the size of the fixed part of a container
+func fixedSizeOf(n *sszNode, s *Spec) uint64 {
+	total := uint64(0)
+	for _, field := range sszFields(n.typ) {
+		size, fixed, _ := s.sszFixedSize(sszFieldNode(field))
+		if !fixed {
+			size = 4
+		}
+		total += size
+	}
+	return total
+}
+
+func (s *Spec) unmarshalSequence(buf []byte, v reflect.Value, n *sszNode) error {
+	size, err := s.sszSize(n)
+	if err != nil {
+		return err
+	}
+	list := isSSZList(n)
+
+	elem := n.elem()
+	elemSize, fixed, err := s.sszFixedSize(elem)
+	if err != nil {
+		return err
+	}
+
+	// number of items of the sequence
+	var num uint64
+	switch {
+	case n.bitlist:
+		if err := ssz.ValidateBitlist(buf, size); err != nil {
+			return err
+		}
+		num = uint64(len(buf))
+	case fixed:
+		if uint64(len(buf))%elemSize != 0 {
+			return ssz.ErrSize
+		}
+		num = uint64(len(buf)) / elemSize
+	default:
+		count, err := ssz.DecodeDynamicLength(buf, int(size))
+		if err != nil {
+			return err
+		}
+		num = uint64(count)
+	}
+	if !n.bitlist && ((list && num > size) || (!list && num != size)) {
+		return fmt.Errorf("%d items for a sequence of %d items", num, size)
+	}
+
+	if n.typ.Kind() == reflect.Slice {
+		v.Set(reflect.MakeSlice(n.typ, int(num), int(num)))
+	} else {
+		v.Set(reflect.Zero(n.typ))
+	}
+
+	if elem.typ.Kind() == reflect.Uint8 {
+		reflect.Copy(v.Slice(0, int(num)), reflect.ValueOf(buf))
+		return nil
+	}
+	if fixed {
+		for i := uint64(0); i < num; i++ {
+			if err := s.unmarshalSSZ(buf[i*elemSize:(i+1)*elemSize], v.Index(int(i)), elem); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return ssz.UnmarshalDynamic(buf, int(num), func(indx int, buf []byte) error {
+		return s.unmarshalSSZ(buf, v.Index(indx), elem)
+	})
+}
+
+func (s *Spec) hashTreeRootWith(hh *ssz.Hasher, v reflect.Value, n *sszNode) error {
+	v = sszValue(v)
+
+	switch n.typ.Kind() {
+	case reflect.Bool:
+		hh.PutBool(v.Bool())
+	case reflect.Uint8:
+		hh.PutUint8(uint8(v.Uint()))
+	case reflect.Uint16:
+		hh.PutUint16(uint16(v.Uint()))
+	case reflect.Uint32:
+		hh.PutUint32(uint32(v.Uint()))
+	case reflect.Uint64:
+		hh.PutUint64(v.Uint())
+
+	case reflect.Struct:
+		if s.mainnetSizes(n.typ) {
+			if m, ok := v.Addr().Interface().(ssz.HashRoot); ok {
+				return m.HashTreeRootWith(hh)
+			}
+		}
+		indx := hh.Index()
+		for _, field := range sszFields(n.typ) {
+			if err := s.hashTreeRootWith(hh, v.FieldByIndex(field.Index), sszFieldNode(field)); err != nil {
+				return fmt.Errorf("%s.%s: %v", n.typ.Name(), field.Name, err)
+			}
+		}
+		hh.Merkleize(indx)
+
+	case reflect.Slice, reflect.Array:
+		return s.hashSequence(hh, v, n)
+
+	default:
+		return fmt.Errorf("type %s not supported", n.typ)
+	}
+	return nil
+}
+
+func (s *Spec) hashSequence(hh *ssz.Hasher, v reflect.Value, n *sszNode) error {
+	size, err := s.sszSize(n)
+	if err != nil {
+		return err
+	}
+	num, err := s.sequenceLen(v, n)
+	if err != nil {
+		return err
+	}
+	list := isSSZList(n)
+	elem := n.elem()
+
+	if elem.typ.Kind() == reflect.Uint8 {
+		buf := v.Slice(0, num).Bytes()
+		switch {
+		case n.bitlist:
+			if num == 0 {
+				return fmt.Errorf("bitlist empty, it does not have length bit")
+			}
+			hh.PutBitlist(buf, size)
+		case list:
+			indx := hh.Index()
+			hh.Append(buf)
+			hh.MerkleizeWithMixin(indx, uint64(num), (size+31)/32)
+		default:
+			hh.PutBytes(buf)
+		}
+		return nil
+	}
+
+	indx := hh.Index()
+	if basicSize := sszBasicSize(elem.typ); basicSize != 0 {
+		// basic items are packed in chunks
+		for i := 0; i < num; i++ {
+			buf, err := s.marshalSSZ(nil, v.Index(i), elem)
+			if err != nil {
+				return err
+			}
+			hh.Append(buf)
+		}
+		hh.FillUpTo32()
+		if list {
+			hh.MerkleizeWithMixin(indx, uint64(num), (size*basicSize+31)/32)
+		} else {
+			hh.Merkleize(indx)
+		}
+		return nil
+	}
+
+	for i := 0; i < num; i++ {
+		if err := s.hashTreeRootWith(hh, v.Index(i), elem); err != nil {
+			return err
+		}
+	}
+	if list {
+		hh.MerkleizeWithMixin(indx, uint64(num), size)
+	} else {
+		hh.Merkleize(indx)
+	}
+	return nil
+}
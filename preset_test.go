@@ -0,0 +1,315 @@
+package consensus
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+// the preset values that size the ssz types
+var mainnetPreset = &Spec{
+	SlotsPerEpoch:                      32,
+	SlotsPerHistoricalRoot:             8192,
+	EpochsPerHistoricalVector:          65536,
+	EpochsPerSlashingsVector:           8192,
+	EpochsPerEth1VotingPeriod:          64,
+	MaxAttestations:                    128,
+	SyncCommitteeSize:                  512,
+	MaxWithdrawalsPerPayload:           16,
+	MaxBlobCommitmentsPerBlock:         4096,
+	KzgCommitmentInclusionProofDepth:   17,
+	MaxValidatorsPerCommittee:          2048,
+	MaxCommitteesPerSlot:               64,
+	MaxDepositRequestsPerPayload:       8192,
+	MaxWithdrawalRequestsPerPayload:    16,
+	MaxConsolidationRequestsPerPayload: 2,
+	PendingDepositsLimit:               134217728,
+	PendingPartialWithdrawalsLimit:     134217728,
+	PendingConsolidationsLimit:         262144,
+}
+
+var minimalPreset = &Spec{
+	SlotsPerEpoch:                      8,
+	SlotsPerHistoricalRoot:             64,
+	EpochsPerHistoricalVector:          64,
+	EpochsPerSlashingsVector:           64,
+	EpochsPerEth1VotingPeriod:          4,
+	MaxAttestations:                    128,
+	SyncCommitteeSize:                  32,
+	MaxWithdrawalsPerPayload:           4,
+	MaxBlobCommitmentsPerBlock:         32,
+	KzgCommitmentInclusionProofDepth:   10,
+	MaxValidatorsPerCommittee:          2048,
+	MaxCommitteesPerSlot:               4,
+	MaxDepositRequestsPerPayload:       4,
+	MaxWithdrawalRequestsPerPayload:    2,
+	MaxConsolidationRequestsPerPayload: 2,
+	PendingDepositsLimit:               134217728,
+	PendingPartialWithdrawalsLimit:     64,
+	PendingConsolidationsLimit:         64,
+}
+
+var presetTestObjects = []func() codec{
+	func() codec { return new(BeaconStatePhase0) },
+	func() codec { return new(BeaconStateAltair) },
+	func() codec { return new(BeaconStateBellatrix) },
+	func() codec { return new(BeaconStateCapella) },
+	func() codec { return new(BeaconStateDeneb) },
+	func() codec { return new(BeaconStateElectra) },
+	func() codec { return new(SignedBeaconBlockPhase0) },
+	func() codec { return new(SignedBeaconBlockAltair) },
+	func() codec { return new(SignedBeaconBlockBellatrix) },
+	func() codec { return new(SignedBeaconBlockCapella) },
+	func() codec { return new(SignedBeaconBlockDeneb) },
+	func() codec { return new(SignedBeaconBlockElectra) },
+	func() codec { return new(HistoricalBatch) },
+	func() codec { return new(BlobSidecar) },
+	func() codec { return new(LightClientUpdateDeneb) },
+	func() codec { return new(LightClientUpdateElectra) },
+	func() codec { return new(SignedContributionAndProof) },
+	func() codec { return new(SignedAggregateAndProofElectra) },
+}
+
+// fillSSZ populates the object with values that fit in the sizes of the spec
+func fillSSZ(t *testing.T, s *Spec, obj interface{}) {
+	count := uint64(0)
+
+	var fill func(v reflect.Value, n *sszNode)
+	fill = func(v reflect.Value, n *sszNode) {
+		if v.Kind() == reflect.Ptr {
+			v.Set(reflect.New(v.Type().Elem()))
+			v = v.Elem()
+		}
+		count++
+
+		switch n.typ.Kind() {
+		case reflect.Bool:
+			v.SetBool(count%2 == 0)
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			v.SetUint(count)
+
+		case reflect.Struct:
+			for _, field := range sszFields(n.typ) {
+				fill(v.FieldByIndex(field.Index), sszFieldNode(field))
+			}
+
+		case reflect.Slice, reflect.Array:
+			size, err := s.sszSize(n)
+			require.NoError(t, err)
+
+			if n.bitlist {
+				v.SetBytes([]byte{byte(count), 0x1})
+				return
+			}
+			num := int(size)
+			if isSSZList(n) && num > 2 {
+				num = 2
+			}
+			if n.typ.Kind() == reflect.Slice {
+				v.Set(reflect.MakeSlice(n.typ, num, num))
+			}
+			elem := n.elem()
+			for i := 0; i < num; i++ {
+				fill(v.Index(i), elem)
+			}
+
+		default:
+			t.Fatalf("type %s not expected", n.typ)
+		}
+	}
+	fill(reflect.ValueOf(obj).Elem(), &sszNode{typ: reflect.TypeOf(obj).Elem()})
+}
+
+func TestSpecSSZ_Generated(t *testing.T) {
+	// the reflection codec with the mainnet preset matches the generated code
+	generatedSSZ = false
+	defer func() {
+		generatedSSZ = true
+	}()
+
+	for _, newObj := range presetTestObjects {
+		obj := newObj()
+		fillSSZ(t, mainnetPreset, obj)
+
+		t.Run(reflect.TypeOf(obj).Elem().Name(), func(t *testing.T) {
+			expected, err := obj.MarshalSSZ()
+			require.NoError(t, err)
+
+			buf, err := mainnetPreset.EncodeSSZ(obj)
+			require.NoError(t, err)
+			require.Equal(t, expected, buf)
+
+			expectedRoot, err := obj.HashTreeRoot()
+			require.NoError(t, err)
+
+			root, err := mainnetPreset.HashTreeRoot(obj)
+			require.NoError(t, err)
+			require.Equal(t, expectedRoot, root)
+
+			obj2 := newObj()
+			require.NoError(t, mainnetPreset.DecodeSSZ(buf, obj2))
+			require.True(t, reflect.DeepEqual(obj, obj2))
+		})
+	}
+}
+
+func TestSpecSSZ_Minimal(t *testing.T) {
+	for _, newObj := range presetTestObjects {
+		obj := newObj()
+		fillSSZ(t, minimalPreset, obj)
+
+		t.Run(reflect.TypeOf(obj).Elem().Name(), func(t *testing.T) {
+			buf, err := minimalPreset.EncodeSSZ(obj)
+			require.NoError(t, err)
+
+			obj2 := newObj()
+			require.NoError(t, minimalPreset.DecodeSSZ(buf, obj2))
+			require.True(t, reflect.DeepEqual(obj, obj2))
+
+			root, err := minimalPreset.HashTreeRoot(obj)
+			require.NoError(t, err)
+
+			root2, err := minimalPreset.HashTreeRoot(obj2)
+			require.NoError(t, err)
+			require.Equal(t, root, root2)
+		})
+	}
+
+	t.Run("SyncAggregate", func(t *testing.T) {
+		obj := &SyncAggregate{SyncCommiteeBits: [64]byte{0x1, 0x2, 0x3, 0x4}}
+
+		// bitvector of 32 bits and the signature
+		buf, err := minimalPreset.EncodeSSZ(obj)
+		require.NoError(t, err)
+		require.Len(t, buf, 4+96)
+		require.Equal(t, []byte{0x1, 0x2, 0x3, 0x4}, buf[:4])
+
+		// the bits out of the minimal committee are not part of the object
+		obj.SyncCommiteeBits[4] = 0x1
+		_, err = minimalPreset.EncodeSSZ(obj)
+		require.NoError(t, err)
+	})
+
+	t.Run("VectorSize", func(t *testing.T) {
+		obj := &BeaconStatePhase0{Slashings: make([]uint64, 8192)}
+
+		_, err := minimalPreset.EncodeSSZ(obj)
+		require.Error(t, err)
+	})
+}
+
+func TestSpec_PresetValue(t *testing.T) {
+	cases := []struct {
+		expr string
+		val  uint64
+		err  bool
+	}{
+		{"SLOTS_PER_EPOCH", 8, false},
+		{"EPOCHS_PER_ETH1_VOTING_PERIOD*SLOTS_PER_EPOCH", 32, false},
+		{"MAX_COMMITTEES_PER_SLOT/8", 1, false},
+		{"SYNC_COMMITTEE_SIZE/32", 1, false},
+		{"MAX_VALIDATORS_PER_COMMITTEE * MAX_COMMITTEES_PER_SLOT", 8192, false},
+		{"SLOTS_PER_EPOCH/0", 0, true},
+		{"UNKNOWN", 0, true},
+		{"SECONDS_PER_SLOT", 0, true},
+	}
+
+	for _, c := range cases {
+		val, err := minimalPreset.presetValue(c.expr)
+		if c.err {
+			require.Error(t, err, c.expr)
+		} else {
+			require.NoError(t, err, c.expr)
+			require.Equal(t, c.val, val, c.expr)
+		}
+	}
+}
+
+func testMinimalFork(t *testing.T, fork fork) {
+	files := readDir(t, filepath.Join(testsPath, "/minimal/"+string(fork)+"/ssz_static"))
+	for _, f := range files {
+		spl := strings.Split(f, "/")
+		name := spl[len(spl)-1]
+
+		base, ok := codecs[name]
+		if !ok {
+			t.Logf("type %s not found in fork %s", name, fork)
+			continue
+		}
+		if base(fork) == nil {
+			t.Logf("type %s not supported in fork %s", name, fork)
+			continue
+		}
+
+		t.Run(name, func(t *testing.T) {
+			files := readDir(t, filepath.Join(f, "ssz_random"))
+			for _, f := range files {
+				checkMinimalSSZEncoding(t, fork, f, base)
+			}
+		})
+	}
+}
+
+func checkMinimalSSZEncoding(t *testing.T, f fork, fileName string, base testCallback) {
+	serializedSnappy, err := ioutil.ReadFile(filepath.Join(fileName, serializedFile))
+	require.NoError(t, err)
+
+	serialized, err := snappy.Decode(nil, serializedSnappy)
+	require.NoError(t, err)
+
+	raw, err := ioutil.ReadFile(filepath.Join(fileName, rootsFile))
+	require.NoError(t, err)
+
+	var out map[string]string
+	require.NoError(t, yaml.Unmarshal(raw, &out))
+
+	expectedRoot, err := hex.DecodeString(strings.TrimPrefix(out["root"], "0x"))
+	require.NoError(t, err)
+
+	obj := base(f)
+	require.NoError(t, minimalPreset.DecodeSSZ(serialized, obj), fileName)
+
+	buf, err := minimalPreset.EncodeSSZ(obj)
+	require.NoError(t, err)
+	if !bytes.Equal(buf, serialized) {
+		t.Fatalf("bad marshalling %s", fileName)
+	}
+
+	root, err := minimalPreset.HashTreeRoot(obj)
+	require.NoError(t, err)
+	if !bytes.Equal(root[:], expectedRoot) {
+		t.Fatalf("bad root %s", fileName)
+	}
+}
+
+func TestSpecMinimal_Phase0(t *testing.T) {
+	testMinimalFork(t, phase0Fork)
+}
+
+func TestSpecMinimal_Altair(t *testing.T) {
+	testMinimalFork(t, altairFork)
+}
+
+func TestSpecMinimal_Bellatrix(t *testing.T) {
+	testMinimalFork(t, bellatrixFork)
+}
+
+func TestSpecMinimal_Capella(t *testing.T) {
+	testMinimalFork(t, capellaFork)
+}
+
+func TestSpecMinimal_Deneb(t *testing.T) {
+	testMinimalFork(t, denebFork)
+}
+
+func TestSpecMinimal_Electra(t *testing.T) {
+	testMinimalFork(t, electraFork)
+}
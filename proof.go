@@ -0,0 +1,310 @@
+package consensus
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/bits"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	ssz "github.com/ferranbt/fastssz"
+)
+
+// Proof is a merkle proof of a node in the SSZ tree of an object
+type Proof struct {
+	// GIndex is the generalized index of the node
+	GIndex uint64
+
+	// Leaf is the root of the node
+	Leaf [32]byte
+
+	// Branch are the sibling nodes from the leaf to the root
+	Branch [][32]byte
+}
+
+// Depth returns the depth of the node in the tree
+func (p *Proof) Depth() int {
+	return bits.Len64(p.GIndex) - 1
+}
+
+// Verify checks the proof against the hash tree root of the object
+func (p *Proof) Verify(root [32]byte) bool {
+	return VerifyProof(root, p.Leaf, p.Branch, p.GIndex)
+}
+
+// Prove builds the merkle proof of the node at the generalized index gindex
+// of the SSZ tree of obj. Use GIndex to compute the index of a field.
+func Prove(obj ssz.HashRootProof, gindex uint64) (*Proof, error) {
+	if gindex == 0 {
+		return nil, fmt.Errorf("generalized index zero is not valid")
+	}
+	if gindex > uint64(^uint(0)>>1) {
+		return nil, fmt.Errorf("generalized index %d is too large", gindex)
+	}
+
+	tree, err := ssz.ProofTree(obj)
+	if err != nil {
+		return nil, err
+	}
+	// Node.Prove does not check that the path exists
+	if _, err := tree.Get(int(gindex)); err != nil {
+		return nil, fmt.Errorf("node %d not found", gindex)
+	}
+	res, err := tree.Prove(int(gindex))
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &Proof{
+		GIndex: gindex,
+		Branch: make([][32]byte, len(res.Hashes)),
+	}
+	copy(proof.Leaf[:], res.Leaf)
+	for i, hash := range res.Hashes {
+		copy(proof.Branch[i][:], hash)
+	}
+	return proof, nil
+}
+
+// VerifyProof checks that the merkle branch of leaf at the generalized
+// index gindex resolves to the given root.
+func VerifyProof(root [32]byte, leaf [32]byte, branch [][32]byte, gindex uint64) bool {
+	if gindex == 0 {
+		return false
+	}
+	if len(branch) != bits.Len64(gindex)-1 {
+		return false
+	}
+
+	node := leaf
+	buf := make([]byte, 64)
+	for i, sibling := range branch {
+		if (gindex>>i)&1 == 1 {
+			copy(buf[:32], sibling[:])
+			copy(buf[32:], node[:])
+		} else {
+			copy(buf[:32], node[:])
+			copy(buf[32:], sibling[:])
+		}
+		node = sha256.Sum256(buf)
+	}
+	return node == root
+}
+
+// GIndex returns the generalized index of the node reached by following path from
+// the root of obj. Each element in the path is either the json name of a container
+// field (i.e. finalized_checkpoint), the index of an item in a list or vector or
+// '__len__' for the length of a list. For lists and vectors of basic types
+// (i.e. balances) the index points to the chunk that packs the item.
+func GIndex(obj interface{}, path ...string) (uint64, error) {
+	typ := reflect.TypeOf(obj)
+	if typ == nil {
+		return 0, fmt.Errorf("object is nil")
+	}
+	node := &sszNode{typ: indirect(typ)}
+
+	gindex := uint64(1)
+	for i, elem := range path {
+		next, pos, width, err := node.child(elem)
+		if err != nil {
+			return 0, fmt.Errorf("path '%s': %v", strings.Join(path[:i+1], "."), err)
+		}
+		if bits.Len64(gindex)+width > 64 {
+			return 0, fmt.Errorf("path '%s': generalized index overflows", strings.Join(path[:i+1], "."))
+		}
+		gindex = gindex<<width | pos
+		node = next
+	}
+	return gindex, nil
+}
+
+// sszNode is a type in the SSZ tree of an object along with the
+// ssz-size, ssz-max and ssz-preset tags that apply to it
+type sszNode struct {
+	typ     reflect.Type
+	sizes   []string
+	maxes   []string
+	bitlist bool
+
+	// preset is the expression of spec values that sizes the
+	// outer list or vector of the node (see Spec.EncodeSSZ)
+	preset string
+}
+
+// child returns the node of the path element, its position and the depth of the subtree
+func (s *sszNode) child(elem string) (*sszNode, uint64, int, error) {
+	switch s.typ.Kind() {
+	case reflect.Struct:
+		return s.field(elem)
+	case reflect.Slice, reflect.Array:
+		return s.item(elem)
+	default:
+		return nil, 0, 0, fmt.Errorf("cannot descend into type %s", s.typ)
+	}
+}
+
+func (s *sszNode) field(name string) (*sszNode, uint64, int, error) {
+	fields := sszFields(s.typ)
+	for indx, field := range fields {
+		if sszFieldName(field) != name {
+			continue
+		}
+		return sszFieldNode(field), uint64(indx), ceilLog2(uint64(len(fields))), nil
+	}
+	return nil, 0, 0, fmt.Errorf("field '%s' not found in %s", name, s.typ.Name())
+}
+
+func (s *sszNode) item(elem string) (*sszNode, uint64, int, error) {
+	var size, max string
+	if len(s.sizes) != 0 {
+		size = s.sizes[0]
+	}
+	if len(s.maxes) != 0 {
+		max = s.maxes[0]
+	}
+
+	isList := max != "" && max != "?"
+	if elem == "__len__" {
+		if !isList {
+			return nil, 0, 0, fmt.Errorf("length of a vector")
+		}
+		return &sszNode{typ: reflect.TypeOf(uint64(0))}, 1, 1, nil
+	}
+
+	indx, err := strconv.ParseUint(elem, 10, 64)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("'%s' is not an index", elem)
+	}
+
+	var limit uint64
+	if isList {
+		limit, err = strconv.ParseUint(max, 10, 64)
+	} else if size != "" && size != "?" {
+		limit, err = strconv.ParseUint(size, 10, 64)
+	} else if s.typ.Kind() == reflect.Array {
+		limit = uint64(s.typ.Len())
+	} else {
+		err = fmt.Errorf("size of %s not found", s.typ)
+	}
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if indx >= limit {
+		return nil, 0, 0, fmt.Errorf("index %d out of range %d", indx, limit)
+	}
+
+	next := s.elem()
+
+	// number of items packed in a chunk
+	perChunk := uint64(1)
+	if s.bitlist {
+		perChunk = 256
+	} else if basicSize := sszBasicSize(next.typ); basicSize != 0 {
+		perChunk = 32 / basicSize
+	}
+	chunks := (limit + perChunk - 1) / perChunk
+
+	pos, width := indx/perChunk, ceilLog2(chunks)
+	if isList {
+		// the list is mixed in with its length
+		width++
+	}
+	return next, pos, width, nil
+}
+
+// sszFieldNode returns the node of a container field
+func sszFieldNode(field reflect.StructField) *sszNode {
+	return &sszNode{
+		typ:     indirect(field.Type),
+		sizes:   splitTag(field.Tag.Get("ssz-size")),
+		maxes:   splitTag(field.Tag.Get("ssz-max")),
+		bitlist: field.Tag.Get("ssz") == "bitlist",
+		preset:  field.Tag.Get("ssz-preset"),
+	}
+}
+
+// elem returns the node of the items of a list or vector
+func (s *sszNode) elem() *sszNode {
+	return &sszNode{
+		typ:   indirect(s.typ.Elem()),
+		sizes: tail(s.sizes),
+		maxes: tail(s.maxes),
+	}
+}
+
+// sszFields returns the fields of a struct that are part of the SSZ container
+func sszFields(typ reflect.Type) []reflect.StructField {
+	fields := []reflect.StructField{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" || field.Tag.Get("ssz") == "-" {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// sszFieldName returns the json name of the field or the snake case
+// version of the field name if it does not have one
+func sszFieldName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" {
+		return name
+	}
+
+	var str strings.Builder
+	for i, r := range field.Name {
+		if unicode.IsUpper(r) {
+			if i != 0 {
+				str.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		str.WriteRune(r)
+	}
+	return str.String()
+}
+
+func sszBasicSize(typ reflect.Type) uint64 {
+	switch typ.Kind() {
+	case reflect.Bool, reflect.Uint8:
+		return 1
+	case reflect.Uint16:
+		return 2
+	case reflect.Uint32:
+		return 4
+	case reflect.Uint64:
+		return 8
+	}
+	return 0
+}
+
+func indirect(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+func splitTag(tag string) []string {
+	if tag == "" {
+		return nil
+	}
+	return strings.Split(tag, ",")
+}
+
+func tail(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return s[1:]
+}
+
+func ceilLog2(n uint64) int {
+	if n <= 1 {
+		return 0
+	}
+	return bits.Len64(n - 1)
+}
@@ -0,0 +1,114 @@
+package consensus
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGIndex(t *testing.T) {
+	cases := []struct {
+		obj    interface{}
+		path   []string
+		gindex uint64
+	}{
+		// light client gindices from the altair, capella and electra specs
+		{BeaconStateAltair{}, []string{"finalized_checkpoint", "root"}, 105},
+		{BeaconStateAltair{}, []string{"current_sync_committee"}, 54},
+		{BeaconStateAltair{}, []string{"next_sync_committee"}, 55},
+		{&BeaconStateDeneb{}, []string{"finalized_checkpoint", "root"}, 105},
+		{BeaconBlockBodyCapella{}, []string{"execution_payload"}, 25},
+		{BeaconStateElectra{}, []string{"finalized_checkpoint", "root"}, 169},
+		{BeaconStateElectra{}, []string{"current_sync_committee"}, 86},
+		{BeaconStateElectra{}, []string{"next_sync_committee"}, 87},
+		// lists, vectors and basic types
+		{BeaconStatePhase0{}, []string{"validators", "__len__"}, 43*2 + 1},
+		{BeaconStatePhase0{}, []string{"validators", "1"}, (43*2)<<40 | 1},
+		{BeaconStatePhase0{}, []string{"validators", "1", "effective_balance"}, ((43*2)<<40|1)<<3 | 2},
+		{BeaconStatePhase0{}, []string{"balances", "9"}, (44*2)<<38 | 2},
+		{BeaconStatePhase0{}, []string{"block_roots", "3"}, 37<<13 | 3},
+		{Deposit{}, []string{"proof", "32"}, 2<<6 | 32},
+		{Deposit{}, []string{"data", "amount"}, 3<<2 | 2},
+		{ExecutionPayload{}, []string{"extra_data"}, 26},
+		{ExecutionPayload{}, []string{"transactions", "2", "__len__"}, ((29*2)<<20|2)*2 + 1},
+		{AttestationElectra{}, []string{"aggregation_bits", "300"}, (4*2)<<9 | 1},
+	}
+
+	for _, c := range cases {
+		gindex, err := GIndex(c.obj, c.path...)
+		require.NoError(t, err, c.path)
+		require.Equal(t, c.gindex, gindex, c.path)
+	}
+}
+
+func TestGIndex_Errors(t *testing.T) {
+	cases := [][]string{
+		{"unknown"},
+		{"slot", "0"},
+		{"block_roots", "8192"},
+		{"block_roots", "__len__"},
+		{"validators", "a"},
+	}
+	for _, path := range cases {
+		_, err := GIndex(BeaconStateDeneb{}, path...)
+		require.Error(t, err, path)
+	}
+}
+
+func TestProof(t *testing.T) {
+	state := &BeaconStateDeneb{
+		Slot:                         10,
+		Slashings:                    make([]uint64, 8192),
+		Validators:                   []*Validator{{EffectiveBalance: 1}, {EffectiveBalance: 2}},
+		Balances:                     []uint64{1, 2, 3, 4, 5},
+		LatestExecutionPayloadHeader: &ExecutionPayloadHeaderDeneb{},
+		FinalizedCheckpoint: &Checkpoint{
+			Epoch: 1,
+			Root:  [32]byte{0x1},
+		},
+	}
+	root, err := state.HashTreeRoot()
+	require.NoError(t, err)
+
+	prove := func(path ...string) *Proof {
+		gindex, err := GIndex(state, path...)
+		require.NoError(t, err)
+
+		proof, err := Prove(state, gindex)
+		require.NoError(t, err)
+		require.Equal(t, gindex, proof.GIndex)
+		require.True(t, proof.Verify(root), path)
+		return proof
+	}
+
+	proof := prove("finalized_checkpoint", "root")
+	require.Equal(t, [32]byte{0x1}, proof.Leaf)
+	require.Equal(t, 6, proof.Depth())
+
+	proof = prove("slot")
+	require.Equal(t, uint64(10), binary.LittleEndian.Uint64(proof.Leaf[:]))
+
+	validatorRoot, err := state.Validators[1].HashTreeRoot()
+	require.NoError(t, err)
+	proof = prove("validators", "1")
+	require.Equal(t, validatorRoot, proof.Leaf)
+
+	proof = prove("validators", "__len__")
+	require.Equal(t, uint64(2), binary.LittleEndian.Uint64(proof.Leaf[:]))
+
+	// balances 4 and 5 are packed in the second chunk
+	proof = prove("balances", "4")
+	require.Equal(t, uint64(5), binary.LittleEndian.Uint64(proof.Leaf[:]))
+
+	// the proof does not verify with a different leaf or index
+	require.False(t, VerifyProof(root, [32]byte{0x2}, proof.Branch, proof.GIndex))
+	require.False(t, VerifyProof(root, proof.Leaf, proof.Branch, proof.GIndex+1))
+	require.False(t, VerifyProof(root, proof.Leaf, proof.Branch[1:], proof.GIndex))
+
+	// validator 5 is not part of the tree
+	gindex, err := GIndex(state, "validators", "5")
+	require.NoError(t, err)
+	_, err = Prove(state, gindex)
+	require.Error(t, err)
+}
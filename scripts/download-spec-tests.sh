@@ -16,6 +16,7 @@ function download {
 }
 
 download "mainnet"
+download "minimal"
 download "general"
 
 # Download bls tests
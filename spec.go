@@ -1,6 +1,12 @@
 package consensus
 
 type Spec struct {
+	// PresetBase is the name of the preset the config is built on (i.e. mainnet or minimal).
+	PresetBase string `json:"PRESET_BASE"`
+
+	// ConfigName is the name of the network.
+	ConfigName string `json:"CONFIG_NAME"`
+
 	// GenesisSlot represents the first canonical slot number of the beacon chain.
 	GenesisSlot uint64 `json:"GENESIS_SLOT"`
 
@@ -53,6 +59,101 @@ type Spec struct {
 	ChurnLimitQuotient    uint64 `json:"CHURN_LIMIT_QUOTIENT"`
 	MinPerEpochChurnLimit uint64 `json:"MIN_PER_EPOCH_CHURN_LIMIT"`
 
+	MaxValidatorsPerCommittee uint64 `json:"MAX_VALIDATORS_PER_COMMITTEE"`
+	MinDepositAmount          uint64 `json:"MIN_DEPOSIT_AMOUNT"`
+	HistoricalRootsLimit      uint64 `json:"HISTORICAL_ROOTS_LIMIT"`
+	ValidatorRegistryLimit    uint64 `json:"VALIDATOR_REGISTRY_LIMIT"`
+
+	MaxProposerSlashings uint64 `json:"MAX_PROPOSER_SLASHINGS"`
+	MaxAttesterSlashings uint64 `json:"MAX_ATTESTER_SLASHINGS"`
+	MaxAttestations      uint64 `json:"MAX_ATTESTATIONS"`
+	MaxDeposits          uint64 `json:"MAX_DEPOSITS"`
+	MaxVoluntaryExits    uint64 `json:"MAX_VOLUNTARY_EXITS"`
+
+	// ShuffleRoundCount is the number of rounds of the swap-or-not shuffle.
+	ShuffleRoundCount uint64 `json:"SHUFFLE_ROUND_COUNT"`
+
+	InactivityPenaltyQuotientAltair      uint64 `json:"INACTIVITY_PENALTY_QUOTIENT_ALTAIR"`
+	MinSlashingPenaltyQuotientAltair     uint64 `json:"MIN_SLASHING_PENALTY_QUOTIENT_ALTAIR"`
+	ProportionalSlashingMultiplierAltair uint64 `json:"PROPORTIONAL_SLASHING_MULTIPLIER_ALTAIR"`
+
+	// EpochsPerSyncCommitteePeriod is the number of epochs a sync committee is active.
+	EpochsPerSyncCommitteePeriod uint64 `json:"EPOCHS_PER_SYNC_COMMITTEE_PERIOD"`
+	MinSyncCommitteeParticipants uint64 `json:"MIN_SYNC_COMMITTEE_PARTICIPANTS"`
+
+	InactivityScoreBias         uint64 `json:"INACTIVITY_SCORE_BIAS"`
+	InactivityScoreRecoveryRate uint64 `json:"INACTIVITY_SCORE_RECOVERY_RATE"`
+
+	InactivityPenaltyQuotientBellatrix      uint64 `json:"INACTIVITY_PENALTY_QUOTIENT_BELLATRIX"`
+	MinSlashingPenaltyQuotientBellatrix     uint64 `json:"MIN_SLASHING_PENALTY_QUOTIENT_BELLATRIX"`
+	ProportionalSlashingMultiplierBellatrix uint64 `json:"PROPORTIONAL_SLASHING_MULTIPLIER_BELLATRIX"`
+
+	MaxBytesPerTransaction    uint64 `json:"MAX_BYTES_PER_TRANSACTION"`
+	MaxTransactionsPerPayload uint64 `json:"MAX_TRANSACTIONS_PER_PAYLOAD"`
+	BytesPerLogsBloom         uint64 `json:"BYTES_PER_LOGS_BLOOM"`
+	MaxExtraDataBytes         uint64 `json:"MAX_EXTRA_DATA_BYTES"`
+
+	// TerminalTotalDifficulty is the total difficulty of the execution chain at the merge.
+	TerminalTotalDifficulty Uint256 `json:"TERMINAL_TOTAL_DIFFICULTY"`
+
+	MaxBlsToExecutionChanges         uint64 `json:"MAX_BLS_TO_EXECUTION_CHANGES"`
+	MaxWithdrawalsPerPayload         uint64 `json:"MAX_WITHDRAWALS_PER_PAYLOAD"`
+	MaxValidatorsPerWithdrawalsSweep uint64 `json:"MAX_VALIDATORS_PER_WITHDRAWALS_SWEEP"`
+
+	FieldElementsPerBlob             uint64 `json:"FIELD_ELEMENTS_PER_BLOB"`
+	MaxBlobCommitmentsPerBlock       uint64 `json:"MAX_BLOB_COMMITMENTS_PER_BLOCK"`
+	KzgCommitmentInclusionProofDepth uint64 `json:"KZG_COMMITMENT_INCLUSION_PROOF_DEPTH"`
+
+	// MaxBlobsPerBlock is the maximum number of blobs in a block before Electra.
+	MaxBlobsPerBlock                 uint64 `json:"MAX_BLOBS_PER_BLOCK"`
+	MaxRequestBlobSidecars           uint64 `json:"MAX_REQUEST_BLOB_SIDECARS"`
+	MinEpochsForBlobSidecarsRequests uint64 `json:"MIN_EPOCHS_FOR_BLOB_SIDECARS_REQUESTS"`
+	MaxPerEpochActivationChurnLimit  uint64 `json:"MAX_PER_EPOCH_ACTIVATION_CHURN_LIMIT"`
+
+	MinActivationBalance                  uint64 `json:"MIN_ACTIVATION_BALANCE"`
+	MaxEffectiveBalanceElectra            uint64 `json:"MAX_EFFECTIVE_BALANCE_ELECTRA"`
+	PendingDepositsLimit                  uint64 `json:"PENDING_DEPOSITS_LIMIT"`
+	PendingPartialWithdrawalsLimit        uint64 `json:"PENDING_PARTIAL_WITHDRAWALS_LIMIT"`
+	PendingConsolidationsLimit            uint64 `json:"PENDING_CONSOLIDATIONS_LIMIT"`
+	MinSlashingPenaltyQuotientElectra     uint64 `json:"MIN_SLASHING_PENALTY_QUOTIENT_ELECTRA"`
+	WhistleblowerRewardQuotientElectra    uint64 `json:"WHISTLEBLOWER_REWARD_QUOTIENT_ELECTRA"`
+	MaxAttesterSlashingsElectra           uint64 `json:"MAX_ATTESTER_SLASHINGS_ELECTRA"`
+	MaxAttestationsElectra                uint64 `json:"MAX_ATTESTATIONS_ELECTRA"`
+	MaxConsolidationRequestsPerPayload    uint64 `json:"MAX_CONSOLIDATION_REQUESTS_PER_PAYLOAD"`
+	MaxDepositRequestsPerPayload          uint64 `json:"MAX_DEPOSIT_REQUESTS_PER_PAYLOAD"`
+	MaxWithdrawalRequestsPerPayload       uint64 `json:"MAX_WITHDRAWAL_REQUESTS_PER_PAYLOAD"`
+	MaxPendingPartialsPerWithdrawalsSweep uint64 `json:"MAX_PENDING_PARTIALS_PER_WITHDRAWALS_SWEEP"`
+	MaxPendingDepositsPerEpoch            uint64 `json:"MAX_PENDING_DEPOSITS_PER_EPOCH"`
+	MinPerEpochChurnLimitElectra          uint64 `json:"MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA"`
+	MaxPerEpochActivationExitChurnLimit   uint64 `json:"MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT"`
+
+	// MaxBlobsPerBlockElectra is the maximum number of blobs in a block from Electra.
+	MaxBlobsPerBlockElectra       uint64 `json:"MAX_BLOBS_PER_BLOCK_ELECTRA"`
+	MaxRequestBlobSidecarsElectra uint64 `json:"MAX_REQUEST_BLOB_SIDECARS_ELECTRA"`
+	BlobSidecarSubnetCount        uint64 `json:"BLOB_SIDECAR_SUBNET_COUNT"`
+	BlobSidecarSubnetCountElectra uint64 `json:"BLOB_SIDECAR_SUBNET_COUNT_ELECTRA"`
+
+	// MinGenesisActiveValidatorCount is the number of validators required to trigger the genesis.
+	MinGenesisActiveValidatorCount uint64 `json:"MIN_GENESIS_ACTIVE_VALIDATOR_COUNT"`
+
+	// MinGenesisTime is the earliest timestamp for the genesis.
+	MinGenesisTime uint64 `json:"MIN_GENESIS_TIME"`
+
+	// GenesisDelay is the number of seconds between the eth1 block that triggers the genesis and the genesis.
+	GenesisDelay uint64 `json:"GENESIS_DELAY"`
+
+	SecondsPerEth1Block uint64 `json:"SECONDS_PER_ETH1_BLOCK"`
+	Eth1FollowDistance  uint64 `json:"ETH1_FOLLOW_DISTANCE"`
+
+	// DepositChainID is the chain id of the execution chain with the deposit contract.
+	DepositChainID uint64 `json:"DEPOSIT_CHAIN_ID"`
+
+	// DepositNetworkID is the network id of the execution chain with the deposit contract.
+	DepositNetworkID uint64 `json:"DEPOSIT_NETWORK_ID"`
+
+	// DepositContractAddress is the address of the deposit contract.
+	DepositContractAddress [20]byte `json:"DEPOSIT_CONTRACT_ADDRESS"`
+
 	// TargetAggregatorsPerCommittee defines the number of aggregators inside one committee.
 	TargetAggregatorsPerCommittee uint64 `json:"TARGET_AGGREGATORS_PER_COMMITTEE"`
 
@@ -64,4 +165,13 @@ type Spec struct {
 
 	BellatrixForkVersion Domain `json:"BELLATRIX_FORK_VERSION"`
 	BellatrixForkEpoch   uint64 `json:"BELLATRIX_FORK_EPOCH"`
+
+	CapellaForkVersion Domain `json:"CAPELLA_FORK_VERSION"`
+	CapellaForkEpoch   uint64 `json:"CAPELLA_FORK_EPOCH"`
+
+	DenebForkVersion Domain `json:"DENEB_FORK_VERSION"`
+	DenebForkEpoch   uint64 `json:"DENEB_FORK_EPOCH"`
+
+	ElectraForkVersion Domain `json:"ELECTRA_FORK_VERSION"`
+	ElectraForkEpoch   uint64 `json:"ELECTRA_FORK_EPOCH"`
 }
@@ -0,0 +1,61 @@
+package spec
+
+import (
+	"fmt"
+
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+// GetEpochCommittees returns the beacon committees of the epoch by slot and committee
+// index. The epoch must be between the previous and the next epoch of the state.
+func (s *StateTransitioner) GetEpochCommittees(state consensus.BeaconState, epoch uint64) ([][][]uint64, error) {
+	obj, err := toBeaconState(state)
+	if err != nil {
+		return nil, err
+	}
+	if epoch < s.getPreviousEpoch(obj) || epoch > s.getCurrentEpoch(obj)+1 {
+		return nil, fmt.Errorf("epoch %d is not between the previous and the next epoch of the state", epoch)
+	}
+	return s.getEpochCommittees(obj, epoch), nil
+}
+
+// getEpochCommittees computes all the committees of the epoch with a single shuffle
+// of the active validators
+func (s *StateTransitioner) getEpochCommittees(state *beaconState, epoch uint64) [][][]uint64 {
+	committeesPerSlot := s.getCommitteeCountPerSlot(state, epoch)
+	count := committeesPerSlot * s.spec.SlotsPerEpoch
+
+	seed := s.getSeed(state, epoch, consensus.DomainBeaconAttesterType)
+	shuffled := s.shuffleIndices(getActiveValidatorIndices(state, epoch), seed)
+	numActiveValidators := uint64(len(shuffled))
+
+	committees := make([][][]uint64, s.spec.SlotsPerEpoch)
+	for slot := range committees {
+		committees[slot] = make([][]uint64, committeesPerSlot)
+
+		for index := range committees[slot] {
+			i := uint64(slot)*committeesPerSlot + uint64(index)
+
+			start := (numActiveValidators * i) / count
+			end := (numActiveValidators * (i + 1)) / count
+			committees[slot][index] = shuffled[start:end]
+		}
+	}
+	return committees
+}
+
+// GetBeaconProposerIndex returns the proposer of the slot. The slot must be in the
+// epoch of the state.
+func (s *StateTransitioner) GetBeaconProposerIndex(state consensus.BeaconState, slot uint64) (uint64, error) {
+	obj, err := toBeaconState(state)
+	if err != nil {
+		return 0, err
+	}
+	if epoch := s.getEpochAtSlot(slot); epoch != s.getCurrentEpoch(obj) {
+		return 0, fmt.Errorf("slot %d is not in the epoch %d of the state", slot, s.getCurrentEpoch(obj))
+	}
+	if len(getActiveValidatorIndices(obj, s.getCurrentEpoch(obj))) == 0 {
+		return 0, fmt.Errorf("no active validators")
+	}
+	return s.getBeaconProposerIndexAtSlot(obj, slot), nil
+}
@@ -0,0 +1,51 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+func TestEpochCommittees(t *testing.T) {
+	st, err := NewStateTransitioner(MinimalSpec)
+	require.NoError(t, err)
+
+	state := &consensus.BeaconStatePhase0{
+		Slot: 3 * MinimalSpec.SlotsPerEpoch,
+	}
+	for i := 0; i < 100; i++ {
+		state.Validators = append(state.Validators, &consensus.Validator{
+			EffectiveBalance: MinimalSpec.MaxEffectiveBalance,
+			ExitEpoch:        farFutureEpoch,
+		})
+		state.RandaoMixes[i] = [32]byte{byte(i)}
+	}
+	obj, err := toBeaconState(state)
+	require.NoError(t, err)
+
+	for _, epoch := range []uint64{2, 3, 4} {
+		committees, err := st.GetEpochCommittees(state, epoch)
+		require.NoError(t, err)
+		require.Len(t, committees, int(MinimalSpec.SlotsPerEpoch))
+
+		// the committees match the ones computed one by one
+		for slot, slotCommittees := range committees {
+			for index, committee := range slotCommittees {
+				require.Equal(t, st.getBeaconCommittee(obj, epoch*MinimalSpec.SlotsPerEpoch+uint64(slot), uint64(index)), committee)
+			}
+		}
+	}
+
+	_, err = st.GetEpochCommittees(state, 1)
+	require.Error(t, err)
+	_, err = st.GetEpochCommittees(state, 5)
+	require.Error(t, err)
+
+	proposer, err := st.GetBeaconProposerIndex(state, state.Slot)
+	require.NoError(t, err)
+	require.Equal(t, st.getBeaconProposerIndex(obj), proposer)
+
+	_, err = st.GetBeaconProposerIndex(state, state.Slot+MinimalSpec.SlotsPerEpoch)
+	require.Error(t, err)
+}
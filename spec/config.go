@@ -0,0 +1,213 @@
+package spec
+
+import (
+	"embed"
+	"encoding"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	consensus "github.com/umbracle/go-eth-consensus"
+	"gopkg.in/yaml.v2"
+)
+
+//go:embed presets
+var presetsFS embed.FS
+
+//go:embed networks
+var networksFS embed.FS
+
+// Network is a network bundled with the package
+type Network struct {
+	// Name is the name of the network
+	Name string
+
+	// Spec is the spec of the network
+	Spec *consensus.Spec
+
+	// GenesisValidatorsRoot is the genesis validators root of the network
+	GenesisValidatorsRoot consensus.Root
+}
+
+// Networks returns the names of the networks bundled with the package
+func Networks() []string {
+	entries, err := networksFS.ReadDir("networks")
+	if err != nil {
+		panic(err)
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetNetwork returns the network with the given name (i.e. mainnet, sepolia, holesky or hoodi)
+func GetNetwork(name string) (*Network, error) {
+	config, err := networksFS.ReadFile(path.Join("networks", name, "config.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("network '%s' not found", name)
+	}
+	spec, err := decodeSpecWithPresets(config, presetsFS, "presets")
+	if err != nil {
+		return nil, err
+	}
+
+	rootStr, err := networksFS.ReadFile(path.Join("networks", name, "genesis_validators_root.txt"))
+	if err != nil {
+		return nil, err
+	}
+	root, err := decodeRoot(strings.TrimSpace(string(rootStr)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode genesis validators root: %v", err)
+	}
+
+	network := &Network{
+		Name:                  name,
+		Spec:                  spec,
+		GenesisValidatorsRoot: root,
+	}
+	return network, nil
+}
+
+// LoadSpec reads a config file and the preset files of its base preset (PRESET_BASE). The
+// presets are read from presetsDir/<PRESET_BASE>/*.yaml, if presetsDir is empty the presets
+// bundled with the package are used instead.
+func LoadSpec(configPath string, presetsDir string) (*consensus.Spec, error) {
+	config, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if presetsDir == "" {
+		return decodeSpecWithPresets(config, presetsFS, "presets")
+	}
+	return decodeSpecWithPresets(config, os.DirFS(presetsDir), ".")
+}
+
+func decodeSpecWithPresets(config []byte, fsys fs.FS, dir string) (*consensus.Spec, error) {
+	var base struct {
+		PresetBase string `yaml:"PRESET_BASE"`
+	}
+	if err := yaml.Unmarshal(config, &base); err != nil {
+		return nil, err
+	}
+	if base.PresetBase == "" {
+		return nil, fmt.Errorf("config does not have a PRESET_BASE")
+	}
+
+	matches, err := fs.Glob(fsys, path.Join(dir, base.PresetBase, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("preset '%s' not found", base.PresetBase)
+	}
+
+	presets := [][]byte{}
+	for _, match := range matches {
+		preset, err := fs.ReadFile(fsys, match)
+		if err != nil {
+			return nil, err
+		}
+		presets = append(presets, preset)
+	}
+	return DecodeSpec(config, presets...)
+}
+
+// DecodeSpec decodes a config file and its preset files into a spec. The values
+// from the config take precedence over the ones from the presets.
+func DecodeSpec(config []byte, presets ...[]byte) (*consensus.Spec, error) {
+	values := map[string]string{}
+	for _, data := range append(presets, config) {
+		var raw map[string]string
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		for k, v := range raw {
+			values[k] = v
+		}
+	}
+
+	// constants that are not part of neither the config nor the presets
+	spec := &consensus.Spec{
+		GenesisSlot:                   0,
+		GenesisEpoch:                  0,
+		BaseRewardsPerEpoch:           4,
+		TargetAggregatorsPerCommittee: 16,
+	}
+
+	dc := &mapstructure.DecoderConfig{
+		Result:           spec,
+		WeaklyTypedInput: true,
+		DecodeHook:       decodeSpecHook,
+		TagName:          "json",
+	}
+	ms, err := mapstructure.NewDecoder(dc)
+	if err != nil {
+		return nil, err
+	}
+	if err := ms.Decode(values); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+func decodeSpecHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if f.Kind() != reflect.String {
+		return data, nil
+	}
+
+	// i.e. Domain or Uint256
+	result := reflect.New(t).Interface()
+	if unmarshaller, ok := result.(encoding.TextUnmarshaler); ok {
+		if err := unmarshaller.UnmarshalText([]byte(data.(string))); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	// [n]byte (i.e. the deposit contract address)
+	if t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint8 {
+		raw := data.(string)
+		if !strings.HasPrefix(raw, "0x") {
+			return nil, fmt.Errorf("0x prefix not found for [n]byte")
+		}
+		elem, err := hex.DecodeString(raw[2:])
+		if err != nil {
+			return nil, err
+		}
+		if t.Len() != len(elem) {
+			return nil, fmt.Errorf("incorrect array length: %d %d", t.Len(), len(elem))
+		}
+
+		v := reflect.New(t)
+		reflect.Copy(v.Elem(), reflect.ValueOf(elem))
+		return v.Interface(), nil
+	}
+
+	return data, nil
+}
+
+func decodeRoot(str string) (consensus.Root, error) {
+	var root consensus.Root
+	if !strings.HasPrefix(str, "0x") {
+		return root, fmt.Errorf("0x prefix not found")
+	}
+	buf, err := hex.DecodeString(str[2:])
+	if err != nil {
+		return root, err
+	}
+	if len(buf) != 32 {
+		return root, fmt.Errorf("incorrect length: %d", len(buf))
+	}
+	copy(root[:], buf)
+	return root, nil
+}
@@ -0,0 +1,92 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+func TestGetNetwork(t *testing.T) {
+	require.Equal(t, []string{"holesky", "hoodi", "mainnet", "sepolia"}, Networks())
+
+	for _, name := range Networks() {
+		network, err := GetNetwork(name)
+		require.NoError(t, err)
+
+		require.Equal(t, name, network.Spec.ConfigName)
+		require.Equal(t, "mainnet", network.Spec.PresetBase)
+		require.NotEqual(t, consensus.Root{}, network.GenesisValidatorsRoot)
+		require.NotZero(t, network.Spec.DepositChainID)
+		require.NotEqual(t, [20]byte{}, network.Spec.DepositContractAddress)
+
+		_, err = NewStateTransitioner(network.Spec)
+		require.NoError(t, err)
+	}
+
+	_, err := GetNetwork("not-found")
+	require.Error(t, err)
+}
+
+func TestGetNetworkMainnet(t *testing.T) {
+	network, err := GetNetwork("mainnet")
+	require.NoError(t, err)
+
+	// every value set in the mainnet spec matches the loaded one
+	expected := reflect.ValueOf(Spec).Elem()
+	found := reflect.ValueOf(network.Spec).Elem()
+
+	for i := 0; i < expected.NumField(); i++ {
+		if expected.Field(i).IsZero() {
+			continue
+		}
+		name := expected.Type().Field(i).Name
+		require.Equal(t, expected.Field(i).Interface(), found.Field(i).Interface(), name)
+	}
+
+	require.Equal(t, consensus.Domain{0x05, 0, 0, 0}, network.Spec.ElectraForkVersion)
+	require.Equal(t, uint64(1), network.Spec.DepositChainID)
+	require.Equal(t, "58750000000000000000000", mustMarshalText(t, network.Spec.TerminalTotalDifficulty))
+}
+
+func TestLoadSpec(t *testing.T) {
+	config := []byte(`PRESET_BASE: 'minimal'
+CONFIG_NAME: 'devnet'
+SECONDS_PER_SLOT: 6
+GENESIS_FORK_VERSION: 0x00000064
+DEPOSIT_CONTRACT_ADDRESS: 0x4242424242424242424242424242424242424242
+`)
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, config, 0644))
+
+	for _, presetsDir := range []string{"", "presets"} {
+		spec, err := LoadSpec(configPath, presetsDir)
+		require.NoError(t, err)
+
+		require.Equal(t, "devnet", spec.ConfigName)
+		require.Equal(t, MinimalSpec.SlotsPerEpoch, spec.SlotsPerEpoch)
+		require.Equal(t, MinimalSpec.SyncCommitteeSize, spec.SyncCommitteeSize)
+		require.Equal(t, MinimalSpec.MaxBlobCommitmentsPerBlock, spec.MaxBlobCommitmentsPerBlock)
+		require.Equal(t, uint64(6), spec.SecondsPerSlot)
+		require.Equal(t, consensus.Domain{0, 0, 0, 0x64}, spec.GenesisForkVersion)
+		require.Equal(t, [20]byte{0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42, 0x42}, spec.DepositContractAddress)
+	}
+
+	// config without base preset
+	_, err := DecodeSpec([]byte("CONFIG_NAME: 'devnet'"))
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(configPath, []byte("CONFIG_NAME: 'devnet'"), 0644))
+	_, err = LoadSpec(configPath, "")
+	require.Error(t, err)
+}
+
+func mustMarshalText(t *testing.T, u consensus.Uint256) string {
+	data, err := u.MarshalText()
+	require.NoError(t, err)
+	return string(data)
+}
@@ -2,70 +2,121 @@ package spec
 
 import (
 	"bytes"
+	"fmt"
 	"sort"
 
+	ssz "github.com/ferranbt/fastssz"
 	consensus "github.com/umbracle/go-eth-consensus"
 )
 
-func processEffectiveBalanceUpdates(state *consensus.BeaconStatePhase0) error {
-	for indx, validator := range state.Validators {
-		balance := state.Balances[indx]
+type epochProcessignFunc func(state *beaconState) error
 
-		hysteresisIncrement := Spec.EffectiveBalanceIncrement / Spec.HysteresisQuotient
-		downwardThreshold := hysteresisIncrement * Spec.HysteresisDownwardMultiplier
-		upwardThreshold := hysteresisIncrement * Spec.HysteresisUpwardMultiplier
+func (s *StateTransitioner) processEffectiveBalanceUpdates(state *beaconState) error {
+	balances := *state.balances
+
+	for indx, validator := range *state.validators {
+		balance := balances[indx]
+
+		hysteresisIncrement := s.spec.EffectiveBalanceIncrement / s.spec.HysteresisQuotient
+		downwardThreshold := hysteresisIncrement * s.spec.HysteresisDownwardMultiplier
+		upwardThreshold := hysteresisIncrement * s.spec.HysteresisUpwardMultiplier
 
 		if balance+downwardThreshold < validator.EffectiveBalance || validator.EffectiveBalance+upwardThreshold < balance {
-			validator.EffectiveBalance = min(balance-balance%Spec.EffectiveBalanceIncrement, Spec.MaxEffectiveBalance)
+			validator.EffectiveBalance = min(balance-balance%s.spec.EffectiveBalanceIncrement, s.getMaxEffectiveBalance(state, validator))
 		}
 	}
 	return nil
 }
 
-func processEth1DataReset(state *consensus.BeaconStatePhase0) error {
-	nextEpoch := getCurrentEpoch(state) + 1
+func (s *StateTransitioner) processEth1DataReset(state *beaconState) error {
+	nextEpoch := s.getCurrentEpoch(state) + 1
 
-	if nextEpoch%Spec.EpochsPerEth1VotingPeriod == 0 {
-		state.Eth1DataVotes = []*consensus.Eth1Data{}
+	if nextEpoch%s.spec.EpochsPerEth1VotingPeriod == 0 {
+		*state.eth1DataVotes = []*consensus.Eth1Data{}
 	}
 	return nil
 }
 
-func processHistoricalRootsUpdate(state *consensus.BeaconStatePhase0) error {
-	nextEpoch := getCurrentEpoch(state) + 1
+func (s *StateTransitioner) processHistoricalRootsUpdate(state *beaconState) error {
+	nextEpoch := s.getCurrentEpoch(state) + 1
+
+	if nextEpoch%(s.spec.SlotsPerHistoricalRoot/s.spec.SlotsPerEpoch) == 0 {
+		historicalBatch := &consensus.HistoricalBatch{}
+		copy(historicalBatch.BlockRoots[:], state.blockRoots)
+		copy(historicalBatch.StateRoots[:], state.stateRoots)
+
+		root, err := s.spec.HashTreeRoot(historicalBatch)
+		if err != nil {
+			return err
+		}
 
-	if nextEpoch%(Spec.SlotsPerHistoricalRoot/Spec.SlotsPerEpoch) == 0 {
-		historicalBatch := consensus.HistoricalBatch{
-			BlockRoots: state.BlockRoots,
-			StateRoots: state.StateRoots,
+		switch obj := state.obj.(type) {
+		case *consensus.BeaconStatePhase0:
+			obj.HistoricalRoots = append(obj.HistoricalRoots, root)
+		case *consensus.BeaconStateAltair:
+			obj.HistoricalRoots = append(obj.HistoricalRoots, root)
+		case *consensus.BeaconStateBellatrix:
+			obj.HistoricalRoots = append(obj.HistoricalRoots, root[:])
+		default:
+			return fmt.Errorf("beacon state %T uses historical summaries", state.obj)
 		}
-		root, err := historicalBatch.HashTreeRoot()
+	}
+	return nil
+}
+
+func (s *StateTransitioner) processHistoricalSummariesUpdate(state *beaconState) error {
+	// Set historical block root accumulator
+	nextEpoch := s.getCurrentEpoch(state) + 1
+
+	if nextEpoch%(s.spec.SlotsPerHistoricalRoot/s.spec.SlotsPerEpoch) == 0 {
+		blockSummaryRoot, err := s.historicalVectorRoot(state.blockRoots)
 		if err != nil {
 			return err
 		}
-		state.HistoricalRoots = append(state.HistoricalRoots, root)
+		stateSummaryRoot, err := s.historicalVectorRoot(state.stateRoots)
+		if err != nil {
+			return err
+		}
+
+		*state.historicalSummaries = append(*state.historicalSummaries, &consensus.HistoricalSummary{
+			BlockSummaryRoot: blockSummaryRoot,
+			StateSummaryRoot: stateSummaryRoot,
+		})
 	}
 	return nil
 }
 
-func computeStartSlotAtEpoch(epoch uint64) uint64 {
-	return epoch * Spec.SlotsPerEpoch
+// historicalVectorRoot returns the hash tree root of the block or state roots vector
+func (s *StateTransitioner) historicalVectorRoot(roots [][32]byte) ([32]byte, error) {
+	hh := ssz.NewHasher()
+
+	indx := hh.Index()
+	for _, root := range roots[:s.spec.SlotsPerHistoricalRoot] {
+		hh.Append(root[:])
+	}
+	hh.Merkleize(indx)
+
+	return hh.HashRoot()
+}
+
+func (s *StateTransitioner) computeStartSlotAtEpoch(epoch uint64) uint64 {
+	return epoch * s.spec.SlotsPerEpoch
 }
 
-func getBlockRootAtSlot(state *consensus.BeaconStatePhase0, slot uint64) [32]byte {
+func (s *StateTransitioner) getBlockRootAtSlot(state *beaconState, slot uint64) [32]byte {
 	// Return the block root at a recent ``slot``.
-	return state.BlockRoots[slot%Spec.SlotsPerHistoricalRoot]
+	return state.blockRoots[slot%s.spec.SlotsPerHistoricalRoot]
 }
 
-func getBlockRoot(state *consensus.BeaconStatePhase0, epoch uint64) [32]byte {
-	return getBlockRootAtSlot(state, computeStartSlotAtEpoch(epoch))
+func (s *StateTransitioner) getBlockRoot(state *beaconState, epoch uint64) [32]byte {
+	return s.getBlockRootAtSlot(state, s.computeStartSlotAtEpoch(epoch))
 }
 
-func getMatchingTargetAttestations(state *consensus.BeaconStatePhase0, epoch uint64) []*consensus.PendingAttestation {
-	root := getBlockRoot(state, epoch)
+func (s *StateTransitioner) getMatchingTargetAttestations(state *beaconState, epoch uint64) []*consensus.PendingAttestation {
+	root := s.getBlockRoot(state, epoch)
 
 	res := []*consensus.PendingAttestation{}
-	for _, a := range getMatchingSourceAttestations(state, epoch) {
+	for _, a := range s.getMatchingSourceAttestations(state, epoch) {
 		if bytes.Equal(a.Data.Target.Root[:], root[:]) {
 			res = append(res, a)
 		}
@@ -74,114 +125,135 @@ func getMatchingTargetAttestations(state *consensus.BeaconStatePhase0, epoch uin
 	return res
 }
 
-func getAttestingBalance(state *consensus.BeaconStatePhase0, attestations []*consensus.PendingAttestation) uint64 {
+func (s *StateTransitioner) getAttestingBalance(state *beaconState, attestations []*consensus.PendingAttestation) uint64 {
 	// Return the combined effective balance of the set of unslashed validators participating in ``attestations``.
 	// Note: ``get_total_balance`` returns ``EFFECTIVE_BALANCE_INCREMENT`` Gwei minimum to avoid divisions by zero
-	indices, err := getUnslashedAttestingIndices(state, attestations)
+	indices, err := s.getUnslashedAttestingIndices(state, attestations)
 	if err != nil {
 		panic(err)
 	}
-	return getTotalBalance(state, indices)
+	return s.getTotalBalance(state, indices)
 }
 
-func processJustificationAndFinalization(state *consensus.BeaconStatePhase0) error {
+func (s *StateTransitioner) processJustificationAndFinalization(state *beaconState) error {
 	// Initial FFG checkpoint values have a `0x00` stub for `root`.
 	// Skip FFG updates in the first two epochs to avoid corner cases that might result in modifying this stub.
-	if getCurrentEpoch(state) <= Spec.GenesisEpoch+1 {
+	if s.getCurrentEpoch(state) <= s.spec.GenesisEpoch+1 {
 		return nil
 	}
 
-	previousAttestations := getMatchingSourceAttestations(state, getPreviousEpoch(state))
-	currentAttestations := getMatchingTargetAttestations(state, getCurrentEpoch(state))
-	totalActiveBalance := getTotalActiveBalance(state)
+	var previousTargetBalance, currentTargetBalance uint64
+
+	if !state.atLeast(consensus.VersionAltair) {
+		previousAttestations := s.getMatchingTargetAttestations(state, s.getPreviousEpoch(state))
+		currentAttestations := s.getMatchingTargetAttestations(state, s.getCurrentEpoch(state))
 
-	previousTargetBalalnce := getAttestingBalance(state, previousAttestations)
-	currentTargetBalance := getAttestingBalance(state, currentAttestations)
+		previousTargetBalance = s.getAttestingBalance(state, previousAttestations)
+		currentTargetBalance = s.getAttestingBalance(state, currentAttestations)
+	} else {
+		previousIndices := s.getUnslashedParticipatingIndices(state, timelyTargetFlagIndex, s.getPreviousEpoch(state))
+		currentIndices := s.getUnslashedParticipatingIndices(state, timelyTargetFlagIndex, s.getCurrentEpoch(state))
 
-	weighJustificationAndFinalization(state, totalActiveBalance, previousTargetBalalnce, currentTargetBalance)
+		previousTargetBalance = s.getTotalBalance(state, previousIndices)
+		currentTargetBalance = s.getTotalBalance(state, currentIndices)
+	}
+
+	totalActiveBalance := s.getTotalActiveBalance(state)
+	s.weighJustificationAndFinalization(state, totalActiveBalance, previousTargetBalance, currentTargetBalance)
 	return nil
 }
 
-func weighJustificationAndFinalization(state *consensus.BeaconStatePhase0, totalActiveBalance uint64, previousEpochTargetBalance uint64, currentEpochTargetBalance uint64) {
-	previousEpoch := getPreviousEpoch(state)
-	currentEpoch := getCurrentEpoch(state)
+func (s *StateTransitioner) weighJustificationAndFinalization(state *beaconState, totalActiveBalance uint64, previousEpochTargetBalance uint64, currentEpochTargetBalance uint64) {
+	previousEpoch := s.getPreviousEpoch(state)
+	currentEpoch := s.getCurrentEpoch(state)
+
+	oldPreviousJustifiedCheckpoint := *state.previousJustifiedCheckpoint
+	oldCurrentJustifiedCheckpoint := *state.currentJustifiedCheckpoint
 
-	oldPreviousJustifiedCheckpoint := state.PreviousJustifiedCheckpoint
-	oldCurrentJustifiedCheckpoint := state.CurrentJustifiedCheckpoint
+	justificationBits := state.justificationBits
 
 	// Process justifications
-	state.PreviousJustifiedCheckpoint = state.CurrentJustifiedCheckpoint
-	state.JustificationBits[0] = (state.JustificationBits[0] << 1) & 0x0f
+	*state.previousJustifiedCheckpoint = *state.currentJustifiedCheckpoint
+	justificationBits[0] = (justificationBits[0] << 1) & 0x0f
 
 	if previousEpochTargetBalance*3 >= totalActiveBalance*2 {
-		state.CurrentJustifiedCheckpoint = &consensus.Checkpoint{
+		*state.currentJustifiedCheckpoint = &consensus.Checkpoint{
 			Epoch: previousEpoch,
-			Root:  getBlockRoot(state, previousEpoch),
+			Root:  s.getBlockRoot(state, previousEpoch),
 		}
 
-		state.JustificationBits[0] |= 1 << 1
+		justificationBits[0] |= 1 << 1
 	}
 
 	if currentEpochTargetBalance*3 >= totalActiveBalance*2 {
-		state.CurrentJustifiedCheckpoint = &consensus.Checkpoint{
+		*state.currentJustifiedCheckpoint = &consensus.Checkpoint{
 			Epoch: currentEpoch,
-			Root:  getBlockRoot(state, currentEpoch),
+			Root:  s.getBlockRoot(state, currentEpoch),
 		}
 
-		state.JustificationBits[0] |= 1 << 0
+		justificationBits[0] |= 1 << 0
 	}
 
 	// Process finalizations
-	bits := state.JustificationBits[0]
+	bits := justificationBits[0]
 
 	// the 2nd/3rd/4th most recent epochs are justified, the 2nd using the 4th as source
 	if bits&0x0E == 0x0E && oldPreviousJustifiedCheckpoint.Epoch+3 == currentEpoch {
-		state.FinalizedCheckpoint = oldPreviousJustifiedCheckpoint
+		*state.finalizedCheckpoint = oldPreviousJustifiedCheckpoint
 	}
 	// the 2nd/3rd most recent epochs are justified, the 2nd using the 3rd as source
 	if bits&0x06 == 0x06 && oldPreviousJustifiedCheckpoint.Epoch+2 == currentEpoch {
-		state.FinalizedCheckpoint = oldPreviousJustifiedCheckpoint
+		*state.finalizedCheckpoint = oldPreviousJustifiedCheckpoint
 	}
 	// the 1st/2nd/3rd most recent epochs are justified, the 1st using the 3rd as source
 	if bits&0x07 == 0x07 && oldCurrentJustifiedCheckpoint.Epoch+2 == currentEpoch {
-		state.FinalizedCheckpoint = oldCurrentJustifiedCheckpoint
+		*state.finalizedCheckpoint = oldCurrentJustifiedCheckpoint
 	}
 	// the 1st/2nd most recent epochs are justified, the 1st using the 2nd as source
 	if bits&0x03 == 0x03 && oldCurrentJustifiedCheckpoint.Epoch+1 == currentEpoch {
-		state.FinalizedCheckpoint = oldCurrentJustifiedCheckpoint
+		*state.finalizedCheckpoint = oldCurrentJustifiedCheckpoint
 	}
 }
 
-func processParticipationRecordUpdates(state *consensus.BeaconStatePhase0) error {
-	state.PreviousEpochAttestations = state.CurrentEpochAttestations
-	state.CurrentEpochAttestations = []*consensus.PendingAttestation{}
+func processParticipationRecordUpdates(state *beaconState) error {
+	*state.previousEpochAttestations = *state.currentEpochAttestations
+	*state.currentEpochAttestations = []*consensus.PendingAttestation{}
 	return nil
 }
 
-func processRandaoMixesReset(state *consensus.BeaconStatePhase0) error {
-	currentEpoch := getCurrentEpoch(state)
+func (s *StateTransitioner) processRandaoMixesReset(state *beaconState) error {
+	currentEpoch := s.getCurrentEpoch(state)
 	nextEpoch := currentEpoch + 1
-	state.RandaoMixes[nextEpoch%Spec.EpochsPerHistoricalVector] = getRandaoMix(state, currentEpoch)
+	state.randaoMixes[nextEpoch%s.spec.EpochsPerHistoricalVector] = s.getRandaoMix(state, currentEpoch)
 	return nil
 }
 
-func isElegibleForActivationQueue(validator *consensus.Validator) bool {
-	return validator.ActivationEligibilityEpoch == farFutureEpoch && validator.EffectiveBalance == Spec.MaxEffectiveBalance
+func (s *StateTransitioner) isElegibleForActivationQueue(state *beaconState, validator *consensus.Validator) bool {
+	if state.atLeast(consensus.VersionElectra) {
+		return validator.ActivationEligibilityEpoch == farFutureEpoch && validator.EffectiveBalance >= s.spec.MinActivationBalance
+	}
+	return validator.ActivationEligibilityEpoch == farFutureEpoch && validator.EffectiveBalance == s.spec.MaxEffectiveBalance
 }
 
-func isElegibleForActivation(state *consensus.BeaconStatePhase0, validator *consensus.Validator) bool {
-	return validator.ActivationEligibilityEpoch <= state.FinalizedCheckpoint.Epoch && validator.ActivationEpoch == farFutureEpoch
+func isElegibleForActivation(state *beaconState, validator *consensus.Validator) bool {
+	return validator.ActivationEligibilityEpoch <= (*state.finalizedCheckpoint).Epoch && validator.ActivationEpoch == farFutureEpoch
 }
 
-func processRegistryUpdates(state *consensus.BeaconStatePhase0) error {
+func (s *StateTransitioner) processRegistryUpdates(state *beaconState) error {
+	if state.atLeast(consensus.VersionElectra) {
+		return s.processRegistryUpdatesElectra(state)
+	}
+
+	validators := *state.validators
+
 	// Process activation eligibility and ejections
-	for indx, validator := range state.Validators {
-		if isElegibleForActivationQueue(validator) {
-			validator.ActivationEligibilityEpoch = getCurrentEpoch(state) + 1
+	for indx, validator := range validators {
+		if s.isElegibleForActivationQueue(state, validator) {
+			validator.ActivationEligibilityEpoch = s.getCurrentEpoch(state) + 1
 		}
 
-		if isActiveValidator(validator, getCurrentEpoch(state)) && validator.EffectiveBalance <= Spec.EjectionBalance {
-			if err := initiateValidatorExit(state, uint64(indx)); err != nil {
+		if isActiveValidator(validator, s.getCurrentEpoch(state)) && validator.EffectiveBalance <= s.spec.EjectionBalance {
+			if err := s.initiateValidatorExit(state, uint64(indx)); err != nil {
 				return err
 			}
 		}
@@ -189,7 +261,7 @@ func processRegistryUpdates(state *consensus.BeaconStatePhase0) error {
 
 	// Queue validators eligible for activation and not yet dequeued for activation
 	activationQueue := []uint64{}
-	for indx, validator := range state.Validators {
+	for indx, validator := range validators {
 		if isElegibleForActivation(state, validator) {
 			activationQueue = append(activationQueue, uint64(indx))
 		}
@@ -197,31 +269,158 @@ func processRegistryUpdates(state *consensus.BeaconStatePhase0) error {
 
 	// Order by the sequence of activation_eligibility_epoch setting and then index
 	sort.Slice(activationQueue, func(i, j int) bool {
-		if state.Validators[i].ActivationEligibilityEpoch == state.Validators[j].ActivationEligibilityEpoch {
+		valI, valJ := validators[activationQueue[i]], validators[activationQueue[j]]
+		if valI.ActivationEligibilityEpoch == valJ.ActivationEligibilityEpoch {
 			// Order by index
-			return i < j
+			return activationQueue[i] < activationQueue[j]
 		}
 		// Order by ActivationEligibilityEpoch
-		return state.Validators[i].ActivationEligibilityEpoch < state.Validators[j].ActivationEligibilityEpoch
+		return valI.ActivationEligibilityEpoch < valJ.ActivationEligibilityEpoch
 	})
 
-	churnLimit := min(uint64(len(activationQueue)), getValidatorChurnLimit(state))
+	churnLimit := min(uint64(len(activationQueue)), s.getValidatorActivationChurnLimit(state))
 
 	// Dequeued validators for activation up to churn limit
 	for _, indx := range activationQueue[:churnLimit] {
-		validator := state.Validators[indx]
-		validator.ActivationEpoch = computeActivationExitEpoch(getCurrentEpoch(state))
+		validator := validators[indx]
+		validator.ActivationEpoch = s.computeActivationExitEpoch(s.getCurrentEpoch(state))
+	}
+	return nil
+}
+
+// processRegistryUpdatesElectra activates all the eligible validators since the
+// activation churn is applied to the deposits instead.
+func (s *StateTransitioner) processRegistryUpdatesElectra(state *beaconState) error {
+	currentEpoch := s.getCurrentEpoch(state)
+	activationEpoch := s.computeActivationExitEpoch(currentEpoch)
+
+	// Process activation eligibility, ejections, and activations
+	for indx, validator := range *state.validators {
+		if s.isElegibleForActivationQueue(state, validator) {
+			validator.ActivationEligibilityEpoch = currentEpoch + 1
+		} else if isActiveValidator(validator, currentEpoch) && validator.EffectiveBalance <= s.spec.EjectionBalance {
+			if err := s.initiateValidatorExit(state, uint64(indx)); err != nil {
+				return err
+			}
+		} else if isElegibleForActivation(state, validator) {
+			validator.ActivationEpoch = activationEpoch
+		}
+	}
+	return nil
+}
+
+func (s *StateTransitioner) processPendingDeposits(state *beaconState) error {
+	nextEpoch := s.getCurrentEpoch(state) + 1
+	availableForProcessing := *state.depositBalanceToConsume + s.getActivationExitChurnLimit(state)
+	processedAmount := uint64(0)
+	nextDepositIndex := uint64(0)
+	depositsToPostpone := []*consensus.PendingDeposit{}
+	isChurnLimitReached := false
+	finalizedSlot := s.computeStartSlotAtEpoch((*state.finalizedCheckpoint).Epoch)
+
+	for _, deposit := range *state.pendingDeposits {
+		// Do not process deposit requests if Eth1 bridge deposits are not yet applied
+		if deposit.Slot > s.spec.GenesisSlot && *state.eth1DepositIndex < *state.depositRequestsStartIndex {
+			break
+		}
+		// Check if deposit has been finalized, otherwise, stop processing
+		if deposit.Slot > finalizedSlot {
+			break
+		}
+		// Check if number of processed deposits has not reached the limit, otherwise, stop processing
+		if nextDepositIndex >= s.spec.MaxPendingDepositsPerEpoch {
+			break
+		}
+
+		// Read validator state
+		isValidatorExited := false
+		isValidatorWithdrawn := false
+		if indx, ok := isInValidatorSet(state, deposit.Pubkey); ok {
+			validator := (*state.validators)[indx]
+			isValidatorExited = validator.ExitEpoch < farFutureEpoch
+			isValidatorWithdrawn = validator.WithdrawableEpoch < nextEpoch
+		}
+
+		if isValidatorWithdrawn {
+			// Deposited balance will never become active. Increase balance but do not consume churn
+			s.applyPendingDeposit(state, deposit)
+		} else if isValidatorExited {
+			// Validator is exiting, postpone the deposit until after withdrawable epoch
+			depositsToPostpone = append(depositsToPostpone, deposit)
+		} else {
+			// Check if deposit fits in the churn, otherwise, do no more deposit processing in this epoch
+			isChurnLimitReached = processedAmount+deposit.Amount > availableForProcessing
+			if isChurnLimitReached {
+				break
+			}
+			// Consume churn and apply deposit
+			processedAmount += deposit.Amount
+			s.applyPendingDeposit(state, deposit)
+		}
+
+		// Regardless of how the deposit was handled, we move on in the queue
+		nextDepositIndex++
+	}
+
+	pendingDeposits := append([]*consensus.PendingDeposit{}, (*state.pendingDeposits)[nextDepositIndex:]...)
+	*state.pendingDeposits = append(pendingDeposits, depositsToPostpone...)
+
+	// Accumulate churn only if the churn limit has been hit
+	if isChurnLimitReached {
+		*state.depositBalanceToConsume = availableForProcessing - processedAmount
+	} else {
+		*state.depositBalanceToConsume = 0
 	}
 	return nil
 }
 
+func (s *StateTransitioner) applyPendingDeposit(state *beaconState, deposit *consensus.PendingDeposit) {
+	indx, ok := isInValidatorSet(state, deposit.Pubkey)
+	if !ok {
+		// Verify the deposit signature (proof of possession) which is not checked by the deposit contract
+		if s.isValidDepositSignature(deposit.Pubkey, deposit.WithdrawalCredentials, deposit.Amount, deposit.Signature) {
+			s.addValidatorToRegistry(state, deposit.Pubkey, deposit.WithdrawalCredentials, deposit.Amount)
+		}
+	} else {
+		increaseBalance(state, indx, deposit.Amount)
+	}
+}
+
+func (s *StateTransitioner) processPendingConsolidations(state *beaconState) error {
+	nextEpoch := s.getCurrentEpoch(state) + 1
+	validators := *state.validators
+
+	nextPendingConsolidation := 0
+	for _, pendingConsolidation := range *state.pendingConsolidations {
+		sourceValidator := validators[pendingConsolidation.SourceIndex]
+		if sourceValidator.Slashed {
+			nextPendingConsolidation++
+			continue
+		}
+		if sourceValidator.WithdrawableEpoch > nextEpoch {
+			break
+		}
+
+		// Calculate the consolidated balance
+		sourceEffectiveBalance := min((*state.balances)[pendingConsolidation.SourceIndex], sourceValidator.EffectiveBalance)
+
+		// Move active balance to target. Excess balance is withdrawable
+		decreaseBalance(state, pendingConsolidation.SourceIndex, sourceEffectiveBalance)
+		increaseBalance(state, pendingConsolidation.TargetIndex, sourceEffectiveBalance)
+		nextPendingConsolidation++
+	}
+
+	*state.pendingConsolidations = (*state.pendingConsolidations)[nextPendingConsolidation:]
+	return nil
+}
+
 // getInclusionDelayDeltas returns proposer and inclusion delay micro-rewards/penalties for each validator.
-func getInclusionDelayDeltas(state *consensus.BeaconStatePhase0) ([]uint64, []uint64) {
-	rewards := make([]uint64, len(state.Validators))
+func (s *StateTransitioner) getInclusionDelayDeltas(state *beaconState) ([]uint64, []uint64) {
+	rewards := make([]uint64, len(*state.validators))
 
-	matchingSourceAttestations := getMatchingSourceAttestations(state, getPreviousEpoch(state))
+	matchingSourceAttestations := s.getMatchingSourceAttestations(state, s.getPreviousEpoch(state))
 
-	unslashedAttIndex, err := getUnslashedAttestingIndices(state, matchingSourceAttestations)
+	unslashedAttIndex, err := s.getUnslashedAttestingIndices(state, matchingSourceAttestations)
 	if err != nil {
 		panic(err)
 	}
@@ -229,7 +428,7 @@ func getInclusionDelayDeltas(state *consensus.BeaconStatePhase0) ([]uint64, []ui
 	for _, index := range unslashedAttIndex {
 		var attestation *consensus.PendingAttestation
 		for _, a := range matchingSourceAttestations {
-			attIndex, err := getAttestingIndices(state, a.Data, a.AggregationBits)
+			attIndex, err := s.getAttestingIndices(state, a.Data, a.AggregationBits)
 			if err != nil {
 				panic(err)
 			}
@@ -243,13 +442,13 @@ func getInclusionDelayDeltas(state *consensus.BeaconStatePhase0) ([]uint64, []ui
 			}
 		}
 
-		rewards[attestation.ProposerIndex] += getProposerReward(state, index)
-		maxAttesterReward := getBaseReward(state, index) - getProposerReward(state, index)
+		rewards[attestation.ProposerIndex] += s.getProposerReward(state, index)
+		maxAttesterReward := s.getBaseReward(state, index) - s.getProposerReward(state, index)
 		rewards[index] += maxAttesterReward / attestation.InclusionDelay
 	}
 
 	// no penalties associated with inclusion delay
-	penalties := make([]uint64, len(state.Validators))
+	penalties := make([]uint64, len(*state.validators))
 
 	return rewards, penalties
 }
@@ -264,72 +463,85 @@ func contains(a []uint64, b uint64) bool {
 }
 
 // getInactivityPenaltyDeltas return inactivity reward/penalty deltas for each validator.
-func getInactivityPenaltyDeltas(state *consensus.BeaconStatePhase0) ([]uint64, []uint64) {
-	penalties := make([]uint64, len(state.Validators))
+func (s *StateTransitioner) getInactivityPenaltyDeltas(state *beaconState) ([]uint64, []uint64) {
+	penalties := make([]uint64, len(*state.validators))
 
-	if isInInactivityLeak(state) {
-		matchingTargetAttestations := getMatchingTargetAttestations(state, getPreviousEpoch(state))
-		matchingTargetAttestingIndices, err := getUnslashedAttestingIndices(state, matchingTargetAttestations)
+	if s.isInInactivityLeak(state) {
+		matchingTargetAttestations := s.getMatchingTargetAttestations(state, s.getPreviousEpoch(state))
+		matchingTargetAttestingIndices, err := s.getUnslashedAttestingIndices(state, matchingTargetAttestations)
 		if err != nil {
 			panic(err)
 		}
 
-		for _, index := range getElegibleValidatorIndices(state) {
+		for _, index := range s.getElegibleValidatorIndices(state) {
 			// If validator is performing optimally this cancels all rewards for a neutral balance
-			baseReward := getBaseReward(state, index)
-			penalties[index] += Spec.BaseRewardsPerEpoch*baseReward - getProposerReward(state, index)
+			baseReward := s.getBaseReward(state, index)
+			penalties[index] += s.spec.BaseRewardsPerEpoch*baseReward - s.getProposerReward(state, index)
 
 			if !contains(matchingTargetAttestingIndices, index) {
-				effectiveBalance := state.Validators[index].EffectiveBalance
-				penalties[index] += effectiveBalance * getFinalityDelay(state) / Spec.InactivityPenaltyQuotient
+				effectiveBalance := (*state.validators)[index].EffectiveBalance
+				penalties[index] += effectiveBalance * s.getFinalityDelay(state) / s.spec.InactivityPenaltyQuotient
 			}
 		}
 	}
 
 	// No rewards associated with inactivity penalties
-	rewards := make([]uint64, len(state.Validators))
+	rewards := make([]uint64, len(*state.validators))
 
 	return rewards, penalties
 }
 
-func getProposerReward(state *consensus.BeaconStatePhase0, attestingIndex uint64) uint64 {
-	return getBaseReward(state, attestingIndex) / Spec.ProposerRewardQuotient
+func (s *StateTransitioner) getProposerReward(state *beaconState, attestingIndex uint64) uint64 {
+	return s.getBaseReward(state, attestingIndex) / s.spec.ProposerRewardQuotient
 }
 
-func getAttestationDeltas(state *consensus.BeaconStatePhase0) ([]uint64, []uint64) {
+func (s *StateTransitioner) getAttestationDeltas(state *beaconState) ([]uint64, []uint64) {
 	// Return attestation reward/penalty deltas for each validator.
-	sourceRewards, sourcePenalties := getSourceDeltas(state)
-	targetRewards, targetPenalties := getTargetDeltas(state)
-	headRewards, headPenalties := getHeadDeltas(state)
-	inclusionDelayRewards, _ := getInclusionDelayDeltas(state)
-	_, inactivityPenalties := getInactivityPenaltyDeltas(state)
+	sourceRewards, sourcePenalties := s.getSourceDeltas(state)
+	targetRewards, targetPenalties := s.getTargetDeltas(state)
+	headRewards, headPenalties := s.getHeadDeltas(state)
+	inclusionDelayRewards, _ := s.getInclusionDelayDeltas(state)
+	_, inactivityPenalties := s.getInactivityPenaltyDeltas(state)
 
-	penalties := make([]uint64, len(state.Validators))
-	rewards := make([]uint64, len(state.Validators))
+	penalties := make([]uint64, len(*state.validators))
+	rewards := make([]uint64, len(*state.validators))
 
-	for i := 0; i < len(state.Validators); i++ {
+	for i := 0; i < len(*state.validators); i++ {
 		rewards[i] = sourceRewards[i] + targetRewards[i] + headRewards[i] + inclusionDelayRewards[i]
 	}
 
-	for i := 0; i < len(state.Validators); i++ {
+	for i := 0; i < len(*state.validators); i++ {
 		penalties[i] = sourcePenalties[i] + targetPenalties[i] + headPenalties[i] + inactivityPenalties[i]
 	}
 
 	return rewards, penalties
 }
 
-func processRewardsAndPenalties(state *consensus.BeaconStatePhase0) error {
+func (s *StateTransitioner) processRewardsAndPenalties(state *beaconState) error {
 	// No rewards are applied at the end of `GENESIS_EPOCH` because rewards are for work done in the previous epoch
-	if getCurrentEpoch(state) == Spec.GenesisEpoch {
+	if s.getCurrentEpoch(state) == s.spec.GenesisEpoch {
 		return nil
 	}
 
-	rewards, penalties := getAttestationDeltas(state)
-	for indx := range state.Validators {
+	if !state.atLeast(consensus.VersionAltair) {
+		rewards, penalties := s.getAttestationDeltas(state)
+		applyDeltas(state, rewards, penalties)
+	} else {
+		for flagIndex := range participationFlagWeights {
+			rewards, penalties := s.getFlagIndexDeltas(state, flagIndex)
+			applyDeltas(state, rewards, penalties)
+		}
+		rewards, penalties := s.getInactivityPenaltyDeltasAltair(state)
+		applyDeltas(state, rewards, penalties)
+	}
+	return nil
+}
+
+func applyDeltas(state *beaconState, rewards, penalties []uint64) {
+	for indx := range *state.validators {
 		increaseBalance(state, uint64(indx), rewards[indx])
 		decreaseBalance(state, uint64(indx), penalties[indx])
 	}
-	return nil
 }
 
 func sum(i []uint64) (res uint64) {
@@ -339,25 +551,88 @@ func sum(i []uint64) (res uint64) {
 	return
 }
 
-func processSlashings(state *consensus.BeaconStatePhase0) error {
-	epoch := getCurrentEpoch(state)
+func (s *StateTransitioner) processSlashings(state *beaconState) error {
+	epoch := s.getCurrentEpoch(state)
+
+	totalBalance := s.getTotalActiveBalance(state)
+	adjustedTotalSlashingBalance := min(sum(state.slashings)*s.proportionalSlashingMultiplier(state), totalBalance)
 
-	totalBalance := getTotalActiveBalance(state)
-	adjustedTotalSlashingBalance := min(sum(state.Slashings)*Spec.ProportionalSlashingsMultiplier, totalBalance)
+	increment := s.spec.EffectiveBalanceIncrement
+	// the penalty is computed per increment since electra
+	penaltyPerEffectiveBalanceIncrement := adjustedTotalSlashingBalance / (totalBalance / increment)
 
-	for index, validator := range state.Validators {
-		if validator.Slashed && epoch+Spec.EpochsPerSlashingsVector/2 == validator.WithdrawableEpoch {
-			increment := Spec.EffectiveBalanceIncrement
-			penaltyNumerator := (validator.EffectiveBalance / increment) * adjustedTotalSlashingBalance
-			penalty := (penaltyNumerator / totalBalance) * increment
+	for index, validator := range *state.validators {
+		if validator.Slashed && epoch+s.spec.EpochsPerSlashingsVector/2 == validator.WithdrawableEpoch {
+			var penalty uint64
+			if state.atLeast(consensus.VersionElectra) {
+				penalty = penaltyPerEffectiveBalanceIncrement * (validator.EffectiveBalance / increment)
+			} else {
+				penaltyNumerator := (validator.EffectiveBalance / increment) * adjustedTotalSlashingBalance
+				penalty = (penaltyNumerator / totalBalance) * increment
+			}
 			decreaseBalance(state, uint64(index), penalty)
 		}
 	}
 	return nil
 }
 
-func processSlashingsReset(state *consensus.BeaconStatePhase0) error {
-	nextEpoch := getCurrentEpoch(state) + 1
-	state.Slashings[nextEpoch%Spec.EpochsPerSlashingsVector] = 0
+func (s *StateTransitioner) processSlashingsReset(state *beaconState) error {
+	nextEpoch := s.getCurrentEpoch(state) + 1
+	state.slashings[nextEpoch%s.spec.EpochsPerSlashingsVector] = 0
+	return nil
+}
+
+func (s *StateTransitioner) proportionalSlashingMultiplier(state *beaconState) uint64 {
+	switch {
+	case state.atLeast(consensus.VersionBellatrix):
+		return s.spec.ProportionalSlashingMultiplierBellatrix
+	case state.atLeast(consensus.VersionAltair):
+		return s.spec.ProportionalSlashingMultiplierAltair
+	default:
+		return s.spec.ProportionalSlashingsMultiplier
+	}
+}
+
+func (s *StateTransitioner) processInactivityUpdates(state *beaconState) error {
+	// Skip the genesis epoch as score updates are based on the previous epoch participation
+	if s.getCurrentEpoch(state) == s.spec.GenesisEpoch {
+		return nil
+	}
+
+	previousIndices := s.getUnslashedParticipatingIndices(state, timelyTargetFlagIndex, s.getPreviousEpoch(state))
+	isInLeak := s.isInInactivityLeak(state)
+
+	for _, index := range s.getElegibleValidatorIndices(state) {
+		// Increase the inactivity score of inactive validators
+		if contains(previousIndices, index) {
+			(*state.inactivityScores)[index] -= min(1, (*state.inactivityScores)[index])
+		} else {
+			(*state.inactivityScores)[index] += s.spec.InactivityScoreBias
+		}
+		// Decrease the inactivity score of all eligible validators during a leak-free epoch
+		if !isInLeak {
+			(*state.inactivityScores)[index] -= min(s.spec.InactivityScoreRecoveryRate, (*state.inactivityScores)[index])
+		}
+	}
+	return nil
+}
+
+func processParticipationFlagUpdates(state *beaconState) error {
+	*state.previousEpochParticipation = *state.currentEpochParticipation
+	*state.currentEpochParticipation = make([]byte, len(*state.validators))
+	return nil
+}
+
+func (s *StateTransitioner) processSyncCommitteeUpdates(state *beaconState) error {
+	nextEpoch := s.getCurrentEpoch(state) + 1
+
+	if nextEpoch%s.spec.EpochsPerSyncCommitteePeriod == 0 {
+		nextSyncCommittee, err := s.getNextSyncCommittee(state)
+		if err != nil {
+			return err
+		}
+		*state.currentSyncCommittee = *state.nextSyncCommittee
+		*state.nextSyncCommittee = nextSyncCommittee
+	}
 	return nil
 }
@@ -8,92 +8,156 @@ import (
 	consensus "github.com/umbracle/go-eth-consensus"
 )
 
-type epochProcessignFunc func(state *consensus.BeaconStatePhase0) error
+type epochProcessingCase struct {
+	name    string
+	path    string
+	handler func(st *StateTransitioner, state *beaconState) error
+
+	// the forks the case applies to, from since until (excluded)
+	since, until consensus.Version
+}
+
+func (c epochProcessingCase) applies(version consensus.Version) bool {
+	if version < c.since {
+		return false
+	}
+	return c.until == 0 || version < c.until
+}
 
 func TestEpochProcessing(t *testing.T) {
 	type epochTest struct {
-		Pre  consensus.BeaconStatePhase0
-		Post consensus.BeaconStatePhase0
+		Pre  consensus.BeaconState
+		Post consensus.BeaconState
 	}
 
-	cases := []struct {
-		name    string
-		path    string
-		handler epochProcessignFunc
-	}{
+	cases := []epochProcessingCase{
+		{
+			name:    "Effective balance updates",
+			path:    "effective_balance_updates/*/*",
+			handler: (*StateTransitioner).processEffectiveBalanceUpdates,
+		},
+		{
+			name:    "Eth1 data reset",
+			path:    "eth1_data_reset/*/*",
+			handler: (*StateTransitioner).processEth1DataReset,
+		},
+		{
+			name:    "Historical roots update",
+			path:    "historical_roots_update/*/*",
+			handler: (*StateTransitioner).processHistoricalRootsUpdate,
+			until:   consensus.VersionCapella,
+		},
 		{
-			"Effective balance updates",
-			"effective_balance_updates/*/*",
-			processEffectiveBalanceUpdates,
+			name:    "Historical summaries update",
+			path:    "historical_summaries_update/*/*",
+			handler: (*StateTransitioner).processHistoricalSummariesUpdate,
+			since:   consensus.VersionCapella,
 		},
 		{
-			"Eth1 data reset",
-			"eth1_data_reset/*/*",
-			processEth1DataReset,
+			name:    "Justification_and_finalization",
+			path:    "justification_and_finalization/*/*",
+			handler: (*StateTransitioner).processJustificationAndFinalization,
 		},
 		{
-			"Historical roots update",
-			"historical_roots_update/*/*",
-			processHistoricalRootsUpdate,
+			name:    "Randao mix",
+			path:    "randao_mixes_reset/*/*",
+			handler: (*StateTransitioner).processRandaoMixesReset,
 		},
 		{
-			"Justification_and_finalization",
-			"justification_and_finalization/*/*",
-			processJustificationAndFinalization,
+			name:    "Registry updates",
+			path:    "registry_updates/*/*",
+			handler: (*StateTransitioner).processRegistryUpdates,
 		},
 		{
-			"Participation record",
-			"participation_record_updates/*/*",
-			processParticipationRecordUpdates,
+			name:    "Rewards and Penalties",
+			path:    "rewards_and_penalties/*/*",
+			handler: (*StateTransitioner).processRewardsAndPenalties,
 		},
 		{
-			"Randao mix",
-			"randao_mixes_reset/*/*",
-			processRandaoMixesReset,
+			name:    "Process slashing",
+			path:    "slashings/*/*",
+			handler: (*StateTransitioner).processSlashings,
 		},
 		{
-			"Registry updates",
-			"registry_updates/*/*",
-			processRegistryUpdates,
+			name:    "Slashings reset",
+			path:    "slashings_reset/*/*",
+			handler: (*StateTransitioner).processSlashingsReset,
 		},
 		{
-			"Rewards and Penalties",
-			"rewards_and_penalties/*/*",
-			processRewardsAndPenalties,
+			name: "Participation record",
+			path: "participation_record_updates/*/*",
+			handler: func(st *StateTransitioner, state *beaconState) error {
+				return processParticipationRecordUpdates(state)
+			},
+			until: consensus.VersionAltair,
 		},
 		{
-			"Process slashing",
-			"slashings/*/*",
-			processSlashings,
+			name:    "Inactivity updates",
+			path:    "inactivity_updates/*/*",
+			handler: (*StateTransitioner).processInactivityUpdates,
+			since:   consensus.VersionAltair,
 		},
 		{
-			"Slashings reset",
-			"slashings_reset/*/*",
-			processSlashingsReset,
+			name: "Participation flag updates",
+			path: "participation_flag_updates/*/*",
+			handler: func(st *StateTransitioner, state *beaconState) error {
+				return processParticipationFlagUpdates(state)
+			},
+			since: consensus.VersionAltair,
+		},
+		{
+			name:    "Sync committee updates",
+			path:    "sync_committee_updates/*/*",
+			handler: (*StateTransitioner).processSyncCommitteeUpdates,
+			since:   consensus.VersionAltair,
+		},
+		{
+			name:    "Pending deposits",
+			path:    "pending_deposits/*/*",
+			handler: (*StateTransitioner).processPendingDeposits,
+			since:   consensus.VersionElectra,
+		},
+		{
+			name:    "Pending consolidations",
+			path:    "pending_consolidations/*/*",
+			handler: (*StateTransitioner).processPendingConsolidations,
+			since:   consensus.VersionElectra,
 		},
 	}
 
-	for _, c := range cases {
-		t.Run(c.name, func(t *testing.T) {
-			listTestData(t, filepath.Join("mainnet/phase0/epoch_processing/", c.path), func(th *testHandler) {
-				eTest := &epochTest{}
-				th.decodeFile("pre", &eTest.Pre)
-				ok := th.decodeFile("post", &eTest.Post, true)
+	for _, fork := range testForks {
+		for _, c := range cases {
+			if !c.applies(fork.version) {
+				continue
+			}
+			t.Run(fork.name+"/"+c.name, func(t *testing.T) {
+				listTestData(t, filepath.Join(fork.name, "epoch_processing", c.path), func(th *testHandler) {
+					eTest := &epochTest{
+						Pre:  fork.newState(),
+						Post: fork.newState(),
+					}
+					th.decodeFile("pre", eTest.Pre)
+					ok := th.decodeFile("post", eTest.Post, true)
 
-				if err := c.handler(&eTest.Pre); err != nil {
-					if ok {
+					state, err := toBeaconState(eTest.Pre)
+					if err != nil {
 						t.Fatal(err)
 					}
-					return
-				}
+					if err := c.handler(th.transitioner, state); err != nil {
+						if ok {
+							t.Fatal(err)
+						}
+						return
+					}
 
-				if !ok {
-					t.Fatal("it should fail")
-				}
-				if !reflect.DeepEqual(eTest.Pre, eTest.Post) {
-					t.Fatal("bad")
-				}
+					if !ok {
+						t.Fatal("it should fail")
+					}
+					if !reflect.DeepEqual(eTest.Pre, eTest.Post) {
+						t.Fatal("bad")
+					}
+				})
 			})
-		})
+		}
 	}
 }
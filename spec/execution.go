@@ -0,0 +1,555 @@
+package spec
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+
+	ssz "github.com/ferranbt/fastssz"
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+const (
+	blsWithdrawalPrefix         = 0x00
+	eth1AddressWithdrawalPrefix = 0x01
+	compoundingWithdrawalPrefix = 0x02
+)
+
+// toPayloadDeneb converts the execution payload of any fork into the deneb one, which
+// has the fields of all the previous forks. The fields the fork does not have are empty.
+func toPayloadDeneb(payload interface{}) (*consensus.ExecutionPayloadDeneb, error) {
+	switch obj := payload.(type) {
+	case *consensus.ExecutionPayload:
+		return bellatrixToPayloadDeneb(obj), nil
+	case *consensus.ExecutionPayloadCapella:
+		return capellaToPayloadDeneb(obj), nil
+	case *consensus.ExecutionPayloadDeneb:
+		if obj == nil {
+			return &consensus.ExecutionPayloadDeneb{}, nil
+		}
+		return obj, nil
+	default:
+		return nil, fmt.Errorf("execution payload %T not supported", payload)
+	}
+}
+
+func bellatrixToPayloadDeneb(payload *consensus.ExecutionPayload) *consensus.ExecutionPayloadDeneb {
+	if payload == nil {
+		return &consensus.ExecutionPayloadDeneb{}
+	}
+	return &consensus.ExecutionPayloadDeneb{
+		ParentHash:    payload.ParentHash,
+		FeeRecipient:  payload.FeeRecipient,
+		StateRoot:     payload.StateRoot,
+		ReceiptsRoot:  payload.ReceiptsRoot,
+		LogsBloom:     payload.LogsBloom,
+		PrevRandao:    payload.PrevRandao,
+		BlockNumber:   payload.BlockNumber,
+		GasLimit:      payload.GasLimit,
+		GasUsed:       payload.GasUsed,
+		Timestamp:     payload.Timestamp,
+		ExtraData:     payload.ExtraData,
+		BaseFeePerGas: payload.BaseFeePerGas,
+		BlockHash:     payload.BlockHash,
+		Transactions:  payload.Transactions,
+	}
+}
+
+func capellaToPayloadDeneb(payload *consensus.ExecutionPayloadCapella) *consensus.ExecutionPayloadDeneb {
+	if payload == nil {
+		return &consensus.ExecutionPayloadDeneb{}
+	}
+	return &consensus.ExecutionPayloadDeneb{
+		ParentHash:    payload.ParentHash,
+		FeeRecipient:  payload.FeeRecipient,
+		StateRoot:     payload.StateRoot,
+		ReceiptsRoot:  payload.ReceiptsRoot,
+		LogsBloom:     payload.LogsBloom,
+		PrevRandao:    payload.PrevRandao,
+		BlockNumber:   payload.BlockNumber,
+		GasLimit:      payload.GasLimit,
+		GasUsed:       payload.GasUsed,
+		Timestamp:     payload.Timestamp,
+		ExtraData:     payload.ExtraData,
+		BaseFeePerGas: payload.BaseFeePerGas,
+		BlockHash:     payload.BlockHash,
+		Transactions:  payload.Transactions,
+		Withdrawals:   payload.Withdrawals,
+	}
+}
+
+// ProcessExecutionPayload processes the execution payload of the body of a block.
+// The payload is not sent to an execution engine and the caller is responsible of
+// validating it with one.
+func (s *StateTransitioner) ProcessExecutionPayload(state consensus.BeaconState, block consensus.BeaconBlock) error {
+	obj, err := toBeaconState(state)
+	if err != nil {
+		return err
+	}
+	body, err := toBlockBody(block)
+	if err != nil {
+		return err
+	}
+	if body.version < consensus.VersionBellatrix {
+		return fmt.Errorf("beacon block %T does not have an execution payload", block)
+	}
+	return s.processExecutionPayload(obj, body)
+}
+
+// ProcessWithdrawals processes the withdrawals of the execution payload.
+func (s *StateTransitioner) ProcessWithdrawals(state consensus.BeaconState, payload interface{}) error {
+	obj, err := toBeaconState(state)
+	if err != nil {
+		return err
+	}
+	if !obj.atLeast(consensus.VersionCapella) {
+		return fmt.Errorf("beacon state %T does not have withdrawals", state)
+	}
+	payloadDeneb, err := toPayloadDeneb(payload)
+	if err != nil {
+		return err
+	}
+	return s.processWithdrawals(obj, payloadDeneb)
+}
+
+// ProcessBlsToExecutionChange processes the change of the withdrawal credentials of a
+// validator from a bls key to an execution address.
+func (s *StateTransitioner) ProcessBlsToExecutionChange(state consensus.BeaconState, signedAddressChange *consensus.SignedBLSToExecutionChange) error {
+	obj, err := toBeaconState(state)
+	if err != nil {
+		return err
+	}
+	if !obj.atLeast(consensus.VersionCapella) {
+		return fmt.Errorf("beacon state %T does not have execution withdrawal credentials", state)
+	}
+	return s.processBlsToExecutionChange(obj, signedAddressChange)
+}
+
+func (s *StateTransitioner) latestExecutionPayloadHeaderBlockHash(state *beaconState) ([32]byte, error) {
+	switch obj := state.obj.(type) {
+	case *consensus.BeaconStateBellatrix:
+		return obj.LatestExecutionPayloadHeader.BlockHash, nil
+	case *consensus.BeaconStateCapella:
+		return obj.LatestExecutionPayloadHeader.BlockHash, nil
+	case *consensus.BeaconStateDeneb:
+		return obj.LatestExecutionPayloadHeader.BlockHash, nil
+	case *consensus.BeaconStateElectra:
+		return obj.LatestExecutionPayloadHeader.BlockHash, nil
+	default:
+		return [32]byte{}, fmt.Errorf("beacon state %T does not have an execution payload", state.obj)
+	}
+}
+
+// isMergeTransitionComplete returns whether the state has an execution payload header. It
+// is only required for bellatrix since the later forks always start after the merge.
+func (s *StateTransitioner) isMergeTransitionComplete(state *beaconState) (bool, error) {
+	obj, ok := state.obj.(*consensus.BeaconStateBellatrix)
+	if !ok {
+		return true, nil
+	}
+	root, err := obj.LatestExecutionPayloadHeader.HashTreeRoot()
+	if err != nil {
+		return false, err
+	}
+	emptyRoot, err := (&consensus.ExecutionPayloadHeader{}).HashTreeRoot()
+	if err != nil {
+		return false, err
+	}
+	return root != emptyRoot, nil
+}
+
+func (s *StateTransitioner) isExecutionEnabled(state *beaconState, payload *consensus.ExecutionPayloadDeneb) (bool, error) {
+	complete, err := s.isMergeTransitionComplete(state)
+	if err != nil {
+		return false, err
+	}
+	if complete {
+		return true, nil
+	}
+
+	// it is the merge transition block if the payload is not empty
+	root, err := payload.HashTreeRoot()
+	if err != nil {
+		return false, err
+	}
+	emptyRoot, err := (&consensus.ExecutionPayloadDeneb{}).HashTreeRoot()
+	if err != nil {
+		return false, err
+	}
+	return root != emptyRoot, nil
+}
+
+func (s *StateTransitioner) computeTimestampAtSlot(state *beaconState, slot uint64) uint64 {
+	return *state.genesisTime + (slot-s.spec.GenesisSlot)*s.spec.SecondsPerSlot
+}
+
+func (s *StateTransitioner) processExecutionPayload(state *beaconState, body *blockBody) error {
+	payload := body.executionPayload
+
+	// Verify consistency of the parent hash with respect to the previous execution payload header.
+	// The merge is complete from capella onwards.
+	complete := state.atLeast(consensus.VersionCapella)
+	if !complete {
+		var err error
+		if complete, err = s.isMergeTransitionComplete(state); err != nil {
+			return err
+		}
+	}
+	if complete {
+		parentHash, err := s.latestExecutionPayloadHeaderBlockHash(state)
+		if err != nil {
+			return err
+		}
+		if payload.ParentHash != parentHash {
+			return fmt.Errorf("incorrect execution parent hash %x, expected %x", payload.ParentHash, parentHash)
+		}
+	}
+
+	// Verify prev_randao
+	if randaoMix := s.getRandaoMix(state, s.getCurrentEpoch(state)); payload.PrevRandao != randaoMix {
+		return fmt.Errorf("incorrect execution prev randao %x, expected %x", payload.PrevRandao, randaoMix)
+	}
+
+	// Verify timestamp
+	if timestamp := s.computeTimestampAtSlot(state, *state.slot); payload.Timestamp != timestamp {
+		return fmt.Errorf("incorrect execution timestamp %d, expected %d", payload.Timestamp, timestamp)
+	}
+
+	// Verify commitments are under limit
+	if state.atLeast(consensus.VersionDeneb) {
+		maxBlobsPerBlock := s.spec.MaxBlobsPerBlock
+		if state.atLeast(consensus.VersionElectra) {
+			maxBlobsPerBlock = s.spec.MaxBlobsPerBlockElectra
+		}
+		if uint64(len(body.blobKZGCommitments)) > maxBlobsPerBlock {
+			return fmt.Errorf("too many blob commitments %d, the limit is %d", len(body.blobKZGCommitments), maxBlobsPerBlock)
+		}
+	}
+
+	// The payload is not verified with an execution engine
+
+	// Cache execution payload header
+	transactionsRoot, err := s.transactionsRoot(payload.Transactions)
+	if err != nil {
+		return err
+	}
+
+	switch obj := state.obj.(type) {
+	case *consensus.BeaconStateBellatrix:
+		obj.LatestExecutionPayloadHeader = &consensus.ExecutionPayloadHeader{
+			ParentHash:       payload.ParentHash,
+			FeeRecipient:     payload.FeeRecipient,
+			StateRoot:        payload.StateRoot,
+			ReceiptsRoot:     payload.ReceiptsRoot,
+			LogsBloom:        payload.LogsBloom,
+			PrevRandao:       payload.PrevRandao,
+			BlockNumber:      payload.BlockNumber,
+			GasLimit:         payload.GasLimit,
+			GasUsed:          payload.GasUsed,
+			Timestamp:        payload.Timestamp,
+			ExtraData:        payload.ExtraData,
+			BaseFeePerGas:    payload.BaseFeePerGas,
+			BlockHash:        payload.BlockHash,
+			TransactionsRoot: transactionsRoot,
+		}
+		return nil
+	}
+
+	withdrawalsRoot, err := s.withdrawalsRoot(payload.Withdrawals)
+	if err != nil {
+		return err
+	}
+
+	switch obj := state.obj.(type) {
+	case *consensus.BeaconStateCapella:
+		obj.LatestExecutionPayloadHeader = &consensus.ExecutionPayloadHeaderCapella{
+			ParentHash:       payload.ParentHash,
+			FeeRecipient:     payload.FeeRecipient,
+			StateRoot:        payload.StateRoot,
+			ReceiptsRoot:     payload.ReceiptsRoot,
+			LogsBloom:        payload.LogsBloom,
+			PrevRandao:       payload.PrevRandao,
+			BlockNumber:      payload.BlockNumber,
+			GasLimit:         payload.GasLimit,
+			GasUsed:          payload.GasUsed,
+			Timestamp:        payload.Timestamp,
+			ExtraData:        payload.ExtraData,
+			BaseFeePerGas:    payload.BaseFeePerGas,
+			BlockHash:        payload.BlockHash,
+			TransactionsRoot: transactionsRoot,
+			WithdrawalRoot:   withdrawalsRoot,
+		}
+	case *consensus.BeaconStateDeneb:
+		obj.LatestExecutionPayloadHeader = toPayloadHeaderDeneb(payload, transactionsRoot, withdrawalsRoot)
+	case *consensus.BeaconStateElectra:
+		obj.LatestExecutionPayloadHeader = toPayloadHeaderDeneb(payload, transactionsRoot, withdrawalsRoot)
+	default:
+		return fmt.Errorf("beacon state %T does not have an execution payload", state.obj)
+	}
+	return nil
+}
+
+func toPayloadHeaderDeneb(payload *consensus.ExecutionPayloadDeneb, transactionsRoot, withdrawalsRoot [32]byte) *consensus.ExecutionPayloadHeaderDeneb {
+	return &consensus.ExecutionPayloadHeaderDeneb{
+		ParentHash:       payload.ParentHash,
+		FeeRecipient:     payload.FeeRecipient,
+		StateRoot:        payload.StateRoot,
+		ReceiptsRoot:     payload.ReceiptsRoot,
+		LogsBloom:        payload.LogsBloom,
+		PrevRandao:       payload.PrevRandao,
+		BlockNumber:      payload.BlockNumber,
+		GasLimit:         payload.GasLimit,
+		GasUsed:          payload.GasUsed,
+		Timestamp:        payload.Timestamp,
+		ExtraData:        payload.ExtraData,
+		BaseFeePerGas:    payload.BaseFeePerGas,
+		BlockHash:        payload.BlockHash,
+		TransactionsRoot: transactionsRoot,
+		WithdrawalRoot:   withdrawalsRoot,
+		BlobGasUsed:      payload.BlobGasUsed,
+		ExcessBlobGas:    payload.ExcessBlobGas,
+	}
+}
+
+// transactionsRoot returns the hash tree root of the transactions list sized with the spec
+func (s *StateTransitioner) transactionsRoot(transactions [][]byte) ([32]byte, error) {
+	if uint64(len(transactions)) > s.spec.MaxTransactionsPerPayload {
+		return [32]byte{}, ssz.ErrIncorrectListSize
+	}
+	hh := ssz.NewHasher()
+
+	indx := hh.Index()
+	for _, tx := range transactions {
+		if uint64(len(tx)) > s.spec.MaxBytesPerTransaction {
+			return [32]byte{}, ssz.ErrIncorrectListSize
+		}
+		elemIndx := hh.Index()
+		hh.AppendBytes32(tx)
+		hh.MerkleizeWithMixin(elemIndx, uint64(len(tx)), (s.spec.MaxBytesPerTransaction+31)/32)
+	}
+	hh.MerkleizeWithMixin(indx, uint64(len(transactions)), s.spec.MaxTransactionsPerPayload)
+
+	return hh.HashRoot()
+}
+
+// withdrawalsRoot returns the hash tree root of the withdrawals list sized with the spec
+func (s *StateTransitioner) withdrawalsRoot(withdrawals []*consensus.Withdrawal) ([32]byte, error) {
+	if uint64(len(withdrawals)) > s.spec.MaxWithdrawalsPerPayload {
+		return [32]byte{}, ssz.ErrIncorrectListSize
+	}
+	hh := ssz.NewHasher()
+
+	indx := hh.Index()
+	for _, withdrawal := range withdrawals {
+		if err := withdrawal.HashTreeRootWith(hh); err != nil {
+			return [32]byte{}, err
+		}
+	}
+	hh.MerkleizeWithMixin(indx, uint64(len(withdrawals)), s.spec.MaxWithdrawalsPerPayload)
+
+	return hh.HashRoot()
+}
+
+func hasEth1WithdrawalCredential(validator *consensus.Validator) bool {
+	return validator.WithdrawalCredentials[0] == eth1AddressWithdrawalPrefix
+}
+
+func hasCompoundingWithdrawalCredential(validator *consensus.Validator) bool {
+	return validator.WithdrawalCredentials[0] == compoundingWithdrawalPrefix
+}
+
+func hasExecutionWithdrawalCredential(validator *consensus.Validator) bool {
+	return hasEth1WithdrawalCredential(validator) || hasCompoundingWithdrawalCredential(validator)
+}
+
+func executionAddress(validator *consensus.Validator) (address [20]byte) {
+	copy(address[:], validator.WithdrawalCredentials[12:])
+	return
+}
+
+// getMaxEffectiveBalance returns the max effective balance of the validator, which
+// depends on the withdrawal credentials since electra.
+func (s *StateTransitioner) getMaxEffectiveBalance(state *beaconState, validator *consensus.Validator) uint64 {
+	if !state.atLeast(consensus.VersionElectra) {
+		return s.spec.MaxEffectiveBalance
+	}
+	if hasCompoundingWithdrawalCredential(validator) {
+		return s.spec.MaxEffectiveBalanceElectra
+	}
+	return s.spec.MinActivationBalance
+}
+
+func (s *StateTransitioner) isFullyWithdrawableValidator(state *beaconState, validator *consensus.Validator, balance uint64, epoch uint64) bool {
+	hasWithdrawalCredential := hasEth1WithdrawalCredential(validator)
+	if state.atLeast(consensus.VersionElectra) {
+		hasWithdrawalCredential = hasExecutionWithdrawalCredential(validator)
+	}
+	return hasWithdrawalCredential && validator.WithdrawableEpoch <= epoch && balance > 0
+}
+
+func (s *StateTransitioner) isPartiallyWithdrawableValidator(state *beaconState, validator *consensus.Validator, balance uint64) bool {
+	hasWithdrawalCredential := hasEth1WithdrawalCredential(validator)
+	if state.atLeast(consensus.VersionElectra) {
+		hasWithdrawalCredential = hasExecutionWithdrawalCredential(validator)
+	}
+	maxEffectiveBalance := s.getMaxEffectiveBalance(state, validator)
+
+	hasMaxEffectiveBalance := validator.EffectiveBalance == maxEffectiveBalance
+	hasExcessBalance := balance > maxEffectiveBalance
+	return hasWithdrawalCredential && hasMaxEffectiveBalance && hasExcessBalance
+}
+
+// getExpectedWithdrawals returns the withdrawals of the next payload and the number
+// of pending partial withdrawals they consume.
+func (s *StateTransitioner) getExpectedWithdrawals(state *beaconState) ([]*consensus.Withdrawal, uint64) {
+	epoch := s.getCurrentEpoch(state)
+	withdrawalIndex := *state.nextWithdrawalIndex
+	validatorIndex := *state.nextWithdrawalValidatorIndex
+	validators := *state.validators
+	balances := *state.balances
+
+	withdrawals := []*consensus.Withdrawal{}
+	totalWithdrawn := func(validatorIndex uint64) uint64 {
+		amount := uint64(0)
+		for _, withdrawal := range withdrawals {
+			if withdrawal.ValidatorIndex == validatorIndex {
+				amount += withdrawal.Amount
+			}
+		}
+		return amount
+	}
+
+	processedPartialWithdrawalsCount := uint64(0)
+	if state.atLeast(consensus.VersionElectra) {
+		// Consume pending partial withdrawals
+		for _, withdrawal := range *state.pendingPartialWithdrawals {
+			if withdrawal.WithdrawableEpoch > epoch || uint64(len(withdrawals)) == s.spec.MaxPendingPartialsPerWithdrawalsSweep {
+				break
+			}
+
+			validator := validators[withdrawal.ValidatorIndex]
+			hasSufficientEffectiveBalance := validator.EffectiveBalance >= s.spec.MinActivationBalance
+			balance := balances[withdrawal.ValidatorIndex] - totalWithdrawn(withdrawal.ValidatorIndex)
+			hasExcessBalance := balance > s.spec.MinActivationBalance
+
+			if validator.ExitEpoch == farFutureEpoch && hasSufficientEffectiveBalance && hasExcessBalance {
+				withdrawals = append(withdrawals, &consensus.Withdrawal{
+					Index:          withdrawalIndex,
+					ValidatorIndex: withdrawal.ValidatorIndex,
+					Address:        executionAddress(validator),
+					Amount:         min(balance-s.spec.MinActivationBalance, withdrawal.Amount),
+				})
+				withdrawalIndex++
+			}
+			processedPartialWithdrawalsCount++
+		}
+	}
+
+	// Sweep for remaining
+	bound := min(uint64(len(validators)), s.spec.MaxValidatorsPerWithdrawalsSweep)
+	for i := uint64(0); i < bound; i++ {
+		validator := validators[validatorIndex]
+		balance := balances[validatorIndex] - totalWithdrawn(validatorIndex)
+
+		if s.isFullyWithdrawableValidator(state, validator, balance, epoch) {
+			withdrawals = append(withdrawals, &consensus.Withdrawal{
+				Index:          withdrawalIndex,
+				ValidatorIndex: validatorIndex,
+				Address:        executionAddress(validator),
+				Amount:         balance,
+			})
+			withdrawalIndex++
+		} else if s.isPartiallyWithdrawableValidator(state, validator, balance) {
+			withdrawals = append(withdrawals, &consensus.Withdrawal{
+				Index:          withdrawalIndex,
+				ValidatorIndex: validatorIndex,
+				Address:        executionAddress(validator),
+				Amount:         balance - s.getMaxEffectiveBalance(state, validator),
+			})
+			withdrawalIndex++
+		}
+		if uint64(len(withdrawals)) == s.spec.MaxWithdrawalsPerPayload {
+			break
+		}
+		validatorIndex = (validatorIndex + 1) % uint64(len(validators))
+	}
+	return withdrawals, processedPartialWithdrawalsCount
+}
+
+func (s *StateTransitioner) processWithdrawals(state *beaconState, payload *consensus.ExecutionPayloadDeneb) error {
+	expectedWithdrawals, processedPartialWithdrawalsCount := s.getExpectedWithdrawals(state)
+
+	if len(payload.Withdrawals) != len(expectedWithdrawals) {
+		return fmt.Errorf("expected %d withdrawals but found %d", len(expectedWithdrawals), len(payload.Withdrawals))
+	}
+	for indx, withdrawal := range expectedWithdrawals {
+		if *payload.Withdrawals[indx] != *withdrawal {
+			return fmt.Errorf("withdrawal %d does not match the expected one", indx)
+		}
+		decreaseBalance(state, withdrawal.ValidatorIndex, withdrawal.Amount)
+	}
+
+	// Update pending partial withdrawals
+	if state.atLeast(consensus.VersionElectra) {
+		*state.pendingPartialWithdrawals = (*state.pendingPartialWithdrawals)[processedPartialWithdrawalsCount:]
+	}
+
+	// Update the next withdrawal index if this block contained withdrawals
+	if len(expectedWithdrawals) != 0 {
+		latestWithdrawal := expectedWithdrawals[len(expectedWithdrawals)-1]
+		*state.nextWithdrawalIndex = latestWithdrawal.Index + 1
+	}
+
+	// Update the next validator index to start the next withdrawal sweep
+	numValidators := uint64(len(*state.validators))
+	if uint64(len(expectedWithdrawals)) == s.spec.MaxWithdrawalsPerPayload {
+		// Next sweep starts after the latest withdrawal's validator index
+		latestWithdrawal := expectedWithdrawals[len(expectedWithdrawals)-1]
+		*state.nextWithdrawalValidatorIndex = (latestWithdrawal.ValidatorIndex + 1) % numValidators
+	} else {
+		// Advance sweep by the max length of the sweep if there was not a full set of withdrawals
+		*state.nextWithdrawalValidatorIndex = (*state.nextWithdrawalValidatorIndex + s.spec.MaxValidatorsPerWithdrawalsSweep) % numValidators
+	}
+	return nil
+}
+
+func (s *StateTransitioner) processBlsToExecutionChange(state *beaconState, signedAddressChange *consensus.SignedBLSToExecutionChange) error {
+	addressChange := signedAddressChange.Message
+
+	validators := *state.validators
+	if addressChange.ValidatorIndex >= uint64(len(validators)) {
+		return fmt.Errorf("validator index %d out of bounds", addressChange.ValidatorIndex)
+	}
+	validator := validators[addressChange.ValidatorIndex]
+
+	if validator.WithdrawalCredentials[0] != blsWithdrawalPrefix {
+		return fmt.Errorf("withdrawal credentials are not a bls key")
+	}
+	pubKeyHash := sha256.Sum256(addressChange.FromBLSPubKey[:])
+	if !bytes.Equal(validator.WithdrawalCredentials[1:], pubKeyHash[1:]) {
+		return fmt.Errorf("withdrawal credentials do not match the bls key")
+	}
+
+	// Fork-agnostic domain since address changes are valid across forks
+	domain, err := consensus.ComputeDomain(consensus.DomainBLSToExecutionChange, s.spec.GenesisForkVersion, *state.genesisValidatorsRoot)
+	if err != nil {
+		return err
+	}
+	signingRoot, err := s.computeSigningRoot(domain, addressChange)
+	if err != nil {
+		return err
+	}
+	ok, err := blsVerify(addressChange.FromBLSPubKey[:], signedAddressChange.Signature[:], signingRoot)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("failed to verify bls to execution change signature")
+	}
+
+	withdrawalCredentials := [32]byte{eth1AddressWithdrawalPrefix}
+	copy(withdrawalCredentials[12:], addressChange.ToExecutionAddress[:])
+	validator.WithdrawalCredentials = withdrawalCredentials
+
+	return nil
+}
@@ -0,0 +1,457 @@
+package spec
+
+import (
+	"sort"
+
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+// UpgradeToAltair converts a phase0 state into an altair state at the fork boundary.
+func (s *StateTransitioner) UpgradeToAltair(pre *consensus.BeaconStatePhase0) (*consensus.BeaconStateAltair, error) {
+	epoch := s.computeEpochAtSlot(pre.Slot)
+
+	post := &consensus.BeaconStateAltair{
+		GenesisTime:           pre.GenesisTime,
+		GenesisValidatorsRoot: pre.GenesisValidatorsRoot,
+		Slot:                  pre.Slot,
+		Fork: &consensus.Fork{
+			PreviousVersion: pre.Fork.CurrentVersion,
+			CurrentVersion:  s.spec.AltairForkVersion,
+			Epoch:           epoch,
+		},
+		// History
+		LatestBlockHeader: pre.LatestBlockHeader,
+		BlockRoots:        pre.BlockRoots,
+		StateRoots:        pre.StateRoots,
+		HistoricalRoots:   pre.HistoricalRoots,
+		// Eth1
+		Eth1Data:         pre.Eth1Data,
+		Eth1DataVotes:    pre.Eth1DataVotes,
+		Eth1DepositIndex: pre.Eth1DepositIndex,
+		// Registry
+		Validators: pre.Validators,
+		Balances:   pre.Balances,
+		// Randomness
+		RandaoMixes: pre.RandaoMixes,
+		// Slashings
+		Slashings: pre.Slashings,
+		// Participation
+		PreviousEpochParticipation: make([]byte, len(pre.Validators)),
+		CurrentEpochParticipation:  make([]byte, len(pre.Validators)),
+		// Finality
+		JustificationBits:           pre.JustificationBits,
+		PreviousJustifiedCheckpoint: pre.PreviousJustifiedCheckpoint,
+		CurrentJustifiedCheckpoint:  pre.CurrentJustifiedCheckpoint,
+		FinalizedCheckpoint:         pre.FinalizedCheckpoint,
+		// Inactivity
+		InactivityScores: make([]uint64, len(pre.Validators)),
+	}
+	state, err := toBeaconState(post)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fill in previous epoch participation from the pre state's pending attestations
+	if err := s.translateParticipation(state, pre.PreviousEpochAttestations); err != nil {
+		return nil, err
+	}
+
+	// Fill in sync committees
+	// Note: A duplicate committee is assigned for the current and next committee at the fork boundary
+	syncCommittee, err := s.getNextSyncCommittee(state)
+	if err != nil {
+		return nil, err
+	}
+	post.CurrentSyncCommittee = syncCommittee
+
+	syncCommittee, err = s.getNextSyncCommittee(state)
+	if err != nil {
+		return nil, err
+	}
+	post.NextSyncCommittee = syncCommittee
+
+	return post, nil
+}
+
+func (s *StateTransitioner) translateParticipation(state *beaconState, pendingAttestations []*consensus.PendingAttestation) error {
+	for _, attestation := range pendingAttestations {
+		data := attestation.Data
+
+		// Translate attestation inclusion info to flag indices
+		participationFlagIndices, err := s.getAttestationParticipationFlagIndices(state, data, attestation.InclusionDelay)
+		if err != nil {
+			return err
+		}
+
+		attestingIndices, err := s.getAttestingIndices(state, data, attestation.AggregationBits)
+		if err != nil {
+			return err
+		}
+
+		// Apply flags to all attesting validators
+		epochParticipation := *state.previousEpochParticipation
+		for _, index := range attestingIndices {
+			for _, flagIndex := range participationFlagIndices {
+				epochParticipation[index] = addFlag(epochParticipation[index], int(flagIndex))
+			}
+		}
+	}
+	return nil
+}
+
+// UpgradeToBellatrix converts an altair state into a bellatrix state at the fork boundary.
+func (s *StateTransitioner) UpgradeToBellatrix(pre *consensus.BeaconStateAltair) *consensus.BeaconStateBellatrix {
+	epoch := s.computeEpochAtSlot(pre.Slot)
+
+	historicalRoots := make([][]byte, len(pre.HistoricalRoots))
+	for i := range pre.HistoricalRoots {
+		historicalRoots[i] = append([]byte{}, pre.HistoricalRoots[i][:]...)
+	}
+
+	return &consensus.BeaconStateBellatrix{
+		GenesisTime:           pre.GenesisTime,
+		GenesisValidatorsRoot: pre.GenesisValidatorsRoot,
+		Slot:                  pre.Slot,
+		Fork: &consensus.Fork{
+			PreviousVersion: pre.Fork.CurrentVersion,
+			CurrentVersion:  s.spec.BellatrixForkVersion,
+			Epoch:           epoch,
+		},
+		// History
+		LatestBlockHeader: pre.LatestBlockHeader,
+		BlockRoots:        pre.BlockRoots,
+		StateRoots:        pre.StateRoots,
+		HistoricalRoots:   historicalRoots,
+		// Eth1
+		Eth1Data:         pre.Eth1Data,
+		Eth1DataVotes:    pre.Eth1DataVotes,
+		Eth1DepositIndex: pre.Eth1DepositIndex,
+		// Registry
+		Validators: pre.Validators,
+		Balances:   pre.Balances,
+		// Randomness
+		RandaoMixes: pre.RandaoMixes,
+		// Slashings
+		Slashings: pre.Slashings,
+		// Participation
+		PreviousEpochParticipation: pre.PreviousEpochParticipation,
+		CurrentEpochParticipation:  pre.CurrentEpochParticipation,
+		// Finality
+		JustificationBits:           pre.JustificationBits,
+		PreviousJustifiedCheckpoint: pre.PreviousJustifiedCheckpoint,
+		CurrentJustifiedCheckpoint:  pre.CurrentJustifiedCheckpoint,
+		FinalizedCheckpoint:         pre.FinalizedCheckpoint,
+		// Inactivity
+		InactivityScores: pre.InactivityScores,
+		// Sync
+		CurrentSyncCommittee: pre.CurrentSyncCommittee,
+		NextSyncCommittee:    pre.NextSyncCommittee,
+		// Execution-layer
+		LatestExecutionPayloadHeader: &consensus.ExecutionPayloadHeader{
+			ExtraData: []byte{},
+		},
+	}
+}
+
+// UpgradeToCapella converts a bellatrix state into a capella state at the fork boundary.
+func (s *StateTransitioner) UpgradeToCapella(pre *consensus.BeaconStateBellatrix) *consensus.BeaconStateCapella {
+	epoch := s.computeEpochAtSlot(pre.Slot)
+
+	header := pre.LatestExecutionPayloadHeader
+	latestExecutionPayloadHeader := &consensus.ExecutionPayloadHeaderCapella{
+		ParentHash:       header.ParentHash,
+		FeeRecipient:     header.FeeRecipient,
+		StateRoot:        header.StateRoot,
+		ReceiptsRoot:     header.ReceiptsRoot,
+		LogsBloom:        header.LogsBloom,
+		PrevRandao:       header.PrevRandao,
+		BlockNumber:      header.BlockNumber,
+		GasLimit:         header.GasLimit,
+		GasUsed:          header.GasUsed,
+		Timestamp:        header.Timestamp,
+		ExtraData:        header.ExtraData,
+		BaseFeePerGas:    header.BaseFeePerGas,
+		BlockHash:        header.BlockHash,
+		TransactionsRoot: header.TransactionsRoot,
+	}
+
+	return &consensus.BeaconStateCapella{
+		GenesisTime:           pre.GenesisTime,
+		GenesisValidatorsRoot: pre.GenesisValidatorsRoot,
+		Slot:                  pre.Slot,
+		Fork: &consensus.Fork{
+			PreviousVersion: pre.Fork.CurrentVersion,
+			CurrentVersion:  s.spec.CapellaForkVersion,
+			Epoch:           epoch,
+		},
+		// History
+		LatestBlockHeader: pre.LatestBlockHeader,
+		BlockRoots:        pre.BlockRoots,
+		StateRoots:        pre.StateRoots,
+		HistoricalRoots:   pre.HistoricalRoots,
+		// Eth1
+		Eth1Data:         pre.Eth1Data,
+		Eth1DataVotes:    pre.Eth1DataVotes,
+		Eth1DepositIndex: pre.Eth1DepositIndex,
+		// Registry
+		Validators: pre.Validators,
+		Balances:   pre.Balances,
+		// Randomness
+		RandaoMixes: pre.RandaoMixes,
+		// Slashings
+		Slashings: pre.Slashings,
+		// Participation
+		PreviousEpochParticipation: pre.PreviousEpochParticipation,
+		CurrentEpochParticipation:  pre.CurrentEpochParticipation,
+		// Finality
+		JustificationBits:           pre.JustificationBits,
+		PreviousJustifiedCheckpoint: pre.PreviousJustifiedCheckpoint,
+		CurrentJustifiedCheckpoint:  pre.CurrentJustifiedCheckpoint,
+		FinalizedCheckpoint:         pre.FinalizedCheckpoint,
+		// Inactivity
+		InactivityScores: pre.InactivityScores,
+		// Sync
+		CurrentSyncCommittee: pre.CurrentSyncCommittee,
+		NextSyncCommittee:    pre.NextSyncCommittee,
+		// Execution-layer
+		LatestExecutionPayloadHeader: latestExecutionPayloadHeader,
+		// Withdrawals
+		NextWithdrawalIndex:          0,
+		NextWithdrawalValidatorIndex: 0,
+		// Deep history valid from Capella onwards
+		HistoricalSummaries: []*consensus.HistoricalSummary{},
+	}
+}
+
+// UpgradeToDeneb converts a capella state into a deneb state at the fork boundary.
+func (s *StateTransitioner) UpgradeToDeneb(pre *consensus.BeaconStateCapella) *consensus.BeaconStateDeneb {
+	epoch := s.computeEpochAtSlot(pre.Slot)
+
+	header := pre.LatestExecutionPayloadHeader
+	latestExecutionPayloadHeader := &consensus.ExecutionPayloadHeaderDeneb{
+		ParentHash:       header.ParentHash,
+		FeeRecipient:     header.FeeRecipient,
+		StateRoot:        header.StateRoot,
+		ReceiptsRoot:     header.ReceiptsRoot,
+		LogsBloom:        header.LogsBloom,
+		PrevRandao:       header.PrevRandao,
+		BlockNumber:      header.BlockNumber,
+		GasLimit:         header.GasLimit,
+		GasUsed:          header.GasUsed,
+		Timestamp:        header.Timestamp,
+		ExtraData:        header.ExtraData,
+		BaseFeePerGas:    header.BaseFeePerGas,
+		BlockHash:        header.BlockHash,
+		TransactionsRoot: header.TransactionsRoot,
+		WithdrawalRoot:   header.WithdrawalRoot,
+		BlobGasUsed:      0,
+		ExcessBlobGas:    0,
+	}
+
+	return &consensus.BeaconStateDeneb{
+		GenesisTime:           pre.GenesisTime,
+		GenesisValidatorsRoot: pre.GenesisValidatorsRoot,
+		Slot:                  pre.Slot,
+		Fork: &consensus.Fork{
+			PreviousVersion: pre.Fork.CurrentVersion,
+			CurrentVersion:  s.spec.DenebForkVersion,
+			Epoch:           epoch,
+		},
+		// History
+		LatestBlockHeader: pre.LatestBlockHeader,
+		BlockRoots:        pre.BlockRoots,
+		StateRoots:        pre.StateRoots,
+		HistoricalRoots:   pre.HistoricalRoots,
+		// Eth1
+		Eth1Data:         pre.Eth1Data,
+		Eth1DataVotes:    pre.Eth1DataVotes,
+		Eth1DepositIndex: pre.Eth1DepositIndex,
+		// Registry
+		Validators: pre.Validators,
+		Balances:   pre.Balances,
+		// Randomness
+		RandaoMixes: pre.RandaoMixes,
+		// Slashings
+		Slashings: pre.Slashings,
+		// Participation
+		PreviousEpochParticipation: pre.PreviousEpochParticipation,
+		CurrentEpochParticipation:  pre.CurrentEpochParticipation,
+		// Finality
+		JustificationBits:           pre.JustificationBits,
+		PreviousJustifiedCheckpoint: pre.PreviousJustifiedCheckpoint,
+		CurrentJustifiedCheckpoint:  pre.CurrentJustifiedCheckpoint,
+		FinalizedCheckpoint:         pre.FinalizedCheckpoint,
+		// Inactivity
+		InactivityScores: pre.InactivityScores,
+		// Sync
+		CurrentSyncCommittee: pre.CurrentSyncCommittee,
+		NextSyncCommittee:    pre.NextSyncCommittee,
+		// Execution-layer
+		LatestExecutionPayloadHeader: latestExecutionPayloadHeader,
+		// Withdrawals
+		NextWithdrawalIndex:          pre.NextWithdrawalIndex,
+		NextWithdrawalValidatorIndex: pre.NextWithdrawalValidatorIndex,
+		// Deep history valid from Capella onwards
+		HistoricalSummaries: pre.HistoricalSummaries,
+	}
+}
+
+// UpgradeToElectra converts a deneb state into an electra state at the fork boundary.
+func (s *StateTransitioner) UpgradeToElectra(pre *consensus.BeaconStateDeneb) (*consensus.BeaconStateElectra, error) {
+	epoch := s.computeEpochAtSlot(pre.Slot)
+
+	earliestExitEpoch := s.computeActivationExitEpoch(epoch)
+	for _, validator := range pre.Validators {
+		if validator.ExitEpoch != farFutureEpoch && validator.ExitEpoch > earliestExitEpoch {
+			earliestExitEpoch = validator.ExitEpoch
+		}
+	}
+	earliestExitEpoch++
+
+	post := &consensus.BeaconStateElectra{
+		GenesisTime:           pre.GenesisTime,
+		GenesisValidatorsRoot: pre.GenesisValidatorsRoot,
+		Slot:                  pre.Slot,
+		Fork: &consensus.Fork{
+			PreviousVersion: pre.Fork.CurrentVersion,
+			CurrentVersion:  s.spec.ElectraForkVersion,
+			Epoch:           epoch,
+		},
+		// History
+		LatestBlockHeader: pre.LatestBlockHeader,
+		BlockRoots:        pre.BlockRoots,
+		StateRoots:        pre.StateRoots,
+		HistoricalRoots:   pre.HistoricalRoots,
+		// Eth1
+		Eth1Data:         pre.Eth1Data,
+		Eth1DataVotes:    pre.Eth1DataVotes,
+		Eth1DepositIndex: pre.Eth1DepositIndex,
+		// Registry
+		Validators: pre.Validators,
+		Balances:   pre.Balances,
+		// Randomness
+		RandaoMixes: pre.RandaoMixes,
+		// Slashings
+		Slashings: pre.Slashings,
+		// Participation
+		PreviousEpochParticipation: pre.PreviousEpochParticipation,
+		CurrentEpochParticipation:  pre.CurrentEpochParticipation,
+		// Finality
+		JustificationBits:           pre.JustificationBits,
+		PreviousJustifiedCheckpoint: pre.PreviousJustifiedCheckpoint,
+		CurrentJustifiedCheckpoint:  pre.CurrentJustifiedCheckpoint,
+		FinalizedCheckpoint:         pre.FinalizedCheckpoint,
+		// Inactivity
+		InactivityScores: pre.InactivityScores,
+		// Sync
+		CurrentSyncCommittee: pre.CurrentSyncCommittee,
+		NextSyncCommittee:    pre.NextSyncCommittee,
+		// Execution-layer
+		LatestExecutionPayloadHeader: pre.LatestExecutionPayloadHeader,
+		// Withdrawals
+		NextWithdrawalIndex:          pre.NextWithdrawalIndex,
+		NextWithdrawalValidatorIndex: pre.NextWithdrawalValidatorIndex,
+		// Deep history valid from Capella onwards
+		HistoricalSummaries: pre.HistoricalSummaries,
+		// Execution requests
+		DepositRequestsStartIndex: unsetDepositRequestsStartIndex,
+		// Balance churn
+		DepositBalanceToConsume:       0,
+		ExitBalanceToConsume:          0,
+		EarliestExitEpoch:             earliestExitEpoch,
+		ConsolidationBalanceToConsume: 0,
+		EarliestConsolidationEpoch:    s.computeActivationExitEpoch(epoch),
+		PendingDeposits:               []*consensus.PendingDeposit{},
+		PendingPartialWithdrawals:     []*consensus.PendingPartialWithdrawal{},
+		PendingConsolidations:         []*consensus.PendingConsolidation{},
+	}
+	state, err := toBeaconState(post)
+	if err != nil {
+		return nil, err
+	}
+
+	post.ExitBalanceToConsume = s.getActivationExitChurnLimit(state)
+	post.ConsolidationBalanceToConsume = s.getConsolidationChurnLimit(state)
+
+	// Add validators that are not yet active to the pending deposits
+	preActivation := []uint64{}
+	for index, validator := range post.Validators {
+		if validator.ActivationEpoch == farFutureEpoch {
+			preActivation = append(preActivation, uint64(index))
+		}
+	}
+	sort.SliceStable(preActivation, func(i, j int) bool {
+		return post.Validators[preActivation[i]].ActivationEligibilityEpoch < post.Validators[preActivation[j]].ActivationEligibilityEpoch
+	})
+
+	for _, index := range preActivation {
+		balance := post.Balances[index]
+		post.Balances[index] = 0
+
+		validator := post.Validators[index]
+		validator.EffectiveBalance = 0
+		validator.ActivationEligibilityEpoch = farFutureEpoch
+
+		// Use bls.G2_POINT_AT_INFINITY as a signature field placeholder and
+		// GENESIS_SLOT to distinguish from a pending deposit request
+		post.PendingDeposits = append(post.PendingDeposits, &consensus.PendingDeposit{
+			Pubkey:                validator.Pubkey,
+			WithdrawalCredentials: validator.WithdrawalCredentials,
+			Amount:                balance,
+			Signature:             consensus.Signature{0xc0},
+			Slot:                  s.spec.GenesisSlot,
+		})
+	}
+
+	// Ensure early adopters of compounding credentials go through the activation churn
+	for index, validator := range post.Validators {
+		if hasCompoundingWithdrawalCredential(validator) {
+			s.queueExcessActiveBalance(state, uint64(index))
+		}
+	}
+	return post, nil
+}
+
+// upgradeState upgrades the state to the forks scheduled at the epoch
+// of the state. More than one fork can be scheduled at the same epoch.
+func (s *StateTransitioner) upgradeState(state *beaconState) (*beaconState, error) {
+	epoch := s.getCurrentEpoch(state)
+
+	for {
+		var (
+			post consensus.BeaconState
+			err  error
+		)
+		switch obj := state.obj.(type) {
+		case *consensus.BeaconStatePhase0:
+			if epoch == s.spec.AltairForkEpoch {
+				post, err = s.UpgradeToAltair(obj)
+			}
+		case *consensus.BeaconStateAltair:
+			if epoch == s.spec.BellatrixForkEpoch {
+				post = s.UpgradeToBellatrix(obj)
+			}
+		case *consensus.BeaconStateBellatrix:
+			if epoch == s.spec.CapellaForkEpoch {
+				post = s.UpgradeToCapella(obj)
+			}
+		case *consensus.BeaconStateCapella:
+			if epoch == s.spec.DenebForkEpoch {
+				post = s.UpgradeToDeneb(obj)
+			}
+		case *consensus.BeaconStateDeneb:
+			if epoch == s.spec.ElectraForkEpoch {
+				post, err = s.UpgradeToElectra(obj)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if post == nil {
+			return state, nil
+		}
+		if state, err = toBeaconState(post); err != nil {
+			return nil, err
+		}
+	}
+}
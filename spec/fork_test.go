@@ -0,0 +1,77 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+func TestForkUpgrade(t *testing.T) {
+	t.Run("altair", func(t *testing.T) {
+		listTestData(t, "altair/fork/fork/pyspec_tests/*", func(th *testHandler) {
+			pre, post := &consensus.BeaconStatePhase0{}, &consensus.BeaconStateAltair{}
+			th.decodeFile("pre", pre)
+			th.decodeFile("post", post)
+
+			res, err := th.transitioner.UpgradeToAltair(pre)
+			require.NoError(t, err)
+
+			if !reflect.DeepEqual(res, post) {
+				t.Fatalf("bad: %s", th.path)
+			}
+		})
+	})
+
+	t.Run("bellatrix", func(t *testing.T) {
+		listTestData(t, "bellatrix/fork/fork/pyspec_tests/*", func(th *testHandler) {
+			pre, post := &consensus.BeaconStateAltair{}, &consensus.BeaconStateBellatrix{}
+			th.decodeFile("pre", pre)
+			th.decodeFile("post", post)
+
+			if !reflect.DeepEqual(th.transitioner.UpgradeToBellatrix(pre), post) {
+				t.Fatalf("bad: %s", th.path)
+			}
+		})
+	})
+
+	t.Run("capella", func(t *testing.T) {
+		listTestData(t, "capella/fork/fork/pyspec_tests/*", func(th *testHandler) {
+			pre, post := &consensus.BeaconStateBellatrix{}, &consensus.BeaconStateCapella{}
+			th.decodeFile("pre", pre)
+			th.decodeFile("post", post)
+
+			if !reflect.DeepEqual(th.transitioner.UpgradeToCapella(pre), post) {
+				t.Fatalf("bad: %s", th.path)
+			}
+		})
+	})
+
+	t.Run("deneb", func(t *testing.T) {
+		listTestData(t, "deneb/fork/fork/pyspec_tests/*", func(th *testHandler) {
+			pre, post := &consensus.BeaconStateCapella{}, &consensus.BeaconStateDeneb{}
+			th.decodeFile("pre", pre)
+			th.decodeFile("post", post)
+
+			if !reflect.DeepEqual(th.transitioner.UpgradeToDeneb(pre), post) {
+				t.Fatalf("bad: %s", th.path)
+			}
+		})
+	})
+
+	t.Run("electra", func(t *testing.T) {
+		listTestData(t, "electra/fork/fork/pyspec_tests/*", func(th *testHandler) {
+			pre, post := &consensus.BeaconStateDeneb{}, &consensus.BeaconStateElectra{}
+			th.decodeFile("pre", pre)
+			th.decodeFile("post", post)
+
+			res, err := th.transitioner.UpgradeToElectra(pre)
+			require.NoError(t, err)
+
+			if !reflect.DeepEqual(res, post) {
+				t.Fatalf("bad: %s", th.path)
+			}
+		})
+	})
+}
@@ -9,80 +9,261 @@ import (
 	"sort"
 
 	consensus "github.com/umbracle/go-eth-consensus"
+	"github.com/umbracle/go-eth-consensus/bitlist"
 	"github.com/umbracle/go-eth-consensus/bls"
-	"github.com/umbracle/go-eth-consensus/deposit"
 )
 
-func ProcessAttestation(state *consensus.BeaconStatePhase0, attestation *consensus.Attestation) error {
-	data := attestation.Data
+func (s *StateTransitioner) ProcessAttestation(state consensus.BeaconState, attestation *consensus.Attestation) error {
+	obj, err := toBeaconState(state)
+	if err != nil {
+		return err
+	}
+	if obj.atLeast(consensus.VersionElectra) {
+		return fmt.Errorf("beacon state %T uses the electra attestations", state)
+	}
+	return s.processAttestation(obj, attestation)
+}
 
-	if data.Target.Epoch != getPreviousEpoch(state) && data.Target.Epoch != getCurrentEpoch(state) {
+// ProcessAttestationElectra processes an attestation of the electra fork, which
+// aggregates the votes of multiple committees of the same slot.
+func (s *StateTransitioner) ProcessAttestationElectra(state consensus.BeaconState, attestation *consensus.AttestationElectra) error {
+	obj, err := toBeaconState(state)
+	if err != nil {
+		return err
+	}
+	if !obj.atLeast(consensus.VersionElectra) {
+		return fmt.Errorf("beacon state %T does not use the electra attestations", state)
+	}
+	return s.processAttestationElectra(obj, attestation)
+}
+
+func (s *StateTransitioner) validateAttestationData(state *beaconState, data *consensus.AttestationData) error {
+	if data.Target.Epoch != s.getPreviousEpoch(state) && data.Target.Epoch != s.getCurrentEpoch(state) {
 		return fmt.Errorf("one")
 	}
-	if data.Target.Epoch != computeEpochAtSlot(data.Slot) {
+	if data.Target.Epoch != s.computeEpochAtSlot(data.Slot) {
 		return fmt.Errorf("two")
 	}
 
-	if !(state.Slot <= data.Slot+Spec.SlotsPerEpoch) {
+	// the attestations of the previous epoch are valid until the end of the current one since deneb
+	if !state.atLeast(consensus.VersionDeneb) && !(*state.slot <= data.Slot+s.spec.SlotsPerEpoch) {
 		return fmt.Errorf("attestation slot is too old")
 	}
-	if !(data.Slot+Spec.MinAttestationInclusionDelay <= state.Slot) {
+	if !(data.Slot+s.spec.MinAttestationInclusionDelay <= *state.slot) {
 		return fmt.Errorf("attestation is too new")
 	}
+	return nil
+}
+
+func (s *StateTransitioner) processAttestation(state *beaconState, attestation *consensus.Attestation) error {
+	data := attestation.Data
 
-	if data.Index >= getCommitteeCountPerSlot(state, data.Target.Epoch) {
+	if err := s.validateAttestationData(state, data); err != nil {
+		return err
+	}
+	if data.Index >= s.getCommitteeCountPerSlot(state, data.Target.Epoch) {
 		return fmt.Errorf("ten")
 	}
 
-	proposerIndex := getBeaconProposerIndex(state)
+	if !state.atLeast(consensus.VersionAltair) {
+		return s.processAttestationPhase0(state, attestation)
+	}
+
+	attestingIndices, err := s.getAttestingIndices(state, data, attestation.AggregationBits)
+	if err != nil {
+		return err
+	}
+	return s.processAttestationAltair(state, data, attestingIndices, attestation.Signature)
+}
+
+func (s *StateTransitioner) processAttestationElectra(state *beaconState, attestation *consensus.AttestationElectra) error {
+	data := attestation.Data
+
+	if err := s.validateAttestationData(state, data); err != nil {
+		return err
+	}
+	if data.Index != 0 {
+		return fmt.Errorf("attestation data index %d is not zero", data.Index)
+	}
+
+	committeeCount := s.getCommitteeCountPerSlot(state, data.Target.Epoch)
+	for _, committeeIndex := range s.getCommitteeIndices(attestation.CommitteeBits) {
+		if committeeIndex >= committeeCount {
+			return fmt.Errorf("committee index %d out of range", committeeIndex)
+		}
+	}
+
+	attestingIndices, err := s.getAttestingIndicesElectra(state, attestation)
+	if err != nil {
+		return err
+	}
+	return s.processAttestationAltair(state, data, attestingIndices, attestation.Signature)
+}
+
+func (s *StateTransitioner) processAttestationPhase0(state *beaconState, attestation *consensus.Attestation) error {
+	data := attestation.Data
+	proposerIndex := s.getBeaconProposerIndex(state)
 
 	pendingAttestation := &consensus.PendingAttestation{
 		Data:            data,
 		AggregationBits: attestation.AggregationBits,
-		InclusionDelay:  state.Slot - data.Slot,
+		InclusionDelay:  *state.slot - data.Slot,
 		ProposerIndex:   proposerIndex,
 	}
 
-	if data.Target.Epoch == getCurrentEpoch(state) {
-		if *data.Source != *state.CurrentJustifiedCheckpoint {
+	if data.Target.Epoch == s.getCurrentEpoch(state) {
+		if *data.Source != **state.currentJustifiedCheckpoint {
 			return fmt.Errorf("three")
 		}
-		state.CurrentEpochAttestations = append(state.CurrentEpochAttestations, pendingAttestation)
+		*state.currentEpochAttestations = append(*state.currentEpochAttestations, pendingAttestation)
 	} else {
-		if *data.Source != *state.PreviousJustifiedCheckpoint {
+		if *data.Source != **state.previousJustifiedCheckpoint {
 			return fmt.Errorf("four")
 		}
-		state.PreviousEpochAttestations = append(state.PreviousEpochAttestations, pendingAttestation)
+		*state.previousEpochAttestations = append(*state.previousEpochAttestations, pendingAttestation)
 	}
 
-	indexedAtt, err := getIndexedAttestation(state, attestation)
+	indexedAtt, err := s.getIndexedAttestation(state, attestation)
 	if err != nil {
 		return err
 	}
-	if err := isValidIndexedAttestation(state, indexedAtt); err != nil {
+	if err := s.isValidIndexedAttestation(state, indexedAtt); err != nil {
 		return err
 	}
 	return nil
 }
 
-func getIndexedAttestation(state *consensus.BeaconStatePhase0, attestation *consensus.Attestation) (*consensus.IndexedAttestation, error) {
-	attestingIndices, err := getAttestingIndices(state, attestation.Data, attestation.AggregationBits)
+func (s *StateTransitioner) processAttestationAltair(state *beaconState, data *consensus.AttestationData, attestingIndices []uint64, signature consensus.Signature) error {
+	// Participation flag indices
+	participationFlagIndices, err := s.getAttestationParticipationFlagIndices(state, data, *state.slot-data.Slot)
+	if err != nil {
+		return err
+	}
+
+	// Verify signature
+	if err := s.isValidIndexedAttestation(state, newIndexedAttestation(attestingIndices, data, signature)); err != nil {
+		return err
+	}
+
+	// Update epoch participation flags
+	var epochParticipation []byte
+	if data.Target.Epoch == s.getCurrentEpoch(state) {
+		epochParticipation = *state.currentEpochParticipation
+	} else {
+		epochParticipation = *state.previousEpochParticipation
+	}
+
+	proposerRewardNumerator := uint64(0)
+	for _, index := range attestingIndices {
+		for flagIndex, weight := range participationFlagWeights {
+			if contains(participationFlagIndices, uint64(flagIndex)) && !hasFlag(epochParticipation[index], flagIndex) {
+				epochParticipation[index] = addFlag(epochParticipation[index], flagIndex)
+				proposerRewardNumerator += s.getBaseReward(state, index) * weight
+			}
+		}
+	}
+
+	// Reward proposer
+	proposerRewardDenominator := (weightDenominator - proposerWeight) * weightDenominator / proposerWeight
+	increaseBalance(state, s.getBeaconProposerIndex(state), proposerRewardNumerator/proposerRewardDenominator)
+
+	return nil
+}
+
+// getCommitteeIndices returns the indices of the committees set in the committee bits
+func (s *StateTransitioner) getCommitteeIndices(committeeBits [8]byte) []uint64 {
+	indices := []uint64{}
+	for i := uint64(0); i < s.spec.MaxCommitteesPerSlot; i++ {
+		if committeeBits[i/8]&(1<<(i%8)) != 0 {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// getAttestingIndicesElectra returns the attesters of the committees of the attestation. The
+// aggregation bits are the concatenation of the bits of every committee.
+func (s *StateTransitioner) getAttestingIndicesElectra(state *beaconState, attestation *consensus.AttestationElectra) ([]uint64, error) {
+	blist := bitlist.BitList(attestation.AggregationBits)
+
+	res := []uint64{}
+	committeeOffset := uint64(0)
+	for _, committeeIndex := range s.getCommitteeIndices(attestation.CommitteeBits) {
+		committee := s.getBeaconCommittee(state, attestation.Data.Slot, committeeIndex)
+
+		committeeAttesters := 0
+		for indx, c := range committee {
+			if blist.BitAt(committeeOffset + uint64(indx)) {
+				res = append(res, c)
+				committeeAttesters++
+			}
+		}
+		if committeeAttesters == 0 {
+			return nil, fmt.Errorf("committee %d has no attesters", committeeIndex)
+		}
+		committeeOffset += uint64(len(committee))
+	}
+
+	// Bitfield length matches total number of participants
+	if blist.Len() != committeeOffset {
+		return nil, fmt.Errorf("aggregation bits length %d does not match the committees size %d", blist.Len(), committeeOffset)
+	}
+	return res, nil
+}
+
+func (s *StateTransitioner) getAttestationParticipationFlagIndices(state *beaconState, data *consensus.AttestationData, inclusionDelay uint64) ([]uint64, error) {
+	var justifiedCheckpoint *consensus.Checkpoint
+	if data.Target.Epoch == s.getCurrentEpoch(state) {
+		justifiedCheckpoint = *state.currentJustifiedCheckpoint
+	} else {
+		justifiedCheckpoint = *state.previousJustifiedCheckpoint
+	}
+
+	// Matching roots
+	isMatchingSource := *data.Source == *justifiedCheckpoint
+	isMatchingTarget := isMatchingSource && data.Target.Root == s.getBlockRoot(state, data.Target.Epoch)
+	isMatchingHead := isMatchingTarget && data.BeaconBlockHash == s.getBlockRootAtSlot(state, data.Slot)
+
+	if !isMatchingSource {
+		return nil, fmt.Errorf("attestation source does not match the justified checkpoint")
+	}
+
+	participationFlagIndices := []uint64{}
+	if isMatchingSource && inclusionDelay <= integerSquareRoot(s.spec.SlotsPerEpoch) {
+		participationFlagIndices = append(participationFlagIndices, timelySourceFlagIndex)
+	}
+	// the target vote is timely for any inclusion delay since deneb
+	if isMatchingTarget && (state.atLeast(consensus.VersionDeneb) || inclusionDelay <= s.spec.SlotsPerEpoch) {
+		participationFlagIndices = append(participationFlagIndices, timelyTargetFlagIndex)
+	}
+	if isMatchingHead && inclusionDelay == s.spec.MinAttestationInclusionDelay {
+		participationFlagIndices = append(participationFlagIndices, timelyHeadFlagIndex)
+	}
+	return participationFlagIndices, nil
+}
+
+func (s *StateTransitioner) getIndexedAttestation(state *beaconState, attestation *consensus.Attestation) (*consensus.IndexedAttestation, error) {
+	attestingIndices, err := s.getAttestingIndices(state, attestation.Data, attestation.AggregationBits)
 	if err != nil {
 		return nil, err
 	}
+	return newIndexedAttestation(attestingIndices, attestation.Data, attestation.Signature), nil
+}
 
-	sort.Slice(attestingIndices, func(i, j int) bool {
-		return attestingIndices[i] < attestingIndices[j]
+func newIndexedAttestation(attestingIndices []uint64, data *consensus.AttestationData, signature consensus.Signature) *consensus.IndexedAttestation {
+	indices := append([]uint64{}, attestingIndices...)
+	sort.Slice(indices, func(i, j int) bool {
+		return indices[i] < indices[j]
 	})
 
 	return &consensus.IndexedAttestation{
-		AttestationIndices: attestingIndices,
-		Data:               attestation.Data,
-		Signature:          attestation.Signature,
-	}, nil
+		AttestationIndices: indices,
+		Data:               data,
+		Signature:          signature,
+	}
 }
 
-func isValidIndexedAttestation(state *consensus.BeaconStatePhase0, indexedAttestation *consensus.IndexedAttestation) error {
+func (s *StateTransitioner) isValidIndexedAttestation(state *beaconState, indexedAttestation *consensus.IndexedAttestation) error {
 	indices := indexedAttestation.AttestationIndices
 
 	// the attestation cannot be empty
@@ -106,25 +287,26 @@ func isValidIndexedAttestation(state *consensus.BeaconStatePhase0, indexedAttest
 	}
 
 	// check if the indices are inside the bounds of the validator set
-	if indices[len(indices)-1] >= uint64(len(state.Validators)) {
+	validators := *state.validators
+	if indices[len(indices)-1] >= uint64(len(validators)) {
 		return fmt.Errorf("validators out of bounds")
 	}
 
 	pubKeys := []*bls.PublicKey{}
 	for _, i := range indices {
 		pub := new(bls.PublicKey)
-		if err := pub.Deserialize(state.Validators[i].Pubkey[:]); err != nil {
+		if err := pub.Deserialize(validators[i].Pubkey[:]); err != nil {
 			return err
 		}
 		pubKeys = append(pubKeys, pub)
 	}
 
-	domain, err := getDomain(consensus.DomainBeaconAttesterType, state, &indexedAttestation.Data.Target.Epoch)
+	domain, err := s.getDomain(consensus.DomainBeaconAttesterType, state, &indexedAttestation.Data.Target.Epoch)
 	if err != nil {
 		return err
 	}
 
-	root, err := consensus.ComputeSigningRoot(domain, indexedAttestation.Data)
+	root, err := s.computeSigningRoot(domain, indexedAttestation.Data)
 	if err != nil {
 		return err
 	}
@@ -159,7 +341,48 @@ func isSlashableAttestationData(d1, d2 *consensus.AttestationData) (bool, error)
 	return (hash1 != hash2 && d1.Target.Epoch == d2.Target.Epoch) || (d1.Source.Epoch < d2.Source.Epoch && d2.Target.Epoch < d1.Target.Epoch), nil
 }
 
-func ProcessAttesterSlashing(state *consensus.BeaconStatePhase0, attesterSlashing *consensus.AttesterSlashing) error {
+func (s *StateTransitioner) ProcessAttesterSlashing(state consensus.BeaconState, attesterSlashing *consensus.AttesterSlashing) error {
+	obj, err := toBeaconState(state)
+	if err != nil {
+		return err
+	}
+	return s.processAttesterSlashing(obj, attesterSlashing)
+}
+
+// ProcessAttesterSlashingElectra processes an attester slashing of the electra fork.
+func (s *StateTransitioner) ProcessAttesterSlashingElectra(state consensus.BeaconState, attesterSlashing *consensus.AttesterSlashingElectra) error {
+	obj, err := toBeaconState(state)
+	if err != nil {
+		return err
+	}
+	return s.processAttesterSlashing(obj, toAttesterSlashing(attesterSlashing))
+}
+
+// toAttesterSlashing converts an electra attester slashing, which only differs
+// in the size limit of the attesting indices, into the phase0 one.
+func toAttesterSlashing(attesterSlashing *consensus.AttesterSlashingElectra) *consensus.AttesterSlashing {
+	toIndexedAttestation := func(att *consensus.IndexedAttestationElectra) *consensus.IndexedAttestation {
+		return &consensus.IndexedAttestation{
+			AttestationIndices: att.AttestationIndices,
+			Data:               att.Data,
+			Signature:          att.Signature,
+		}
+	}
+	return &consensus.AttesterSlashing{
+		Attestation1: toIndexedAttestation(attesterSlashing.Attestation1),
+		Attestation2: toIndexedAttestation(attesterSlashing.Attestation2),
+	}
+}
+
+func toAttesterSlashings(attesterSlashings []*consensus.AttesterSlashingElectra) []*consensus.AttesterSlashing {
+	res := make([]*consensus.AttesterSlashing, 0, len(attesterSlashings))
+	for _, attesterSlashing := range attesterSlashings {
+		res = append(res, toAttesterSlashing(attesterSlashing))
+	}
+	return res
+}
+
+func (s *StateTransitioner) processAttesterSlashing(state *beaconState, attesterSlashing *consensus.AttesterSlashing) error {
 	att1 := attesterSlashing.Attestation1
 	att2 := attesterSlashing.Attestation2
 
@@ -172,11 +395,11 @@ func ProcessAttesterSlashing(state *consensus.BeaconStatePhase0, attesterSlashin
 	}
 
 	// Check if attestation 1 is valid
-	if err := isValidIndexedAttestation(state, att1); err != nil {
+	if err := s.isValidIndexedAttestation(state, att1); err != nil {
 		return fmt.Errorf("failed to validate attestation 1: %v", err)
 	}
 	// Check if attestation 2 is valid
-	if err := isValidIndexedAttestation(state, att2); err != nil {
+	if err := s.isValidIndexedAttestation(state, att2); err != nil {
 		return fmt.Errorf("failed to validate attestation 2: %v", err)
 	}
 
@@ -188,8 +411,8 @@ func ProcessAttesterSlashing(state *consensus.BeaconStatePhase0, attesterSlashin
 	})
 
 	for _, index := range indices {
-		if isSlashableValidator(state.Validators[index], getCurrentEpoch(state)) {
-			if err := slashValidator(state, index, nil); err != nil {
+		if isSlashableValidator((*state.validators)[index], s.getCurrentEpoch(state)) {
+			if err := s.slashValidator(state, index, nil); err != nil {
 				return err
 			}
 			slashedAny = true
@@ -218,47 +441,72 @@ func intersection(a, b []uint64) (res []uint64) {
 	return
 }
 
-func computeProposerIndex(state *consensus.BeaconStatePhase0, indices []uint64, seed [32]byte) uint64 {
+func (s *StateTransitioner) computeProposerIndex(state *beaconState, indices []uint64, seed [32]byte) uint64 {
 	if len(indices) == 0 {
 		panic(fmt.Errorf("must have >0 indices"))
 	}
-	maxRandomByte := uint64(1<<8 - 1)
+	validators := *state.validators
+
 	i := uint64(0)
 	total := uint64(len(indices))
-	hash := sha256.New()
-	buf := make([]byte, 8)
 	for {
-		shuffled := computeShuffleIndex(i%total, total, seed)
+		shuffled := s.computeShuffleIndex(i%total, total, seed)
 
 		candidateIndex := indices[shuffled]
-		if candidateIndex >= uint64(len(state.Validators)) {
-			panic(fmt.Errorf("candidate index out of range: %d for validator set of length: %d", candidateIndex, len(state.Validators)))
+		if candidateIndex >= uint64(len(validators)) {
+			panic(fmt.Errorf("candidate index out of range: %d for validator set of length: %d", candidateIndex, len(validators)))
 		}
-		binary.LittleEndian.PutUint64(buf, i/32)
-		input := append(seed[:], buf...)
-		hash.Reset()
-		hash.Write(input)
-		randomByte := uint64(hash.Sum(nil)[i%32])
-		effectiveBalance := state.Validators[candidateIndex].EffectiveBalance
-		if effectiveBalance*maxRandomByte >= Spec.MaxEffectiveBalance*randomByte {
+		if s.isSelectedCandidate(state, seed, i, validators[candidateIndex].EffectiveBalance) {
 			return candidateIndex
 		}
 		i += 1
 	}
 }
 
-func getEpochAtSlot(slot uint64) uint64 {
-	return slot / Spec.SlotsPerEpoch
+// isSelectedCandidate runs the sampling weighted by the effective balance used to pick
+// the proposer and the sync committee members. The i-th candidate is selected if its
+// effective balance is over a random fraction of the max effective balance.
+func (s *StateTransitioner) isSelectedCandidate(state *beaconState, seed [32]byte, i uint64, effectiveBalance uint64) bool {
+	buf := make([]byte, 8)
+
+	if state.atLeast(consensus.VersionElectra) {
+		// electra uses 16 bit random values
+		maxRandomValue := uint64(1<<16 - 1)
+
+		binary.LittleEndian.PutUint64(buf, i/16)
+		randomBytes := sha256.Sum256(append(seed[:], buf...))
+		offset := i % 16 * 2
+		randomValue := uint64(binary.LittleEndian.Uint16(randomBytes[offset : offset+2]))
+
+		return effectiveBalance*maxRandomValue >= s.spec.MaxEffectiveBalanceElectra*randomValue
+	}
+
+	maxRandomByte := uint64(1<<8 - 1)
+
+	binary.LittleEndian.PutUint64(buf, i/32)
+	randomBytes := sha256.Sum256(append(seed[:], buf...))
+	randomByte := uint64(randomBytes[i%32])
+
+	return effectiveBalance*maxRandomByte >= s.spec.MaxEffectiveBalance*randomByte
+}
+
+func (s *StateTransitioner) getEpochAtSlot(slot uint64) uint64 {
+	return slot / s.spec.SlotsPerEpoch
+}
+
+func (s *StateTransitioner) getBeaconProposerIndex(state *beaconState) uint64 {
+	return s.getBeaconProposerIndexAtSlot(state, *state.slot)
 }
 
-func getBeaconProposerIndex(state *consensus.BeaconStatePhase0) uint64 {
-	epoch := getEpochAtSlot(state.Slot)
+// getBeaconProposerIndexAtSlot returns the proposer of a slot in the epoch of the state
+func (s *StateTransitioner) getBeaconProposerIndexAtSlot(state *beaconState, slot uint64) uint64 {
+	epoch := s.getEpochAtSlot(slot)
 
 	hash := sha256.New()
 	// Input for the seed hash.
-	input := getSeed(state, epoch, consensus.DomainBeaconProposerType)
+	input := s.getSeed(state, epoch, consensus.DomainBeaconProposerType)
 	slotByteArray := make([]byte, 8)
-	binary.LittleEndian.PutUint64(slotByteArray, state.Slot)
+	binary.LittleEndian.PutUint64(slotByteArray, slot)
 
 	// Add slot to the end of the input.
 	inputWithSlot := append(input[:], slotByteArray...)
@@ -273,28 +521,78 @@ func getBeaconProposerIndex(state *consensus.BeaconStatePhase0) uint64 {
 	seedArray := [32]byte{}
 	copy(seedArray[:], seed)
 
-	return computeProposerIndex(state, indices, seedArray)
+	return s.computeProposerIndex(state, indices, seedArray)
+}
+
+func (s *StateTransitioner) ProcessBlockHeader(state consensus.BeaconState, block consensus.BeaconBlock) error {
+	obj, err := toBeaconState(state)
+	if err != nil {
+		return err
+	}
+	header, err := s.toBlockHeader(block)
+	if err != nil {
+		return err
+	}
+	return s.processBlockHeader(obj, header)
+}
+
+// toBlockHeader returns the header of the block with the body
+// replaced by its hash tree root.
+func (s *StateTransitioner) toBlockHeader(block consensus.BeaconBlock) (*consensus.BeaconBlockHeader, error) {
+	var (
+		header = &consensus.BeaconBlockHeader{}
+		err    error
+	)
+
+	switch obj := block.(type) {
+	case *consensus.BeaconBlockPhase0:
+		header.Slot, header.ProposerIndex, header.ParentRoot = obj.Slot, obj.ProposerIndex, obj.ParentRoot
+		header.BodyRoot, err = s.spec.HashTreeRoot(obj.Body)
+	case *consensus.BeaconBlockAltair:
+		header.Slot, header.ProposerIndex, header.ParentRoot = obj.Slot, obj.ProposerIndex, obj.ParentRoot
+		header.BodyRoot, err = s.spec.HashTreeRoot(obj.Body)
+	case *consensus.BeaconBlockBellatrix:
+		header.Slot, header.ProposerIndex, header.ParentRoot = obj.Slot, obj.ProposerIndex, obj.ParentRoot
+		header.BodyRoot, err = s.spec.HashTreeRoot(obj.Body)
+	case *consensus.BeaconBlockCapella:
+		header.Slot, header.ProposerIndex, header.ParentRoot = obj.Slot, obj.ProposerIndex, obj.ParentRoot
+		header.BodyRoot, err = s.spec.HashTreeRoot(obj.Body)
+	case *consensus.BeaconBlockDeneb:
+		header.Slot, header.ProposerIndex, header.ParentRoot = obj.Slot, obj.ProposerIndex, obj.ParentRoot
+		header.BodyRoot, err = s.spec.HashTreeRoot(obj.Body)
+	case *consensus.BeaconBlockElectra:
+		header.Slot, header.ProposerIndex, header.ParentRoot = obj.Slot, obj.ProposerIndex, obj.ParentRoot
+		header.BodyRoot, err = s.spec.HashTreeRoot(obj.Body)
+	default:
+		return nil, fmt.Errorf("beacon block %T not supported", block)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return header, nil
 }
 
-func ProcessBlockHeader(state *consensus.BeaconStatePhase0, block *consensus.BeaconBlockPhase0) error {
+func (s *StateTransitioner) processBlockHeader(state *beaconState, block *consensus.BeaconBlockHeader) error {
+	latestBlockHeader := *state.latestBlockHeader
+
 	// Verify that the slots match
-	if block.Slot != state.Slot {
-		return fmt.Errorf("slot mismatch: %d, %d", block.Slot, state.Slot)
+	if block.Slot != *state.slot {
+		return fmt.Errorf("slot mismatch: %d, %d", block.Slot, *state.slot)
 	}
 
 	// Verify that the block is newer than latest block header
-	if block.Slot <= state.LatestBlockHeader.Slot {
-		return fmt.Errorf("block slot %d is older than latest block header %d", state.LatestBlockHeader.Slot, block.Slot)
+	if block.Slot <= latestBlockHeader.Slot {
+		return fmt.Errorf("block slot %d is older than latest block header %d", latestBlockHeader.Slot, block.Slot)
 	}
 
 	// Verify that proposer index is the correct index
-	proposerIndex := getBeaconProposerIndex(state)
+	proposerIndex := s.getBeaconProposerIndex(state)
 	if block.ProposerIndex != proposerIndex {
 		return fmt.Errorf("incorrect proposer index '%d', expected '%d'", block.ProposerIndex, proposerIndex)
 	}
 
 	// Verify that the parent matches
-	parentRoot, err := state.LatestBlockHeader.HashTreeRoot()
+	parentRoot, err := latestBlockHeader.HashTreeRoot()
 	if err != nil {
 		return err
 	}
@@ -303,19 +601,15 @@ func ProcessBlockHeader(state *consensus.BeaconStatePhase0, block *consensus.Bea
 	}
 
 	// Cache current block as the new latest block
-	bodyRoot, err := block.Body.HashTreeRoot()
-	if err != nil {
-		return err
-	}
-	state.LatestBlockHeader = &consensus.BeaconBlockHeader{
+	*state.latestBlockHeader = &consensus.BeaconBlockHeader{
 		Slot:          block.Slot,
 		ProposerIndex: block.ProposerIndex,
 		ParentRoot:    block.ParentRoot,
-		BodyRoot:      bodyRoot,
+		BodyRoot:      block.BodyRoot,
 	}
 
 	// Verify proposer is not slashed
-	if state.Validators[block.ProposerIndex].Slashed {
+	if (*state.validators)[block.ProposerIndex].Slashed {
 		return fmt.Errorf("proposer is slashed")
 	}
 
@@ -328,58 +622,109 @@ const (
 	farFutureEpoch = 18446744073709551615 // 2**64-1
 )
 
-func ProcessDeposit(state *consensus.BeaconStatePhase0, depositObj *consensus.Deposit) error {
+func (s *StateTransitioner) ProcessDeposit(state consensus.BeaconState, depositObj *consensus.Deposit) error {
+	obj, err := toBeaconState(state)
+	if err != nil {
+		return err
+	}
+	return s.processDeposit(obj, depositObj)
+}
+
+func (s *StateTransitioner) processDeposit(state *beaconState, depositObj *consensus.Deposit) error {
 	// Verify the Merkle branch
 	depositRoot, err := depositObj.Data.HashTreeRoot()
 	if err != nil {
 		return err
 	}
-	if !isValidMerkleBranch(depositRoot, depositObj.Proof, depositContractTreeDepth+1, state.Eth1DepositIndex, state.Eth1Data.DepositRoot) {
+	if !isValidMerkleBranch(depositRoot, depositObj.Proof, depositContractTreeDepth+1, *state.eth1DepositIndex, (*state.eth1Data).DepositRoot) {
 		return fmt.Errorf("bad merkle root")
 	}
 
 	// Deposits must be processed in order
-	state.Eth1DepositIndex++
+	*state.eth1DepositIndex++
 
-	pubKey := depositObj.Data.Pubkey
-	amount := depositObj.Data.Amount
+	data := depositObj.Data
+	s.applyDeposit(state, data.Pubkey, data.WithdrawalCredentials, data.Amount, data.Signature)
+	return nil
+}
 
+func (s *StateTransitioner) applyDeposit(state *beaconState, pubKey [48]byte, withdrawalCredentials [32]byte, amount uint64, signature consensus.Signature) {
 	indx, ok := isInValidatorSet(state, pubKey)
-	if !ok {
-		// Verify the deposit signature (proof of possession) which is not checked by the deposit contract
-		if err := deposit.Verify(depositObj.Data); err != nil {
-			// failures in the deposit are tolerated
-			return nil
-		}
 
-		effectiveBalance := amount - amount%Spec.EffectiveBalanceIncrement
-		if effectiveBalance > Spec.MaxEffectiveBalance {
-			effectiveBalance = Spec.MaxEffectiveBalance
+	if state.atLeast(consensus.VersionElectra) {
+		if !ok {
+			// Verify the deposit signature (proof of possession) which is not checked by the deposit contract
+			if !s.isValidDepositSignature(pubKey, withdrawalCredentials, amount, signature) {
+				// failures in the deposit are tolerated
+				return
+			}
+			s.addValidatorToRegistry(state, pubKey, withdrawalCredentials, 0)
 		}
 
-		val := &consensus.Validator{
-			Pubkey:                     pubKey,
-			EffectiveBalance:           effectiveBalance,
-			WithdrawalCredentials:      depositObj.Data.WithdrawalCredentials,
-			ActivationEligibilityEpoch: farFutureEpoch,
-			ActivationEpoch:            farFutureEpoch,
-			ExitEpoch:                  farFutureEpoch,
-			WithdrawableEpoch:          farFutureEpoch,
-		}
+		// the balance is increased once the deposit is applied from the queue
+		*state.pendingDeposits = append(*state.pendingDeposits, &consensus.PendingDeposit{
+			Pubkey:                pubKey,
+			WithdrawalCredentials: withdrawalCredentials,
+			Amount:                amount,
+			Signature:             signature,
+			Slot:                  s.spec.GenesisSlot,
+		})
+		return
+	}
 
-		// Add validator and balance entries
-		state.Validators = append(state.Validators, val)
-		state.Balances = append(state.Balances, amount)
+	if !ok {
+		// Verify the deposit signature (proof of possession) which is not checked by the deposit contract
+		if !s.isValidDepositSignature(pubKey, withdrawalCredentials, amount, signature) {
+			// failures in the deposit are tolerated
+			return
+		}
+		s.addValidatorToRegistry(state, pubKey, withdrawalCredentials, amount)
 	} else {
 		// increase balance by deposit amount
-		state.Balances[indx] += amount
+		increaseBalance(state, indx, amount)
 	}
+}
 
-	return nil
+// isValidDepositSignature verifies the signature of the deposit with the fork-agnostic
+// domain of the genesis fork version, since the deposits are valid across forks.
+func (s *StateTransitioner) isValidDepositSignature(pubKey [48]byte, withdrawalCredentials [32]byte, amount uint64, signature consensus.Signature) bool {
+	domain, err := consensus.ComputeDomain(consensus.DomainDepositType, s.spec.GenesisForkVersion, consensus.Root{})
+	if err != nil {
+		return false
+	}
+	depositMessage := &consensus.DepositMessage{
+		Pubkey:                pubKey,
+		WithdrawalCredentials: withdrawalCredentials,
+		Amount:                amount,
+	}
+	signingRoot, err := s.computeSigningRoot(domain, depositMessage)
+	if err != nil {
+		return false
+	}
+	ok, err := blsVerify(pubKey[:], signature[:], signingRoot)
+	if err != nil {
+		return false
+	}
+	return ok
+}
+
+func (s *StateTransitioner) addValidatorToRegistry(state *beaconState, pubKey [48]byte, withdrawalCredentials [32]byte, amount uint64) {
+	val := &consensus.Validator{
+		Pubkey:                     pubKey,
+		WithdrawalCredentials:      withdrawalCredentials,
+		ActivationEligibilityEpoch: farFutureEpoch,
+		ActivationEpoch:            farFutureEpoch,
+		ExitEpoch:                  farFutureEpoch,
+		WithdrawableEpoch:          farFutureEpoch,
+	}
+	val.EffectiveBalance = min(amount-amount%s.spec.EffectiveBalanceIncrement, s.getMaxEffectiveBalance(state, val))
+
+	// Add validator and balance entries
+	state.addValidator(val, amount)
 }
 
-func isInValidatorSet(state *consensus.BeaconStatePhase0, pubKey [48]byte) (uint64, bool) {
-	for indx, val := range state.Validators {
+func isInValidatorSet(state *beaconState, pubKey [48]byte) (uint64, bool) {
+	for indx, val := range *state.validators {
 		if bytes.Equal(val.Pubkey[:], pubKey[:]) {
 			return uint64(indx), true
 		}
@@ -405,33 +750,34 @@ func isSlashableValidator(validator *consensus.Validator, epoch uint64) bool {
 	return !validator.Slashed && validator.ActivationEpoch <= epoch && epoch < validator.WithdrawableEpoch
 }
 
-func decreaseBalance(state *consensus.BeaconStatePhase0, index uint64, delta uint64) {
-	if delta > state.Balances[index] {
-		state.Balances[index] = 0
+func decreaseBalance(state *beaconState, index uint64, delta uint64) {
+	balances := *state.balances
+	if delta > balances[index] {
+		balances[index] = 0
 	} else {
-		state.Balances[index] -= delta
+		balances[index] -= delta
 	}
 }
 
-func increaseBalance(state *consensus.BeaconStatePhase0, index uint64, delta uint64) {
-	state.Balances[index] += delta
+func increaseBalance(state *beaconState, index uint64, delta uint64) {
+	(*state.balances)[index] += delta
 }
 
-func slashValidator(state *consensus.BeaconStatePhase0, slashedIndex uint64, whistleblowerIndexPtr *uint64) error {
-	epoch := getCurrentEpoch(state)
-	if err := initiateValidatorExit(state, slashedIndex); err != nil {
+func (s *StateTransitioner) slashValidator(state *beaconState, slashedIndex uint64, whistleblowerIndexPtr *uint64) error {
+	epoch := s.getCurrentEpoch(state)
+	if err := s.initiateValidatorExit(state, slashedIndex); err != nil {
 		return err
 	}
 
-	validator := state.Validators[slashedIndex]
+	validator := (*state.validators)[slashedIndex]
 	validator.Slashed = true
-	validator.WithdrawableEpoch = max(validator.WithdrawableEpoch, epoch+Spec.EpochsPerSlashingsVector)
+	validator.WithdrawableEpoch = max(validator.WithdrawableEpoch, epoch+s.spec.EpochsPerSlashingsVector)
 
-	state.Slashings[epoch%Spec.EpochsPerSlashingsVector] += validator.EffectiveBalance
-	decreaseBalance(state, slashedIndex, validator.EffectiveBalance/Spec.MinSlashingPenaltyQuotient)
+	state.slashings[epoch%s.spec.EpochsPerSlashingsVector] += validator.EffectiveBalance
+	decreaseBalance(state, slashedIndex, validator.EffectiveBalance/s.minSlashingPenaltyQuotient(state))
 
 	// Apply proposer and whistleblower rewards
-	proposerIndex := getBeaconProposerIndex(state)
+	proposerIndex := s.getBeaconProposerIndex(state)
 
 	var whistleblowerIndex uint64
 	if whistleblowerIndexPtr != nil {
@@ -440,8 +786,14 @@ func slashValidator(state *consensus.BeaconStatePhase0, slashedIndex uint64, whi
 		whistleblowerIndex = proposerIndex
 	}
 
-	whistleblowerReward := validator.EffectiveBalance / Spec.WhistleblowerRewardQuotient
-	proposerReward := whistleblowerReward / Spec.ProposerRewardQuotient
+	whistleblowerReward := validator.EffectiveBalance / s.whistleblowerRewardQuotient(state)
+
+	var proposerReward uint64
+	if !state.atLeast(consensus.VersionAltair) {
+		proposerReward = whistleblowerReward / s.spec.ProposerRewardQuotient
+	} else {
+		proposerReward = whistleblowerReward * proposerWeight / weightDenominator
+	}
 
 	increaseBalance(state, proposerIndex, proposerReward)
 	increaseBalance(state, whistleblowerIndex, whistleblowerReward-proposerReward)
@@ -449,6 +801,26 @@ func slashValidator(state *consensus.BeaconStatePhase0, slashedIndex uint64, whi
 	return nil
 }
 
+func (s *StateTransitioner) minSlashingPenaltyQuotient(state *beaconState) uint64 {
+	switch {
+	case state.atLeast(consensus.VersionElectra):
+		return s.spec.MinSlashingPenaltyQuotientElectra
+	case state.atLeast(consensus.VersionBellatrix):
+		return s.spec.MinSlashingPenaltyQuotientBellatrix
+	case state.atLeast(consensus.VersionAltair):
+		return s.spec.MinSlashingPenaltyQuotientAltair
+	default:
+		return s.spec.MinSlashingPenaltyQuotient
+	}
+}
+
+func (s *StateTransitioner) whistleblowerRewardQuotient(state *beaconState) uint64 {
+	if state.atLeast(consensus.VersionElectra) {
+		return s.spec.WhistleblowerRewardQuotientElectra
+	}
+	return s.spec.WhistleblowerRewardQuotient
+}
+
 func blsVerify(pubKey []byte, signature []byte, root [32]byte) (bool, error) {
 	sig := new(bls.Signature)
 	if err := sig.Deserialize(signature); err != nil {
@@ -466,7 +838,15 @@ func blsVerify(pubKey []byte, signature []byte, root [32]byte) (bool, error) {
 	return ok, nil
 }
 
-func ProcessProposerSlashing(state *consensus.BeaconStatePhase0, proposerSlashing *consensus.ProposerSlashing) error {
+func (s *StateTransitioner) ProcessProposerSlashing(state consensus.BeaconState, proposerSlashing *consensus.ProposerSlashing) error {
+	obj, err := toBeaconState(state)
+	if err != nil {
+		return err
+	}
+	return s.processProposerSlashing(obj, proposerSlashing)
+}
+
+func (s *StateTransitioner) processProposerSlashing(state *beaconState, proposerSlashing *consensus.ProposerSlashing) error {
 	header1 := proposerSlashing.Header1.Header
 	header2 := proposerSlashing.Header2.Header
 
@@ -496,24 +876,25 @@ func ProcessProposerSlashing(state *consensus.BeaconStatePhase0, proposerSlashin
 	}
 
 	// Verify the proposer is slashable
-	if header1.ProposerIndex >= uint64(len(state.Validators)) {
+	validators := *state.validators
+	if header1.ProposerIndex >= uint64(len(validators)) {
 		return fmt.Errorf("four1")
 	}
-	proposer := state.Validators[header1.ProposerIndex]
-	if !isSlashableValidator(proposer, getCurrentEpoch(state)) {
+	proposer := validators[header1.ProposerIndex]
+	if !isSlashableValidator(proposer, s.getCurrentEpoch(state)) {
 		return fmt.Errorf("four")
 	}
 
 	// Verify signatures
 	verifySignature := func(signedHeader *consensus.SignedBeaconBlockHeader) error {
-		epoch := computeEpochAtSlot(signedHeader.Header.Slot)
+		epoch := s.computeEpochAtSlot(signedHeader.Header.Slot)
 
-		domain, err := getDomain(consensus.DomainBeaconProposerType, state, &epoch)
+		domain, err := s.getDomain(consensus.DomainBeaconProposerType, state, &epoch)
 		if err != nil {
 			return err
 		}
 
-		root, err := consensus.ComputeSigningRoot(domain, signedHeader.Header)
+		root, err := s.computeSigningRoot(domain, signedHeader.Header)
 		if err != nil {
 			return err
 		}
@@ -536,66 +917,152 @@ func ProcessProposerSlashing(state *consensus.BeaconStatePhase0, proposerSlashin
 		return err
 	}
 
-	if err := slashValidator(state, header1.ProposerIndex, nil); err != nil {
+	if err := s.slashValidator(state, header1.ProposerIndex, nil); err != nil {
 		return err
 	}
 	return nil
 }
 
-func computeActivationExitEpoch(epoch uint64) uint64 {
-	return epoch + 1 + Spec.MaxSeedLookAhead
+func (s *StateTransitioner) computeActivationExitEpoch(epoch uint64) uint64 {
+	return epoch + 1 + s.spec.MaxSeedLookAhead
 }
 
-func getValidatorChurnLimit(state *consensus.BeaconStatePhase0) uint64 {
-	activeValidatorIndices := getActiveValidatorIndices(state, getCurrentEpoch(state))
+func (s *StateTransitioner) getValidatorChurnLimit(state *beaconState) uint64 {
+	activeValidatorIndices := getActiveValidatorIndices(state, s.getCurrentEpoch(state))
 
-	churnLimit := uint64(len(activeValidatorIndices)) / Spec.ChurnLimitQuotient
-	if churnLimit < Spec.MinPerEpochChurnLimit {
-		churnLimit = Spec.MinPerEpochChurnLimit
+	churnLimit := uint64(len(activeValidatorIndices)) / s.spec.ChurnLimitQuotient
+	if churnLimit < s.spec.MinPerEpochChurnLimit {
+		churnLimit = s.spec.MinPerEpochChurnLimit
 	}
 	return churnLimit
 }
 
-func initiateValidatorExit(state *consensus.BeaconStatePhase0, index uint64) error {
+// getValidatorActivationChurnLimit returns the number of validators activated per epoch, which is capped since deneb
+func (s *StateTransitioner) getValidatorActivationChurnLimit(state *beaconState) uint64 {
+	if state.atLeast(consensus.VersionDeneb) {
+		return min(s.spec.MaxPerEpochActivationChurnLimit, s.getValidatorChurnLimit(state))
+	}
+	return s.getValidatorChurnLimit(state)
+}
+
+// getBalanceChurnLimit returns the churn of the epoch in gwei which is used since electra
+func (s *StateTransitioner) getBalanceChurnLimit(state *beaconState) uint64 {
+	churn := max(s.spec.MinPerEpochChurnLimitElectra, s.getTotalActiveBalance(state)/s.spec.ChurnLimitQuotient)
+	return churn - churn%s.spec.EffectiveBalanceIncrement
+}
+
+func (s *StateTransitioner) getActivationExitChurnLimit(state *beaconState) uint64 {
+	return min(s.spec.MaxPerEpochActivationExitChurnLimit, s.getBalanceChurnLimit(state))
+}
+
+func (s *StateTransitioner) getConsolidationChurnLimit(state *beaconState) uint64 {
+	return s.getBalanceChurnLimit(state) - s.getActivationExitChurnLimit(state)
+}
+
+// computeExitEpochAndUpdateChurn returns the first epoch with enough exit churn
+// for the balance and consumes the churn from the state.
+func (s *StateTransitioner) computeExitEpochAndUpdateChurn(state *beaconState, exitBalance uint64) uint64 {
+	earliestExitEpoch := max(*state.earliestExitEpoch, s.computeActivationExitEpoch(s.getCurrentEpoch(state)))
+	perEpochChurn := s.getActivationExitChurnLimit(state)
+
+	// New epoch for exits
+	var exitBalanceToConsume uint64
+	if *state.earliestExitEpoch < earliestExitEpoch {
+		exitBalanceToConsume = perEpochChurn
+	} else {
+		exitBalanceToConsume = *state.exitBalanceToConsume
+	}
+
+	// Exit doesn't fit in the current earliest epoch
+	if exitBalance > exitBalanceToConsume {
+		balanceToProcess := exitBalance - exitBalanceToConsume
+		additionalEpochs := (balanceToProcess-1)/perEpochChurn + 1
+		earliestExitEpoch += additionalEpochs
+		exitBalanceToConsume += additionalEpochs * perEpochChurn
+	}
+
+	// Consume the balance and update state variables
+	*state.exitBalanceToConsume = exitBalanceToConsume - exitBalance
+	*state.earliestExitEpoch = earliestExitEpoch
+
+	return earliestExitEpoch
+}
+
+// computeConsolidationEpochAndUpdateChurn is the same as computeExitEpochAndUpdateChurn
+// for the consolidation churn.
+func (s *StateTransitioner) computeConsolidationEpochAndUpdateChurn(state *beaconState, consolidationBalance uint64) uint64 {
+	earliestConsolidationEpoch := max(*state.earliestConsolidationEpoch, s.computeActivationExitEpoch(s.getCurrentEpoch(state)))
+	perEpochConsolidationChurn := s.getConsolidationChurnLimit(state)
+
+	// New epoch for consolidations
+	var consolidationBalanceToConsume uint64
+	if *state.earliestConsolidationEpoch < earliestConsolidationEpoch {
+		consolidationBalanceToConsume = perEpochConsolidationChurn
+	} else {
+		consolidationBalanceToConsume = *state.consolidationBalanceToConsume
+	}
+
+	// Consolidation doesn't fit in the current earliest epoch
+	if consolidationBalance > consolidationBalanceToConsume {
+		balanceToProcess := consolidationBalance - consolidationBalanceToConsume
+		additionalEpochs := (balanceToProcess-1)/perEpochConsolidationChurn + 1
+		earliestConsolidationEpoch += additionalEpochs
+		consolidationBalanceToConsume += additionalEpochs * perEpochConsolidationChurn
+	}
+
+	// Consume the balance and update state variables
+	*state.consolidationBalanceToConsume = consolidationBalanceToConsume - consolidationBalance
+	*state.earliestConsolidationEpoch = earliestConsolidationEpoch
+
+	return earliestConsolidationEpoch
+}
+
+func (s *StateTransitioner) initiateValidatorExit(state *beaconState, index uint64) error {
+	validators := *state.validators
 
 	// Return if validator already initiated exit
-	validator := state.Validators[index]
+	validator := validators[index]
 	if validator.ExitEpoch != farFutureEpoch {
 		return nil
 	}
 
-	// Compute exit queue epoch
-	exitEpochs := []uint64{}
-	for _, v := range state.Validators {
-		if v.ExitEpoch != farFutureEpoch {
-			exitEpochs = append(exitEpochs, v.ExitEpoch)
+	var exitQueueEpoch uint64
+	if state.atLeast(consensus.VersionElectra) {
+		// the exit queue is limited by the exited balance since electra
+		exitQueueEpoch = s.computeExitEpochAndUpdateChurn(state, validator.EffectiveBalance)
+	} else {
+		// Compute exit queue epoch
+		exitEpochs := []uint64{}
+		for _, v := range validators {
+			if v.ExitEpoch != farFutureEpoch {
+				exitEpochs = append(exitEpochs, v.ExitEpoch)
+			}
 		}
-	}
-	exitEpochs = append(exitEpochs, computeActivationExitEpoch(getCurrentEpoch(state)))
+		exitEpochs = append(exitEpochs, s.computeActivationExitEpoch(s.getCurrentEpoch(state)))
 
-	exitQueueEpoch := uint64(0)
-	for _, epoch := range exitEpochs {
-		if exitQueueEpoch < epoch {
-			exitQueueEpoch = epoch
+		for _, epoch := range exitEpochs {
+			if exitQueueEpoch < epoch {
+				exitQueueEpoch = epoch
+			}
 		}
-	}
 
-	exitEpochChurn := uint64(0)
-	for _, v := range state.Validators {
-		if v.ExitEpoch == exitQueueEpoch {
-			exitEpochChurn++
+		exitEpochChurn := uint64(0)
+		for _, v := range validators {
+			if v.ExitEpoch == exitQueueEpoch {
+				exitEpochChurn++
+			}
 		}
-	}
 
-	churnLimit := getValidatorChurnLimit(state)
-	if exitEpochChurn >= churnLimit {
-		exitQueueEpoch++
+		churnLimit := s.getValidatorChurnLimit(state)
+		if exitEpochChurn >= churnLimit {
+			exitQueueEpoch++
+		}
 	}
 
 	// Set validator exit epoch and withdrawable epoch
 	validator.ExitEpoch = exitQueueEpoch
 
-	withdrawalEpoch := validator.ExitEpoch + Spec.MinValidatorWithdrawabilityDelay
+	withdrawalEpoch := validator.ExitEpoch + s.spec.MinValidatorWithdrawabilityDelay
 	if withdrawalEpoch < exitQueueEpoch {
 		return fmt.Errorf("overflow epoch")
 	}
@@ -603,15 +1070,24 @@ func initiateValidatorExit(state *consensus.BeaconStatePhase0, index uint64) err
 	return nil
 }
 
-func ProcessVoluntaryExit(state *consensus.BeaconStatePhase0, signedVoluntaryExit *consensus.SignedVoluntaryExit) error {
+func (s *StateTransitioner) ProcessVoluntaryExit(state consensus.BeaconState, signedVoluntaryExit *consensus.SignedVoluntaryExit) error {
+	obj, err := toBeaconState(state)
+	if err != nil {
+		return err
+	}
+	return s.processVoluntaryExit(obj, signedVoluntaryExit)
+}
+
+func (s *StateTransitioner) processVoluntaryExit(state *beaconState, signedVoluntaryExit *consensus.SignedVoluntaryExit) error {
 	voluntaryExit := signedVoluntaryExit.Exit
-	if voluntaryExit.ValidatorIndex >= uint64(len(state.Validators)) {
+	validators := *state.validators
+	if voluntaryExit.ValidatorIndex >= uint64(len(validators)) {
 		return fmt.Errorf("bad length")
 	}
-	validator := state.Validators[voluntaryExit.ValidatorIndex]
+	validator := validators[voluntaryExit.ValidatorIndex]
 
 	// Verify the validator is active
-	if !isActiveValidator(validator, getCurrentEpoch(state)) {
+	if !isActiveValidator(validator, s.getCurrentEpoch(state)) {
 		return fmt.Errorf("the validator is not active")
 	}
 
@@ -621,21 +1097,35 @@ func ProcessVoluntaryExit(state *consensus.BeaconStatePhase0, signedVoluntaryExi
 	}
 
 	// Exits must specify an epoch when they become valid; they are not valid before then
-	if getCurrentEpoch(state) < voluntaryExit.Epoch {
+	if s.getCurrentEpoch(state) < voluntaryExit.Epoch {
 		return fmt.Errorf("one")
 	}
 
 	// Verify the validator has been active long enough
-	if getCurrentEpoch(state) < validator.ActivationEpoch+Spec.ShardCommiteePeriod {
+	if s.getCurrentEpoch(state) < validator.ActivationEpoch+s.spec.ShardCommiteePeriod {
 		return fmt.Errorf("two")
 	}
 
+	// Only exit validator if it has no pending withdrawals in the queue
+	if state.atLeast(consensus.VersionElectra) && s.getPendingBalanceToWithdraw(state, voluntaryExit.ValidatorIndex) != 0 {
+		return fmt.Errorf("validator has pending withdrawals")
+	}
+
 	// Verify signature
-	domain, err := getDomain(consensus.DomainVoluntaryExitType, state, nil)
+	var (
+		domain [32]byte
+		err    error
+	)
+	if state.atLeast(consensus.VersionDeneb) {
+		// the exits are signed with the capella domain forever since deneb (EIP-7044)
+		domain, err = consensus.ComputeDomain(consensus.DomainVoluntaryExitType, s.spec.CapellaForkVersion, *state.genesisValidatorsRoot)
+	} else {
+		domain, err = s.getDomain(consensus.DomainVoluntaryExitType, state, nil)
+	}
 	if err != nil {
 		return fmt.Errorf("three %v", err)
 	}
-	signingRoot, err := consensus.ComputeSigningRoot(domain, voluntaryExit)
+	signingRoot, err := s.computeSigningRoot(domain, voluntaryExit)
 	if err != nil {
 		return fmt.Errorf("four %v", err)
 	}
@@ -649,25 +1139,40 @@ func ProcessVoluntaryExit(state *consensus.BeaconStatePhase0, signedVoluntaryExi
 	}
 
 	// Initiate exit
-	if err := initiateValidatorExit(state, voluntaryExit.ValidatorIndex); err != nil {
+	if err := s.initiateValidatorExit(state, voluntaryExit.ValidatorIndex); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func getDomain(domain consensus.Domain, state *consensus.BeaconStatePhase0, epoch *uint64) ([32]byte, error) {
+func (s *StateTransitioner) getDomain(domain consensus.Domain, state *beaconState, epoch *uint64) ([32]byte, error) {
 	var forkVersion [4]byte
 
-	curEpoch := getCurrentEpoch(state)
+	curEpoch := s.getCurrentEpoch(state)
 	if epoch != nil {
 		curEpoch = *epoch
 	}
 
-	if curEpoch < state.Fork.Epoch {
-		forkVersion = state.Fork.PreviousVersion
+	fork := *state.fork
+	if curEpoch < fork.Epoch {
+		forkVersion = fork.PreviousVersion
 	} else {
-		forkVersion = state.Fork.CurrentVersion
+		forkVersion = fork.CurrentVersion
+	}
+	return consensus.ComputeDomain(domain, forkVersion, *state.genesisValidatorsRoot)
+}
+
+// computeSigningRoot returns the signing root of the object hashed
+// with the sizes of the spec.
+func (s *StateTransitioner) computeSigningRoot(domain [32]byte, obj interface{}) ([32]byte, error) {
+	root, err := s.spec.HashTreeRoot(obj)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	signingData := &consensus.SigningData{
+		ObjectRoot: root,
+		Domain:     domain,
 	}
-	return consensus.ComputeDomain(domain, forkVersion, state.GenesisValidatorsRoot)
+	return signingData.HashTreeRoot()
 }
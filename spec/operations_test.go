@@ -1,183 +1,470 @@
 package spec
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	consensus "github.com/umbracle/go-eth-consensus"
+	"gopkg.in/yaml.v2"
 )
 
 func TestOpAttestation(t *testing.T) {
 	type attestationTest struct {
-		Attestation consensus.Attestation
-		Pre         consensus.BeaconStatePhase0
-		Post        consensus.BeaconStatePhase0
+		Attestation        consensus.Attestation
+		AttestationElectra consensus.AttestationElectra
+		Pre                consensus.BeaconState
+		Post               consensus.BeaconState
 	}
 
-	listTestData(t, "mainnet/phase0/operations/attestation/*/*", func(th *testHandler) {
-		attestationTest := &attestationTest{}
-		th.decodeFile("attestation", &attestationTest.Attestation)
-		th.decodeFile("pre", &attestationTest.Pre)
-		ok := th.decodeFile("post", &attestationTest.Post, true)
+	for _, fork := range testForks {
+		listTestData(t, ""+fork.name+"/operations/attestation/*/*", func(th *testHandler) {
+			attestationTest := &attestationTest{Pre: fork.newState(), Post: fork.newState()}
+			th.decodeFile("pre", attestationTest.Pre)
+			ok := th.decodeFile("post", attestationTest.Post, true)
 
-		if err := ProcessAttestation(&attestationTest.Pre, &attestationTest.Attestation); err != nil {
-			if ok {
-				t.Fatal(err)
+			var err error
+			if fork.version >= consensus.VersionElectra {
+				th.decodeFile("attestation", &attestationTest.AttestationElectra)
+				err = th.transitioner.ProcessAttestationElectra(attestationTest.Pre, &attestationTest.AttestationElectra)
+			} else {
+				th.decodeFile("attestation", &attestationTest.Attestation)
+				err = th.transitioner.ProcessAttestation(attestationTest.Pre, &attestationTest.Attestation)
+			}
+			if err != nil {
+				if ok {
+					t.Fatal(err)
+				}
+				return
 			}
-			return
-		}
 
-		if !ok {
-			t.Fatal("it should fail")
-		}
-		if !reflect.DeepEqual(attestationTest.Pre, attestationTest.Post) {
-			t.Fatal("bad")
-		}
-	})
+			if !ok {
+				t.Fatal("it should fail")
+			}
+			if !reflect.DeepEqual(attestationTest.Pre, attestationTest.Post) {
+				t.Fatal("bad")
+			}
+		})
+	}
 }
 
 func TestOpProcessAttesterSlashing(t *testing.T) {
 	type processAttesterSlashingTest struct {
-		Pre              consensus.BeaconStatePhase0
-		Post             consensus.BeaconStatePhase0
-		AttesterSlashing consensus.AttesterSlashing
+		Pre                     consensus.BeaconState
+		Post                    consensus.BeaconState
+		AttesterSlashing        consensus.AttesterSlashing
+		AttesterSlashingElectra consensus.AttesterSlashingElectra
 	}
 
-	listTestData(t, "mainnet/phase0/operations/attester_slashing/*/*", func(th *testHandler) {
-		slashTest := &processAttesterSlashingTest{}
-		th.decodeFile("pre", &slashTest.Pre)
-		ok := th.decodeFile("post", &slashTest.Post, true)
-		th.decodeFile("attester_slashing", &slashTest.AttesterSlashing)
+	for _, fork := range testForks {
+		listTestData(t, ""+fork.name+"/operations/attester_slashing/*/*", func(th *testHandler) {
+			slashTest := &processAttesterSlashingTest{Pre: fork.newState(), Post: fork.newState()}
+			th.decodeFile("pre", slashTest.Pre)
+			ok := th.decodeFile("post", slashTest.Post, true)
 
-		if err := ProcessAttesterSlashing(&slashTest.Pre, &slashTest.AttesterSlashing); err != nil {
-			if ok {
-				t.Fatal(err)
+			var err error
+			if fork.version >= consensus.VersionElectra {
+				th.decodeFile("attester_slashing", &slashTest.AttesterSlashingElectra)
+				err = th.transitioner.ProcessAttesterSlashingElectra(slashTest.Pre, &slashTest.AttesterSlashingElectra)
+			} else {
+				th.decodeFile("attester_slashing", &slashTest.AttesterSlashing)
+				err = th.transitioner.ProcessAttesterSlashing(slashTest.Pre, &slashTest.AttesterSlashing)
+			}
+			if err != nil {
+				if ok {
+					t.Fatal(err)
+				}
+				return
 			}
-			return
-		}
 
-		if !ok {
-			t.Fatal("it should fail")
-		}
-		if !reflect.DeepEqual(slashTest.Pre, slashTest.Post) {
-			t.Fatal("bad")
-		}
-	})
+			if !ok {
+				t.Fatal("it should fail")
+			}
+			if !reflect.DeepEqual(slashTest.Pre, slashTest.Post) {
+				t.Fatal("bad")
+			}
+		})
+	}
 }
 
 func TestOpProcessBlockBlockHeader(t *testing.T) {
 	type blockHeaderTest struct {
-		Pre   consensus.BeaconStatePhase0
-		Post  consensus.BeaconStatePhase0
-		Block consensus.BeaconBlockPhase0
+		Pre   consensus.BeaconState
+		Post  consensus.BeaconState
+		Block consensus.BeaconBlock
 	}
 
-	listTestData(t, "mainnet/phase0/operations/block_header/*/*", func(th *testHandler) {
-		blockHeaderTest := &blockHeaderTest{}
-		th.decodeFile("block", &blockHeaderTest.Block)
-		th.decodeFile("pre", &blockHeaderTest.Pre)
-		ok := th.decodeFile("post", &blockHeaderTest.Post, true)
+	for _, fork := range testForks {
+		listTestData(t, ""+fork.name+"/operations/block_header/*/*", func(th *testHandler) {
+			blockHeaderTest := &blockHeaderTest{Pre: fork.newState(), Post: fork.newState(), Block: fork.newBlock()}
+			th.decodeFile("block", blockHeaderTest.Block)
+			th.decodeFile("pre", blockHeaderTest.Pre)
+			ok := th.decodeFile("post", blockHeaderTest.Post, true)
 
-		if err := ProcessBlockHeader(&blockHeaderTest.Pre, &blockHeaderTest.Block); err != nil {
-			if ok {
-				t.Fatal(err)
+			if err := th.transitioner.ProcessBlockHeader(blockHeaderTest.Pre, blockHeaderTest.Block); err != nil {
+				if ok {
+					t.Fatal(err)
+				}
+				return
 			}
-			return
-		}
-
-		if !ok {
-			t.Fatal("it should fail")
-		}
-		if !reflect.DeepEqual(blockHeaderTest.Pre, blockHeaderTest.Post) {
-			t.Fatal("bad")
-		}
-	})
 
+			if !ok {
+				t.Fatal("it should fail")
+			}
+			if !reflect.DeepEqual(blockHeaderTest.Pre, blockHeaderTest.Post) {
+				t.Fatal("bad")
+			}
+		})
+	}
 }
 
 func TestOpDeposit(t *testing.T) {
 	type depositTest struct {
 		Deposit consensus.Deposit
-		Pre     consensus.BeaconStatePhase0
-		Post    consensus.BeaconStatePhase0
+		Pre     consensus.BeaconState
+		Post    consensus.BeaconState
 	}
 
-	listTestData(t, "mainnet/phase0/operations/deposit/*/*", func(th *testHandler) {
-		depositTest := &depositTest{}
-		th.decodeFile("deposit", &depositTest.Deposit)
-		th.decodeFile("pre", &depositTest.Pre)
-		ok := th.decodeFile("post", &depositTest.Post, true)
+	for _, fork := range testForks {
+		listTestData(t, ""+fork.name+"/operations/deposit/*/*", func(th *testHandler) {
+			depositTest := &depositTest{Pre: fork.newState(), Post: fork.newState()}
+			th.decodeFile("deposit", &depositTest.Deposit)
+			th.decodeFile("pre", depositTest.Pre)
+			ok := th.decodeFile("post", depositTest.Post, true)
 
-		if err := ProcessDeposit(&depositTest.Pre, &depositTest.Deposit); err != nil {
-			if ok {
-				t.Fatal(err)
+			if err := th.transitioner.ProcessDeposit(depositTest.Pre, &depositTest.Deposit); err != nil {
+				if ok {
+					t.Fatal(err)
+				}
+				return
 			}
-			return
-		}
 
-		if !ok {
-			t.Fatal("it should fail")
-		}
-		if !reflect.DeepEqual(depositTest.Pre, depositTest.Post) {
-			t.Fatal("bad")
-		}
-	})
+			if !ok {
+				t.Fatal("it should fail")
+			}
+			if !reflect.DeepEqual(depositTest.Pre, depositTest.Post) {
+				t.Fatal("bad")
+			}
+		})
+	}
 }
 
 func TestOpProposerSlashing(t *testing.T) {
 	type proposerSlashingTest struct {
 		ProposerSlashing consensus.ProposerSlashing
-		Pre              consensus.BeaconStatePhase0
-		Post             consensus.BeaconStatePhase0
+		Pre              consensus.BeaconState
+		Post             consensus.BeaconState
 	}
 
-	listTestData(t, "mainnet/phase0/operations/proposer_slashing/*/*", func(th *testHandler) {
-		proposerSlashingTest := &proposerSlashingTest{}
-		th.decodeFile("proposer_slashing", &proposerSlashingTest.ProposerSlashing)
-		th.decodeFile("pre", &proposerSlashingTest.Pre)
-		ok := th.decodeFile("post", &proposerSlashingTest.Post, true)
+	for _, fork := range testForks {
+		listTestData(t, ""+fork.name+"/operations/proposer_slashing/*/*", func(th *testHandler) {
+			proposerSlashingTest := &proposerSlashingTest{Pre: fork.newState(), Post: fork.newState()}
+			th.decodeFile("proposer_slashing", &proposerSlashingTest.ProposerSlashing)
+			th.decodeFile("pre", proposerSlashingTest.Pre)
+			ok := th.decodeFile("post", proposerSlashingTest.Post, true)
 
-		if err := ProcessProposerSlashing(&proposerSlashingTest.Pre, &proposerSlashingTest.ProposerSlashing); err != nil {
-			if ok {
-				t.Fatal(err)
+			if err := th.transitioner.ProcessProposerSlashing(proposerSlashingTest.Pre, &proposerSlashingTest.ProposerSlashing); err != nil {
+				if ok {
+					t.Fatal(err)
+				}
+				return
 			}
-			return
-		}
 
-		if !ok {
-			t.Fatal("it should fail")
-		}
-		if !reflect.DeepEqual(proposerSlashingTest.Pre, proposerSlashingTest.Post) {
-			t.Fatal("bad")
-		}
-	})
+			if !ok {
+				t.Fatal("it should fail")
+			}
+			if !reflect.DeepEqual(proposerSlashingTest.Pre, proposerSlashingTest.Post) {
+				t.Fatal("bad")
+			}
+		})
+	}
 }
 
 func TestOpVoluntaryExit(t *testing.T) {
 	type voluntaryExitTest struct {
 		VoluntaryExit consensus.SignedVoluntaryExit
-		Pre           consensus.BeaconStatePhase0
-		Post          consensus.BeaconStatePhase0
+		Pre           consensus.BeaconState
+		Post          consensus.BeaconState
+	}
+
+	for _, fork := range testForks {
+		listTestData(t, ""+fork.name+"/operations/voluntary_exit/*/*", func(th *testHandler) {
+			voluntaryExitTest := &voluntaryExitTest{Pre: fork.newState(), Post: fork.newState()}
+			th.decodeFile("voluntary_exit", &voluntaryExitTest.VoluntaryExit)
+			th.decodeFile("pre", voluntaryExitTest.Pre)
+			ok := th.decodeFile("post", voluntaryExitTest.Post, true)
+
+			if err := th.transitioner.ProcessVoluntaryExit(voluntaryExitTest.Pre, &voluntaryExitTest.VoluntaryExit); err != nil {
+				if ok {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if !ok {
+				t.Fatal("it should fail")
+			}
+			if !reflect.DeepEqual(voluntaryExitTest.Pre, voluntaryExitTest.Post) {
+				t.Fatal("bad")
+			}
+		})
+	}
+}
+
+func TestOpSyncAggregate(t *testing.T) {
+	type syncAggregateTest struct {
+		SyncAggregate consensus.SyncAggregate
+		Pre           consensus.BeaconState
+		Post          consensus.BeaconState
+	}
+
+	for _, fork := range testForks {
+		if fork.version < consensus.VersionAltair {
+			continue
+		}
+		listTestData(t, ""+fork.name+"/operations/sync_aggregate/*/*", func(th *testHandler) {
+			syncAggregateTest := &syncAggregateTest{Pre: fork.newState(), Post: fork.newState()}
+			th.decodeFile("sync_aggregate", &syncAggregateTest.SyncAggregate)
+			th.decodeFile("pre", syncAggregateTest.Pre)
+			ok := th.decodeFile("post", syncAggregateTest.Post, true)
+
+			if err := th.transitioner.ProcessSyncAggregate(syncAggregateTest.Pre, &syncAggregateTest.SyncAggregate); err != nil {
+				if ok {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if !ok {
+				t.Fatal("it should fail")
+			}
+			if !reflect.DeepEqual(syncAggregateTest.Pre, syncAggregateTest.Post) {
+				t.Fatal("bad")
+			}
+		})
+	}
+}
+
+func TestOpExecutionPayload(t *testing.T) {
+	type executionPayloadTest struct {
+		Pre   consensus.BeaconState
+		Post  consensus.BeaconState
+		Block consensus.BeaconBlock
+	}
+
+	for _, fork := range testForks {
+		if fork.version < consensus.VersionBellatrix {
+			continue
+		}
+		listTestData(t, ""+fork.name+"/operations/execution_payload/*/*", func(th *testHandler) {
+			var execution struct {
+				ExecutionValid bool `yaml:"execution_valid"`
+			}
+			data, err := ioutil.ReadFile(filepath.Join(th.path, "execution.yaml"))
+			require.NoError(t, err)
+			require.NoError(t, yaml.Unmarshal(data, &execution))
+
+			if !execution.ExecutionValid {
+				// the payload is not verified with an execution engine
+				return
+			}
+
+			payloadTest := &executionPayloadTest{Pre: fork.newState(), Post: fork.newState(), Block: fork.newBlock()}
+			th.decodeBody(payloadTest.Block)
+			th.decodeFile("pre", payloadTest.Pre)
+			ok := th.decodeFile("post", payloadTest.Post, true)
+
+			if err := th.transitioner.ProcessExecutionPayload(payloadTest.Pre, payloadTest.Block); err != nil {
+				if ok {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if !ok {
+				t.Fatal("it should fail")
+			}
+			if !reflect.DeepEqual(payloadTest.Pre, payloadTest.Post) {
+				t.Fatal("bad")
+			}
+		})
+	}
+}
+
+func TestOpWithdrawals(t *testing.T) {
+	type withdrawalsTest struct {
+		Pre  consensus.BeaconState
+		Post consensus.BeaconState
 	}
 
-	listTestData(t, "mainnet/phase0/operations/voluntary_exit/*/*", func(th *testHandler) {
-		voluntaryExitTest := &voluntaryExitTest{}
-		th.decodeFile("voluntary_exit", &voluntaryExitTest.VoluntaryExit)
-		th.decodeFile("pre", &voluntaryExitTest.Pre)
-		ok := th.decodeFile("post", &voluntaryExitTest.Post, true)
+	for _, fork := range testForks {
+		if fork.version < consensus.VersionCapella {
+			continue
+		}
+		listTestData(t, ""+fork.name+"/operations/withdrawals/*/*", func(th *testHandler) {
+			withdrawalsTest := &withdrawalsTest{Pre: fork.newState(), Post: fork.newState()}
+			th.decodeFile("pre", withdrawalsTest.Pre)
+			ok := th.decodeFile("post", withdrawalsTest.Post, true)
 
-		if err := ProcessVoluntaryExit(&voluntaryExitTest.Pre, &voluntaryExitTest.VoluntaryExit); err != nil {
-			if ok {
-				t.Fatal(err)
+			var payload interface{}
+			if fork.version == consensus.VersionCapella {
+				payload = &consensus.ExecutionPayloadCapella{}
+			} else {
+				payload = &consensus.ExecutionPayloadDeneb{}
 			}
-			return
+			th.decodeFile("execution_payload", payload)
+
+			if err := th.transitioner.ProcessWithdrawals(withdrawalsTest.Pre, payload); err != nil {
+				if ok {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if !ok {
+				t.Fatal("it should fail")
+			}
+			if !reflect.DeepEqual(withdrawalsTest.Pre, withdrawalsTest.Post) {
+				t.Fatal("bad")
+			}
+		})
+	}
+}
+
+func TestOpBlsToExecutionChange(t *testing.T) {
+	type blsToExecutionChangeTest struct {
+		AddressChange consensus.SignedBLSToExecutionChange
+		Pre           consensus.BeaconState
+		Post          consensus.BeaconState
+	}
+
+	for _, fork := range testForks {
+		if fork.version < consensus.VersionCapella {
+			continue
+		}
+		listTestData(t, ""+fork.name+"/operations/bls_to_execution_change/*/*", func(th *testHandler) {
+			changeTest := &blsToExecutionChangeTest{Pre: fork.newState(), Post: fork.newState()}
+			th.decodeFile("address_change", &changeTest.AddressChange)
+			th.decodeFile("pre", changeTest.Pre)
+			ok := th.decodeFile("post", changeTest.Post, true)
+
+			if err := th.transitioner.ProcessBlsToExecutionChange(changeTest.Pre, &changeTest.AddressChange); err != nil {
+				if ok {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if !ok {
+				t.Fatal("it should fail")
+			}
+			if !reflect.DeepEqual(changeTest.Pre, changeTest.Post) {
+				t.Fatal("bad")
+			}
+		})
+	}
+}
+
+func TestOpDepositRequest(t *testing.T) {
+	type depositRequestTest struct {
+		DepositRequest consensus.DepositRequest
+		Pre            consensus.BeaconState
+		Post           consensus.BeaconState
+	}
+
+	for _, fork := range testForks {
+		if fork.version < consensus.VersionElectra {
+			continue
 		}
+		listTestData(t, ""+fork.name+"/operations/deposit_request/*/*", func(th *testHandler) {
+			requestTest := &depositRequestTest{Pre: fork.newState(), Post: fork.newState()}
+			th.decodeFile("deposit_request", &requestTest.DepositRequest)
+			th.decodeFile("pre", requestTest.Pre)
+			ok := th.decodeFile("post", requestTest.Post, true)
 
-		if !ok {
-			t.Fatal("it should fail")
+			if err := th.transitioner.ProcessDepositRequest(requestTest.Pre, &requestTest.DepositRequest); err != nil {
+				if ok {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if !ok {
+				t.Fatal("it should fail")
+			}
+			if !reflect.DeepEqual(requestTest.Pre, requestTest.Post) {
+				t.Fatal("bad")
+			}
+		})
+	}
+}
+
+func TestOpWithdrawalRequest(t *testing.T) {
+	type withdrawalRequestTest struct {
+		WithdrawalRequest consensus.WithdrawalRequest
+		Pre               consensus.BeaconState
+		Post              consensus.BeaconState
+	}
+
+	for _, fork := range testForks {
+		if fork.version < consensus.VersionElectra {
+			continue
 		}
-		if !reflect.DeepEqual(voluntaryExitTest.Pre, voluntaryExitTest.Post) {
-			t.Fatal("bad")
+		listTestData(t, ""+fork.name+"/operations/withdrawal_request/*/*", func(th *testHandler) {
+			requestTest := &withdrawalRequestTest{Pre: fork.newState(), Post: fork.newState()}
+			th.decodeFile("withdrawal_request", &requestTest.WithdrawalRequest)
+			th.decodeFile("pre", requestTest.Pre)
+			ok := th.decodeFile("post", requestTest.Post, true)
+
+			if err := th.transitioner.ProcessWithdrawalRequest(requestTest.Pre, &requestTest.WithdrawalRequest); err != nil {
+				if ok {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if !ok {
+				t.Fatal("it should fail")
+			}
+			if !reflect.DeepEqual(requestTest.Pre, requestTest.Post) {
+				t.Fatal("bad")
+			}
+		})
+	}
+}
+
+func TestOpConsolidationRequest(t *testing.T) {
+	type consolidationRequestTest struct {
+		ConsolidationRequest consensus.ConsolidationRequest
+		Pre                  consensus.BeaconState
+		Post                 consensus.BeaconState
+	}
+
+	for _, fork := range testForks {
+		if fork.version < consensus.VersionElectra {
+			continue
 		}
-	})
+		listTestData(t, ""+fork.name+"/operations/consolidation_request/*/*", func(th *testHandler) {
+			requestTest := &consolidationRequestTest{Pre: fork.newState(), Post: fork.newState()}
+			th.decodeFile("consolidation_request", &requestTest.ConsolidationRequest)
+			th.decodeFile("pre", requestTest.Pre)
+			ok := th.decodeFile("post", requestTest.Post, true)
+
+			if err := th.transitioner.ProcessConsolidationRequest(requestTest.Pre, &requestTest.ConsolidationRequest); err != nil {
+				if ok {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if !ok {
+				t.Fatal("it should fail")
+			}
+			if !reflect.DeepEqual(requestTest.Pre, requestTest.Post) {
+				t.Fatal("bad")
+			}
+		})
+	}
 }
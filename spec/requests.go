@@ -0,0 +1,274 @@
+package spec
+
+import (
+	"fmt"
+
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+const (
+	// unsetDepositRequestsStartIndex is the start index of the deposit requests before the first one is processed
+	unsetDepositRequestsStartIndex = 18446744073709551615 // 2**64-1
+
+	// fullExitRequestAmount is the amount of a withdrawal request that exits the validator
+	fullExitRequestAmount = 0
+)
+
+// ProcessDepositRequest processes a deposit request of the execution layer (EIP-6110)
+func (s *StateTransitioner) ProcessDepositRequest(state consensus.BeaconState, depositRequest *consensus.DepositRequest) error {
+	obj, err := s.toElectraState(state)
+	if err != nil {
+		return err
+	}
+	processDepositRequest(obj, depositRequest)
+	return nil
+}
+
+// ProcessWithdrawalRequest processes a withdrawal request of the execution layer (EIP-7002)
+func (s *StateTransitioner) ProcessWithdrawalRequest(state consensus.BeaconState, withdrawalRequest *consensus.WithdrawalRequest) error {
+	obj, err := s.toElectraState(state)
+	if err != nil {
+		return err
+	}
+	return s.processWithdrawalRequest(obj, withdrawalRequest)
+}
+
+// ProcessConsolidationRequest processes a consolidation request of the execution layer (EIP-7251)
+func (s *StateTransitioner) ProcessConsolidationRequest(state consensus.BeaconState, consolidationRequest *consensus.ConsolidationRequest) error {
+	obj, err := s.toElectraState(state)
+	if err != nil {
+		return err
+	}
+	s.processConsolidationRequest(obj, consolidationRequest)
+	return nil
+}
+
+func (s *StateTransitioner) toElectraState(state consensus.BeaconState) (*beaconState, error) {
+	obj, err := toBeaconState(state)
+	if err != nil {
+		return nil, err
+	}
+	if !obj.atLeast(consensus.VersionElectra) {
+		return nil, fmt.Errorf("beacon state %T does not have execution requests", state)
+	}
+	return obj, nil
+}
+
+func processDepositRequest(state *beaconState, depositRequest *consensus.DepositRequest) {
+	// Set deposit request start index
+	if *state.depositRequestsStartIndex == unsetDepositRequestsStartIndex {
+		*state.depositRequestsStartIndex = depositRequest.Index
+	}
+
+	// Create pending deposit
+	*state.pendingDeposits = append(*state.pendingDeposits, &consensus.PendingDeposit{
+		Pubkey:                depositRequest.Pubkey,
+		WithdrawalCredentials: depositRequest.WithdrawalCredentials,
+		Amount:                depositRequest.Amount,
+		Signature:             depositRequest.Signature,
+		Slot:                  *state.slot,
+	})
+}
+
+// getPendingBalanceToWithdraw returns the balance of the validator in the pending partial withdrawals
+func (s *StateTransitioner) getPendingBalanceToWithdraw(state *beaconState, validatorIndex uint64) uint64 {
+	amount := uint64(0)
+	for _, withdrawal := range *state.pendingPartialWithdrawals {
+		if withdrawal.ValidatorIndex == validatorIndex {
+			amount += withdrawal.Amount
+		}
+	}
+	return amount
+}
+
+// processWithdrawalRequest processes the request. Invalid requests are ignored since they
+// come from the execution layer and cannot invalidate the block.
+func (s *StateTransitioner) processWithdrawalRequest(state *beaconState, withdrawalRequest *consensus.WithdrawalRequest) error {
+	amount := withdrawalRequest.Amount
+	isFullExitRequest := amount == fullExitRequestAmount
+
+	// If partial withdrawal queue is full, only full exits are processed
+	if uint64(len(*state.pendingPartialWithdrawals)) == s.spec.PendingPartialWithdrawalsLimit && !isFullExitRequest {
+		return nil
+	}
+
+	// Verify pubkey exists
+	index, ok := isInValidatorSet(state, withdrawalRequest.ValidatorPubkey)
+	if !ok {
+		return nil
+	}
+	validator := (*state.validators)[index]
+
+	// Verify withdrawal credentials
+	if !hasExecutionWithdrawalCredential(validator) || executionAddress(validator) != withdrawalRequest.SourceAddress {
+		return nil
+	}
+	// Verify the validator is active
+	currentEpoch := s.getCurrentEpoch(state)
+	if !isActiveValidator(validator, currentEpoch) {
+		return nil
+	}
+	// Verify exit has not been initiated
+	if validator.ExitEpoch != farFutureEpoch {
+		return nil
+	}
+	// Verify the validator has been active long enough
+	if currentEpoch < validator.ActivationEpoch+s.spec.ShardCommiteePeriod {
+		return nil
+	}
+
+	pendingBalanceToWithdraw := s.getPendingBalanceToWithdraw(state, index)
+
+	if isFullExitRequest {
+		// Only exit validator if it has no pending withdrawals in the queue
+		if pendingBalanceToWithdraw == 0 {
+			return s.initiateValidatorExit(state, index)
+		}
+		return nil
+	}
+
+	balance := (*state.balances)[index]
+	hasSufficientEffectiveBalance := validator.EffectiveBalance >= s.spec.MinActivationBalance
+	hasExcessBalance := balance > s.spec.MinActivationBalance+pendingBalanceToWithdraw
+
+	// Only allow partial withdrawals with compounding withdrawal credentials
+	if hasCompoundingWithdrawalCredential(validator) && hasSufficientEffectiveBalance && hasExcessBalance {
+		toWithdraw := min(balance-s.spec.MinActivationBalance-pendingBalanceToWithdraw, amount)
+		exitQueueEpoch := s.computeExitEpochAndUpdateChurn(state, toWithdraw)
+		withdrawableEpoch := exitQueueEpoch + s.spec.MinValidatorWithdrawabilityDelay
+
+		*state.pendingPartialWithdrawals = append(*state.pendingPartialWithdrawals, &consensus.PendingPartialWithdrawal{
+			ValidatorIndex:    index,
+			Amount:            toWithdraw,
+			WithdrawableEpoch: withdrawableEpoch,
+		})
+	}
+	return nil
+}
+
+func (s *StateTransitioner) isValidSwitchToCompoundingRequest(state *beaconState, consolidationRequest *consensus.ConsolidationRequest) bool {
+	// Switch to compounding requires source and target be equal
+	if consolidationRequest.SourcePubkey != consolidationRequest.TargetPubkey {
+		return false
+	}
+
+	// Verify source pubkey exists
+	index, ok := isInValidatorSet(state, consolidationRequest.SourcePubkey)
+	if !ok {
+		return false
+	}
+	validator := (*state.validators)[index]
+
+	// Verify request has been authorized
+	if executionAddress(validator) != consolidationRequest.SourceAddress {
+		return false
+	}
+	// Verify source withdrawal credentials
+	if !hasEth1WithdrawalCredential(validator) {
+		return false
+	}
+	// Verify the source is active
+	if !isActiveValidator(validator, s.getCurrentEpoch(state)) {
+		return false
+	}
+	// Verify exit for source has not been initiated
+	return validator.ExitEpoch == farFutureEpoch
+}
+
+func (s *StateTransitioner) switchToCompoundingValidator(state *beaconState, index uint64) {
+	validator := (*state.validators)[index]
+	validator.WithdrawalCredentials[0] = compoundingWithdrawalPrefix
+	s.queueExcessActiveBalance(state, index)
+}
+
+func (s *StateTransitioner) queueExcessActiveBalance(state *beaconState, index uint64) {
+	balance := (*state.balances)[index]
+	if balance <= s.spec.MinActivationBalance {
+		return
+	}
+	excessBalance := balance - s.spec.MinActivationBalance
+	(*state.balances)[index] = s.spec.MinActivationBalance
+
+	validator := (*state.validators)[index]
+
+	// Use bls.G2_POINT_AT_INFINITY as a signature field placeholder and
+	// GENESIS_SLOT to distinguish from a pending deposit request
+	*state.pendingDeposits = append(*state.pendingDeposits, &consensus.PendingDeposit{
+		Pubkey:                validator.Pubkey,
+		WithdrawalCredentials: validator.WithdrawalCredentials,
+		Amount:                excessBalance,
+		Signature:             consensus.Signature{0xc0},
+		Slot:                  s.spec.GenesisSlot,
+	})
+}
+
+// processConsolidationRequest processes the request. Like the withdrawal requests, the
+// invalid ones are ignored.
+func (s *StateTransitioner) processConsolidationRequest(state *beaconState, consolidationRequest *consensus.ConsolidationRequest) {
+	if s.isValidSwitchToCompoundingRequest(state, consolidationRequest) {
+		sourceIndex, _ := isInValidatorSet(state, consolidationRequest.SourcePubkey)
+		s.switchToCompoundingValidator(state, sourceIndex)
+		return
+	}
+
+	// Verify that source != target, so a consolidation cannot be used as an exit
+	if consolidationRequest.SourcePubkey == consolidationRequest.TargetPubkey {
+		return
+	}
+	// If the pending consolidations queue is full, consolidation requests are ignored
+	if uint64(len(*state.pendingConsolidations)) == s.spec.PendingConsolidationsLimit {
+		return
+	}
+	// If there is too little available consolidation churn limit, consolidation requests are ignored
+	if s.getConsolidationChurnLimit(state) <= s.spec.MinActivationBalance {
+		return
+	}
+
+	// Verify pubkeys exists
+	sourceIndex, ok := isInValidatorSet(state, consolidationRequest.SourcePubkey)
+	if !ok {
+		return
+	}
+	targetIndex, ok := isInValidatorSet(state, consolidationRequest.TargetPubkey)
+	if !ok {
+		return
+	}
+	sourceValidator := (*state.validators)[sourceIndex]
+	targetValidator := (*state.validators)[targetIndex]
+
+	// Verify source withdrawal credentials
+	if !hasExecutionWithdrawalCredential(sourceValidator) || executionAddress(sourceValidator) != consolidationRequest.SourceAddress {
+		return
+	}
+	// Verify that target has compounding withdrawal credentials
+	if !hasCompoundingWithdrawalCredential(targetValidator) {
+		return
+	}
+
+	// Verify the source and the target are active
+	currentEpoch := s.getCurrentEpoch(state)
+	if !isActiveValidator(sourceValidator, currentEpoch) || !isActiveValidator(targetValidator, currentEpoch) {
+		return
+	}
+	// Verify exits for source and target have not been initiated
+	if sourceValidator.ExitEpoch != farFutureEpoch || targetValidator.ExitEpoch != farFutureEpoch {
+		return
+	}
+	// Verify the source has been active long enough
+	if currentEpoch < sourceValidator.ActivationEpoch+s.spec.ShardCommiteePeriod {
+		return
+	}
+	// Verify the source has no pending withdrawals in the queue
+	if s.getPendingBalanceToWithdraw(state, sourceIndex) > 0 {
+		return
+	}
+
+	// Initiate source validator exit and append pending consolidation
+	sourceValidator.ExitEpoch = s.computeConsolidationEpochAndUpdateChurn(state, sourceValidator.EffectiveBalance)
+	sourceValidator.WithdrawableEpoch = sourceValidator.ExitEpoch + s.spec.MinValidatorWithdrawabilityDelay
+
+	*state.pendingConsolidations = append(*state.pendingConsolidations, &consensus.PendingConsolidation{
+		SourceIndex: sourceIndex,
+		TargetIndex: targetIndex,
+	})
+}
@@ -53,10 +53,10 @@ func (d *Deltas) UnmarshalSSZ(buf []byte) error {
 	return nil
 }
 
-func getMatchingHeadAttestations(state *consensus.BeaconStatePhase0, epoch uint64) []*consensus.PendingAttestation {
+func (s *StateTransitioner) getMatchingHeadAttestations(state *beaconState, epoch uint64) []*consensus.PendingAttestation {
 	res := []*consensus.PendingAttestation{}
-	for _, a := range getMatchingTargetAttestations(state, epoch) {
-		root := getBlockRootAtSlot(state, a.Data.Slot)
+	for _, a := range s.getMatchingTargetAttestations(state, epoch) {
+		root := s.getBlockRootAtSlot(state, a.Data.Slot)
 
 		if bytes.Equal(a.Data.BeaconBlockHash[:], root[:]) {
 			res = append(res, a)
@@ -66,95 +66,206 @@ func getMatchingHeadAttestations(state *consensus.BeaconStatePhase0, epoch uint6
 	return res
 }
 
-func getMatchingSourceAttestations(state *consensus.BeaconStatePhase0, epoch uint64) []*consensus.PendingAttestation {
-	if epoch == getCurrentEpoch(state) {
-		return state.CurrentEpochAttestations
+func (s *StateTransitioner) getMatchingSourceAttestations(state *beaconState, epoch uint64) []*consensus.PendingAttestation {
+	if epoch == s.getCurrentEpoch(state) {
+		return *state.currentEpochAttestations
 	}
-	return state.PreviousEpochAttestations
+	return *state.previousEpochAttestations
 }
 
-func getTargetDeltas(state *consensus.BeaconStatePhase0) ([]uint64, []uint64) {
-	return getAttestationComponentDeltas(state, getMatchingTargetAttestations(state, getPreviousEpoch(state)))
+func (s *StateTransitioner) getTargetDeltas(state *beaconState) ([]uint64, []uint64) {
+	return s.getAttestationComponentDeltas(state, s.getMatchingTargetAttestations(state, s.getPreviousEpoch(state)))
 }
 
-func getHeadDeltas(state *consensus.BeaconStatePhase0) ([]uint64, []uint64) {
-	return getAttestationComponentDeltas(state, getMatchingHeadAttestations(state, getPreviousEpoch(state)))
+func (s *StateTransitioner) getHeadDeltas(state *beaconState) ([]uint64, []uint64) {
+	return s.getAttestationComponentDeltas(state, s.getMatchingHeadAttestations(state, s.getPreviousEpoch(state)))
 }
 
-func getSourceDeltas(state *consensus.BeaconStatePhase0) ([]uint64, []uint64) {
-	return getAttestationComponentDeltas(state, getMatchingSourceAttestations(state, getPreviousEpoch(state)))
+func (s *StateTransitioner) getSourceDeltas(state *beaconState) ([]uint64, []uint64) {
+	return s.getAttestationComponentDeltas(state, s.getMatchingSourceAttestations(state, s.getPreviousEpoch(state)))
 }
 
-func getPreviousEpoch(state *consensus.BeaconStatePhase0) uint64 {
-	curEpoch := getCurrentEpoch(state)
+func (s *StateTransitioner) getPreviousEpoch(state *beaconState) uint64 {
+	curEpoch := s.getCurrentEpoch(state)
 	if curEpoch == 0 {
 		return 0
 	}
 	return curEpoch - 1
 }
 
-func getCurrentEpoch(state *consensus.BeaconStatePhase0) uint64 {
-	return state.Slot / Spec.SlotsPerEpoch
+func (s *StateTransitioner) getCurrentEpoch(state *beaconState) uint64 {
+	return *state.slot / s.spec.SlotsPerEpoch
 }
 
-func getAttestationComponentDeltas(state *consensus.BeaconStatePhase0, attestations []*consensus.PendingAttestation) ([]uint64, []uint64) {
-	numValidators := len(state.Validators)
+func (s *StateTransitioner) getAttestationComponentDeltas(state *beaconState, attestations []*consensus.PendingAttestation) ([]uint64, []uint64) {
+	numValidators := len(*state.validators)
 	rewards := make([]uint64, numValidators)
 	penalties := make([]uint64, numValidators)
 
-	totalBalance := getTotalActiveBalance(state)
+	totalBalance := s.getTotalActiveBalance(state)
 
-	unslashedAttestingIndices, err := getUnslashedAttestingIndices(state, attestations)
+	unslashedAttestingIndices, err := s.getUnslashedAttestingIndices(state, attestations)
 	if err != nil {
 		panic(err)
 	}
 
-	attestingBalance := getTotalBalance(state, unslashedAttestingIndices)
+	attestingBalance := s.getTotalBalance(state, unslashedAttestingIndices)
 
 	unslashedAttestingIndicesMap := make(map[uint64]bool)
 	for _, i := range unslashedAttestingIndices {
 		unslashedAttestingIndicesMap[i] = true
 	}
 
-	for _, indx := range getElegibleValidatorIndices(state) {
+	for _, indx := range s.getElegibleValidatorIndices(state) {
 		if unslashedAttestingIndicesMap[indx] {
 			// reward
-			increment := Spec.EffectiveBalanceIncrement
-			if isInInactivityLeak(state) {
-				rewards[indx] += getBaseReward(state, indx)
+			increment := s.spec.EffectiveBalanceIncrement
+			if s.isInInactivityLeak(state) {
+				rewards[indx] += s.getBaseReward(state, indx)
 			} else {
-				rewardNumerator := getBaseReward(state, indx) * (attestingBalance / increment)
+				rewardNumerator := s.getBaseReward(state, indx) * (attestingBalance / increment)
 				rewards[indx] += rewardNumerator / (totalBalance / increment)
 			}
 		} else {
 			// penalty
-			penalties[indx] += getBaseReward(state, indx)
+			penalties[indx] += s.getBaseReward(state, indx)
 		}
 	}
 
 	return rewards, penalties
 }
 
-func getBaseReward(state *consensus.BeaconStatePhase0, index uint64) uint64 {
-	totalBalance := getTotalActiveBalance(state)
-	effectiveBalance := state.Validators[index].EffectiveBalance
+func (s *StateTransitioner) getBaseReward(state *beaconState, index uint64) uint64 {
+	effectiveBalance := (*state.validators)[index].EffectiveBalance
 
-	return effectiveBalance * Spec.BaseRewardFactor / integerSquareRoot(totalBalance) / Spec.BaseRewardsPerEpoch
+	if !state.atLeast(consensus.VersionAltair) {
+		totalBalance := s.getTotalActiveBalance(state)
+		return effectiveBalance * s.spec.BaseRewardFactor / integerSquareRoot(totalBalance) / s.spec.BaseRewardsPerEpoch
+	}
+
+	increments := effectiveBalance / s.spec.EffectiveBalanceIncrement
+	return increments * s.getBaseRewardPerIncrement(state)
 }
 
-func getFinalityDelay(state *consensus.BeaconStatePhase0) uint64 {
-	return getPreviousEpoch(state) - state.FinalizedCheckpoint.Epoch
+func (s *StateTransitioner) getBaseRewardPerIncrement(state *beaconState) uint64 {
+	return s.spec.EffectiveBalanceIncrement * s.spec.BaseRewardFactor / integerSquareRoot(s.getTotalActiveBalance(state))
 }
 
-func isInInactivityLeak(state *consensus.BeaconStatePhase0) bool {
-	return getFinalityDelay(state) > Spec.MinEpochsToInactivityPenalty
+const (
+	timelySourceFlagIndex = 0
+	timelyTargetFlagIndex = 1
+	timelyHeadFlagIndex   = 2
+)
+
+const (
+	syncRewardWeight  uint64 = 2
+	proposerWeight    uint64 = 8
+	weightDenominator uint64 = 64
+)
+
+// participationFlagWeights are the reward weights of the timely source,
+// target and head flags indexed by flag index.
+var participationFlagWeights = []uint64{14, 26, 14}
+
+func hasFlag(flags byte, flagIndex int) bool {
+	return flags&(1<<flagIndex) != 0
 }
 
-func getElegibleValidatorIndices(state *consensus.BeaconStatePhase0) []uint64 {
-	previousEpoch := getPreviousEpoch(state)
+func addFlag(flags byte, flagIndex int) byte {
+	return flags | (1 << flagIndex)
+}
+
+func (s *StateTransitioner) getUnslashedParticipatingIndices(state *beaconState, flagIndex int, epoch uint64) []uint64 {
+	var epochParticipation []byte
+	if epoch == s.getCurrentEpoch(state) {
+		epochParticipation = *state.currentEpochParticipation
+	} else {
+		epochParticipation = *state.previousEpochParticipation
+	}
 
 	res := []uint64{}
-	for indx, val := range state.Validators {
+	for _, indx := range getActiveValidatorIndices(state, epoch) {
+		if hasFlag(epochParticipation[indx], flagIndex) && !(*state.validators)[indx].Slashed {
+			res = append(res, indx)
+		}
+	}
+	return res
+}
+
+func (s *StateTransitioner) getFlagIndexDeltas(state *beaconState, flagIndex int) ([]uint64, []uint64) {
+	numValidators := len(*state.validators)
+	rewards := make([]uint64, numValidators)
+	penalties := make([]uint64, numValidators)
+
+	previousEpoch := s.getPreviousEpoch(state)
+	unslashedParticipatingIndices := s.getUnslashedParticipatingIndices(state, flagIndex, previousEpoch)
+
+	unslashedParticipatingIndicesMap := make(map[uint64]bool)
+	for _, i := range unslashedParticipatingIndices {
+		unslashedParticipatingIndicesMap[i] = true
+	}
+
+	weight := participationFlagWeights[flagIndex]
+	unslashedParticipatingIncrements := s.getTotalBalance(state, unslashedParticipatingIndices) / s.spec.EffectiveBalanceIncrement
+	activeIncrements := s.getTotalActiveBalance(state) / s.spec.EffectiveBalanceIncrement
+	baseRewardPerIncrement := s.getBaseRewardPerIncrement(state)
+	isInLeak := s.isInInactivityLeak(state)
+
+	for _, indx := range s.getElegibleValidatorIndices(state) {
+		baseReward := (*state.validators)[indx].EffectiveBalance / s.spec.EffectiveBalanceIncrement * baseRewardPerIncrement
+
+		if unslashedParticipatingIndicesMap[indx] {
+			if !isInLeak {
+				rewardNumerator := baseReward * weight * unslashedParticipatingIncrements
+				rewards[indx] += rewardNumerator / (activeIncrements * weightDenominator)
+			}
+		} else if flagIndex != timelyHeadFlagIndex {
+			penalties[indx] += baseReward * weight / weightDenominator
+		}
+	}
+
+	return rewards, penalties
+}
+
+func (s *StateTransitioner) getInactivityPenaltyDeltasAltair(state *beaconState) ([]uint64, []uint64) {
+	numValidators := len(*state.validators)
+	rewards := make([]uint64, numValidators)
+	penalties := make([]uint64, numValidators)
+
+	previousEpoch := s.getPreviousEpoch(state)
+	matchingTargetIndices := s.getUnslashedParticipatingIndices(state, timelyTargetFlagIndex, previousEpoch)
+
+	for _, indx := range s.getElegibleValidatorIndices(state) {
+		if !contains(matchingTargetIndices, indx) {
+			penaltyNumerator := (*state.validators)[indx].EffectiveBalance * (*state.inactivityScores)[indx]
+			penaltyDenominator := s.spec.InactivityScoreBias * s.inactivityPenaltyQuotient(state)
+			penalties[indx] += penaltyNumerator / penaltyDenominator
+		}
+	}
+
+	return rewards, penalties
+}
+
+func (s *StateTransitioner) inactivityPenaltyQuotient(state *beaconState) uint64 {
+	if state.atLeast(consensus.VersionBellatrix) {
+		return s.spec.InactivityPenaltyQuotientBellatrix
+	}
+	return s.spec.InactivityPenaltyQuotientAltair
+}
+
+func (s *StateTransitioner) getFinalityDelay(state *beaconState) uint64 {
+	return s.getPreviousEpoch(state) - (*state.finalizedCheckpoint).Epoch
+}
+
+func (s *StateTransitioner) isInInactivityLeak(state *beaconState) bool {
+	return s.getFinalityDelay(state) > s.spec.MinEpochsToInactivityPenalty
+}
+
+func (s *StateTransitioner) getElegibleValidatorIndices(state *beaconState) []uint64 {
+	previousEpoch := s.getPreviousEpoch(state)
+
+	res := []uint64{}
+	for indx, val := range *state.validators {
 		if isActiveValidator(val, previousEpoch) || (val.Slashed && previousEpoch+1 < val.WithdrawableEpoch) {
 			res = append(res, uint64(indx))
 		}
@@ -163,12 +274,12 @@ func getElegibleValidatorIndices(state *consensus.BeaconStatePhase0) []uint64 {
 	return res
 }
 
-func getUnslashedAttestingIndices(state *consensus.BeaconStatePhase0, attestations []*consensus.PendingAttestation) ([]uint64, error) {
+func (s *StateTransitioner) getUnslashedAttestingIndices(state *beaconState, attestations []*consensus.PendingAttestation) ([]uint64, error) {
 	output := make([]uint64, 0)
 	seen := make(map[uint64]bool)
 
 	for _, a := range attestations {
-		indices, err := getAttestingIndices(state, a.Data, a.AggregationBits)
+		indices, err := s.getAttestingIndices(state, a.Data, a.AggregationBits)
 		if err != nil {
 			return nil, err
 		}
@@ -188,7 +299,7 @@ func getUnslashedAttestingIndices(state *consensus.BeaconStatePhase0, attestatio
 	// Remove slashed validator indices.
 	ret := make([]uint64, 0)
 	for i := range output {
-		val := state.Validators[output[i]]
+		val := (*state.validators)[output[i]]
 		if !val.Slashed {
 			ret = append(ret, output[i])
 		}
@@ -196,10 +307,10 @@ func getUnslashedAttestingIndices(state *consensus.BeaconStatePhase0, attestatio
 	return ret, nil
 }
 
-func getAttestingIndices(state *consensus.BeaconStatePhase0, data *consensus.AttestationData, bits []byte) ([]uint64, error) {
+func (s *StateTransitioner) getAttestingIndices(state *beaconState, data *consensus.AttestationData, bits []byte) ([]uint64, error) {
 	blist := bitlist.BitList(bits)
 
-	committee := getBeaconCommittee(state, data.Slot, data.Index)
+	committee := s.getBeaconCommittee(state, data.Slot, data.Index)
 
 	if blist.Len() != uint64(len(committee)) {
 		return nil, fmt.Errorf("bad size")
@@ -214,27 +325,27 @@ func getAttestingIndices(state *consensus.BeaconStatePhase0, data *consensus.Att
 	return res, nil
 }
 
-func getBeaconCommittee(state *consensus.BeaconStatePhase0, slot uint64, index uint64) []uint64 {
-	epoch := computeEpochAtSlot(slot)
-	committeesPerSlot := getCommitteeCountPerSlot(state, epoch)
+func (s *StateTransitioner) getBeaconCommittee(state *beaconState, slot uint64, index uint64) []uint64 {
+	epoch := s.computeEpochAtSlot(slot)
+	committeesPerSlot := s.getCommitteeCountPerSlot(state, epoch)
 
-	seed := getSeed(state, epoch, consensus.DomainBeaconAttesterType)
+	seed := s.getSeed(state, epoch, consensus.DomainBeaconAttesterType)
 	active := getActiveValidatorIndices(state, epoch)
 
-	return computeCommittee(
+	return s.computeCommittee(
 		active,
 		seed,
-		(slot%Spec.SlotsPerEpoch)*committeesPerSlot+index,
-		committeesPerSlot*Spec.SlotsPerEpoch,
+		(slot%s.spec.SlotsPerEpoch)*committeesPerSlot+index,
+		committeesPerSlot*s.spec.SlotsPerEpoch,
 	)
 }
 
-func getCommitteeCountPerSlot(state *consensus.BeaconStatePhase0, epoch uint64) uint64 {
-	return max(1, min(Spec.MaxCommitteesPerSlot, uint64(len(getActiveValidatorIndices(state, epoch)))/Spec.SlotsPerEpoch/Spec.TargetCommitteeSize))
+func (s *StateTransitioner) getCommitteeCountPerSlot(state *beaconState, epoch uint64) uint64 {
+	return max(1, min(s.spec.MaxCommitteesPerSlot, uint64(len(getActiveValidatorIndices(state, epoch)))/s.spec.SlotsPerEpoch/s.spec.TargetCommitteeSize))
 }
 
-func getSeed(state *consensus.BeaconStatePhase0, epoch uint64, domain consensus.Domain) consensus.Root {
-	mix := getRandaoMix(state, epoch+Spec.EpochsPerHistoricalVector-Spec.MinSeedLookAhead-1)
+func (s *StateTransitioner) getSeed(state *beaconState, epoch uint64, domain consensus.Domain) consensus.Root {
+	mix := s.getRandaoMix(state, epoch+s.spec.EpochsPerHistoricalVector-s.spec.MinSeedLookAhead-1)
 
 	epochBuf := make([]byte, 8)
 	binary.LittleEndian.PutUint64(epochBuf, epoch)
@@ -250,8 +361,8 @@ func getSeed(state *consensus.BeaconStatePhase0, epoch uint64, domain consensus.
 	return root
 }
 
-func getRandaoMix(state *consensus.BeaconStatePhase0, epoch uint64) [32]byte {
-	return state.RandaoMixes[epoch%Spec.EpochsPerHistoricalVector]
+func (s *StateTransitioner) getRandaoMix(state *beaconState, epoch uint64) [32]byte {
+	return state.randaoMixes[epoch%s.spec.EpochsPerHistoricalVector]
 }
 
 func max(i, j uint64) uint64 {
@@ -268,13 +379,13 @@ func min(i, j uint64) uint64 {
 	return j
 }
 
-func computeEpochAtSlot(slot uint64) uint64 {
-	return slot / Spec.SlotsPerEpoch
+func (s *StateTransitioner) computeEpochAtSlot(slot uint64) uint64 {
+	return slot / s.spec.SlotsPerEpoch
 }
 
-func getActiveValidatorIndices(state *consensus.BeaconStatePhase0, epoch uint64) []uint64 {
+func getActiveValidatorIndices(state *beaconState, epoch uint64) []uint64 {
 	activeValidators := []uint64{}
-	for indx, val := range state.Validators {
+	for indx, val := range *state.validators {
 		if isActiveValidator(val, epoch) {
 			activeValidators = append(activeValidators, uint64(indx))
 		}
@@ -287,39 +398,45 @@ func isActiveValidator(val *consensus.Validator, epoch uint64) bool {
 	return val.ActivationEpoch <= epoch && epoch < val.ExitEpoch
 }
 
-func getTotalActiveBalance(state *consensus.BeaconStatePhase0) uint64 {
-	return getTotalBalance(state, getActiveValidatorIndices(state, getCurrentEpoch(state)))
+func (s *StateTransitioner) getTotalActiveBalance(state *beaconState) uint64 {
+	return s.getTotalBalance(state, getActiveValidatorIndices(state, s.getCurrentEpoch(state)))
 }
 
-func getTotalBalance(state *consensus.BeaconStatePhase0, indices []uint64) uint64 {
+func (s *StateTransitioner) getTotalBalance(state *beaconState, indices []uint64) uint64 {
+	validators := *state.validators
 	balance := uint64(0)
 
 	for _, indx := range indices {
-		balance += state.Validators[indx].EffectiveBalance
+		balance += validators[indx].EffectiveBalance
 	}
 
-	balance = max(balance, Spec.EffectiveBalanceIncrement)
+	balance = max(balance, s.spec.EffectiveBalanceIncrement)
 	return balance
 }
 
-func computeCommittee(indices []uint64, seed consensus.Root, index, count uint64) []uint64 {
+func (s *StateTransitioner) computeCommittee(indices []uint64, seed consensus.Root, index, count uint64) []uint64 {
 	numActiveValidators := uint64(len(indices))
 
 	start := (numActiveValidators * index) / count
 	end := (numActiveValidators * (index + 1)) / count
 
+	return s.shuffleIndices(indices, seed)[start:end]
+}
+
+// shuffleIndices returns a copy of the indices in the order of the committees
+func (s *StateTransitioner) shuffleIndices(indices []uint64, seed consensus.Root) []uint64 {
 	eth2ShuffleHashFunc := func(data []byte) []byte {
 		hash := sha256.New()
 		hash.Write(data)
 		return hash.Sum(nil)
 	}
 
-	commmittee := make([]uint64, len(indices))
-	copy(commmittee[:], indices)
+	shuffled := make([]uint64, len(indices))
+	copy(shuffled[:], indices)
 
-	eth2_shuffle.UnshuffleList(eth2ShuffleHashFunc, commmittee, shuffleRoundCount, seed)
+	eth2_shuffle.UnshuffleList(eth2ShuffleHashFunc, shuffled, uint8(s.spec.ShuffleRoundCount), seed)
 
-	return commmittee[start:end]
+	return shuffled
 }
 
 func integerSquareRoot(n uint64) uint64 {
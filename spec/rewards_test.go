@@ -7,39 +7,70 @@ import (
 	consensus "github.com/umbracle/go-eth-consensus"
 )
 
-type rewardFunc func(state *consensus.BeaconStatePhase0) ([]uint64, []uint64)
+type rewardFunc func(st *StateTransitioner, state *beaconState) ([]uint64, []uint64)
 
 func TestRewards(t *testing.T) {
-	listTestData(t, "mainnet/phase0/rewards/basic/pyspec_tests/*", func(th *testHandler) {
-		test := &specRewardTest{}
-		test.Decode(th)
-
-		cases := []struct {
-			name  string
-			fn    rewardFunc
-			delta Deltas
-		}{
-			{"source", getSourceDeltas, test.SourceDeltas},
-			{"target", getTargetDeltas, test.TargetDeltas},
-			{"head", getHeadDeltas, test.HeadDeltas},
-			{"inactivity", getInactivityPenaltyDeltas, test.InactivityPenaltyDeltas},
+	altairFlagDeltas := func(flagIndex int) rewardFunc {
+		return func(st *StateTransitioner, state *beaconState) ([]uint64, []uint64) {
+			return st.getFlagIndexDeltas(state, flagIndex)
 		}
+	}
 
-		for _, c := range cases {
-			rewards, penalties := c.fn(&test.Pre)
+	phase0Funcs := map[string]rewardFunc{
+		"source":     (*StateTransitioner).getSourceDeltas,
+		"target":     (*StateTransitioner).getTargetDeltas,
+		"head":       (*StateTransitioner).getHeadDeltas,
+		"inactivity": (*StateTransitioner).getInactivityPenaltyDeltas,
+	}
+	// the deltas are computed with the participation flags since altair
+	altairFuncs := map[string]rewardFunc{
+		"source":     altairFlagDeltas(timelySourceFlagIndex),
+		"target":     altairFlagDeltas(timelyTargetFlagIndex),
+		"head":       altairFlagDeltas(timelyHeadFlagIndex),
+		"inactivity": (*StateTransitioner).getInactivityPenaltyDeltasAltair,
+	}
 
-			if !reflect.DeepEqual(rewards, c.delta.Rewards) {
-				t.Fatalf("bad '%s' rewards: %s", c.name, th.path)
+	for _, fork := range testForks {
+		funcs := altairFuncs
+		if fork.version == consensus.VersionPhase0 {
+			funcs = phase0Funcs
+		}
+
+		listTestData(t, ""+fork.name+"/rewards/basic/pyspec_tests/*", func(th *testHandler) {
+			test := &specRewardTest{Pre: fork.newState()}
+			test.Decode(th)
+
+			state, err := toBeaconState(test.Pre)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cases := []struct {
+				name  string
+				delta Deltas
+			}{
+				{"source", test.SourceDeltas},
+				{"target", test.TargetDeltas},
+				{"head", test.HeadDeltas},
+				{"inactivity", test.InactivityPenaltyDeltas},
 			}
-			if !reflect.DeepEqual(penalties, c.delta.Penalties) {
-				t.Fatalf("bad '%s' penalties: %s", c.name, th.path)
+
+			for _, c := range cases {
+				rewards, penalties := funcs[c.name](th.transitioner, state)
+
+				if !reflect.DeepEqual(rewards, c.delta.Rewards) {
+					t.Fatalf("bad '%s' rewards: %s", c.name, th.path)
+				}
+				if !reflect.DeepEqual(penalties, c.delta.Penalties) {
+					t.Fatalf("bad '%s' penalties: %s", c.name, th.path)
+				}
 			}
-		}
-	})
+		})
+	}
 }
 
 type specRewardTest struct {
-	Pre                     consensus.BeaconStatePhase0
+	Pre                     consensus.BeaconState
 	HeadDeltas              Deltas
 	InactivityPenaltyDeltas Deltas
 	SourceDeltas            Deltas
@@ -47,7 +78,7 @@ type specRewardTest struct {
 }
 
 func (s *specRewardTest) Decode(th *testHandler) {
-	th.decodeFile("pre", &s.Pre)
+	th.decodeFile("pre", s.Pre)
 	th.decodeFile("head_deltas", &s.HeadDeltas)
 	th.decodeFile("inactivity_penalty_deltas", &s.InactivityPenaltyDeltas)
 	th.decodeFile("source_deltas", &s.SourceDeltas)
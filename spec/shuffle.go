@@ -8,14 +8,12 @@ import (
 	consensus "github.com/umbracle/go-eth-consensus"
 )
 
-const shuffleRoundCount = 90
-
-func computeShuffleIndex(index, indexCount uint64, seed consensus.Root) uint64 {
+func (s *StateTransitioner) computeShuffleIndex(index, indexCount uint64, seed consensus.Root) uint64 {
 	if index >= indexCount {
 		panic(fmt.Sprintf("BAD: index %d higher than count %d", index, indexCount))
 	}
 
-	for i := 0; i < shuffleRoundCount; i++ {
+	for i := uint64(0); i < s.spec.ShuffleRoundCount; i++ {
 		input := make([]byte, 0, len(seed)+1)
 		input = append(input, seed[:]...)
 		input = append(input, byte(i))
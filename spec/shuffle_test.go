@@ -8,12 +8,12 @@ import (
 )
 
 func TestShuffle(t *testing.T) {
-	listTestData(t, "mainnet/phase0/shuffling/*/*/*", func(th *testHandler) {
+	listTestData(t, "phase0/shuffling/*/*/*", func(th *testHandler) {
 		shuffleTest := &shuffleTest{}
 		shuffleTest.Decode(th)
 
 		for i := uint64(0); i < shuffleTest.Count; i++ {
-			index := computeShuffleIndex(i, shuffleTest.Count, shuffleTest.Seed)
+			index := th.transitioner.computeShuffleIndex(i, shuffleTest.Count, shuffleTest.Seed)
 			require.Equal(t, shuffleTest.Mapping[i], index)
 		}
 	})
@@ -1,7 +1,12 @@
 package spec
 
-import consensus "github.com/umbracle/go-eth-consensus"
+import (
+	"math"
 
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+// Spec is the spec of the mainnet preset
 var Spec = &consensus.Spec{
 	SecondsPerSlot:                   12,
 	SlotsPerEpoch:                    32,
@@ -32,4 +37,183 @@ var Spec = &consensus.Spec{
 	HysteresisUpwardMultiplier:       5,
 	EjectionBalance:                  16000000000, // Gwei(2**4 * 10**9)
 	InactivityPenaltyQuotient:        67108864,    // Gwei(2**26)
+	SyncCommitteeSize:                512,
+	MaxValidatorsPerCommittee:        2048,
+	MinDepositAmount:                 1000000000,
+	HistoricalRootsLimit:             16777216,
+	ValidatorRegistryLimit:           1099511627776,
+	MaxProposerSlashings:             16,
+	MaxAttesterSlashings:             2,
+	MaxAttestations:                  128,
+	MaxDeposits:                      16,
+	MaxVoluntaryExits:                16,
+	ShuffleRoundCount:                90,
+
+	// altair
+	InactivityPenaltyQuotientAltair:      50331648, // 3 * 2**24
+	MinSlashingPenaltyQuotientAltair:     64,
+	ProportionalSlashingMultiplierAltair: 2,
+	EpochsPerSyncCommitteePeriod:         256,
+	MinSyncCommitteeParticipants:         1,
+	InactivityScoreBias:                  4,
+	InactivityScoreRecoveryRate:          16,
+
+	// bellatrix
+	InactivityPenaltyQuotientBellatrix:      16777216, // 2**24
+	MinSlashingPenaltyQuotientBellatrix:     32,
+	ProportionalSlashingMultiplierBellatrix: 3,
+	MaxBytesPerTransaction:                  1073741824,
+	MaxTransactionsPerPayload:               1048576,
+	BytesPerLogsBloom:                       256,
+	MaxExtraDataBytes:                       32,
+
+	// capella
+	MaxBlsToExecutionChanges:         16,
+	MaxWithdrawalsPerPayload:         16,
+	MaxValidatorsPerWithdrawalsSweep: 16384,
+
+	// deneb
+	FieldElementsPerBlob:             4096,
+	MaxBlobCommitmentsPerBlock:       4096,
+	KzgCommitmentInclusionProofDepth: 17,
+	MaxBlobsPerBlock:                 6,
+	MaxPerEpochActivationChurnLimit:  8,
+
+	// electra
+	MinActivationBalance:                  32000000000,
+	MaxEffectiveBalanceElectra:            2048000000000,
+	PendingDepositsLimit:                  134217728,
+	PendingPartialWithdrawalsLimit:        134217728,
+	PendingConsolidationsLimit:            262144,
+	MinSlashingPenaltyQuotientElectra:     4096,
+	WhistleblowerRewardQuotientElectra:    4096,
+	MaxAttesterSlashingsElectra:           1,
+	MaxAttestationsElectra:                8,
+	MaxConsolidationRequestsPerPayload:    2,
+	MaxDepositRequestsPerPayload:          8192,
+	MaxWithdrawalRequestsPerPayload:       16,
+	MaxPendingPartialsPerWithdrawalsSweep: 8,
+	MaxPendingDepositsPerEpoch:            16,
+	MinPerEpochChurnLimitElectra:          128000000000, // Gwei(2**7 * 10**9)
+	MaxPerEpochActivationExitChurnLimit:   256000000000, // Gwei(2**8 * 10**9)
+	MaxBlobsPerBlockElectra:               9,
+
+	// forks
+	GenesisForkVersion:   consensus.Domain{0, 0, 0, 0},
+	AltairForkVersion:    consensus.Domain{1, 0, 0, 0},
+	AltairForkEpoch:      74240,
+	BellatrixForkVersion: consensus.Domain{2, 0, 0, 0},
+	BellatrixForkEpoch:   144896,
+	CapellaForkVersion:   consensus.Domain{3, 0, 0, 0},
+	CapellaForkEpoch:     194048,
+	DenebForkVersion:     consensus.Domain{4, 0, 0, 0},
+	DenebForkEpoch:       269568,
+	ElectraForkVersion:   consensus.Domain{5, 0, 0, 0},
+	ElectraForkEpoch:     364032,
+}
+
+// MinimalSpec is the spec of the minimal preset used by the consensus spec tests
+var MinimalSpec = &consensus.Spec{
+	SecondsPerSlot:                   6,
+	SlotsPerEpoch:                    8,
+	MaxCommitteesPerSlot:             4,
+	EpochsPerHistoricalVector:        64,
+	MinSeedLookAhead:                 1,
+	MinEpochsToInactivityPenalty:     4,
+	EffectiveBalanceIncrement:        1000000000,
+	MaxEffectiveBalance:              32000000000,
+	BaseRewardFactor:                 64,
+	BaseRewardsPerEpoch:              4,
+	TargetCommitteeSize:              4,
+	ShardCommiteePeriod:              64,
+	MaxSeedLookAhead:                 4,
+	ChurnLimitQuotient:               32,
+	MinPerEpochChurnLimit:            2,
+	EpochsPerSlashingsVector:         64,
+	MinSlashingPenaltyQuotient:       64,
+	WhistleblowerRewardQuotient:      512,
+	ProposerRewardQuotient:           8,
+	MinValidatorWithdrawabilityDelay: 256,
+	MinAttestationInclusionDelay:     1,
+	EpochsPerEth1VotingPeriod:        4,
+	SlotsPerHistoricalRoot:           64,
+	ProportionalSlashingsMultiplier:  2,
+	HysteresisQuotient:               4,
+	HysteresisDownwardMultiplier:     1,
+	HysteresisUpwardMultiplier:       5,
+	EjectionBalance:                  16000000000, // Gwei(2**4 * 10**9)
+	InactivityPenaltyQuotient:        33554432,    // Gwei(2**25)
+	SyncCommitteeSize:                32,
+	MaxValidatorsPerCommittee:        2048,
+	MinDepositAmount:                 1000000000,
+	HistoricalRootsLimit:             16777216,
+	ValidatorRegistryLimit:           1099511627776,
+	MaxProposerSlashings:             16,
+	MaxAttesterSlashings:             2,
+	MaxAttestations:                  128,
+	MaxDeposits:                      16,
+	MaxVoluntaryExits:                16,
+	ShuffleRoundCount:                10,
+
+	// altair
+	InactivityPenaltyQuotientAltair:      50331648, // 3 * 2**24
+	MinSlashingPenaltyQuotientAltair:     64,
+	ProportionalSlashingMultiplierAltair: 2,
+	EpochsPerSyncCommitteePeriod:         8,
+	MinSyncCommitteeParticipants:         1,
+	InactivityScoreBias:                  4,
+	InactivityScoreRecoveryRate:          16,
+
+	// bellatrix
+	InactivityPenaltyQuotientBellatrix:      16777216, // 2**24
+	MinSlashingPenaltyQuotientBellatrix:     32,
+	ProportionalSlashingMultiplierBellatrix: 3,
+	MaxBytesPerTransaction:                  1073741824,
+	MaxTransactionsPerPayload:               1048576,
+	BytesPerLogsBloom:                       256,
+	MaxExtraDataBytes:                       32,
+
+	// capella
+	MaxBlsToExecutionChanges:         16,
+	MaxWithdrawalsPerPayload:         4,
+	MaxValidatorsPerWithdrawalsSweep: 16,
+
+	// deneb
+	FieldElementsPerBlob:             4096,
+	MaxBlobCommitmentsPerBlock:       32,
+	KzgCommitmentInclusionProofDepth: 10,
+	MaxBlobsPerBlock:                 6,
+	MaxPerEpochActivationChurnLimit:  4,
+
+	// electra
+	MinActivationBalance:                  32000000000,
+	MaxEffectiveBalanceElectra:            2048000000000,
+	PendingDepositsLimit:                  134217728,
+	PendingPartialWithdrawalsLimit:        64,
+	PendingConsolidationsLimit:            64,
+	MinSlashingPenaltyQuotientElectra:     4096,
+	WhistleblowerRewardQuotientElectra:    4096,
+	MaxAttesterSlashingsElectra:           1,
+	MaxAttestationsElectra:                8,
+	MaxConsolidationRequestsPerPayload:    2,
+	MaxDepositRequestsPerPayload:          4,
+	MaxWithdrawalRequestsPerPayload:       2,
+	MaxPendingPartialsPerWithdrawalsSweep: 2,
+	MaxPendingDepositsPerEpoch:            16,
+	MinPerEpochChurnLimitElectra:          64000000000,  // Gwei(2**6 * 10**9)
+	MaxPerEpochActivationExitChurnLimit:   128000000000, // Gwei(2**7 * 10**9)
+	MaxBlobsPerBlockElectra:               9,
+
+	// forks
+	GenesisForkVersion:   consensus.Domain{0, 0, 0, 1},
+	AltairForkVersion:    consensus.Domain{1, 0, 0, 1},
+	AltairForkEpoch:      math.MaxUint64,
+	BellatrixForkVersion: consensus.Domain{2, 0, 0, 1},
+	BellatrixForkEpoch:   math.MaxUint64,
+	CapellaForkVersion:   consensus.Domain{3, 0, 0, 1},
+	CapellaForkEpoch:     math.MaxUint64,
+	DenebForkVersion:     consensus.Domain{4, 0, 0, 1},
+	DenebForkEpoch:       math.MaxUint64,
+	ElectraForkVersion:   consensus.Domain{5, 0, 0, 1},
+	ElectraForkEpoch:     math.MaxUint64,
 }
@@ -1,25 +1,39 @@
 package spec
 
 import (
-	_ "embed"
 	"fmt"
+	"io/fs"
 	"strings"
 	"testing"
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/stretchr/testify/require"
+	consensus "github.com/umbracle/go-eth-consensus"
 	"gopkg.in/yaml.v2"
 )
 
-//go:embed presets/phase0.yaml
-var mainnetPresetPhase0 []byte
-
 func TestPresetMainnet(t *testing.T) {
-	var out map[string]interface{}
-	require.NoError(t, yaml.Unmarshal(mainnetPresetPhase0, &out))
+	checkPreset(t, Spec, "mainnet")
+}
+
+func TestPresetMinimal(t *testing.T) {
+	checkPreset(t, MinimalSpec, "minimal")
+}
+
+func checkPreset(t *testing.T, spec *consensus.Spec, name string) {
+	matches, err := fs.Glob(presetsFS, "presets/"+name+"/*.yaml")
+	require.NoError(t, err)
+	require.NotEmpty(t, matches)
+
+	out := map[string]interface{}{}
+	for _, match := range matches {
+		preset, err := presetsFS.ReadFile(match)
+		require.NoError(t, err)
+		require.NoError(t, yaml.Unmarshal(preset, &out))
+	}
 
 	var specOut map[string]interface{}
-	require.NoError(t, mapstructure.Decode(Spec, &specOut))
+	require.NoError(t, mapstructure.Decode(spec, &specOut))
 
 	// check that the preset values from 'out' match
 	// the value from the spec struct
@@ -0,0 +1,287 @@
+package spec
+
+import (
+	"fmt"
+
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+// beaconState gives the state transition functions access to the fields of the
+// BeaconState of every fork. The fields point to the ones of the ssz object and
+// the ones that the fork of the state does not have are nil.
+type beaconState struct {
+	obj     consensus.BeaconState
+	version consensus.Version
+
+	genesisTime           *uint64
+	genesisValidatorsRoot *[32]byte
+	slot                  *uint64
+	fork                  **consensus.Fork
+
+	// History
+	latestBlockHeader **consensus.BeaconBlockHeader
+	blockRoots        [][32]byte
+	stateRoots        [][32]byte
+
+	// Eth1
+	eth1Data         **consensus.Eth1Data
+	eth1DataVotes    *[]*consensus.Eth1Data
+	eth1DepositIndex *uint64
+
+	// Registry
+	validators *[]*consensus.Validator
+	balances   *[]uint64
+
+	// Randomness
+	randaoMixes [][32]byte
+
+	// Slashings
+	slashings []uint64
+
+	// Finality
+	justificationBits           *[1]byte
+	previousJustifiedCheckpoint **consensus.Checkpoint
+	currentJustifiedCheckpoint  **consensus.Checkpoint
+	finalizedCheckpoint         **consensus.Checkpoint
+
+	// Attestations (phase0)
+	previousEpochAttestations *[]*consensus.PendingAttestation
+	currentEpochAttestations  *[]*consensus.PendingAttestation
+
+	// Participation (altair)
+	previousEpochParticipation *[]byte
+	currentEpochParticipation  *[]byte
+	inactivityScores           *[]uint64
+	currentSyncCommittee       **consensus.SyncCommittee
+	nextSyncCommittee          **consensus.SyncCommittee
+
+	// Withdrawals (capella)
+	nextWithdrawalIndex          *uint64
+	nextWithdrawalValidatorIndex *uint64
+	historicalSummaries          *[]*consensus.HistoricalSummary
+
+	// Pending balance changes (electra)
+	depositRequestsStartIndex     *uint64
+	depositBalanceToConsume       *uint64
+	exitBalanceToConsume          *uint64
+	earliestExitEpoch             *uint64
+	consolidationBalanceToConsume *uint64
+	earliestConsolidationEpoch    *uint64
+	pendingDeposits               *[]*consensus.PendingDeposit
+	pendingPartialWithdrawals     *[]*consensus.PendingPartialWithdrawal
+	pendingConsolidations         *[]*consensus.PendingConsolidation
+}
+
+func toBeaconState(state consensus.BeaconState) (*beaconState, error) {
+	switch obj := state.(type) {
+	case *consensus.BeaconStatePhase0:
+		return &beaconState{
+			obj:                         obj,
+			version:                     consensus.VersionPhase0,
+			genesisTime:                 &obj.GenesisTime,
+			genesisValidatorsRoot:       &obj.GenesisValidatorsRoot,
+			slot:                        &obj.Slot,
+			fork:                        &obj.Fork,
+			latestBlockHeader:           &obj.LatestBlockHeader,
+			blockRoots:                  obj.BlockRoots[:],
+			stateRoots:                  obj.StateRoots[:],
+			eth1Data:                    &obj.Eth1Data,
+			eth1DataVotes:               &obj.Eth1DataVotes,
+			eth1DepositIndex:            &obj.Eth1DepositIndex,
+			validators:                  &obj.Validators,
+			balances:                    &obj.Balances,
+			randaoMixes:                 obj.RandaoMixes[:],
+			slashings:                   obj.Slashings,
+			justificationBits:           &obj.JustificationBits,
+			previousJustifiedCheckpoint: &obj.PreviousJustifiedCheckpoint,
+			currentJustifiedCheckpoint:  &obj.CurrentJustifiedCheckpoint,
+			finalizedCheckpoint:         &obj.FinalizedCheckpoint,
+			previousEpochAttestations:   &obj.PreviousEpochAttestations,
+			currentEpochAttestations:    &obj.CurrentEpochAttestations,
+		}, nil
+
+	case *consensus.BeaconStateAltair:
+		return &beaconState{
+			obj:                         obj,
+			version:                     consensus.VersionAltair,
+			genesisTime:                 &obj.GenesisTime,
+			genesisValidatorsRoot:       &obj.GenesisValidatorsRoot,
+			slot:                        &obj.Slot,
+			fork:                        &obj.Fork,
+			latestBlockHeader:           &obj.LatestBlockHeader,
+			blockRoots:                  obj.BlockRoots[:],
+			stateRoots:                  obj.StateRoots[:],
+			eth1Data:                    &obj.Eth1Data,
+			eth1DataVotes:               &obj.Eth1DataVotes,
+			eth1DepositIndex:            &obj.Eth1DepositIndex,
+			validators:                  &obj.Validators,
+			balances:                    &obj.Balances,
+			randaoMixes:                 obj.RandaoMixes[:],
+			slashings:                   obj.Slashings,
+			justificationBits:           &obj.JustificationBits,
+			previousJustifiedCheckpoint: &obj.PreviousJustifiedCheckpoint,
+			currentJustifiedCheckpoint:  &obj.CurrentJustifiedCheckpoint,
+			finalizedCheckpoint:         &obj.FinalizedCheckpoint,
+			previousEpochParticipation:  &obj.PreviousEpochParticipation,
+			currentEpochParticipation:   &obj.CurrentEpochParticipation,
+			inactivityScores:            &obj.InactivityScores,
+			currentSyncCommittee:        &obj.CurrentSyncCommittee,
+			nextSyncCommittee:           &obj.NextSyncCommittee,
+		}, nil
+
+	case *consensus.BeaconStateBellatrix:
+		return &beaconState{
+			obj:                         obj,
+			version:                     consensus.VersionBellatrix,
+			genesisTime:                 &obj.GenesisTime,
+			genesisValidatorsRoot:       &obj.GenesisValidatorsRoot,
+			slot:                        &obj.Slot,
+			fork:                        &obj.Fork,
+			latestBlockHeader:           &obj.LatestBlockHeader,
+			blockRoots:                  obj.BlockRoots[:],
+			stateRoots:                  obj.StateRoots[:],
+			eth1Data:                    &obj.Eth1Data,
+			eth1DataVotes:               &obj.Eth1DataVotes,
+			eth1DepositIndex:            &obj.Eth1DepositIndex,
+			validators:                  &obj.Validators,
+			balances:                    &obj.Balances,
+			randaoMixes:                 obj.RandaoMixes[:],
+			slashings:                   obj.Slashings,
+			justificationBits:           &obj.JustificationBits,
+			previousJustifiedCheckpoint: &obj.PreviousJustifiedCheckpoint,
+			currentJustifiedCheckpoint:  &obj.CurrentJustifiedCheckpoint,
+			finalizedCheckpoint:         &obj.FinalizedCheckpoint,
+			previousEpochParticipation:  &obj.PreviousEpochParticipation,
+			currentEpochParticipation:   &obj.CurrentEpochParticipation,
+			inactivityScores:            &obj.InactivityScores,
+			currentSyncCommittee:        &obj.CurrentSyncCommittee,
+			nextSyncCommittee:           &obj.NextSyncCommittee,
+		}, nil
+
+	case *consensus.BeaconStateCapella:
+		return &beaconState{
+			obj:                          obj,
+			version:                      consensus.VersionCapella,
+			genesisTime:                  &obj.GenesisTime,
+			genesisValidatorsRoot:        &obj.GenesisValidatorsRoot,
+			slot:                         &obj.Slot,
+			fork:                         &obj.Fork,
+			latestBlockHeader:            &obj.LatestBlockHeader,
+			blockRoots:                   obj.BlockRoots[:],
+			stateRoots:                   obj.StateRoots[:],
+			eth1Data:                     &obj.Eth1Data,
+			eth1DataVotes:                &obj.Eth1DataVotes,
+			eth1DepositIndex:             &obj.Eth1DepositIndex,
+			validators:                   &obj.Validators,
+			balances:                     &obj.Balances,
+			randaoMixes:                  obj.RandaoMixes[:],
+			slashings:                    obj.Slashings,
+			justificationBits:            &obj.JustificationBits,
+			previousJustifiedCheckpoint:  &obj.PreviousJustifiedCheckpoint,
+			currentJustifiedCheckpoint:   &obj.CurrentJustifiedCheckpoint,
+			finalizedCheckpoint:          &obj.FinalizedCheckpoint,
+			previousEpochParticipation:   &obj.PreviousEpochParticipation,
+			currentEpochParticipation:    &obj.CurrentEpochParticipation,
+			inactivityScores:             &obj.InactivityScores,
+			currentSyncCommittee:         &obj.CurrentSyncCommittee,
+			nextSyncCommittee:            &obj.NextSyncCommittee,
+			nextWithdrawalIndex:          &obj.NextWithdrawalIndex,
+			nextWithdrawalValidatorIndex: &obj.NextWithdrawalValidatorIndex,
+			historicalSummaries:          &obj.HistoricalSummaries,
+		}, nil
+
+	case *consensus.BeaconStateDeneb:
+		return &beaconState{
+			obj:                          obj,
+			version:                      consensus.VersionDeneb,
+			genesisTime:                  &obj.GenesisTime,
+			genesisValidatorsRoot:        &obj.GenesisValidatorsRoot,
+			slot:                         &obj.Slot,
+			fork:                         &obj.Fork,
+			latestBlockHeader:            &obj.LatestBlockHeader,
+			blockRoots:                   obj.BlockRoots[:],
+			stateRoots:                   obj.StateRoots[:],
+			eth1Data:                     &obj.Eth1Data,
+			eth1DataVotes:                &obj.Eth1DataVotes,
+			eth1DepositIndex:             &obj.Eth1DepositIndex,
+			validators:                   &obj.Validators,
+			balances:                     &obj.Balances,
+			randaoMixes:                  obj.RandaoMixes[:],
+			slashings:                    obj.Slashings,
+			justificationBits:            &obj.JustificationBits,
+			previousJustifiedCheckpoint:  &obj.PreviousJustifiedCheckpoint,
+			currentJustifiedCheckpoint:   &obj.CurrentJustifiedCheckpoint,
+			finalizedCheckpoint:          &obj.FinalizedCheckpoint,
+			previousEpochParticipation:   &obj.PreviousEpochParticipation,
+			currentEpochParticipation:    &obj.CurrentEpochParticipation,
+			inactivityScores:             &obj.InactivityScores,
+			currentSyncCommittee:         &obj.CurrentSyncCommittee,
+			nextSyncCommittee:            &obj.NextSyncCommittee,
+			nextWithdrawalIndex:          &obj.NextWithdrawalIndex,
+			nextWithdrawalValidatorIndex: &obj.NextWithdrawalValidatorIndex,
+			historicalSummaries:          &obj.HistoricalSummaries,
+		}, nil
+
+	case *consensus.BeaconStateElectra:
+		return &beaconState{
+			obj:                           obj,
+			version:                       consensus.VersionElectra,
+			genesisTime:                   &obj.GenesisTime,
+			genesisValidatorsRoot:         &obj.GenesisValidatorsRoot,
+			slot:                          &obj.Slot,
+			fork:                          &obj.Fork,
+			latestBlockHeader:             &obj.LatestBlockHeader,
+			blockRoots:                    obj.BlockRoots[:],
+			stateRoots:                    obj.StateRoots[:],
+			eth1Data:                      &obj.Eth1Data,
+			eth1DataVotes:                 &obj.Eth1DataVotes,
+			eth1DepositIndex:              &obj.Eth1DepositIndex,
+			validators:                    &obj.Validators,
+			balances:                      &obj.Balances,
+			randaoMixes:                   obj.RandaoMixes[:],
+			slashings:                     obj.Slashings,
+			justificationBits:             &obj.JustificationBits,
+			previousJustifiedCheckpoint:   &obj.PreviousJustifiedCheckpoint,
+			currentJustifiedCheckpoint:    &obj.CurrentJustifiedCheckpoint,
+			finalizedCheckpoint:           &obj.FinalizedCheckpoint,
+			previousEpochParticipation:    &obj.PreviousEpochParticipation,
+			currentEpochParticipation:     &obj.CurrentEpochParticipation,
+			inactivityScores:              &obj.InactivityScores,
+			currentSyncCommittee:          &obj.CurrentSyncCommittee,
+			nextSyncCommittee:             &obj.NextSyncCommittee,
+			nextWithdrawalIndex:           &obj.NextWithdrawalIndex,
+			nextWithdrawalValidatorIndex:  &obj.NextWithdrawalValidatorIndex,
+			historicalSummaries:           &obj.HistoricalSummaries,
+			depositRequestsStartIndex:     &obj.DepositRequestsStartIndex,
+			depositBalanceToConsume:       &obj.DepositBalanceToConsume,
+			exitBalanceToConsume:          &obj.ExitBalanceToConsume,
+			earliestExitEpoch:             &obj.EarliestExitEpoch,
+			consolidationBalanceToConsume: &obj.ConsolidationBalanceToConsume,
+			earliestConsolidationEpoch:    &obj.EarliestConsolidationEpoch,
+			pendingDeposits:               &obj.PendingDeposits,
+			pendingPartialWithdrawals:     &obj.PendingPartialWithdrawals,
+			pendingConsolidations:         &obj.PendingConsolidations,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("beacon state %T not supported", state)
+	}
+}
+
+// atLeast returns true if the fork of the state is the given one or a later one
+func (s *beaconState) atLeast(version consensus.Version) bool {
+	return s.version >= version
+}
+
+// addValidator appends a new validator and any per validator
+// field that the fork tracks to the registry.
+func (s *beaconState) addValidator(validator *consensus.Validator, balance uint64) {
+	*s.validators = append(*s.validators, validator)
+	*s.balances = append(*s.balances, balance)
+
+	if s.atLeast(consensus.VersionAltair) {
+		*s.previousEpochParticipation = append(*s.previousEpochParticipation, 0)
+		*s.currentEpochParticipation = append(*s.currentEpochParticipation, 0)
+		*s.inactivityScores = append(*s.inactivityScores, 0)
+	}
+}
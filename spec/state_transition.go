@@ -0,0 +1,531 @@
+package spec
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+// StateTransition applies the signed block on top of the state. The slots between the
+// state and the block are processed first. If validateResult is set, the block signature
+// and the resulting state root are checked against the block.
+// The state is modified in place unless a fork boundary is crossed while the slots are
+// processed, in which case the upgraded state is returned. Callers must always
+// continue with the returned state.
+func (s *StateTransitioner) StateTransition(state consensus.BeaconState, signedBlock consensus.SignedBeaconBlock, validateResult bool) (consensus.BeaconState, error) {
+	block, signature, err := splitSignedBlock(signedBlock)
+	if err != nil {
+		return nil, err
+	}
+	header, err := s.toBlockHeader(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Process slots (including those with no blocks) since block
+	obj, err := toBeaconState(state)
+	if err != nil {
+		return nil, err
+	}
+	if obj, err = s.processSlots(obj, header.Slot); err != nil {
+		return nil, err
+	}
+
+	// Verify signature
+	if validateResult {
+		if err := s.verifyBlockSignature(obj, block, header.ProposerIndex, signature); err != nil {
+			return nil, err
+		}
+	}
+
+	// Process block
+	if err := s.processBlock(obj, block); err != nil {
+		return nil, err
+	}
+
+	// Verify state root
+	if validateResult {
+		root, err := s.spec.HashTreeRoot(obj.obj)
+		if err != nil {
+			return nil, err
+		}
+		if stateRoot := blockStateRoot(block); root != stateRoot {
+			return nil, fmt.Errorf("state root mismatch: expected %x but found %x", stateRoot, root)
+		}
+	}
+	return obj.obj, nil
+}
+
+func splitSignedBlock(signedBlock consensus.SignedBeaconBlock) (consensus.BeaconBlock, consensus.Signature, error) {
+	switch signed := signedBlock.(type) {
+	case *consensus.SignedBeaconBlockPhase0:
+		return signed.Block, signed.Signature, nil
+	case *consensus.SignedBeaconBlockAltair:
+		return signed.Block, signed.Signature, nil
+	case *consensus.SignedBeaconBlockBellatrix:
+		return signed.Block, signed.Signature, nil
+	case *consensus.SignedBeaconBlockCapella:
+		return signed.Block, signed.Signature, nil
+	case *consensus.SignedBeaconBlockDeneb:
+		return signed.Block, signed.Signature, nil
+	case *consensus.SignedBeaconBlockElectra:
+		return signed.Block, signed.Signature, nil
+	default:
+		return nil, consensus.Signature{}, fmt.Errorf("signed beacon block %T not supported", signedBlock)
+	}
+}
+
+func blockStateRoot(block consensus.BeaconBlock) consensus.Root {
+	switch obj := block.(type) {
+	case *consensus.BeaconBlockPhase0:
+		return obj.StateRoot
+	case *consensus.BeaconBlockAltair:
+		return obj.StateRoot
+	case *consensus.BeaconBlockBellatrix:
+		return obj.StateRoot
+	case *consensus.BeaconBlockCapella:
+		return obj.StateRoot
+	case *consensus.BeaconBlockDeneb:
+		return obj.StateRoot
+	case *consensus.BeaconBlockElectra:
+		return obj.StateRoot
+	default:
+		return consensus.Root{}
+	}
+}
+
+func (s *StateTransitioner) verifyBlockSignature(state *beaconState, block consensus.BeaconBlock, proposerIndex uint64, signature consensus.Signature) error {
+	validators := *state.validators
+	if proposerIndex >= uint64(len(validators)) {
+		return fmt.Errorf("proposer index %d out of bounds", proposerIndex)
+	}
+	proposer := validators[proposerIndex]
+
+	domain, err := s.getDomain(consensus.DomainBeaconProposerType, state, nil)
+	if err != nil {
+		return err
+	}
+	signingRoot, err := s.computeSigningRoot(domain, block)
+	if err != nil {
+		return err
+	}
+
+	ok, err := blsVerify(proposer.Pubkey[:], signature[:], signingRoot)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("failed to verify block signature")
+	}
+	return nil
+}
+
+// ProcessSlots advances the state up to the given slot and runs the epoch processing
+// at every epoch boundary on the way. Like StateTransition, it returns the state
+// to continue with, which is a new object if a fork boundary was crossed.
+func (s *StateTransitioner) ProcessSlots(state consensus.BeaconState, slot uint64) (consensus.BeaconState, error) {
+	obj, err := toBeaconState(state)
+	if err != nil {
+		return nil, err
+	}
+	if obj, err = s.processSlots(obj, slot); err != nil {
+		return nil, err
+	}
+	return obj.obj, nil
+}
+
+func (s *StateTransitioner) processSlots(state *beaconState, slot uint64) (*beaconState, error) {
+	if *state.slot >= slot {
+		return nil, fmt.Errorf("slot %d is not newer than the state slot %d", slot, *state.slot)
+	}
+
+	for *state.slot < slot {
+		if err := s.processSlot(state); err != nil {
+			return nil, err
+		}
+		// Process epoch on the start slot of the next epoch
+		if (*state.slot+1)%s.spec.SlotsPerEpoch == 0 {
+			if err := s.processEpoch(state); err != nil {
+				return nil, err
+			}
+		}
+		*state.slot++
+
+		// Upgrade the state on the first slot of a fork epoch
+		if *state.slot%s.spec.SlotsPerEpoch == 0 {
+			var err error
+			if state, err = s.upgradeState(state); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return state, nil
+}
+
+func (s *StateTransitioner) processSlot(state *beaconState) error {
+	// Cache state root
+	previousStateRoot, err := s.spec.HashTreeRoot(state.obj)
+	if err != nil {
+		return err
+	}
+	state.stateRoots[*state.slot%s.spec.SlotsPerHistoricalRoot] = previousStateRoot
+
+	// Cache latest block header state root
+	latestBlockHeader := *state.latestBlockHeader
+	if latestBlockHeader.StateRoot == (consensus.Root{}) {
+		latestBlockHeader.StateRoot = previousStateRoot
+	}
+
+	// Cache block root
+	previousBlockRoot, err := latestBlockHeader.HashTreeRoot()
+	if err != nil {
+		return err
+	}
+	state.blockRoots[*state.slot%s.spec.SlotsPerHistoricalRoot] = previousBlockRoot
+	return nil
+}
+
+func (s *StateTransitioner) processEpoch(state *beaconState) error {
+	steps := []epochProcessignFunc{
+		s.processJustificationAndFinalization,
+	}
+	if state.atLeast(consensus.VersionAltair) {
+		steps = append(steps, s.processInactivityUpdates)
+	}
+	steps = append(steps,
+		s.processRewardsAndPenalties,
+		s.processRegistryUpdates,
+		s.processSlashings,
+		s.processEth1DataReset,
+	)
+	if state.atLeast(consensus.VersionElectra) {
+		steps = append(steps,
+			s.processPendingDeposits,
+			s.processPendingConsolidations,
+		)
+	}
+	steps = append(steps,
+		s.processEffectiveBalanceUpdates,
+		s.processSlashingsReset,
+		s.processRandaoMixesReset,
+	)
+	if state.atLeast(consensus.VersionCapella) {
+		steps = append(steps, s.processHistoricalSummariesUpdate)
+	} else {
+		steps = append(steps, s.processHistoricalRootsUpdate)
+	}
+	if state.atLeast(consensus.VersionAltair) {
+		steps = append(steps,
+			processParticipationFlagUpdates,
+			s.processSyncCommitteeUpdates,
+		)
+	} else {
+		steps = append(steps, processParticipationRecordUpdates)
+	}
+
+	for _, step := range steps {
+		if err := step(state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blockBody is the fork agnostic view of the body of a block. The
+// operations that the fork of the block does not have are empty.
+type blockBody struct {
+	version consensus.Version
+
+	randaoReveal          consensus.Signature
+	eth1Data              *consensus.Eth1Data
+	proposerSlashings     []*consensus.ProposerSlashing
+	attesterSlashings     []*consensus.AttesterSlashing
+	attestations          []*consensus.Attestation
+	attestationsElectra   []*consensus.AttestationElectra
+	deposits              []*consensus.Deposit
+	voluntaryExits        []*consensus.SignedVoluntaryExit
+	syncAggregate         *consensus.SyncAggregate
+	executionPayload      *consensus.ExecutionPayloadDeneb
+	blsToExecutionChanges []*consensus.SignedBLSToExecutionChange
+	blobKZGCommitments    [][48]byte
+	executionRequests     *consensus.ExecutionRequests
+}
+
+func toBlockBody(block consensus.BeaconBlock) (*blockBody, error) {
+	switch obj := block.(type) {
+	case *consensus.BeaconBlockPhase0:
+		body := obj.Body
+		return &blockBody{
+			version:           consensus.VersionPhase0,
+			randaoReveal:      body.RandaoReveal,
+			eth1Data:          body.Eth1Data,
+			proposerSlashings: body.ProposerSlashings,
+			attesterSlashings: body.AttesterSlashings,
+			attestations:      body.Attestations,
+			deposits:          body.Deposits,
+			voluntaryExits:    body.VoluntaryExits,
+		}, nil
+
+	case *consensus.BeaconBlockAltair:
+		body := obj.Body
+		return &blockBody{
+			version:           consensus.VersionAltair,
+			randaoReveal:      body.RandaoReveal,
+			eth1Data:          body.Eth1Data,
+			proposerSlashings: body.ProposerSlashings,
+			attesterSlashings: body.AttesterSlashings,
+			attestations:      body.Attestations,
+			deposits:          body.Deposits,
+			voluntaryExits:    body.VoluntaryExits,
+			syncAggregate:     body.SyncAggregate,
+		}, nil
+
+	case *consensus.BeaconBlockBellatrix:
+		body := obj.Body
+		return &blockBody{
+			version:           consensus.VersionBellatrix,
+			randaoReveal:      body.RandaoReveal,
+			eth1Data:          body.Eth1Data,
+			proposerSlashings: body.ProposerSlashings,
+			attesterSlashings: body.AttesterSlashings,
+			attestations:      body.Attestations,
+			deposits:          body.Deposits,
+			voluntaryExits:    body.VoluntaryExits,
+			syncAggregate:     body.SyncAggregate,
+			executionPayload:  bellatrixToPayloadDeneb(body.ExecutionPayload),
+		}, nil
+
+	case *consensus.BeaconBlockCapella:
+		body := obj.Body
+		return &blockBody{
+			version:               consensus.VersionCapella,
+			randaoReveal:          body.RandaoReveal,
+			eth1Data:              body.Eth1Data,
+			proposerSlashings:     body.ProposerSlashings,
+			attesterSlashings:     body.AttesterSlashings,
+			attestations:          body.Attestations,
+			deposits:              body.Deposits,
+			voluntaryExits:        body.VoluntaryExits,
+			syncAggregate:         body.SyncAggregate,
+			executionPayload:      capellaToPayloadDeneb(body.ExecutionPayload),
+			blsToExecutionChanges: body.BlsToExecutionChanges,
+		}, nil
+
+	case *consensus.BeaconBlockDeneb:
+		body := obj.Body
+		return &blockBody{
+			version:               consensus.VersionDeneb,
+			randaoReveal:          body.RandaoReveal,
+			eth1Data:              body.Eth1Data,
+			proposerSlashings:     body.ProposerSlashings,
+			attesterSlashings:     body.AttesterSlashings,
+			attestations:          body.Attestations,
+			deposits:              body.Deposits,
+			voluntaryExits:        body.VoluntaryExits,
+			syncAggregate:         body.SyncAggregate,
+			executionPayload:      body.ExecutionPayload,
+			blsToExecutionChanges: body.BlsToExecutionChanges,
+			blobKZGCommitments:    body.BlobKZGCommitments,
+		}, nil
+
+	case *consensus.BeaconBlockElectra:
+		body := obj.Body
+		return &blockBody{
+			version:               consensus.VersionElectra,
+			randaoReveal:          body.RandaoReveal,
+			eth1Data:              body.Eth1Data,
+			proposerSlashings:     body.ProposerSlashings,
+			attesterSlashings:     toAttesterSlashings(body.AttesterSlashings),
+			attestationsElectra:   body.Attestations,
+			deposits:              body.Deposits,
+			voluntaryExits:        body.VoluntaryExits,
+			syncAggregate:         body.SyncAggregate,
+			executionPayload:      body.ExecutionPayload,
+			blsToExecutionChanges: body.BlsToExecutionChanges,
+			blobKZGCommitments:    body.BlobKZGCommitments,
+			executionRequests:     body.ExecutionRequests,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("beacon block %T not supported", block)
+	}
+}
+
+func (s *StateTransitioner) processBlock(state *beaconState, block consensus.BeaconBlock) error {
+	body, err := toBlockBody(block)
+	if err != nil {
+		return err
+	}
+	if body.version != state.version {
+		return fmt.Errorf("%s block cannot be applied to a %s state", body.version, state.version)
+	}
+
+	header, err := s.toBlockHeader(block)
+	if err != nil {
+		return err
+	}
+	if err := s.processBlockHeader(state, header); err != nil {
+		return err
+	}
+
+	if body.version >= consensus.VersionBellatrix {
+		if body.executionPayload == nil {
+			return fmt.Errorf("beacon block %T does not have an execution payload", block)
+		}
+		// in bellatrix the execution payload is processed once the merge has started,
+		// from capella onwards the withdrawals and the payload are always processed
+		enabled := true
+		if body.version == consensus.VersionBellatrix {
+			if enabled, err = s.isExecutionEnabled(state, body.executionPayload); err != nil {
+				return err
+			}
+		}
+		if enabled {
+			if state.atLeast(consensus.VersionCapella) {
+				if err := s.processWithdrawals(state, body.executionPayload); err != nil {
+					return err
+				}
+			}
+			if err := s.processExecutionPayload(state, body); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.processRandao(state, body.randaoReveal); err != nil {
+		return err
+	}
+	s.processEth1Data(state, body.eth1Data)
+	if err := s.processOperations(state, body); err != nil {
+		return err
+	}
+	if body.syncAggregate != nil {
+		if err := s.processSyncAggregate(state, body.syncAggregate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *StateTransitioner) processRandao(state *beaconState, randaoReveal consensus.Signature) error {
+	epoch := s.getCurrentEpoch(state)
+
+	// Verify RANDAO reveal
+	proposer := (*state.validators)[s.getBeaconProposerIndex(state)]
+
+	domain, err := s.getDomain(consensus.DomainRandaomType, state, nil)
+	if err != nil {
+		return err
+	}
+	epochRoot := consensus.Root{}
+	binary.LittleEndian.PutUint64(epochRoot[:], epoch)
+
+	signingData := &consensus.SigningData{
+		ObjectRoot: epochRoot,
+		Domain:     domain,
+	}
+	signingRoot, err := signingData.HashTreeRoot()
+	if err != nil {
+		return err
+	}
+
+	ok, err := blsVerify(proposer.Pubkey[:], randaoReveal[:], signingRoot)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("failed to verify randao reveal")
+	}
+
+	// Mix in RANDAO reveal
+	mix := s.getRandaoMix(state, epoch)
+	revealHash := sha256.Sum256(randaoReveal[:])
+	for i := range mix {
+		mix[i] ^= revealHash[i]
+	}
+	state.randaoMixes[epoch%s.spec.EpochsPerHistoricalVector] = mix
+	return nil
+}
+
+func (s *StateTransitioner) processEth1Data(state *beaconState, eth1Data *consensus.Eth1Data) {
+	votes := append(*state.eth1DataVotes, eth1Data)
+	*state.eth1DataVotes = votes
+
+	count := uint64(0)
+	for _, vote := range votes {
+		if *vote == *eth1Data {
+			count++
+		}
+	}
+	if count*2 > s.spec.EpochsPerEth1VotingPeriod*s.spec.SlotsPerEpoch {
+		*state.eth1Data = eth1Data
+	}
+}
+
+func (s *StateTransitioner) processOperations(state *beaconState, body *blockBody) error {
+	// Verify that outstanding deposits are processed up to the maximum number of deposits
+	eth1DepositIndexLimit := (*state.eth1Data).DepositCount
+	if state.atLeast(consensus.VersionElectra) {
+		// Disable former deposit mechanism once all prior deposits are processed
+		eth1DepositIndexLimit = min(eth1DepositIndexLimit, *state.depositRequestsStartIndex)
+	}
+	expectedDeposits := uint64(0)
+	if *state.eth1DepositIndex < eth1DepositIndexLimit {
+		expectedDeposits = min(s.spec.MaxDeposits, eth1DepositIndexLimit-*state.eth1DepositIndex)
+	}
+	if uint64(len(body.deposits)) != expectedDeposits {
+		return fmt.Errorf("expected %d deposits but found %d", expectedDeposits, len(body.deposits))
+	}
+
+	for _, proposerSlashing := range body.proposerSlashings {
+		if err := s.processProposerSlashing(state, proposerSlashing); err != nil {
+			return err
+		}
+	}
+	for _, attesterSlashing := range body.attesterSlashings {
+		if err := s.processAttesterSlashing(state, attesterSlashing); err != nil {
+			return err
+		}
+	}
+	for _, attestation := range body.attestations {
+		if err := s.processAttestation(state, attestation); err != nil {
+			return err
+		}
+	}
+	for _, attestation := range body.attestationsElectra {
+		if err := s.processAttestationElectra(state, attestation); err != nil {
+			return err
+		}
+	}
+	for _, deposit := range body.deposits {
+		if err := s.processDeposit(state, deposit); err != nil {
+			return err
+		}
+	}
+	for _, voluntaryExit := range body.voluntaryExits {
+		if err := s.processVoluntaryExit(state, voluntaryExit); err != nil {
+			return err
+		}
+	}
+	for _, addressChange := range body.blsToExecutionChanges {
+		if err := s.processBlsToExecutionChange(state, addressChange); err != nil {
+			return err
+		}
+	}
+	if requests := body.executionRequests; requests != nil {
+		for _, depositRequest := range requests.Deposits {
+			processDepositRequest(state, depositRequest)
+		}
+		for _, withdrawalRequest := range requests.Withdrawals {
+			if err := s.processWithdrawalRequest(state, withdrawalRequest); err != nil {
+				return err
+			}
+		}
+		for _, consolidationRequest := range requests.Consolidations {
+			s.processConsolidationRequest(state, consolidationRequest)
+		}
+	}
+	return nil
+}
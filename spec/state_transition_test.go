@@ -0,0 +1,76 @@
+package spec
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	consensus "github.com/umbracle/go-eth-consensus"
+	"gopkg.in/yaml.v2"
+)
+
+func TestSanityBlocks(t *testing.T) {
+	for _, fork := range testForks {
+		listTestData(t, ""+fork.name+"/sanity/blocks/*/*", func(th *testHandler) {
+			pre, post := fork.newState(), fork.newState()
+			th.decodeFile("pre", pre)
+			ok := th.decodeFile("post", post, true)
+
+			blocks := []consensus.SignedBeaconBlock{}
+			for i := 0; ; i++ {
+				block := fork.newSignedBlock()
+				if !th.decodeFile(fmt.Sprintf("blocks_%d", i), block, true) {
+					break
+				}
+				blocks = append(blocks, block)
+			}
+
+			state := pre
+			for _, block := range blocks {
+				var err error
+				if state, err = th.transitioner.StateTransition(state, block, true); err != nil {
+					if ok {
+						t.Fatal(err)
+					}
+					return
+				}
+			}
+
+			if !ok {
+				t.Fatal("it should fail")
+			}
+			if !reflect.DeepEqual(state, post) {
+				t.Fatalf("bad: %s", th.path)
+			}
+		})
+	}
+}
+
+func TestSanitySlots(t *testing.T) {
+	for _, fork := range testForks {
+		listTestData(t, ""+fork.name+"/sanity/slots/*/*", func(th *testHandler) {
+			pre, post := fork.newState(), fork.newState()
+			th.decodeFile("pre", pre)
+			th.decodeFile("post", post)
+
+			data, err := ioutil.ReadFile(filepath.Join(th.path, "slots.yaml"))
+			require.NoError(t, err)
+
+			var slots uint64
+			require.NoError(t, yaml.Unmarshal(data, &slots))
+
+			state, err := toBeaconState(pre)
+			require.NoError(t, err)
+
+			res, err := th.transitioner.ProcessSlots(pre, *state.slot+slots)
+			require.NoError(t, err)
+
+			if !reflect.DeepEqual(res, post) {
+				t.Fatalf("bad: %s", th.path)
+			}
+		})
+	}
+}
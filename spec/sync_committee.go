@@ -0,0 +1,150 @@
+package spec
+
+import (
+	"fmt"
+
+	consensus "github.com/umbracle/go-eth-consensus"
+	"github.com/umbracle/go-eth-consensus/bls"
+)
+
+// infinitySignature is the serialized G2 point at infinity. It is the only valid
+// signature for a sync aggregate without participants.
+var infinitySignature = consensus.Signature{0xc0}
+
+func (s *StateTransitioner) getNextSyncCommitteeIndices(state *beaconState) []uint64 {
+	epoch := s.getCurrentEpoch(state) + 1
+
+	activeValidatorIndices := getActiveValidatorIndices(state, epoch)
+	activeValidatorCount := uint64(len(activeValidatorIndices))
+	seed := s.getSeed(state, epoch, consensus.DomainSyncCommitteeType)
+	validators := *state.validators
+
+	i := uint64(0)
+	syncCommitteeIndices := []uint64{}
+	for uint64(len(syncCommitteeIndices)) < s.spec.SyncCommitteeSize {
+		shuffledIndex := s.computeShuffleIndex(i%activeValidatorCount, activeValidatorCount, seed)
+		candidateIndex := activeValidatorIndices[shuffledIndex]
+
+		if s.isSelectedCandidate(state, seed, i, validators[candidateIndex].EffectiveBalance) {
+			syncCommitteeIndices = append(syncCommitteeIndices, candidateIndex)
+		}
+		i++
+	}
+	return syncCommitteeIndices
+}
+
+func (s *StateTransitioner) getNextSyncCommittee(state *beaconState) (*consensus.SyncCommittee, error) {
+	validators := *state.validators
+	committee := &consensus.SyncCommittee{}
+
+	pubKeys := []*bls.PublicKey{}
+	for indx, validatorIndex := range s.getNextSyncCommitteeIndices(state) {
+		pubKey := validators[validatorIndex].Pubkey
+
+		pub := new(bls.PublicKey)
+		if err := pub.Deserialize(pubKey[:]); err != nil {
+			return nil, err
+		}
+		pubKeys = append(pubKeys, pub)
+		committee.PubKeys[indx] = pubKey
+	}
+
+	committee.AggregatePubKey = bls.AggregatePublicKeys(pubKeys).Serialize()
+	return committee, nil
+}
+
+func (s *StateTransitioner) ProcessSyncAggregate(state consensus.BeaconState, syncAggregate *consensus.SyncAggregate) error {
+	obj, err := toBeaconState(state)
+	if err != nil {
+		return err
+	}
+	if !obj.atLeast(consensus.VersionAltair) {
+		return fmt.Errorf("beacon state %T does not have sync committees", state)
+	}
+	return s.processSyncAggregate(obj, syncAggregate)
+}
+
+func (s *StateTransitioner) processSyncAggregate(state *beaconState, syncAggregate *consensus.SyncAggregate) error {
+	committeeBits := syncAggregate.SyncCommiteeBits
+	hasBit := func(i int) bool {
+		return committeeBits[i/8]&(1<<(i%8)) != 0
+	}
+
+	// Verify sync committee aggregate signature signing over the previous slot block root
+	participantPubKeys := []*bls.PublicKey{}
+	for indx, pubKey := range (*state.currentSyncCommittee).PubKeys[:s.spec.SyncCommitteeSize] {
+		if !hasBit(indx) {
+			continue
+		}
+		pub := new(bls.PublicKey)
+		if err := pub.Deserialize(pubKey[:]); err != nil {
+			return err
+		}
+		participantPubKeys = append(participantPubKeys, pub)
+	}
+
+	previousSlot := max(*state.slot, 1) - 1
+	previousEpoch := s.computeEpochAtSlot(previousSlot)
+
+	domain, err := s.getDomain(consensus.DomainSyncCommitteeType, state, &previousEpoch)
+	if err != nil {
+		return err
+	}
+	signingData := &consensus.SigningData{
+		ObjectRoot: s.getBlockRootAtSlot(state, previousSlot),
+		Domain:     domain,
+	}
+	signingRoot, err := signingData.HashTreeRoot()
+	if err != nil {
+		return err
+	}
+
+	if len(participantPubKeys) == 0 {
+		if syncAggregate.SyncCommiteeSignature != infinitySignature {
+			return fmt.Errorf("sync aggregate without participants must have the infinity signature")
+		}
+	} else {
+		sig := new(bls.Signature)
+		if err := sig.Deserialize(syncAggregate.SyncCommiteeSignature[:]); err != nil {
+			return err
+		}
+		ok, err := sig.FastAggregateVerify(participantPubKeys, signingRoot[:])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("failed to verify sync aggregate signature")
+		}
+	}
+
+	// Compute participant and proposer rewards
+	totalActiveIncrements := s.getTotalActiveBalance(state) / s.spec.EffectiveBalanceIncrement
+	totalBaseRewards := s.getBaseRewardPerIncrement(state) * totalActiveIncrements
+	maxParticipantRewards := totalBaseRewards * syncRewardWeight / weightDenominator / s.spec.SlotsPerEpoch
+	participantReward := maxParticipantRewards / s.spec.SyncCommitteeSize
+	proposerReward := participantReward * proposerWeight / (weightDenominator - proposerWeight)
+
+	// Apply participant and proposer rewards
+	validatorIndices := map[[48]byte]uint64{}
+	for indx, validator := range *state.validators {
+		if _, ok := validatorIndices[validator.Pubkey]; !ok {
+			validatorIndices[validator.Pubkey] = uint64(indx)
+		}
+	}
+
+	proposerIndex := s.getBeaconProposerIndex(state)
+	for indx, pubKey := range (*state.currentSyncCommittee).PubKeys[:s.spec.SyncCommitteeSize] {
+		participantIndex, ok := validatorIndices[pubKey]
+		if !ok {
+			return fmt.Errorf("sync committee member %x is not a validator", pubKey)
+		}
+		if hasBit(indx) {
+			increaseBalance(state, participantIndex, participantReward)
+			increaseBalance(state, proposerIndex, proposerReward)
+		} else {
+			decreaseBalance(state, participantIndex, participantReward)
+		}
+	}
+
+	return nil
+}
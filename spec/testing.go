@@ -5,19 +5,103 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	ssz "github.com/ferranbt/fastssz"
 	"github.com/golang/snappy"
 	"github.com/stretchr/testify/require"
+	consensus "github.com/umbracle/go-eth-consensus"
 )
 
 var (
 	testDataFolder = "../eth2.0-spec-tests/tests"
 )
 
+type testFork struct {
+	name     string
+	version  consensus.Version
+	newState func() consensus.BeaconState
+	newBlock func() consensus.BeaconBlock
+
+	newSignedBlock func() consensus.SignedBeaconBlock
+}
+
+// testForks are the forks supported by the state transition functions
+var testForks = []testFork{
+	{
+		name:     "phase0",
+		version:  consensus.VersionPhase0,
+		newState: func() consensus.BeaconState { return &consensus.BeaconStatePhase0{} },
+		newBlock: func() consensus.BeaconBlock { return &consensus.BeaconBlockPhase0{} },
+
+		newSignedBlock: func() consensus.SignedBeaconBlock { return &consensus.SignedBeaconBlockPhase0{} },
+	},
+	{
+		name:     "altair",
+		version:  consensus.VersionAltair,
+		newState: func() consensus.BeaconState { return &consensus.BeaconStateAltair{} },
+		newBlock: func() consensus.BeaconBlock { return &consensus.BeaconBlockAltair{} },
+
+		newSignedBlock: func() consensus.SignedBeaconBlock { return &consensus.SignedBeaconBlockAltair{} },
+	},
+	{
+		name:     "bellatrix",
+		version:  consensus.VersionBellatrix,
+		newState: func() consensus.BeaconState { return &consensus.BeaconStateBellatrix{} },
+		newBlock: func() consensus.BeaconBlock { return &consensus.BeaconBlockBellatrix{} },
+
+		newSignedBlock: func() consensus.SignedBeaconBlock { return &consensus.SignedBeaconBlockBellatrix{} },
+	},
+	{
+		name:     "capella",
+		version:  consensus.VersionCapella,
+		newState: func() consensus.BeaconState { return &consensus.BeaconStateCapella{} },
+		newBlock: func() consensus.BeaconBlock { return &consensus.BeaconBlockCapella{} },
+
+		newSignedBlock: func() consensus.SignedBeaconBlock { return &consensus.SignedBeaconBlockCapella{} },
+	},
+	{
+		name:     "deneb",
+		version:  consensus.VersionDeneb,
+		newState: func() consensus.BeaconState { return &consensus.BeaconStateDeneb{} },
+		newBlock: func() consensus.BeaconBlock { return &consensus.BeaconBlockDeneb{} },
+
+		newSignedBlock: func() consensus.SignedBeaconBlock { return &consensus.SignedBeaconBlockDeneb{} },
+	},
+	{
+		name:     "electra",
+		version:  consensus.VersionElectra,
+		newState: func() consensus.BeaconState { return &consensus.BeaconStateElectra{} },
+		newBlock: func() consensus.BeaconBlock { return &consensus.BeaconBlockElectra{} },
+
+		newSignedBlock: func() consensus.SignedBeaconBlock { return &consensus.SignedBeaconBlockElectra{} },
+	},
+}
+
+type specPreset struct {
+	name string
+	spec *consensus.Spec
+}
+
+// specPresets are the presets the spec tests run with
+var specPresets = []specPreset{
+	{name: "mainnet", spec: Spec},
+	{name: "minimal", spec: MinimalSpec},
+}
+
+// listTestData runs handlerFn over the test data of every preset
 func listTestData(t *testing.T, path string, handlerFn func(tt *testHandler)) {
-	matches, err := filepath.Glob(filepath.Join(testDataFolder, path))
+	for _, preset := range specPresets {
+		listPresetTestData(t, preset, path, handlerFn)
+	}
+}
+
+func listPresetTestData(t *testing.T, preset specPreset, path string, handlerFn func(tt *testHandler)) {
+	transitioner, err := NewStateTransitioner(preset.spec)
+	require.NoError(t, err)
+
+	matches, err := filepath.Glob(filepath.Join(testDataFolder, preset.name, path))
 	require.NoError(t, err)
 
 	if len(matches) == 0 {
@@ -27,8 +111,9 @@ func listTestData(t *testing.T, path string, handlerFn func(tt *testHandler)) {
 	for _, m := range matches {
 		//t.Run(m, func(t *testing.T) {
 		handler := &testHandler{
-			t:    t,
-			path: m,
+			t:            t,
+			path:         m,
+			transitioner: transitioner,
 		}
 		handlerFn(handler)
 		//})
@@ -36,8 +121,9 @@ func listTestData(t *testing.T, path string, handlerFn func(tt *testHandler)) {
 }
 
 type testHandler struct {
-	t    *testing.T
-	path string
+	t            *testing.T
+	path         string
+	transitioner *StateTransitioner
 }
 
 func (th *testHandler) decodeFile(subPath string, obj interface{}, maybeEmpty ...bool) bool {
@@ -74,17 +160,21 @@ func (th *testHandler) decodeFile(subPath string, obj interface{}, maybeEmpty ..
 		content, err = snappy.Decode(nil, snappyContent)
 		require.NoError(th.t, err)
 
-		sszObj, ok := obj.(ssz.Unmarshaler)
-		if !ok {
-			th.t.Fatalf("obj '%s' is not ssz for snappy decompress", subPath)
-		}
-		err = sszObj.UnmarshalSSZ(content)
+		// the ssz objects are sized with the preset of the test data
+		err = th.transitioner.spec.DecodeSSZ(content, obj)
 		require.NoError(th.t, err)
 	}
 
 	return true
 }
 
+// decodeBody decodes the body file of the test into the body of the block
+func (th *testHandler) decodeBody(block consensus.BeaconBlock) {
+	body := reflect.ValueOf(block).Elem().FieldByName("Body")
+	body.Set(reflect.New(body.Type().Elem()))
+	th.decodeFile("body", body.Interface())
+}
+
 func fileExists(path string) (bool, error) {
 	_, err := os.Stat(path)
 	if err != nil {
@@ -0,0 +1,201 @@
+package spec
+
+import (
+	"fmt"
+
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+// StateTransitioner runs the state transition functions with the
+// values of a given spec.
+type StateTransitioner struct {
+	spec *consensus.Spec
+}
+
+// NewStateTransitioner creates a state transitioner for the given spec. The spec can be
+// one of the presets of this package or the one returned by a node (http.ConfigEndpoint.Spec).
+func NewStateTransitioner(spec *consensus.Spec) (*StateTransitioner, error) {
+	if spec == nil {
+		return nil, fmt.Errorf("spec is empty")
+	}
+	// the state transition divides by these values
+	divisors := []struct {
+		name string
+		val  uint64
+	}{
+		{"SLOTS_PER_EPOCH", spec.SlotsPerEpoch},
+		{"SLOTS_PER_HISTORICAL_ROOT", spec.SlotsPerHistoricalRoot},
+		{"EPOCHS_PER_HISTORICAL_VECTOR", spec.EpochsPerHistoricalVector},
+		{"EPOCHS_PER_SLASHINGS_VECTOR", spec.EpochsPerSlashingsVector},
+		{"EPOCHS_PER_ETH1_VOTING_PERIOD", spec.EpochsPerEth1VotingPeriod},
+		{"EPOCHS_PER_SYNC_COMMITTEE_PERIOD", spec.EpochsPerSyncCommitteePeriod},
+		{"EFFECTIVE_BALANCE_INCREMENT", spec.EffectiveBalanceIncrement},
+		{"HYSTERESIS_QUOTIENT", spec.HysteresisQuotient},
+		{"BASE_REWARD_FACTOR", spec.BaseRewardFactor},
+		{"BASE_REWARDS_PER_EPOCH", spec.BaseRewardsPerEpoch},
+		{"TARGET_COMMITTEE_SIZE", spec.TargetCommitteeSize},
+		{"SYNC_COMMITTEE_SIZE", spec.SyncCommitteeSize},
+		{"CHURN_LIMIT_QUOTIENT", spec.ChurnLimitQuotient},
+		{"PROPOSER_REWARD_QUOTIENT", spec.ProposerRewardQuotient},
+		{"WHISTLEBLOWER_REWARD_QUOTIENT", spec.WhistleblowerRewardQuotient},
+		{"MIN_SLASHING_PENALTY_QUOTIENT", spec.MinSlashingPenaltyQuotient},
+		{"MIN_SLASHING_PENALTY_QUOTIENT_ALTAIR", spec.MinSlashingPenaltyQuotientAltair},
+		{"MIN_SLASHING_PENALTY_QUOTIENT_BELLATRIX", spec.MinSlashingPenaltyQuotientBellatrix},
+		{"MIN_SLASHING_PENALTY_QUOTIENT_ELECTRA", spec.MinSlashingPenaltyQuotientElectra},
+		{"WHISTLEBLOWER_REWARD_QUOTIENT_ELECTRA", spec.WhistleblowerRewardQuotientElectra},
+		{"INACTIVITY_PENALTY_QUOTIENT", spec.InactivityPenaltyQuotient},
+		{"INACTIVITY_PENALTY_QUOTIENT_ALTAIR", spec.InactivityPenaltyQuotientAltair},
+		{"INACTIVITY_PENALTY_QUOTIENT_BELLATRIX", spec.InactivityPenaltyQuotientBellatrix},
+		// the electra churn is at least this value and the queues divide by it
+		{"MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA", spec.MinPerEpochChurnLimitElectra},
+		{"INACTIVITY_SCORE_BIAS", spec.InactivityScoreBias},
+	}
+	for _, divisor := range divisors {
+		if divisor.val == 0 {
+			return nil, fmt.Errorf("%s is zero", divisor.name)
+		}
+	}
+	if spec.SlotsPerHistoricalRoot < spec.SlotsPerEpoch {
+		return nil, fmt.Errorf("slots per historical root %d is smaller than an epoch", spec.SlotsPerHistoricalRoot)
+	}
+	if spec.ShuffleRoundCount > 255 {
+		return nil, fmt.Errorf("shuffle round count %d is too large", spec.ShuffleRoundCount)
+	}
+
+	// the vectors of the state types are sized for the mainnet preset
+	// and the spec can only use up to that many items.
+	vectors := []struct {
+		name string
+		val  uint64
+		max  uint64
+	}{
+		{"SLOTS_PER_HISTORICAL_ROOT", spec.SlotsPerHistoricalRoot, Spec.SlotsPerHistoricalRoot},
+		{"EPOCHS_PER_HISTORICAL_VECTOR", spec.EpochsPerHistoricalVector, Spec.EpochsPerHistoricalVector},
+		{"SYNC_COMMITTEE_SIZE", spec.SyncCommitteeSize, Spec.SyncCommitteeSize},
+	}
+	for _, vector := range vectors {
+		if vector.val > vector.max {
+			return nil, fmt.Errorf("%s %d is larger than %d", vector.name, vector.val, vector.max)
+		}
+	}
+	return &StateTransitioner{spec: spec}, nil
+}
+
+// Spec returns the spec used by the state transitioner.
+func (s *StateTransitioner) Spec() *consensus.Spec {
+	return s.spec
+}
+
+// defaultTransitioner is the state transitioner used by the package level
+// functions and it runs with the mainnet preset.
+var defaultTransitioner = &StateTransitioner{spec: Spec}
+
+// StateTransition runs StateTransitioner.StateTransition with the mainnet preset.
+func StateTransition(state consensus.BeaconState, signedBlock consensus.SignedBeaconBlock, validateResult bool) (consensus.BeaconState, error) {
+	return defaultTransitioner.StateTransition(state, signedBlock, validateResult)
+}
+
+// ProcessSlots runs StateTransitioner.ProcessSlots with the mainnet preset.
+func ProcessSlots(state consensus.BeaconState, slot uint64) (consensus.BeaconState, error) {
+	return defaultTransitioner.ProcessSlots(state, slot)
+}
+
+// ProcessAttestation runs StateTransitioner.ProcessAttestation with the mainnet preset.
+func ProcessAttestation(state consensus.BeaconState, attestation *consensus.Attestation) error {
+	return defaultTransitioner.ProcessAttestation(state, attestation)
+}
+
+// ProcessAttestationElectra runs StateTransitioner.ProcessAttestationElectra with the mainnet preset.
+func ProcessAttestationElectra(state consensus.BeaconState, attestation *consensus.AttestationElectra) error {
+	return defaultTransitioner.ProcessAttestationElectra(state, attestation)
+}
+
+// ProcessAttesterSlashing runs StateTransitioner.ProcessAttesterSlashing with the mainnet preset.
+func ProcessAttesterSlashing(state consensus.BeaconState, attesterSlashing *consensus.AttesterSlashing) error {
+	return defaultTransitioner.ProcessAttesterSlashing(state, attesterSlashing)
+}
+
+// ProcessAttesterSlashingElectra runs StateTransitioner.ProcessAttesterSlashingElectra with the mainnet preset.
+func ProcessAttesterSlashingElectra(state consensus.BeaconState, attesterSlashing *consensus.AttesterSlashingElectra) error {
+	return defaultTransitioner.ProcessAttesterSlashingElectra(state, attesterSlashing)
+}
+
+// ProcessBlockHeader runs StateTransitioner.ProcessBlockHeader with the mainnet preset.
+func ProcessBlockHeader(state consensus.BeaconState, block consensus.BeaconBlock) error {
+	return defaultTransitioner.ProcessBlockHeader(state, block)
+}
+
+// ProcessDeposit runs StateTransitioner.ProcessDeposit with the mainnet preset.
+func ProcessDeposit(state consensus.BeaconState, deposit *consensus.Deposit) error {
+	return defaultTransitioner.ProcessDeposit(state, deposit)
+}
+
+// ProcessProposerSlashing runs StateTransitioner.ProcessProposerSlashing with the mainnet preset.
+func ProcessProposerSlashing(state consensus.BeaconState, proposerSlashing *consensus.ProposerSlashing) error {
+	return defaultTransitioner.ProcessProposerSlashing(state, proposerSlashing)
+}
+
+// ProcessVoluntaryExit runs StateTransitioner.ProcessVoluntaryExit with the mainnet preset.
+func ProcessVoluntaryExit(state consensus.BeaconState, signedVoluntaryExit *consensus.SignedVoluntaryExit) error {
+	return defaultTransitioner.ProcessVoluntaryExit(state, signedVoluntaryExit)
+}
+
+// ProcessSyncAggregate runs StateTransitioner.ProcessSyncAggregate with the mainnet preset.
+func ProcessSyncAggregate(state consensus.BeaconState, syncAggregate *consensus.SyncAggregate) error {
+	return defaultTransitioner.ProcessSyncAggregate(state, syncAggregate)
+}
+
+// ProcessExecutionPayload runs StateTransitioner.ProcessExecutionPayload with the mainnet preset.
+func ProcessExecutionPayload(state consensus.BeaconState, block consensus.BeaconBlock) error {
+	return defaultTransitioner.ProcessExecutionPayload(state, block)
+}
+
+// ProcessWithdrawals runs StateTransitioner.ProcessWithdrawals with the mainnet preset.
+func ProcessWithdrawals(state consensus.BeaconState, payload interface{}) error {
+	return defaultTransitioner.ProcessWithdrawals(state, payload)
+}
+
+// ProcessBlsToExecutionChange runs StateTransitioner.ProcessBlsToExecutionChange with the mainnet preset.
+func ProcessBlsToExecutionChange(state consensus.BeaconState, signedAddressChange *consensus.SignedBLSToExecutionChange) error {
+	return defaultTransitioner.ProcessBlsToExecutionChange(state, signedAddressChange)
+}
+
+// ProcessDepositRequest runs StateTransitioner.ProcessDepositRequest with the mainnet preset.
+func ProcessDepositRequest(state consensus.BeaconState, depositRequest *consensus.DepositRequest) error {
+	return defaultTransitioner.ProcessDepositRequest(state, depositRequest)
+}
+
+// ProcessWithdrawalRequest runs StateTransitioner.ProcessWithdrawalRequest with the mainnet preset.
+func ProcessWithdrawalRequest(state consensus.BeaconState, withdrawalRequest *consensus.WithdrawalRequest) error {
+	return defaultTransitioner.ProcessWithdrawalRequest(state, withdrawalRequest)
+}
+
+// ProcessConsolidationRequest runs StateTransitioner.ProcessConsolidationRequest with the mainnet preset.
+func ProcessConsolidationRequest(state consensus.BeaconState, consolidationRequest *consensus.ConsolidationRequest) error {
+	return defaultTransitioner.ProcessConsolidationRequest(state, consolidationRequest)
+}
+
+// UpgradeToAltair runs StateTransitioner.UpgradeToAltair with the mainnet preset.
+func UpgradeToAltair(pre *consensus.BeaconStatePhase0) (*consensus.BeaconStateAltair, error) {
+	return defaultTransitioner.UpgradeToAltair(pre)
+}
+
+// UpgradeToBellatrix runs StateTransitioner.UpgradeToBellatrix with the mainnet preset.
+func UpgradeToBellatrix(pre *consensus.BeaconStateAltair) *consensus.BeaconStateBellatrix {
+	return defaultTransitioner.UpgradeToBellatrix(pre)
+}
+
+// UpgradeToCapella runs StateTransitioner.UpgradeToCapella with the mainnet preset.
+func UpgradeToCapella(pre *consensus.BeaconStateBellatrix) *consensus.BeaconStateCapella {
+	return defaultTransitioner.UpgradeToCapella(pre)
+}
+
+// UpgradeToDeneb runs StateTransitioner.UpgradeToDeneb with the mainnet preset.
+func UpgradeToDeneb(pre *consensus.BeaconStateCapella) *consensus.BeaconStateDeneb {
+	return defaultTransitioner.UpgradeToDeneb(pre)
+}
+
+// UpgradeToElectra runs StateTransitioner.UpgradeToElectra with the mainnet preset.
+func UpgradeToElectra(pre *consensus.BeaconStateDeneb) (*consensus.BeaconStateElectra, error) {
+	return defaultTransitioner.UpgradeToElectra(pre)
+}
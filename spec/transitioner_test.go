@@ -0,0 +1,41 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	consensus "github.com/umbracle/go-eth-consensus"
+)
+
+func TestNewStateTransitioner(t *testing.T) {
+	for _, preset := range specPresets {
+		st, err := NewStateTransitioner(preset.spec)
+		require.NoError(t, err)
+		require.Equal(t, preset.spec, st.Spec())
+	}
+
+	_, err := NewStateTransitioner(nil)
+	require.Error(t, err)
+
+	_, err = NewStateTransitioner(&consensus.Spec{})
+	require.Error(t, err)
+
+	cases := []func(spec *consensus.Spec){
+		func(spec *consensus.Spec) { spec.ShuffleRoundCount = 256 },
+		func(spec *consensus.Spec) { spec.EffectiveBalanceIncrement = 0 },
+		func(spec *consensus.Spec) { spec.BaseRewardFactor = 0 },
+		func(spec *consensus.Spec) { spec.EpochsPerSyncCommitteePeriod = 0 },
+		func(spec *consensus.Spec) { spec.InactivityScoreBias = 0 },
+		func(spec *consensus.Spec) { spec.SlotsPerHistoricalRoot = spec.SlotsPerEpoch / 2 },
+		// larger than the vectors of the state types
+		func(spec *consensus.Spec) { spec.EpochsPerHistoricalVector = 2 * Spec.EpochsPerHistoricalVector },
+		func(spec *consensus.Spec) { spec.SyncCommitteeSize = 1024 },
+	}
+	for _, c := range cases {
+		spec := *MinimalSpec
+		c(&spec)
+
+		_, err = NewStateTransitioner(&spec)
+		require.Error(t, err)
+	}
+}
@@ -89,8 +89,8 @@ type SignedVoluntaryExit struct {
 }
 
 type HistoricalBatch struct {
-	BlockRoots [8192][32]byte `json:"block_roots" ssz-size:"8192,32"`
-	StateRoots [8192][32]byte `json:"state_roots" ssz-size:"8192,32"`
+	BlockRoots [8192][32]byte `json:"block_roots" ssz-size:"8192,32" ssz-preset:"SLOTS_PER_HISTORICAL_ROOT"`
+	StateRoots [8192][32]byte `json:"state_roots" ssz-size:"8192,32" ssz-preset:"SLOTS_PER_HISTORICAL_ROOT"`
 }
 
 type Eth1Data struct {
@@ -130,18 +130,18 @@ type BeaconStatePhase0 struct {
 	Slot                        uint64                `json:"slot"`
 	Fork                        *Fork                 `json:"fork"`
 	LatestBlockHeader           *BeaconBlockHeader    `json:"latest_block_header"`
-	BlockRoots                  [8192][32]byte        `json:"block_roots" ssz-size:"8192,32"`
-	StateRoots                  [8192][32]byte        `json:"state_roots" ssz-size:"8192,32"`
+	BlockRoots                  [8192][32]byte        `json:"block_roots" ssz-size:"8192,32" ssz-preset:"SLOTS_PER_HISTORICAL_ROOT"`
+	StateRoots                  [8192][32]byte        `json:"state_roots" ssz-size:"8192,32" ssz-preset:"SLOTS_PER_HISTORICAL_ROOT"`
 	HistoricalRoots             [][32]byte            `json:"historical_roots" ssz-max:"16777216" ssz-size:"?,32"`
 	Eth1Data                    *Eth1Data             `json:"eth1_data"`
-	Eth1DataVotes               []*Eth1Data           `json:"eth1_data_votes" ssz-max:"2048"`
+	Eth1DataVotes               []*Eth1Data           `json:"eth1_data_votes" ssz-max:"2048" ssz-preset:"EPOCHS_PER_ETH1_VOTING_PERIOD*SLOTS_PER_EPOCH"`
 	Eth1DepositIndex            uint64                `json:"eth1_deposit_index"`
 	Validators                  []*Validator          `json:"validators" ssz-max:"1099511627776"`
 	Balances                    []uint64              `json:"balances" ssz-max:"1099511627776"`
-	RandaoMixes                 [65536][32]byte       `json:"randao_mixes" ssz-size:"65536,32"`
-	Slashings                   []uint64              `json:"slashings" ssz-size:"8192"`
-	PreviousEpochAttestations   []*PendingAttestation `json:"previous_epoch_attestations" ssz-max:"4096"`
-	CurrentEpochAttestations    []*PendingAttestation `json:"current_epoch_attestations" ssz-max:"4096"`
+	RandaoMixes                 [65536][32]byte       `json:"randao_mixes" ssz-size:"65536,32" ssz-preset:"EPOCHS_PER_HISTORICAL_VECTOR"`
+	Slashings                   []uint64              `json:"slashings" ssz-size:"8192" ssz-preset:"EPOCHS_PER_SLASHINGS_VECTOR"`
+	PreviousEpochAttestations   []*PendingAttestation `json:"previous_epoch_attestations" ssz-max:"4096" ssz-preset:"MAX_ATTESTATIONS*SLOTS_PER_EPOCH"`
+	CurrentEpochAttestations    []*PendingAttestation `json:"current_epoch_attestations" ssz-max:"4096" ssz-preset:"MAX_ATTESTATIONS*SLOTS_PER_EPOCH"`
 	JustificationBits           [1]byte               `json:"justification_bits" ssz-size:"1"`
 	PreviousJustifiedCheckpoint *Checkpoint           `json:"previous_justified_checkpoint"`
 	CurrentJustifiedCheckpoint  *Checkpoint           `json:"current_justified_checkpoint"`
@@ -237,16 +237,16 @@ type BeaconStateAltair struct {
 	Slot                        uint64             `json:"slot"`
 	Fork                        *Fork              `json:"fork"`
 	LatestBlockHeader           *BeaconBlockHeader `json:"latest_block_header"`
-	BlockRoots                  [8192][32]byte     `json:"block_roots" ssz-size:"8192,32"`
-	StateRoots                  [8192][32]byte     `json:"state_roots" ssz-size:"8192,32"`
+	BlockRoots                  [8192][32]byte     `json:"block_roots" ssz-size:"8192,32" ssz-preset:"SLOTS_PER_HISTORICAL_ROOT"`
+	StateRoots                  [8192][32]byte     `json:"state_roots" ssz-size:"8192,32" ssz-preset:"SLOTS_PER_HISTORICAL_ROOT"`
 	HistoricalRoots             [][32]byte         `json:"historical_roots" ssz-max:"16777216" ssz-size:"?,32"`
 	Eth1Data                    *Eth1Data          `json:"eth1_data"`
-	Eth1DataVotes               []*Eth1Data        `json:"eth1_data_votes" ssz-max:"2048"`
+	Eth1DataVotes               []*Eth1Data        `json:"eth1_data_votes" ssz-max:"2048" ssz-preset:"EPOCHS_PER_ETH1_VOTING_PERIOD*SLOTS_PER_EPOCH"`
 	Eth1DepositIndex            uint64             `json:"eth1_deposit_index"`
 	Validators                  []*Validator       `json:"validators" ssz-max:"1099511627776"`
 	Balances                    []uint64           `json:"balances" ssz-max:"1099511627776"`
-	RandaoMixes                 [65536][32]byte    `json:"randao_mixes" ssz-size:"65536,32"`
-	Slashings                   []uint64           `json:"slashings" ssz-size:"8192"`
+	RandaoMixes                 [65536][32]byte    `json:"randao_mixes" ssz-size:"65536,32" ssz-preset:"EPOCHS_PER_HISTORICAL_VECTOR"`
+	Slashings                   []uint64           `json:"slashings" ssz-size:"8192" ssz-preset:"EPOCHS_PER_SLASHINGS_VECTOR"`
 	PreviousEpochParticipation  []byte             `json:"previous_epoch_participation" ssz-max:"1099511627776"`
 	CurrentEpochParticipation   []byte             `json:"current_epoch_participation" ssz-max:"1099511627776"`
 	JustificationBits           [1]byte            `json:"justification_bits" ssz-size:"1"`
@@ -284,12 +284,12 @@ type BeaconBlockBodyAltair struct {
 }
 
 type SyncAggregate struct {
-	SyncCommiteeBits      [64]byte  `json:"sync_committee_bits" ssz-size:"64"`
+	SyncCommiteeBits      [64]byte  `json:"sync_committee_bits" ssz-size:"64" ssz-preset:"SYNC_COMMITTEE_SIZE/8"`
 	SyncCommiteeSignature Signature `json:"sync_committee_signature" ssz-size:"96"`
 }
 
 type SyncCommittee struct {
-	PubKeys         [512][48]byte `json:"pubkeys" ssz-size:"512,48"`
+	PubKeys         [512][48]byte `json:"pubkeys" ssz-size:"512,48" ssz-preset:"SYNC_COMMITTEE_SIZE"`
 	AggregatePubKey [48]byte      `json:"aggregate_pubkey" ssz-size:"48"`
 }
 
@@ -301,16 +301,16 @@ type BeaconStateBellatrix struct {
 	Slot                         uint64                  `json:"slot"`
 	Fork                         *Fork                   `json:"fork"`
 	LatestBlockHeader            *BeaconBlockHeader      `json:"latest_block_header"`
-	BlockRoots                   [8192][32]byte          `json:"block_roots" ssz-size:"8192,32"`
-	StateRoots                   [8192][32]byte          `json:"state_roots" ssz-size:"8192,32"`
+	BlockRoots                   [8192][32]byte          `json:"block_roots" ssz-size:"8192,32" ssz-preset:"SLOTS_PER_HISTORICAL_ROOT"`
+	StateRoots                   [8192][32]byte          `json:"state_roots" ssz-size:"8192,32" ssz-preset:"SLOTS_PER_HISTORICAL_ROOT"`
 	HistoricalRoots              [][]byte                `json:"historical_roots" ssz-max:"16777216" ssz-size:"?,32"`
 	Eth1Data                     *Eth1Data               `json:"eth1_data"`
-	Eth1DataVotes                []*Eth1Data             `json:"eth1_data_votes" ssz-max:"2048"`
+	Eth1DataVotes                []*Eth1Data             `json:"eth1_data_votes" ssz-max:"2048" ssz-preset:"EPOCHS_PER_ETH1_VOTING_PERIOD*SLOTS_PER_EPOCH"`
 	Eth1DepositIndex             uint64                  `json:"eth1_deposit_index"`
 	Validators                   []*Validator            `json:"validators" ssz-max:"1099511627776"`
 	Balances                     []uint64                `json:"balances" ssz-max:"1099511627776"`
-	RandaoMixes                  [65536][32]byte         `json:"randao_mixes" ssz-size:"65536,32"`
-	Slashings                    []uint64                `json:"slashings" ssz-size:"8192"`
+	RandaoMixes                  [65536][32]byte         `json:"randao_mixes" ssz-size:"65536,32" ssz-preset:"EPOCHS_PER_HISTORICAL_VECTOR"`
+	Slashings                    []uint64                `json:"slashings" ssz-size:"8192" ssz-preset:"EPOCHS_PER_SLASHINGS_VECTOR"`
 	PreviousEpochParticipation   []byte                  `json:"previous_epoch_participation" ssz-max:"1099511627776"`
 	CurrentEpochParticipation    []byte                  `json:"current_epoch_participation" ssz-max:"1099511627776"`
 	JustificationBits            [1]byte                 `json:"justification_bits" ssz-size:"1"`
@@ -421,7 +421,7 @@ type SyncCommitteeContribution struct {
 	Slot              uint64    `json:"slot"`
 	BeaconBlockRoot   Root      `json:"beacon_block_root" ssz-size:"32"`
 	SubcommitteeIndex uint64    `json:"subcommittee_index"`
-	AggregationBits   []byte    `json:"aggregation_bits" ssz-size:"16"` // bitvector
+	AggregationBits   []byte    `json:"aggregation_bits" ssz-size:"16" ssz-preset:"SYNC_COMMITTEE_SIZE/32"` // bitvector
 	Signature         Signature `json:"signature" ssz-size:"96"`
 }
 
@@ -477,7 +477,7 @@ type ExecutionPayloadCapella struct {
 	BaseFeePerGas Uint256       `ssz-size:"32" json:"base_fee_per_gas"`
 	BlockHash     [32]byte      `ssz-size:"32" json:"block_hash"`
 	Transactions  [][]byte      `ssz-max:"1048576,1073741824" ssz-size:"?,?" json:"transactions"`
-	Withdrawals   []*Withdrawal `json:"withdrawals" ssz-max:"16"`
+	Withdrawals   []*Withdrawal `json:"withdrawals" ssz-max:"16" ssz-preset:"MAX_WITHDRAWALS_PER_PAYLOAD"`
 }
 
 type ExecutionPayloadHeaderCapella struct {
@@ -527,16 +527,16 @@ type BeaconStateCapella struct {
 	Slot                         uint64                         `json:"slot"`
 	Fork                         *Fork                          `json:"fork"`
 	LatestBlockHeader            *BeaconBlockHeader             `json:"latest_block_header"`
-	BlockRoots                   [8192][32]byte                 `json:"block_roots" ssz-size:"8192,32"`
-	StateRoots                   [8192][32]byte                 `json:"state_roots" ssz-size:"8192,32"`
+	BlockRoots                   [8192][32]byte                 `json:"block_roots" ssz-size:"8192,32" ssz-preset:"SLOTS_PER_HISTORICAL_ROOT"`
+	StateRoots                   [8192][32]byte                 `json:"state_roots" ssz-size:"8192,32" ssz-preset:"SLOTS_PER_HISTORICAL_ROOT"`
 	HistoricalRoots              [][]byte                       `json:"historical_roots" ssz-max:"16777216" ssz-size:"?,32"`
 	Eth1Data                     *Eth1Data                      `json:"eth1_data"`
-	Eth1DataVotes                []*Eth1Data                    `json:"eth1_data_votes" ssz-max:"2048"`
+	Eth1DataVotes                []*Eth1Data                    `json:"eth1_data_votes" ssz-max:"2048" ssz-preset:"EPOCHS_PER_ETH1_VOTING_PERIOD*SLOTS_PER_EPOCH"`
 	Eth1DepositIndex             uint64                         `json:"eth1_deposit_index"`
 	Validators                   []*Validator                   `json:"validators" ssz-max:"1099511627776"`
 	Balances                     []uint64                       `json:"balances" ssz-max:"1099511627776"`
-	RandaoMixes                  [65536][32]byte                `json:"randao_mixes" ssz-size:"65536,32"`
-	Slashings                    []uint64                       `json:"slashings" ssz-size:"8192"`
+	RandaoMixes                  [65536][32]byte                `json:"randao_mixes" ssz-size:"65536,32" ssz-preset:"EPOCHS_PER_HISTORICAL_VECTOR"`
+	Slashings                    []uint64                       `json:"slashings" ssz-size:"8192" ssz-preset:"EPOCHS_PER_SLASHINGS_VECTOR"`
 	PreviousEpochParticipation   []byte                         `json:"previous_epoch_participation" ssz-max:"1099511627776"`
 	CurrentEpochParticipation    []byte                         `json:"current_epoch_participation" ssz-max:"1099511627776"`
 	JustificationBits            [1]byte                        `json:"justification_bits" ssz-size:"1"`
@@ -579,6 +579,33 @@ type BeaconBlockBodyCapella struct {
 	BlsToExecutionChanges []*SignedBLSToExecutionChange `json:"bls_to_execution_changes" ssz-max:"16"`
 }
 
+type SignedBlindedBeaconBlockCapella struct {
+	Block     *BlindedBeaconBlockCapella `json:"message"`
+	Signature Signature                  `json:"signature" ssz-size:"96"`
+}
+
+type BlindedBeaconBlockCapella struct {
+	Slot          uint64                         `json:"slot"`
+	ProposerIndex uint64                         `json:"proposer_index"`
+	ParentRoot    Root                           `json:"parent_root" ssz-size:"32"`
+	StateRoot     Root                           `json:"state_root" ssz-size:"32"`
+	Body          *BlindedBeaconBlockBodyCapella `json:"body"`
+}
+
+type BlindedBeaconBlockBodyCapella struct {
+	RandaoReveal           Signature                      `json:"randao_reveal" ssz-size:"96"`
+	Eth1Data               *Eth1Data                      `json:"eth1_data"`
+	Graffiti               [32]byte                       `json:"graffiti" ssz-size:"32"`
+	ProposerSlashings      []*ProposerSlashing            `json:"proposer_slashings" ssz-max:"16"`
+	AttesterSlashings      []*AttesterSlashing            `json:"attester_slashings" ssz-max:"2"`
+	Attestations           []*Attestation                 `json:"attestations" ssz-max:"128"`
+	Deposits               []*Deposit                     `json:"deposits" ssz-max:"16"`
+	VoluntaryExits         []*SignedVoluntaryExit         `json:"voluntary_exits" ssz-max:"16"`
+	SyncAggregate          *SyncAggregate                 `json:"sync_aggregate"`
+	ExecutionPayloadHeader *ExecutionPayloadHeaderCapella `json:"execution_payload_header"`
+	BlsToExecutionChanges  []*SignedBLSToExecutionChange  `json:"bls_to_execution_changes" ssz-max:"16"`
+}
+
 type LightClientHeaderCapella struct {
 	Header          *BeaconBlockHeader             `json:"beacon"`
 	Execution       *ExecutionPayloadHeaderCapella `json:"execution"`
@@ -632,7 +659,7 @@ type ExecutionPayloadDeneb struct {
 	BaseFeePerGas Uint256       `ssz-size:"32" json:"base_fee_per_gas"`
 	BlockHash     [32]byte      `ssz-size:"32" json:"block_hash"`
 	Transactions  [][]byte      `ssz-max:"1048576,1073741824" ssz-size:"?,?" json:"transactions"`
-	Withdrawals   []*Withdrawal `json:"withdrawals" ssz-max:"16"`
+	Withdrawals   []*Withdrawal `json:"withdrawals" ssz-max:"16" ssz-preset:"MAX_WITHDRAWALS_PER_PAYLOAD"`
 	BlobGasUsed   uint64        `json:"blob_gas_used"`
 	ExcessBlobGas uint64        `json:"excess_blob_gas"`
 }
@@ -663,16 +690,16 @@ type BeaconStateDeneb struct {
 	Slot                         uint64                       `json:"slot"`
 	Fork                         *Fork                        `json:"fork"`
 	LatestBlockHeader            *BeaconBlockHeader           `json:"latest_block_header"`
-	BlockRoots                   [8192][32]byte               `json:"block_roots" ssz-size:"8192,32"`
-	StateRoots                   [8192][32]byte               `json:"state_roots" ssz-size:"8192,32"`
+	BlockRoots                   [8192][32]byte               `json:"block_roots" ssz-size:"8192,32" ssz-preset:"SLOTS_PER_HISTORICAL_ROOT"`
+	StateRoots                   [8192][32]byte               `json:"state_roots" ssz-size:"8192,32" ssz-preset:"SLOTS_PER_HISTORICAL_ROOT"`
 	HistoricalRoots              [][]byte                     `json:"historical_roots" ssz-max:"16777216" ssz-size:"?,32"`
 	Eth1Data                     *Eth1Data                    `json:"eth1_data"`
-	Eth1DataVotes                []*Eth1Data                  `json:"eth1_data_votes" ssz-max:"2048"`
+	Eth1DataVotes                []*Eth1Data                  `json:"eth1_data_votes" ssz-max:"2048" ssz-preset:"EPOCHS_PER_ETH1_VOTING_PERIOD*SLOTS_PER_EPOCH"`
 	Eth1DepositIndex             uint64                       `json:"eth1_deposit_index"`
 	Validators                   []*Validator                 `json:"validators" ssz-max:"1099511627776"`
 	Balances                     []uint64                     `json:"balances" ssz-max:"1099511627776"`
-	RandaoMixes                  [65536][32]byte              `json:"randao_mixes" ssz-size:"65536,32"`
-	Slashings                    []uint64                     `json:"slashings" ssz-size:"8192"`
+	RandaoMixes                  [65536][32]byte              `json:"randao_mixes" ssz-size:"65536,32" ssz-preset:"EPOCHS_PER_HISTORICAL_VECTOR"`
+	Slashings                    []uint64                     `json:"slashings" ssz-size:"8192" ssz-preset:"EPOCHS_PER_SLASHINGS_VECTOR"`
 	PreviousEpochParticipation   []byte                       `json:"previous_epoch_participation" ssz-max:"1099511627776"`
 	CurrentEpochParticipation    []byte                       `json:"current_epoch_participation" ssz-max:"1099511627776"`
 	JustificationBits            [1]byte                      `json:"justification_bits" ssz-size:"1"`
@@ -713,7 +740,47 @@ type BeaconBlockBodyDeneb struct {
 	SyncAggregate         *SyncAggregate                `json:"sync_aggregate"`
 	ExecutionPayload      *ExecutionPayloadDeneb        `json:"execution_payload"`
 	BlsToExecutionChanges []*SignedBLSToExecutionChange `json:"bls_to_execution_changes" ssz-max:"16"`
-	BlobKZGCommitments    [][48]byte                    `json:"blob_kzg_commitments" ssz-max:"4096"`
+	BlobKZGCommitments    [][48]byte                    `json:"blob_kzg_commitments" ssz-max:"4096" ssz-preset:"MAX_BLOB_COMMITMENTS_PER_BLOCK"`
+}
+
+type SignedBlindedBeaconBlockDeneb struct {
+	Block     *BlindedBeaconBlockDeneb `json:"message"`
+	Signature Signature                `json:"signature" ssz-size:"96"`
+}
+
+type BlindedBeaconBlockDeneb struct {
+	Slot          uint64                       `json:"slot"`
+	ProposerIndex uint64                       `json:"proposer_index"`
+	ParentRoot    Root                         `json:"parent_root" ssz-size:"32"`
+	StateRoot     Root                         `json:"state_root" ssz-size:"32"`
+	Body          *BlindedBeaconBlockBodyDeneb `json:"body"`
+}
+
+type BlindedBeaconBlockBodyDeneb struct {
+	RandaoReveal           Signature                     `json:"randao_reveal" ssz-size:"96"`
+	Eth1Data               *Eth1Data                     `json:"eth1_data"`
+	Graffiti               [32]byte                      `json:"graffiti" ssz-size:"32"`
+	ProposerSlashings      []*ProposerSlashing           `json:"proposer_slashings" ssz-max:"16"`
+	AttesterSlashings      []*AttesterSlashing           `json:"attester_slashings" ssz-max:"2"`
+	Attestations           []*Attestation                `json:"attestations" ssz-max:"128"`
+	Deposits               []*Deposit                    `json:"deposits" ssz-max:"16"`
+	VoluntaryExits         []*SignedVoluntaryExit        `json:"voluntary_exits" ssz-max:"16"`
+	SyncAggregate          *SyncAggregate                `json:"sync_aggregate"`
+	ExecutionPayloadHeader *ExecutionPayloadHeaderDeneb  `json:"execution_payload_header"`
+	BlsToExecutionChanges  []*SignedBLSToExecutionChange `json:"bls_to_execution_changes" ssz-max:"16"`
+	BlobKZGCommitments     [][48]byte                    `json:"blob_kzg_commitments" ssz-max:"4096" ssz-preset:"MAX_BLOB_COMMITMENTS_PER_BLOCK"`
+}
+
+type BlockContentsDeneb struct {
+	Block     *BeaconBlockDeneb `json:"block"`
+	KZGProofs [][48]byte        `json:"kzg_proofs" ssz-max:"4096" ssz-preset:"MAX_BLOB_COMMITMENTS_PER_BLOCK"`
+	Blobs     [][131072]byte    `json:"blobs" ssz-max:"4096" ssz-preset:"MAX_BLOB_COMMITMENTS_PER_BLOCK"`
+}
+
+type SignedBlockContentsDeneb struct {
+	SignedBlock *SignedBeaconBlockDeneb `json:"signed_block"`
+	KZGProofs   [][48]byte              `json:"kzg_proofs" ssz-max:"4096" ssz-preset:"MAX_BLOB_COMMITMENTS_PER_BLOCK"`
+	Blobs       [][131072]byte          `json:"blobs" ssz-max:"4096" ssz-preset:"MAX_BLOB_COMMITMENTS_PER_BLOCK"`
 }
 
 type LightClientHeaderDeneb struct {
@@ -751,3 +818,236 @@ type LightClientUpdateDeneb struct {
 	SyncAggregate           *SyncAggregate          `json:"sync_aggregate"`
 	SignatureSlot           uint64                  `json:"signature_slot"`
 }
+
+type BlobSidecar struct {
+	Index                       uint64                   `json:"index"`
+	Blob                        [131072]byte             `json:"blob" ssz-size:"131072"`
+	KZGCommitment               [48]byte                 `json:"kzg_commitment" ssz-size:"48"`
+	KZGProof                    [48]byte                 `json:"kzg_proof" ssz-size:"48"`
+	SignedBlockHeader           *SignedBeaconBlockHeader `json:"signed_block_header"`
+	KZGCommitmentInclusionProof [17][32]byte             `json:"kzg_commitment_inclusion_proof" ssz-size:"17,32" ssz-preset:"KZG_COMMITMENT_INCLUSION_PROOF_DEPTH"`
+}
+
+type BlobIdentifier struct {
+	BlockRoot Root   `json:"block_root" ssz-size:"32"`
+	Index     uint64 `json:"index"`
+}
+
+// Electra types
+
+type AttestationElectra struct {
+	AggregationBits []byte           `json:"aggregation_bits" ssz:"bitlist" ssz-max:"131072" ssz-preset:"MAX_VALIDATORS_PER_COMMITTEE*MAX_COMMITTEES_PER_SLOT"`
+	Data            *AttestationData `json:"data"`
+	Signature       Signature        `json:"signature" ssz-size:"96"`
+	CommitteeBits   [8]byte          `json:"committee_bits" ssz-size:"8" ssz-preset:"MAX_COMMITTEES_PER_SLOT/8"`
+}
+
+type SingleAttestation struct {
+	CommitteeIndex uint64           `json:"committee_index"`
+	AttesterIndex  uint64           `json:"attester_index"`
+	Data           *AttestationData `json:"data"`
+	Signature      Signature        `json:"signature" ssz-size:"96"`
+}
+
+type IndexedAttestationElectra struct {
+	AttestationIndices []uint64         `json:"attesting_indices" ssz-max:"131072" ssz-preset:"MAX_VALIDATORS_PER_COMMITTEE*MAX_COMMITTEES_PER_SLOT"`
+	Data               *AttestationData `json:"data"`
+	Signature          Signature        `json:"signature" ssz-size:"96"`
+}
+
+type AttesterSlashingElectra struct {
+	Attestation1 *IndexedAttestationElectra `json:"attestation_1"`
+	Attestation2 *IndexedAttestationElectra `json:"attestation_2"`
+}
+
+type AggregateAndProofElectra struct {
+	Index          uint64              `json:"aggregator_index"`
+	Aggregate      *AttestationElectra `json:"aggregate"`
+	SelectionProof [96]byte            `json:"selection_proof" ssz-size:"96"`
+}
+
+type SignedAggregateAndProofElectra struct {
+	Message   *AggregateAndProofElectra `json:"message"`
+	Signature Signature                 `json:"signature" ssz-size:"96"`
+}
+
+type DepositRequest struct {
+	Pubkey                [48]byte  `json:"pubkey" ssz-size:"48"`
+	WithdrawalCredentials [32]byte  `json:"withdrawal_credentials" ssz-size:"32"`
+	Amount                uint64    `json:"amount"`
+	Signature             Signature `json:"signature" ssz-size:"96"`
+	Index                 uint64    `json:"index"`
+}
+
+type WithdrawalRequest struct {
+	SourceAddress   [20]byte `json:"source_address" ssz-size:"20"`
+	ValidatorPubkey [48]byte `json:"validator_pubkey" ssz-size:"48"`
+	Amount          uint64   `json:"amount"`
+}
+
+type ConsolidationRequest struct {
+	SourceAddress [20]byte `json:"source_address" ssz-size:"20"`
+	SourcePubkey  [48]byte `json:"source_pubkey" ssz-size:"48"`
+	TargetPubkey  [48]byte `json:"target_pubkey" ssz-size:"48"`
+}
+
+type ExecutionRequests struct {
+	Deposits       []*DepositRequest       `json:"deposits" ssz-max:"8192" ssz-preset:"MAX_DEPOSIT_REQUESTS_PER_PAYLOAD"`
+	Withdrawals    []*WithdrawalRequest    `json:"withdrawals" ssz-max:"16" ssz-preset:"MAX_WITHDRAWAL_REQUESTS_PER_PAYLOAD"`
+	Consolidations []*ConsolidationRequest `json:"consolidations" ssz-max:"2" ssz-preset:"MAX_CONSOLIDATION_REQUESTS_PER_PAYLOAD"`
+}
+
+type PendingDeposit struct {
+	Pubkey                [48]byte  `json:"pubkey" ssz-size:"48"`
+	WithdrawalCredentials [32]byte  `json:"withdrawal_credentials" ssz-size:"32"`
+	Amount                uint64    `json:"amount"`
+	Signature             Signature `json:"signature" ssz-size:"96"`
+	Slot                  uint64    `json:"slot"`
+}
+
+type PendingPartialWithdrawal struct {
+	ValidatorIndex    uint64 `json:"validator_index"`
+	Amount            uint64 `json:"amount"`
+	WithdrawableEpoch uint64 `json:"withdrawable_epoch"`
+}
+
+type PendingConsolidation struct {
+	SourceIndex uint64 `json:"source_index"`
+	TargetIndex uint64 `json:"target_index"`
+}
+
+type BeaconStateElectra struct {
+	GenesisTime                   uint64                       `json:"genesis_time"`
+	GenesisValidatorsRoot         [32]byte                     `json:"genesis_validators_root" ssz-size:"32"`
+	Slot                          uint64                       `json:"slot"`
+	Fork                          *Fork                        `json:"fork"`
+	LatestBlockHeader             *BeaconBlockHeader           `json:"latest_block_header"`
+	BlockRoots                    [8192][32]byte               `json:"block_roots" ssz-size:"8192,32" ssz-preset:"SLOTS_PER_HISTORICAL_ROOT"`
+	StateRoots                    [8192][32]byte               `json:"state_roots" ssz-size:"8192,32" ssz-preset:"SLOTS_PER_HISTORICAL_ROOT"`
+	HistoricalRoots               [][]byte                     `json:"historical_roots" ssz-max:"16777216" ssz-size:"?,32"`
+	Eth1Data                      *Eth1Data                    `json:"eth1_data"`
+	Eth1DataVotes                 []*Eth1Data                  `json:"eth1_data_votes" ssz-max:"2048" ssz-preset:"EPOCHS_PER_ETH1_VOTING_PERIOD*SLOTS_PER_EPOCH"`
+	Eth1DepositIndex              uint64                       `json:"eth1_deposit_index"`
+	Validators                    []*Validator                 `json:"validators" ssz-max:"1099511627776"`
+	Balances                      []uint64                     `json:"balances" ssz-max:"1099511627776"`
+	RandaoMixes                   [65536][32]byte              `json:"randao_mixes" ssz-size:"65536,32" ssz-preset:"EPOCHS_PER_HISTORICAL_VECTOR"`
+	Slashings                     []uint64                     `json:"slashings" ssz-size:"8192" ssz-preset:"EPOCHS_PER_SLASHINGS_VECTOR"`
+	PreviousEpochParticipation    []byte                       `json:"previous_epoch_participation" ssz-max:"1099511627776"`
+	CurrentEpochParticipation     []byte                       `json:"current_epoch_participation" ssz-max:"1099511627776"`
+	JustificationBits             [1]byte                      `json:"justification_bits" ssz-size:"1"`
+	PreviousJustifiedCheckpoint   *Checkpoint                  `json:"previous_justified_checkpoint"`
+	CurrentJustifiedCheckpoint    *Checkpoint                  `json:"current_justified_checkpoint"`
+	FinalizedCheckpoint           *Checkpoint                  `json:"finalized_checkpoint"`
+	InactivityScores              []uint64                     `json:"inactivity_scores" ssz-max:"1099511627776"`
+	CurrentSyncCommittee          *SyncCommittee               `json:"current_sync_committee"`
+	NextSyncCommittee             *SyncCommittee               `json:"next_sync_committee"`
+	LatestExecutionPayloadHeader  *ExecutionPayloadHeaderDeneb `json:"latest_execution_payload_header"`
+	NextWithdrawalIndex           uint64                       `json:"next_withdrawal_index"`
+	NextWithdrawalValidatorIndex  uint64                       `json:"next_withdrawal_validator_index"`
+	HistoricalSummaries           []*HistoricalSummary         `json:"historical_summaries" ssz-max:"16777216"`
+	DepositRequestsStartIndex     uint64                       `json:"deposit_requests_start_index"`
+	DepositBalanceToConsume       uint64                       `json:"deposit_balance_to_consume"`
+	ExitBalanceToConsume          uint64                       `json:"exit_balance_to_consume"`
+	EarliestExitEpoch             uint64                       `json:"earliest_exit_epoch"`
+	ConsolidationBalanceToConsume uint64                       `json:"consolidation_balance_to_consume"`
+	EarliestConsolidationEpoch    uint64                       `json:"earliest_consolidation_epoch"`
+	PendingDeposits               []*PendingDeposit            `json:"pending_deposits" ssz-max:"134217728" ssz-preset:"PENDING_DEPOSITS_LIMIT"`
+	PendingPartialWithdrawals     []*PendingPartialWithdrawal  `json:"pending_partial_withdrawals" ssz-max:"134217728" ssz-preset:"PENDING_PARTIAL_WITHDRAWALS_LIMIT"`
+	PendingConsolidations         []*PendingConsolidation      `json:"pending_consolidations" ssz-max:"262144" ssz-preset:"PENDING_CONSOLIDATIONS_LIMIT"`
+}
+
+type SignedBeaconBlockElectra struct {
+	Block     *BeaconBlockElectra `json:"message"`
+	Signature Signature           `json:"signature" ssz-size:"96"`
+}
+
+type BeaconBlockElectra struct {
+	Slot          uint64                  `json:"slot"`
+	ProposerIndex uint64                  `json:"proposer_index"`
+	ParentRoot    Root                    `json:"parent_root" ssz-size:"32"`
+	StateRoot     Root                    `json:"state_root" ssz-size:"32"`
+	Body          *BeaconBlockBodyElectra `json:"body"`
+}
+
+type BeaconBlockBodyElectra struct {
+	RandaoReveal          Signature                     `json:"randao_reveal" ssz-size:"96"`
+	Eth1Data              *Eth1Data                     `json:"eth1_data"`
+	Graffiti              [32]byte                      `json:"graffiti" ssz-size:"32"`
+	ProposerSlashings     []*ProposerSlashing           `json:"proposer_slashings" ssz-max:"16"`
+	AttesterSlashings     []*AttesterSlashingElectra    `json:"attester_slashings" ssz-max:"1"`
+	Attestations          []*AttestationElectra         `json:"attestations" ssz-max:"8"`
+	Deposits              []*Deposit                    `json:"deposits" ssz-max:"16"`
+	VoluntaryExits        []*SignedVoluntaryExit        `json:"voluntary_exits" ssz-max:"16"`
+	SyncAggregate         *SyncAggregate                `json:"sync_aggregate"`
+	ExecutionPayload      *ExecutionPayloadDeneb        `json:"execution_payload"`
+	BlsToExecutionChanges []*SignedBLSToExecutionChange `json:"bls_to_execution_changes" ssz-max:"16"`
+	BlobKZGCommitments    [][48]byte                    `json:"blob_kzg_commitments" ssz-max:"4096" ssz-preset:"MAX_BLOB_COMMITMENTS_PER_BLOCK"`
+	ExecutionRequests     *ExecutionRequests            `json:"execution_requests"`
+}
+
+type SignedBlindedBeaconBlockElectra struct {
+	Block     *BlindedBeaconBlockElectra `json:"message"`
+	Signature Signature                  `json:"signature" ssz-size:"96"`
+}
+
+type BlindedBeaconBlockElectra struct {
+	Slot          uint64                         `json:"slot"`
+	ProposerIndex uint64                         `json:"proposer_index"`
+	ParentRoot    Root                           `json:"parent_root" ssz-size:"32"`
+	StateRoot     Root                           `json:"state_root" ssz-size:"32"`
+	Body          *BlindedBeaconBlockBodyElectra `json:"body"`
+}
+
+type BlindedBeaconBlockBodyElectra struct {
+	RandaoReveal           Signature                     `json:"randao_reveal" ssz-size:"96"`
+	Eth1Data               *Eth1Data                     `json:"eth1_data"`
+	Graffiti               [32]byte                      `json:"graffiti" ssz-size:"32"`
+	ProposerSlashings      []*ProposerSlashing           `json:"proposer_slashings" ssz-max:"16"`
+	AttesterSlashings      []*AttesterSlashingElectra    `json:"attester_slashings" ssz-max:"1"`
+	Attestations           []*AttestationElectra         `json:"attestations" ssz-max:"8"`
+	Deposits               []*Deposit                    `json:"deposits" ssz-max:"16"`
+	VoluntaryExits         []*SignedVoluntaryExit        `json:"voluntary_exits" ssz-max:"16"`
+	SyncAggregate          *SyncAggregate                `json:"sync_aggregate"`
+	ExecutionPayloadHeader *ExecutionPayloadHeaderDeneb  `json:"execution_payload_header"`
+	BlsToExecutionChanges  []*SignedBLSToExecutionChange `json:"bls_to_execution_changes" ssz-max:"16"`
+	BlobKZGCommitments     [][48]byte                    `json:"blob_kzg_commitments" ssz-max:"4096" ssz-preset:"MAX_BLOB_COMMITMENTS_PER_BLOCK"`
+	ExecutionRequests      *ExecutionRequests            `json:"execution_requests"`
+}
+
+type BlockContentsElectra struct {
+	Block     *BeaconBlockElectra `json:"block"`
+	KZGProofs [][48]byte          `json:"kzg_proofs" ssz-max:"4096" ssz-preset:"MAX_BLOB_COMMITMENTS_PER_BLOCK"`
+	Blobs     [][131072]byte      `json:"blobs" ssz-max:"4096" ssz-preset:"MAX_BLOB_COMMITMENTS_PER_BLOCK"`
+}
+
+type SignedBlockContentsElectra struct {
+	SignedBlock *SignedBeaconBlockElectra `json:"signed_block"`
+	KZGProofs   [][48]byte                `json:"kzg_proofs" ssz-max:"4096" ssz-preset:"MAX_BLOB_COMMITMENTS_PER_BLOCK"`
+	Blobs       [][131072]byte            `json:"blobs" ssz-max:"4096" ssz-preset:"MAX_BLOB_COMMITMENTS_PER_BLOCK"`
+}
+
+// the electra light client types use the deneb header and the deeper
+// branches of the electra beacon state
+type LightClientBootstrapElectra struct {
+	Header                     *LightClientHeaderDeneb `json:"header"`
+	CurrentSyncCommittee       *SyncCommittee          `json:"current_sync_committee"`
+	CurrentSyncCommitteeBranch [][32]byte              `json:"current_sync_committee_branch" ssz-size:"6,32"`
+}
+
+type LightClientFinalityUpdateElectra struct {
+	AttestedHeader  *LightClientHeaderDeneb `json:"attested_header"`
+	FinalizedHeader *LightClientHeaderDeneb `json:"finalized_header"`
+	FinalityBranch  [][32]byte              `json:"finality_branch" ssz-size:"7,32"`
+	SyncAggregate   *SyncAggregate          `json:"sync_aggregate"`
+	SignatureSlot   uint64                  `json:"signature_slot"`
+}
+
+type LightClientUpdateElectra struct {
+	AttestedHeader          *LightClientHeaderDeneb `json:"attested_header"`
+	NextSyncCommittee       *SyncCommittee          `json:"next_sync_committee"`
+	NextSyncCommitteeBranch [][32]byte              `json:"next_sync_committee_branch" ssz-size:"6,32"`
+	FinalizedHeader         *LightClientHeaderDeneb `json:"finalized_header"`
+	FinalityBranch          [][32]byte              `json:"finality_branch" ssz-size:"7,32"`
+	SyncAggregate           *SyncAggregate          `json:"sync_aggregate"`
+	SignatureSlot           uint64                  `json:"signature_slot"`
+}
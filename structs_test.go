@@ -33,49 +33,86 @@ const (
 	altairFork    = "altair"
 	bellatrixFork = "bellatrix"
 	capellaFork   = "capella"
+	denebFork     = "deneb"
+	electraFork   = "electra"
 )
 
 type testCallback func(f fork) codec
 
 var codecs = map[string]testCallback{
-	"AttestationData":   func(f fork) codec { return new(AttestationData) },
-	"Checkpoint":        func(f fork) codec { return new(Checkpoint) },
-	"AggregateAndProof": func(f fork) codec { return new(AggregateAndProof) },
-	"Attestation":       func(f fork) codec { return new(Attestation) },
-	"AttesterSlashing":  func(f fork) codec { return new(AttesterSlashing) },
+	"AttestationData": func(f fork) codec { return new(AttestationData) },
+	"Checkpoint":      func(f fork) codec { return new(Checkpoint) },
+	"AggregateAndProof": func(f fork) codec {
+		if f == electraFork {
+			return new(AggregateAndProofElectra)
+		}
+		return new(AggregateAndProof)
+	},
+	"Attestation": func(f fork) codec {
+		if f == electraFork {
+			return new(AttestationElectra)
+		}
+		return new(Attestation)
+	},
+	"AttesterSlashing": func(f fork) codec {
+		if f == electraFork {
+			return new(AttesterSlashingElectra)
+		}
+		return new(AttesterSlashing)
+	},
 	"LightClientHeader": func(f fork) codec {
-		if f == capellaFork {
+		if f == electraFork || f == denebFork {
+			return new(LightClientHeaderDeneb)
+		} else if f == capellaFork {
 			return new(LightClientHeaderCapella)
 		}
 		return new(LightClientHeader)
 	},
 	"LightClientBootstrap": func(f fork) codec {
-		if f == capellaFork {
+		if f == electraFork {
+			return new(LightClientBootstrapElectra)
+		} else if f == denebFork {
+			return new(LightClientBootstrapDeneb)
+		} else if f == capellaFork {
 			return new(LightClientBootstrapCapella)
 		}
 		return new(LightClientBootstrap)
 	},
 	"LightClientFinalityUpdate": func(f fork) codec {
-		if f == capellaFork {
+		if f == electraFork {
+			return new(LightClientFinalityUpdateElectra)
+		} else if f == denebFork {
+			return new(LightClientFinalityUpdateDeneb)
+		} else if f == capellaFork {
 			return new(LightClientFinalityUpdateCapella)
 		}
 		return new(LightClientFinalityUpdate)
 	},
 	"LightClientOptimisticUpdate": func(f fork) codec {
-		if f == capellaFork {
+		if f == electraFork || f == denebFork {
+			return new(LightClientOptimisticUpdateDeneb)
+		} else if f == capellaFork {
 			return new(LightClientOptimisticUpdateCapella)
 		}
 		return new(LightClientOptimisticUpdate)
 	},
 	"LightClientUpdate": func(f fork) codec {
-		if f == capellaFork {
+		if f == electraFork {
+			return new(LightClientUpdateElectra)
+		} else if f == denebFork {
+			return new(LightClientUpdateDeneb)
+		} else if f == capellaFork {
 			return new(LightClientUpdateCapella)
 		}
 		return new(LightClientUpdate)
 	},
 	"HistoricalBatch": func(f fork) codec { return new(HistoricalBatch) },
 	"BeaconBlock": func(f fork) codec {
-		if f == capellaFork {
+		if f == electraFork {
+			return new(BeaconBlockElectra)
+		} else if f == denebFork {
+			return new(BeaconBlockDeneb)
+		} else if f == capellaFork {
 			return new(BeaconBlockCapella)
 		} else if f == altairFork {
 			return new(BeaconBlockAltair)
@@ -85,7 +122,11 @@ var codecs = map[string]testCallback{
 		return new(BeaconBlockPhase0)
 	},
 	"BeaconBlockBody": func(f fork) codec {
-		if f == capellaFork {
+		if f == electraFork {
+			return new(BeaconBlockBodyElectra)
+		} else if f == denebFork {
+			return new(BeaconBlockBodyDeneb)
+		} else if f == capellaFork {
 			return new(BeaconBlockBodyCapella)
 		} else if f == altairFork {
 			return new(BeaconBlockBodyAltair)
@@ -94,17 +135,26 @@ var codecs = map[string]testCallback{
 		}
 		return new(BeaconBlockBodyPhase0)
 	},
-	"BeaconBlockHeader":  func(f fork) codec { return new(BeaconBlockHeader) },
-	"Deposit":            func(f fork) codec { return new(Deposit) },
-	"DepositData":        func(f fork) codec { return new(DepositData) },
-	"DepositMessage":     func(f fork) codec { return new(DepositMessage) },
-	"Eth1Data":           func(f fork) codec { return new(Eth1Data) },
-	"Fork":               func(f fork) codec { return new(Fork) },
-	"IndexedAttestation": func(f fork) codec { return new(IndexedAttestation) },
+	"BeaconBlockHeader": func(f fork) codec { return new(BeaconBlockHeader) },
+	"Deposit":           func(f fork) codec { return new(Deposit) },
+	"DepositData":       func(f fork) codec { return new(DepositData) },
+	"DepositMessage":    func(f fork) codec { return new(DepositMessage) },
+	"Eth1Data":          func(f fork) codec { return new(Eth1Data) },
+	"Fork":              func(f fork) codec { return new(Fork) },
+	"IndexedAttestation": func(f fork) codec {
+		if f == electraFork {
+			return new(IndexedAttestationElectra)
+		}
+		return new(IndexedAttestation)
+	},
 	"PendingAttestation": func(f fork) codec { return new(PendingAttestation) },
 	"ProposerSlashing":   func(f fork) codec { return new(ProposerSlashing) },
 	"SignedBeaconBlock": func(f fork) codec {
-		if f == capellaFork {
+		if f == electraFork {
+			return new(SignedBeaconBlockElectra)
+		} else if f == denebFork {
+			return new(SignedBeaconBlockDeneb)
+		} else if f == capellaFork {
 			return new(SignedBeaconBlockCapella)
 		} else if f == altairFork {
 			return new(SignedBeaconBlockAltair)
@@ -128,16 +178,25 @@ var codecs = map[string]testCallback{
 	"SyncAggregatorSelectionData": func(f fork) codec { return new(SyncAggregatorSelectionData) },
 	"SigningData":                 func(f fork) codec { return new(SigningData) },
 	"ForkData":                    func(f fork) codec { return new(ForkData) },
-	"SignedAggregateAndProof":     func(f fork) codec { return new(SignedAggregateAndProof) },
-	"PowBlock":                    func(f fork) codec { return new(PowBlock) },
+	"SignedAggregateAndProof": func(f fork) codec {
+		if f == electraFork {
+			return new(SignedAggregateAndProofElectra)
+		}
+		return new(SignedAggregateAndProof)
+	},
+	"PowBlock": func(f fork) codec { return new(PowBlock) },
 	"ExecutionPayload": func(f fork) codec {
-		if f == capellaFork {
+		if f == denebFork || f == electraFork {
+			return new(ExecutionPayloadDeneb)
+		} else if f == capellaFork {
 			return new(ExecutionPayloadCapella)
 		}
 		return new(ExecutionPayload)
 	},
 	"ExecutionPayloadHeader": func(f fork) codec {
-		if f == capellaFork {
+		if f == denebFork || f == electraFork {
+			return new(ExecutionPayloadHeaderDeneb)
+		} else if f == capellaFork {
 			return new(ExecutionPayloadHeaderCapella)
 		}
 		return new(ExecutionPayloadHeader)
@@ -149,6 +208,10 @@ var codecs = map[string]testCallback{
 			return new(BeaconStateBellatrix)
 		} else if f == capellaFork {
 			return new(BeaconStateCapella)
+		} else if f == denebFork {
+			return new(BeaconStateDeneb)
+		} else if f == electraFork {
+			return new(BeaconStateElectra)
 		}
 		return new(BeaconStatePhase0)
 	},
@@ -156,6 +219,16 @@ var codecs = map[string]testCallback{
 	"HistoricalSummary":          func(f fork) codec { return new(HistoricalSummary) },
 	"SignedBLSToExecutionChange": func(f fork) codec { return new(SignedBLSToExecutionChange) },
 	"Withdrawal":                 func(f fork) codec { return new(Withdrawal) },
+	"BlobSidecar":                func(f fork) codec { return new(BlobSidecar) },
+	"BlobIdentifier":             func(f fork) codec { return new(BlobIdentifier) },
+	"SingleAttestation":          func(f fork) codec { return new(SingleAttestation) },
+	"DepositRequest":             func(f fork) codec { return new(DepositRequest) },
+	"WithdrawalRequest":          func(f fork) codec { return new(WithdrawalRequest) },
+	"ConsolidationRequest":       func(f fork) codec { return new(ConsolidationRequest) },
+	"ExecutionRequests":          func(f fork) codec { return new(ExecutionRequests) },
+	"PendingDeposit":             func(f fork) codec { return new(PendingDeposit) },
+	"PendingPartialWithdrawal":   func(f fork) codec { return new(PendingPartialWithdrawal) },
+	"PendingConsolidation":       func(f fork) codec { return new(PendingConsolidation) },
 }
 
 func testFork(t *testing.T, fork fork) {
@@ -195,6 +268,14 @@ func TestSpecMainnet_Capella(t *testing.T) {
 	testFork(t, capellaFork)
 }
 
+func TestSpecMainnet_Deneb(t *testing.T) {
+	testFork(t, denebFork)
+}
+
+func TestSpecMainnet_Electra(t *testing.T) {
+	testFork(t, electraFork)
+}
+
 func formatSpecFailure(errHeader, specFile, structName string, err error) string {
 	return fmt.Sprintf("%s spec file=%s, struct=%s, err=%v",
 		errHeader, specFile, structName, err)
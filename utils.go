@@ -20,9 +20,12 @@ func (u *Uint256) UnmarshalText(text []byte) error {
 }
 
 func (u Uint256) MarshalText() (text []byte, err error) {
-	buf := reverse(u[:])
-	x := new(big.Int).SetBytes(buf[:])
-	return []byte(x.String()), nil
+	return []byte(u.Big().String()), nil
+}
+
+// Big returns the value as a big integer
+func (u Uint256) Big() *big.Int {
+	return new(big.Int).SetBytes(reverse(u[:]))
 }
 
 func reverse(in []byte) (out []byte) {
@@ -0,0 +1,871 @@
+package consensus
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	ssz "github.com/ferranbt/fastssz"
+)
+
+type sszObject interface {
+	ssz.Marshaler
+	ssz.Unmarshaler
+	ssz.HashRoot
+}
+
+// Version is the name of a fork as used by the Beacon API (i.e. in the
+// Eth-Consensus-Version header)
+type Version int
+
+const (
+	VersionPhase0 Version = iota
+	VersionAltair
+	VersionBellatrix
+	VersionCapella
+	VersionDeneb
+	VersionElectra
+)
+
+var versionNames = map[Version]string{
+	VersionPhase0:    "phase0",
+	VersionAltair:    "altair",
+	VersionBellatrix: "bellatrix",
+	VersionCapella:   "capella",
+	VersionDeneb:     "deneb",
+	VersionElectra:   "electra",
+}
+
+func (v Version) String() string {
+	name, ok := versionNames[v]
+	if !ok {
+		return fmt.Sprintf("unknown(%d)", int(v))
+	}
+	return name
+}
+
+// ParseVersion returns the version with the given name
+func ParseVersion(name string) (Version, error) {
+	for v, vName := range versionNames {
+		if vName == name {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("version '%s' not found", name)
+}
+
+// VersionAtEpoch returns the fork active at the given epoch. Forks without
+// a fork version are considered not scheduled.
+func (s *Spec) VersionAtEpoch(epoch uint64) Version {
+	forks := []struct {
+		version     Version
+		forkVersion Domain
+		epoch       uint64
+	}{
+		{VersionElectra, s.ElectraForkVersion, s.ElectraForkEpoch},
+		{VersionDeneb, s.DenebForkVersion, s.DenebForkEpoch},
+		{VersionCapella, s.CapellaForkVersion, s.CapellaForkEpoch},
+		{VersionBellatrix, s.BellatrixForkVersion, s.BellatrixForkEpoch},
+		{VersionAltair, s.AltairForkVersion, s.AltairForkEpoch},
+	}
+	for _, fork := range forks {
+		if fork.forkVersion != (Domain{}) && epoch >= fork.epoch {
+			return fork.version
+		}
+	}
+	return VersionPhase0
+}
+
+// VersionAtSlot returns the fork active at the given slot
+func (s *Spec) VersionAtSlot(slot uint64) Version {
+	if s.SlotsPerEpoch == 0 {
+		return s.VersionAtEpoch(0)
+	}
+	return s.VersionAtEpoch(slot / s.SlotsPerEpoch)
+}
+
+// ForkVersion returns the fork version of the given fork
+func (s *Spec) ForkVersion(version Version) Domain {
+	switch version {
+	case VersionAltair:
+		return s.AltairForkVersion
+	case VersionBellatrix:
+		return s.BellatrixForkVersion
+	case VersionCapella:
+		return s.CapellaForkVersion
+	case VersionDeneb:
+		return s.DenebForkVersion
+	case VersionElectra:
+		return s.ElectraForkVersion
+	default:
+		return s.GenesisForkVersion
+	}
+}
+
+// ForkEpoch returns the activation epoch of the given fork
+func (s *Spec) ForkEpoch(version Version) uint64 {
+	switch version {
+	case VersionAltair:
+		return s.AltairForkEpoch
+	case VersionBellatrix:
+		return s.BellatrixForkEpoch
+	case VersionCapella:
+		return s.CapellaForkEpoch
+	case VersionDeneb:
+		return s.DenebForkEpoch
+	case VersionElectra:
+		return s.ElectraForkEpoch
+	default:
+		return s.GenesisEpoch
+	}
+}
+
+// VersionedSignedBeaconBlock is a signed beacon block of any fork
+type VersionedSignedBeaconBlock struct {
+	Version Version
+	Block   SignedBeaconBlock
+}
+
+// NewVersionedSignedBeaconBlock wraps a signed beacon block of any fork
+func NewVersionedSignedBeaconBlock(block SignedBeaconBlock) (*VersionedSignedBeaconBlock, error) {
+	var version Version
+	switch block.(type) {
+	case *SignedBeaconBlockPhase0:
+		version = VersionPhase0
+	case *SignedBeaconBlockAltair:
+		version = VersionAltair
+	case *SignedBeaconBlockBellatrix:
+		version = VersionBellatrix
+	case *SignedBeaconBlockCapella:
+		version = VersionCapella
+	case *SignedBeaconBlockDeneb:
+		version = VersionDeneb
+	case *SignedBeaconBlockElectra:
+		version = VersionElectra
+	default:
+		return nil, fmt.Errorf("signed beacon block %T not supported", block)
+	}
+	return &VersionedSignedBeaconBlock{Version: version, Block: block}, nil
+}
+
+// NewSignedBeaconBlock returns an empty signed beacon block of the given fork
+func NewSignedBeaconBlock(version Version) (SignedBeaconBlock, error) {
+	switch version {
+	case VersionPhase0:
+		return &SignedBeaconBlockPhase0{}, nil
+	case VersionAltair:
+		return &SignedBeaconBlockAltair{}, nil
+	case VersionBellatrix:
+		return &SignedBeaconBlockBellatrix{}, nil
+	case VersionCapella:
+		return &SignedBeaconBlockCapella{}, nil
+	case VersionDeneb:
+		return &SignedBeaconBlockDeneb{}, nil
+	case VersionElectra:
+		return &SignedBeaconBlockElectra{}, nil
+	default:
+		return nil, fmt.Errorf("version %s not supported", version)
+	}
+}
+
+// NewBeaconBlock returns an empty beacon block of the given fork
+func NewBeaconBlock(version Version) (BeaconBlock, error) {
+	switch version {
+	case VersionPhase0:
+		return &BeaconBlockPhase0{}, nil
+	case VersionAltair:
+		return &BeaconBlockAltair{}, nil
+	case VersionBellatrix:
+		return &BeaconBlockBellatrix{}, nil
+	case VersionCapella:
+		return &BeaconBlockCapella{}, nil
+	case VersionDeneb:
+		return &BeaconBlockDeneb{}, nil
+	case VersionElectra:
+		return &BeaconBlockElectra{}, nil
+	default:
+		return nil, fmt.Errorf("version %s not supported", version)
+	}
+}
+
+// DecodeSSZ decodes a signed beacon block of any fork. The fork is resolved with
+// the slot of the block and the fork schedule of the spec and the block is sized
+// with the preset of the spec.
+func (v *VersionedSignedBeaconBlock) DecodeSSZ(spec *Spec, buf []byte) error {
+	// the message offset (4 bytes) and the signature (96 bytes)
+	// are followed by the message which starts with the slot
+	if len(buf) < 108 {
+		return fmt.Errorf("signed beacon block too short: %d bytes", len(buf))
+	}
+	slot := binary.LittleEndian.Uint64(buf[100:108])
+
+	version := spec.VersionAtSlot(slot)
+	block, err := NewSignedBeaconBlock(version)
+	if err != nil {
+		return err
+	}
+	if err := spec.DecodeSSZ(buf, block); err != nil {
+		return err
+	}
+
+	v.Version = version
+	v.Block = block
+	return nil
+}
+
+// MarshalSSZ encodes the signed beacon block
+func (v *VersionedSignedBeaconBlock) MarshalSSZ() ([]byte, error) {
+	obj, ok := v.Block.(sszObject)
+	if !ok {
+		return nil, fmt.Errorf("signed beacon block %T not supported", v.Block)
+	}
+	return obj.MarshalSSZ()
+}
+
+// HashTreeRoot returns the root of the signed beacon block
+func (v *VersionedSignedBeaconBlock) HashTreeRoot() ([32]byte, error) {
+	obj, ok := v.Block.(sszObject)
+	if !ok {
+		return [32]byte{}, fmt.Errorf("signed beacon block %T not supported", v.Block)
+	}
+	return obj.HashTreeRoot()
+}
+
+// BlockRoot returns the root of the beacon block (without the signature)
+func (v *VersionedSignedBeaconBlock) BlockRoot() ([32]byte, error) {
+	var obj sszObject
+	switch block := v.Block.(type) {
+	case *SignedBeaconBlockPhase0:
+		obj = block.Block
+	case *SignedBeaconBlockAltair:
+		obj = block.Block
+	case *SignedBeaconBlockBellatrix:
+		obj = block.Block
+	case *SignedBeaconBlockCapella:
+		obj = block.Block
+	case *SignedBeaconBlockDeneb:
+		obj = block.Block
+	case *SignedBeaconBlockElectra:
+		obj = block.Block
+	default:
+		return [32]byte{}, fmt.Errorf("signed beacon block %T not supported", v.Block)
+	}
+	return obj.HashTreeRoot()
+}
+
+type blockFields struct {
+	slot          uint64
+	proposerIndex uint64
+	parentRoot    Root
+	stateRoot     Root
+	signature     Signature
+}
+
+func (v *VersionedSignedBeaconBlock) fields() blockFields {
+	switch block := v.Block.(type) {
+	case *SignedBeaconBlockPhase0:
+		return blockFields{block.Block.Slot, block.Block.ProposerIndex, block.Block.ParentRoot, block.Block.StateRoot, block.Signature}
+	case *SignedBeaconBlockAltair:
+		return blockFields{block.Block.Slot, block.Block.ProposerIndex, block.Block.ParentRoot, block.Block.StateRoot, block.Signature}
+	case *SignedBeaconBlockBellatrix:
+		return blockFields{block.Block.Slot, block.Block.ProposerIndex, block.Block.ParentRoot, block.Block.StateRoot, block.Signature}
+	case *SignedBeaconBlockCapella:
+		return blockFields{block.Block.Slot, block.Block.ProposerIndex, block.Block.ParentRoot, block.Block.StateRoot, block.Signature}
+	case *SignedBeaconBlockDeneb:
+		return blockFields{block.Block.Slot, block.Block.ProposerIndex, block.Block.ParentRoot, block.Block.StateRoot, block.Signature}
+	case *SignedBeaconBlockElectra:
+		return blockFields{block.Block.Slot, block.Block.ProposerIndex, block.Block.ParentRoot, block.Block.StateRoot, block.Signature}
+	default:
+		return blockFields{}
+	}
+}
+
+// Slot returns the slot of the block
+func (v *VersionedSignedBeaconBlock) Slot() uint64 {
+	return v.fields().slot
+}
+
+// ProposerIndex returns the index of the proposer of the block
+func (v *VersionedSignedBeaconBlock) ProposerIndex() uint64 {
+	return v.fields().proposerIndex
+}
+
+// ParentRoot returns the root of the parent block
+func (v *VersionedSignedBeaconBlock) ParentRoot() Root {
+	return v.fields().parentRoot
+}
+
+// StateRoot returns the root of the post state of the block
+func (v *VersionedSignedBeaconBlock) StateRoot() Root {
+	return v.fields().stateRoot
+}
+
+// Signature returns the signature of the block
+func (v *VersionedSignedBeaconBlock) Signature() Signature {
+	return v.fields().signature
+}
+
+// Header returns the header of the block
+func (v *VersionedSignedBeaconBlock) Header() (*BeaconBlockHeader, error) {
+	var body sszObject
+	switch block := v.Block.(type) {
+	case *SignedBeaconBlockPhase0:
+		body = block.Block.Body
+	case *SignedBeaconBlockAltair:
+		body = block.Block.Body
+	case *SignedBeaconBlockBellatrix:
+		body = block.Block.Body
+	case *SignedBeaconBlockCapella:
+		body = block.Block.Body
+	case *SignedBeaconBlockDeneb:
+		body = block.Block.Body
+	case *SignedBeaconBlockElectra:
+		body = block.Block.Body
+	default:
+		return nil, fmt.Errorf("signed beacon block %T not supported", v.Block)
+	}
+	bodyRoot, err := body.HashTreeRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	fields := v.fields()
+	header := &BeaconBlockHeader{
+		Slot:          fields.slot,
+		ProposerIndex: fields.proposerIndex,
+		ParentRoot:    fields.parentRoot,
+		StateRoot:     fields.stateRoot,
+		BodyRoot:      bodyRoot,
+	}
+	return header, nil
+}
+
+// ExecutionPayload returns the execution payload of the block or nil
+// if the block is from before Bellatrix.
+func (v *VersionedSignedBeaconBlock) ExecutionPayload() *VersionedExecutionPayload {
+	switch block := v.Block.(type) {
+	case *SignedBeaconBlockBellatrix:
+		return &VersionedExecutionPayload{Version: VersionBellatrix, Bellatrix: block.Block.Body.ExecutionPayload}
+	case *SignedBeaconBlockCapella:
+		return &VersionedExecutionPayload{Version: VersionCapella, Capella: block.Block.Body.ExecutionPayload}
+	case *SignedBeaconBlockDeneb:
+		return &VersionedExecutionPayload{Version: VersionDeneb, Deneb: block.Block.Body.ExecutionPayload}
+	case *SignedBeaconBlockElectra:
+		return &VersionedExecutionPayload{Version: VersionElectra, Deneb: block.Block.Body.ExecutionPayload}
+	default:
+		return nil
+	}
+}
+
+// VersionedExecutionPayload is an execution payload of any fork.
+// Electra reuses the Deneb execution payload.
+type VersionedExecutionPayload struct {
+	Version   Version
+	Bellatrix *ExecutionPayload
+	Capella   *ExecutionPayloadCapella
+	Deneb     *ExecutionPayloadDeneb
+}
+
+// BlockHash returns the hash of the execution block
+func (v *VersionedExecutionPayload) BlockHash() [32]byte {
+	switch {
+	case v.Deneb != nil:
+		return v.Deneb.BlockHash
+	case v.Capella != nil:
+		return v.Capella.BlockHash
+	case v.Bellatrix != nil:
+		return v.Bellatrix.BlockHash
+	}
+	return [32]byte{}
+}
+
+// BlockNumber returns the number of the execution block
+func (v *VersionedExecutionPayload) BlockNumber() uint64 {
+	switch {
+	case v.Deneb != nil:
+		return v.Deneb.BlockNumber
+	case v.Capella != nil:
+		return v.Capella.BlockNumber
+	case v.Bellatrix != nil:
+		return v.Bellatrix.BlockNumber
+	}
+	return 0
+}
+
+// Timestamp returns the timestamp of the execution block
+func (v *VersionedExecutionPayload) Timestamp() uint64 {
+	switch {
+	case v.Deneb != nil:
+		return v.Deneb.Timestamp
+	case v.Capella != nil:
+		return v.Capella.Timestamp
+	case v.Bellatrix != nil:
+		return v.Bellatrix.Timestamp
+	}
+	return 0
+}
+
+// HashTreeRoot returns the root of the execution payload
+func (v *VersionedExecutionPayload) HashTreeRoot() ([32]byte, error) {
+	switch {
+	case v.Deneb != nil:
+		return v.Deneb.HashTreeRoot()
+	case v.Capella != nil:
+		return v.Capella.HashTreeRoot()
+	case v.Bellatrix != nil:
+		return v.Bellatrix.HashTreeRoot()
+	}
+	return [32]byte{}, fmt.Errorf("execution payload is empty")
+}
+
+// VersionedBeaconState is a beacon state of any fork
+type VersionedBeaconState struct {
+	Version Version
+	State   BeaconState
+}
+
+// NewVersionedBeaconState wraps a beacon state of any fork
+func NewVersionedBeaconState(state BeaconState) (*VersionedBeaconState, error) {
+	var version Version
+	switch state.(type) {
+	case *BeaconStatePhase0:
+		version = VersionPhase0
+	case *BeaconStateAltair:
+		version = VersionAltair
+	case *BeaconStateBellatrix:
+		version = VersionBellatrix
+	case *BeaconStateCapella:
+		version = VersionCapella
+	case *BeaconStateDeneb:
+		version = VersionDeneb
+	case *BeaconStateElectra:
+		version = VersionElectra
+	default:
+		return nil, fmt.Errorf("beacon state %T not supported", state)
+	}
+	return &VersionedBeaconState{Version: version, State: state}, nil
+}
+
+// NewBeaconState returns an empty beacon state of the given fork
+func NewBeaconState(version Version) (BeaconState, error) {
+	switch version {
+	case VersionPhase0:
+		return &BeaconStatePhase0{}, nil
+	case VersionAltair:
+		return &BeaconStateAltair{}, nil
+	case VersionBellatrix:
+		return &BeaconStateBellatrix{}, nil
+	case VersionCapella:
+		return &BeaconStateCapella{}, nil
+	case VersionDeneb:
+		return &BeaconStateDeneb{}, nil
+	case VersionElectra:
+		return &BeaconStateElectra{}, nil
+	default:
+		return nil, fmt.Errorf("version %s not supported", version)
+	}
+}
+
+// DecodeSSZ decodes a beacon state of any fork. The fork is resolved with the
+// slot of the state and the fork schedule of the spec and the state is sized
+// with the preset of the spec.
+func (v *VersionedBeaconState) DecodeSSZ(spec *Spec, buf []byte) error {
+	// the genesis time (8 bytes) and the genesis validators root (32 bytes)
+	// are followed by the slot
+	if len(buf) < 48 {
+		return fmt.Errorf("beacon state too short: %d bytes", len(buf))
+	}
+	slot := binary.LittleEndian.Uint64(buf[40:48])
+
+	version := spec.VersionAtSlot(slot)
+	state, err := NewBeaconState(version)
+	if err != nil {
+		return err
+	}
+	if err := spec.DecodeSSZ(buf, state); err != nil {
+		return err
+	}
+
+	v.Version = version
+	v.State = state
+	return nil
+}
+
+// MarshalSSZ encodes the beacon state
+func (v *VersionedBeaconState) MarshalSSZ() ([]byte, error) {
+	obj, ok := v.State.(sszObject)
+	if !ok {
+		return nil, fmt.Errorf("beacon state %T not supported", v.State)
+	}
+	return obj.MarshalSSZ()
+}
+
+// HashTreeRoot returns the root of the beacon state
+func (v *VersionedBeaconState) HashTreeRoot() ([32]byte, error) {
+	obj, ok := v.State.(sszObject)
+	if !ok {
+		return [32]byte{}, fmt.Errorf("beacon state %T not supported", v.State)
+	}
+	return obj.HashTreeRoot()
+}
+
+type stateFields struct {
+	genesisTime           uint64
+	genesisValidatorsRoot [32]byte
+	slot                  uint64
+	fork                  *Fork
+	latestBlockHeader     *BeaconBlockHeader
+	validators            []*Validator
+	balances              []uint64
+
+	previousJustifiedCheckpoint *Checkpoint
+	currentJustifiedCheckpoint  *Checkpoint
+	finalizedCheckpoint         *Checkpoint
+}
+
+func (v *VersionedBeaconState) fields() stateFields {
+	switch s := v.State.(type) {
+	case *BeaconStatePhase0:
+		return stateFields{s.GenesisTime, s.GenesisValidatorsRoot, s.Slot, s.Fork, s.LatestBlockHeader, s.Validators, s.Balances, s.PreviousJustifiedCheckpoint, s.CurrentJustifiedCheckpoint, s.FinalizedCheckpoint}
+	case *BeaconStateAltair:
+		return stateFields{s.GenesisTime, s.GenesisValidatorsRoot, s.Slot, s.Fork, s.LatestBlockHeader, s.Validators, s.Balances, s.PreviousJustifiedCheckpoint, s.CurrentJustifiedCheckpoint, s.FinalizedCheckpoint}
+	case *BeaconStateBellatrix:
+		return stateFields{s.GenesisTime, s.GenesisValidatorsRoot, s.Slot, s.Fork, s.LatestBlockHeader, s.Validators, s.Balances, s.PreviousJustifiedCheckpoint, s.CurrentJustifiedCheckpoint, s.FinalizedCheckpoint}
+	case *BeaconStateCapella:
+		return stateFields{s.GenesisTime, s.GenesisValidatorsRoot, s.Slot, s.Fork, s.LatestBlockHeader, s.Validators, s.Balances, s.PreviousJustifiedCheckpoint, s.CurrentJustifiedCheckpoint, s.FinalizedCheckpoint}
+	case *BeaconStateDeneb:
+		return stateFields{s.GenesisTime, s.GenesisValidatorsRoot, s.Slot, s.Fork, s.LatestBlockHeader, s.Validators, s.Balances, s.PreviousJustifiedCheckpoint, s.CurrentJustifiedCheckpoint, s.FinalizedCheckpoint}
+	case *BeaconStateElectra:
+		return stateFields{s.GenesisTime, s.GenesisValidatorsRoot, s.Slot, s.Fork, s.LatestBlockHeader, s.Validators, s.Balances, s.PreviousJustifiedCheckpoint, s.CurrentJustifiedCheckpoint, s.FinalizedCheckpoint}
+	default:
+		return stateFields{}
+	}
+}
+
+// GenesisTime returns the genesis time of the chain
+func (v *VersionedBeaconState) GenesisTime() uint64 {
+	return v.fields().genesisTime
+}
+
+// GenesisValidatorsRoot returns the genesis validators root of the chain
+func (v *VersionedBeaconState) GenesisValidatorsRoot() [32]byte {
+	return v.fields().genesisValidatorsRoot
+}
+
+// Slot returns the slot of the state
+func (v *VersionedBeaconState) Slot() uint64 {
+	return v.fields().slot
+}
+
+// Fork returns the fork of the state
+func (v *VersionedBeaconState) Fork() *Fork {
+	return v.fields().fork
+}
+
+// LatestBlockHeader returns the header of the latest block applied to the state
+func (v *VersionedBeaconState) LatestBlockHeader() *BeaconBlockHeader {
+	return v.fields().latestBlockHeader
+}
+
+// Validators returns the validators registry
+func (v *VersionedBeaconState) Validators() []*Validator {
+	return v.fields().validators
+}
+
+// Balances returns the balances of the validators
+func (v *VersionedBeaconState) Balances() []uint64 {
+	return v.fields().balances
+}
+
+// PreviousJustifiedCheckpoint returns the justified checkpoint of the previous epoch
+func (v *VersionedBeaconState) PreviousJustifiedCheckpoint() *Checkpoint {
+	return v.fields().previousJustifiedCheckpoint
+}
+
+// CurrentJustifiedCheckpoint returns the justified checkpoint of the current epoch
+func (v *VersionedBeaconState) CurrentJustifiedCheckpoint() *Checkpoint {
+	return v.fields().currentJustifiedCheckpoint
+}
+
+// FinalizedCheckpoint returns the finalized checkpoint of the state
+func (v *VersionedBeaconState) FinalizedCheckpoint() *Checkpoint {
+	return v.fields().finalizedCheckpoint
+}
+
+// syncCommittees returns the current and the next sync committees of the
+// state. Phase0 states do not have sync committees.
+func (v *VersionedBeaconState) syncCommittees() (*SyncCommittee, *SyncCommittee) {
+	switch s := v.State.(type) {
+	case *BeaconStateAltair:
+		return s.CurrentSyncCommittee, s.NextSyncCommittee
+	case *BeaconStateBellatrix:
+		return s.CurrentSyncCommittee, s.NextSyncCommittee
+	case *BeaconStateCapella:
+		return s.CurrentSyncCommittee, s.NextSyncCommittee
+	case *BeaconStateDeneb:
+		return s.CurrentSyncCommittee, s.NextSyncCommittee
+	case *BeaconStateElectra:
+		return s.CurrentSyncCommittee, s.NextSyncCommittee
+	default:
+		return nil, nil
+	}
+}
+
+// CurrentSyncCommittee returns the sync committee of the current period. It is nil before Altair.
+func (v *VersionedBeaconState) CurrentSyncCommittee() *SyncCommittee {
+	current, _ := v.syncCommittees()
+	return current
+}
+
+// NextSyncCommittee returns the sync committee of the next period. It is nil before Altair.
+func (v *VersionedBeaconState) NextSyncCommittee() *SyncCommittee {
+	_, next := v.syncCommittees()
+	return next
+}
+
+// randaoMixes returns the randao mixes of the state. It is a pointer to avoid
+// copying the vector.
+func (v *VersionedBeaconState) randaoMixes() *[65536][32]byte {
+	switch s := v.State.(type) {
+	case *BeaconStatePhase0:
+		return &s.RandaoMixes
+	case *BeaconStateAltair:
+		return &s.RandaoMixes
+	case *BeaconStateBellatrix:
+		return &s.RandaoMixes
+	case *BeaconStateCapella:
+		return &s.RandaoMixes
+	case *BeaconStateDeneb:
+		return &s.RandaoMixes
+	case *BeaconStateElectra:
+		return &s.RandaoMixes
+	default:
+		return nil
+	}
+}
+
+// RandaoMix returns the randao mix at the index of the mixes vector
+// (epoch % EPOCHS_PER_HISTORICAL_VECTOR).
+func (v *VersionedBeaconState) RandaoMix(index uint64) [32]byte {
+	mixes := v.randaoMixes()
+	if mixes == nil || index >= uint64(len(mixes)) {
+		return [32]byte{}
+	}
+	return mixes[index]
+}
+
+// VersionedBlindedBeaconBlock is a blinded beacon block of any fork from
+// Bellatrix. Only the block of the fork of the version is set.
+type VersionedBlindedBeaconBlock struct {
+	Version   Version
+	Bellatrix *BlindedBeaconBlock
+	Capella   *BlindedBeaconBlockCapella
+	Deneb     *BlindedBeaconBlockDeneb
+	Electra   *BlindedBeaconBlockElectra
+}
+
+// NewVersionedBlindedBeaconBlock returns an empty blinded beacon block of the given fork
+func NewVersionedBlindedBeaconBlock(version Version) (*VersionedBlindedBeaconBlock, error) {
+	v := &VersionedBlindedBeaconBlock{Version: version}
+	switch version {
+	case VersionBellatrix:
+		v.Bellatrix = &BlindedBeaconBlock{}
+	case VersionCapella:
+		v.Capella = &BlindedBeaconBlockCapella{}
+	case VersionDeneb:
+		v.Deneb = &BlindedBeaconBlockDeneb{}
+	case VersionElectra:
+		v.Electra = &BlindedBeaconBlockElectra{}
+	default:
+		return nil, fmt.Errorf("blinded block of version %s not supported", version)
+	}
+	return v, nil
+}
+
+// Object returns the blinded block of the fork
+func (v *VersionedBlindedBeaconBlock) Object() interface{} {
+	switch {
+	case v.Bellatrix != nil:
+		return v.Bellatrix
+	case v.Capella != nil:
+		return v.Capella
+	case v.Deneb != nil:
+		return v.Deneb
+	case v.Electra != nil:
+		return v.Electra
+	}
+	return nil
+}
+
+func (v *VersionedBlindedBeaconBlock) sszObject() (sszObject, error) {
+	obj, ok := v.Object().(sszObject)
+	if !ok {
+		return nil, fmt.Errorf("blinded block is empty")
+	}
+	return obj, nil
+}
+
+// MarshalSSZ encodes the blinded block
+func (v *VersionedBlindedBeaconBlock) MarshalSSZ() ([]byte, error) {
+	obj, err := v.sszObject()
+	if err != nil {
+		return nil, err
+	}
+	return obj.MarshalSSZ()
+}
+
+// UnmarshalSSZ decodes the blinded block of the fork of the version
+func (v *VersionedBlindedBeaconBlock) UnmarshalSSZ(buf []byte) error {
+	if v.Object() == nil {
+		obj, err := NewVersionedBlindedBeaconBlock(v.Version)
+		if err != nil {
+			return err
+		}
+		*v = *obj
+	}
+	obj, err := v.sszObject()
+	if err != nil {
+		return err
+	}
+	return obj.UnmarshalSSZ(buf)
+}
+
+// HashTreeRoot returns the root of the blinded block which is the
+// same as the root of the full block
+func (v *VersionedBlindedBeaconBlock) HashTreeRoot() ([32]byte, error) {
+	obj, err := v.sszObject()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return obj.HashTreeRoot()
+}
+
+// Slot returns the slot of the blinded block
+func (v *VersionedBlindedBeaconBlock) Slot() uint64 {
+	switch {
+	case v.Bellatrix != nil:
+		return v.Bellatrix.Slot
+	case v.Capella != nil:
+		return v.Capella.Slot
+	case v.Deneb != nil:
+		return v.Deneb.Slot
+	case v.Electra != nil:
+		return v.Electra.Slot
+	}
+	return 0
+}
+
+// WithSignature returns the signed blinded block with the given signature
+func (v *VersionedBlindedBeaconBlock) WithSignature(signature Signature) *VersionedSignedBlindedBeaconBlock {
+	signed := &VersionedSignedBlindedBeaconBlock{Version: v.Version}
+	switch {
+	case v.Bellatrix != nil:
+		signed.Bellatrix = &SignedBlindedBeaconBlock{Block: v.Bellatrix, Signature: signature}
+	case v.Capella != nil:
+		signed.Capella = &SignedBlindedBeaconBlockCapella{Block: v.Capella, Signature: signature}
+	case v.Deneb != nil:
+		signed.Deneb = &SignedBlindedBeaconBlockDeneb{Block: v.Deneb, Signature: signature}
+	case v.Electra != nil:
+		signed.Electra = &SignedBlindedBeaconBlockElectra{Block: v.Electra, Signature: signature}
+	}
+	return signed
+}
+
+// VersionedSignedBlindedBeaconBlock is a signed blinded beacon block of any fork
+// from Bellatrix. Only the block of the fork of the version is set.
+type VersionedSignedBlindedBeaconBlock struct {
+	Version   Version
+	Bellatrix *SignedBlindedBeaconBlock
+	Capella   *SignedBlindedBeaconBlockCapella
+	Deneb     *SignedBlindedBeaconBlockDeneb
+	Electra   *SignedBlindedBeaconBlockElectra
+}
+
+// NewVersionedSignedBlindedBeaconBlock returns an empty signed blinded beacon block of the given fork
+func NewVersionedSignedBlindedBeaconBlock(version Version) (*VersionedSignedBlindedBeaconBlock, error) {
+	v := &VersionedSignedBlindedBeaconBlock{Version: version}
+	switch version {
+	case VersionBellatrix:
+		v.Bellatrix = &SignedBlindedBeaconBlock{}
+	case VersionCapella:
+		v.Capella = &SignedBlindedBeaconBlockCapella{}
+	case VersionDeneb:
+		v.Deneb = &SignedBlindedBeaconBlockDeneb{}
+	case VersionElectra:
+		v.Electra = &SignedBlindedBeaconBlockElectra{}
+	default:
+		return nil, fmt.Errorf("signed blinded block of version %s not supported", version)
+	}
+	return v, nil
+}
+
+// Object returns the signed blinded block of the fork
+func (v *VersionedSignedBlindedBeaconBlock) Object() interface{} {
+	switch {
+	case v.Bellatrix != nil:
+		return v.Bellatrix
+	case v.Capella != nil:
+		return v.Capella
+	case v.Deneb != nil:
+		return v.Deneb
+	case v.Electra != nil:
+		return v.Electra
+	}
+	return nil
+}
+
+func (v *VersionedSignedBlindedBeaconBlock) sszObject() (sszObject, error) {
+	obj, ok := v.Object().(sszObject)
+	if !ok {
+		return nil, fmt.Errorf("signed blinded block is empty")
+	}
+	return obj, nil
+}
+
+// MarshalSSZ encodes the signed blinded block
+func (v *VersionedSignedBlindedBeaconBlock) MarshalSSZ() ([]byte, error) {
+	obj, err := v.sszObject()
+	if err != nil {
+		return nil, err
+	}
+	return obj.MarshalSSZ()
+}
+
+// UnmarshalSSZ decodes the signed blinded block of the fork of the version
+func (v *VersionedSignedBlindedBeaconBlock) UnmarshalSSZ(buf []byte) error {
+	if v.Object() == nil {
+		obj, err := NewVersionedSignedBlindedBeaconBlock(v.Version)
+		if err != nil {
+			return err
+		}
+		*v = *obj
+	}
+	obj, err := v.sszObject()
+	if err != nil {
+		return err
+	}
+	return obj.UnmarshalSSZ(buf)
+}
+
+// HashTreeRoot returns the root of the signed blinded block
+func (v *VersionedSignedBlindedBeaconBlock) HashTreeRoot() ([32]byte, error) {
+	obj, err := v.sszObject()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return obj.HashTreeRoot()
+}
+
+// Slot returns the slot of the signed blinded block
+func (v *VersionedSignedBlindedBeaconBlock) Slot() uint64 {
+	switch {
+	case v.Bellatrix != nil:
+		return v.Bellatrix.Block.Slot
+	case v.Capella != nil:
+		return v.Capella.Block.Slot
+	case v.Deneb != nil:
+		return v.Deneb.Block.Slot
+	case v.Electra != nil:
+		return v.Electra.Block.Slot
+	}
+	return 0
+}
@@ -0,0 +1,245 @@
+package consensus
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testVersionedSpec = func() *Spec {
+	spec := *mainnetPreset
+	spec.GenesisForkVersion = Domain{0, 0, 0, 0}
+	spec.AltairForkVersion, spec.AltairForkEpoch = Domain{1, 0, 0, 0}, 1
+	spec.BellatrixForkVersion, spec.BellatrixForkEpoch = Domain{2, 0, 0, 0}, 2
+	spec.CapellaForkVersion, spec.CapellaForkEpoch = Domain{3, 0, 0, 0}, 3
+	spec.DenebForkVersion, spec.DenebForkEpoch = Domain{4, 0, 0, 0}, 4
+	spec.ElectraForkVersion, spec.ElectraForkEpoch = Domain{5, 0, 0, 0}, 5
+	return &spec
+}()
+
+func TestVersion_Parse(t *testing.T) {
+	for v := VersionPhase0; v <= VersionElectra; v++ {
+		found, err := ParseVersion(v.String())
+		require.NoError(t, err)
+		require.Equal(t, v, found)
+	}
+
+	_, err := ParseVersion("fulu")
+	require.Error(t, err)
+}
+
+func TestSpec_VersionAtSlot(t *testing.T) {
+	cases := []struct {
+		slot    uint64
+		version Version
+	}{
+		{0, VersionPhase0},
+		{31, VersionPhase0},
+		{32, VersionAltair},
+		{64, VersionBellatrix},
+		{96, VersionCapella},
+		{128, VersionDeneb},
+		{160, VersionElectra},
+		{math.MaxUint64, VersionElectra},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.version, testVersionedSpec.VersionAtSlot(c.slot), c.slot)
+	}
+
+	// forks without a version are not scheduled
+	spec := &Spec{
+		SlotsPerEpoch:     32,
+		AltairForkVersion: Domain{1, 0, 0, 0},
+		AltairForkEpoch:   1,
+	}
+	require.Equal(t, VersionAltair, spec.VersionAtSlot(1000))
+}
+
+func TestVersionedSignedBeaconBlock_DecodeSSZ(t *testing.T) {
+	for v := VersionPhase0; v <= VersionElectra; v++ {
+		block, err := NewSignedBeaconBlock(v)
+		require.NoError(t, err)
+
+		versioned, err := NewVersionedSignedBeaconBlock(block)
+		require.NoError(t, err)
+		require.Equal(t, v, versioned.Version)
+
+		// set the slot at the start of the fork
+		slot := uint64(v) * testVersionedSpec.SlotsPerEpoch
+		parentRoot := Root{0x1, byte(v)}
+
+		data := marshalTestBlock(t, block, slot, parentRoot)
+
+		found := &VersionedSignedBeaconBlock{}
+		require.NoError(t, found.DecodeSSZ(testVersionedSpec, data))
+
+		require.Equal(t, v, found.Version)
+		require.Equal(t, slot, found.Slot())
+		require.Equal(t, parentRoot, found.ParentRoot())
+
+		root, err := versioned.HashTreeRoot()
+		require.NoError(t, err)
+		foundRoot, err := found.HashTreeRoot()
+		require.NoError(t, err)
+		require.Equal(t, root, foundRoot)
+
+		// the header has the same root as the block
+		header, err := found.Header()
+		require.NoError(t, err)
+		headerRoot, err := header.HashTreeRoot()
+		require.NoError(t, err)
+		blockRoot, err := found.BlockRoot()
+		require.NoError(t, err)
+		require.Equal(t, blockRoot, headerRoot)
+
+		if v >= VersionBellatrix {
+			require.NotNil(t, found.ExecutionPayload())
+		} else {
+			require.Nil(t, found.ExecutionPayload())
+		}
+	}
+
+	// the slot of a phase0 block in an altair epoch
+	data := marshalTestBlock(t, &SignedBeaconBlockPhase0{}, 32, Root{})
+	require.Error(t, new(VersionedSignedBeaconBlock).DecodeSSZ(testVersionedSpec, data))
+
+	require.Error(t, new(VersionedSignedBeaconBlock).DecodeSSZ(testVersionedSpec, []byte{0x1}))
+}
+
+func marshalTestBlock(t *testing.T, block SignedBeaconBlock, slot uint64, parentRoot Root) []byte {
+	switch obj := block.(type) {
+	case *SignedBeaconBlockPhase0:
+		obj.Block = &BeaconBlockPhase0{Slot: slot, ParentRoot: parentRoot, Body: &BeaconBlockBodyPhase0{Eth1Data: &Eth1Data{}}}
+	case *SignedBeaconBlockAltair:
+		obj.Block = &BeaconBlockAltair{Slot: slot, ParentRoot: parentRoot, Body: &BeaconBlockBodyAltair{Eth1Data: &Eth1Data{}, SyncAggregate: &SyncAggregate{}}}
+	case *SignedBeaconBlockBellatrix:
+		obj.Block = &BeaconBlockBellatrix{Slot: slot, ParentRoot: parentRoot, Body: &BeaconBlockBodyBellatrix{Eth1Data: &Eth1Data{}, SyncAggregate: &SyncAggregate{}, ExecutionPayload: &ExecutionPayload{}}}
+	case *SignedBeaconBlockCapella:
+		obj.Block = &BeaconBlockCapella{Slot: slot, ParentRoot: parentRoot, Body: &BeaconBlockBodyCapella{Eth1Data: &Eth1Data{}, SyncAggregate: &SyncAggregate{}, ExecutionPayload: &ExecutionPayloadCapella{}}}
+	case *SignedBeaconBlockDeneb:
+		obj.Block = &BeaconBlockDeneb{Slot: slot, ParentRoot: parentRoot, Body: &BeaconBlockBodyDeneb{Eth1Data: &Eth1Data{}, SyncAggregate: &SyncAggregate{}, ExecutionPayload: &ExecutionPayloadDeneb{}}}
+	case *SignedBeaconBlockElectra:
+		obj.Block = &BeaconBlockElectra{Slot: slot, ParentRoot: parentRoot, Body: &BeaconBlockBodyElectra{Eth1Data: &Eth1Data{}, SyncAggregate: &SyncAggregate{}, ExecutionPayload: &ExecutionPayloadDeneb{}, ExecutionRequests: &ExecutionRequests{}}}
+	}
+
+	data, err := block.(sszObject).MarshalSSZ()
+	require.NoError(t, err)
+	return data
+}
+
+func TestVersionedBeaconState_DecodeSSZ(t *testing.T) {
+	for v := VersionPhase0; v <= VersionElectra; v++ {
+		state, err := NewBeaconState(v)
+		require.NoError(t, err)
+
+		slot := uint64(v)*testVersionedSpec.SlotsPerEpoch + 1
+		data := marshalTestState(t, state, slot)
+
+		found := &VersionedBeaconState{}
+		require.NoError(t, found.DecodeSSZ(testVersionedSpec, data))
+
+		require.Equal(t, v, found.Version)
+		require.Equal(t, slot, found.Slot())
+		require.Equal(t, [32]byte{0x1}, found.GenesisValidatorsRoot())
+		require.Len(t, found.Validators(), 1)
+	}
+}
+
+func TestVersionedBeaconState_SyncCommittees(t *testing.T) {
+	phase0, err := NewVersionedBeaconState(&BeaconStatePhase0{})
+	require.NoError(t, err)
+	require.Nil(t, phase0.CurrentSyncCommittee())
+	require.Nil(t, phase0.NextSyncCommittee())
+
+	current, next := &SyncCommittee{AggregatePubKey: [48]byte{0x1}}, &SyncCommittee{AggregatePubKey: [48]byte{0x2}}
+
+	deneb, err := NewVersionedBeaconState(&BeaconStateDeneb{CurrentSyncCommittee: current, NextSyncCommittee: next})
+	require.NoError(t, err)
+	require.Equal(t, current, deneb.CurrentSyncCommittee())
+	require.Equal(t, next, deneb.NextSyncCommittee())
+}
+
+func TestVersionedBeaconState_RandaoMix(t *testing.T) {
+	for v := VersionPhase0; v <= VersionElectra; v++ {
+		state, err := NewBeaconState(v)
+		require.NoError(t, err)
+
+		versioned, err := NewVersionedBeaconState(state)
+		require.NoError(t, err)
+
+		mixes := versioned.randaoMixes()
+		require.NotNil(t, mixes)
+		mixes[3] = [32]byte{0x1}
+
+		require.Equal(t, [32]byte{0x1}, versioned.RandaoMix(3))
+		require.Equal(t, [32]byte{}, versioned.RandaoMix(uint64(len(mixes))))
+	}
+}
+
+func marshalTestState(t *testing.T, state BeaconState, slot uint64) []byte {
+	versioned, err := NewVersionedBeaconState(state)
+	require.NoError(t, err)
+
+	var (
+		root       = [32]byte{0x1}
+		validators = []*Validator{{}}
+		slashings  = make([]uint64, 8192)
+	)
+
+	switch obj := versioned.State.(type) {
+	case *BeaconStatePhase0:
+		obj.Slot, obj.GenesisValidatorsRoot, obj.Validators, obj.Slashings = slot, root, validators, slashings
+	case *BeaconStateAltair:
+		obj.Slot, obj.GenesisValidatorsRoot, obj.Validators, obj.Slashings = slot, root, validators, slashings
+	case *BeaconStateBellatrix:
+		obj.Slot, obj.GenesisValidatorsRoot, obj.Validators, obj.Slashings = slot, root, validators, slashings
+	case *BeaconStateCapella:
+		obj.Slot, obj.GenesisValidatorsRoot, obj.Validators, obj.Slashings = slot, root, validators, slashings
+	case *BeaconStateDeneb:
+		obj.Slot, obj.GenesisValidatorsRoot, obj.Validators, obj.Slashings = slot, root, validators, slashings
+	case *BeaconStateElectra:
+		obj.Slot, obj.GenesisValidatorsRoot, obj.Validators, obj.Slashings = slot, root, validators, slashings
+	}
+
+	data, err := versioned.MarshalSSZ()
+	require.NoError(t, err)
+	return data
+}
+
+func TestVersionedBlindedBeaconBlock(t *testing.T) {
+	for version := VersionBellatrix; version <= VersionElectra; version++ {
+		block, err := NewVersionedBlindedBeaconBlock(version)
+		require.NoError(t, err)
+
+		// ssz round trip with the fork of the version
+		data, err := block.MarshalSSZ()
+		require.NoError(t, err)
+
+		found := &VersionedBlindedBeaconBlock{Version: version}
+		require.NoError(t, found.UnmarshalSSZ(data))
+		require.IsType(t, block.Object(), found.Object())
+
+		root, err := block.HashTreeRoot()
+		require.NoError(t, err)
+
+		signed := found.WithSignature(Signature{0x1})
+		require.Equal(t, version, signed.Version)
+
+		data, err = signed.MarshalSSZ()
+		require.NoError(t, err)
+
+		foundSigned := &VersionedSignedBlindedBeaconBlock{Version: version}
+		require.NoError(t, foundSigned.UnmarshalSSZ(data))
+
+		signedRoot, err := foundSigned.HashTreeRoot()
+		require.NoError(t, err)
+		require.NotEqual(t, root, signedRoot)
+	}
+
+	_, err := NewVersionedBlindedBeaconBlock(VersionAltair)
+	require.Error(t, err)
+
+	_, err = (&VersionedSignedBlindedBeaconBlock{}).MarshalSSZ()
+	require.Error(t, err)
+}